@@ -4,7 +4,7 @@ import moment from 'moment';
 
 import TradingViewChart from './TradingViewChart';
 
-import {BalanceMap, ReportSummary} from "../types";
+import {BalanceMap, MonteCarloReport, ReportSummary} from "../types";
 
 import {
   Badge,
@@ -159,6 +159,58 @@ const BalanceDetails = (props: BalanceDetailsProps) => {
   </Table>;
 };
 
+interface MonteCarloDetailsProps {
+  report: MonteCarloReport;
+}
+
+const MonteCarloDetails = (props: MonteCarloDetailsProps) => {
+  const {report} = props;
+  const percent = (v: number) => (Math.round(v * 10000) / 100).toString() + "%";
+  const round = (v: number) => (Math.round(v * 100) / 100).toString();
+
+  return <div>
+    <Title order={6}>
+      Monte Carlo ({report.method}, {report.simulations} simulations of {report.numOfTrades} trades)
+    </Title>
+    <Table verticalSpacing="xs" fontSize="xs">
+      <thead>
+      <tr>
+        <th></th>
+        <th>P5</th>
+        <th>P25</th>
+        <th>P50</th>
+        <th>P75</th>
+        <th>P95</th>
+        <th>Mean</th>
+      </tr>
+      </thead>
+      <tbody>
+      <tr>
+        <td>Final Equity</td>
+        <td>{round(report.finalEquity.p5)}</td>
+        <td>{round(report.finalEquity.p25)}</td>
+        <td>{round(report.finalEquity.p50)}</td>
+        <td>{round(report.finalEquity.p75)}</td>
+        <td>{round(report.finalEquity.p95)}</td>
+        <td>{round(report.finalEquity.mean)}</td>
+      </tr>
+      <tr>
+        <td>Max Drawdown</td>
+        <td>{percent(report.maxDrawdown.p5)}</td>
+        <td>{percent(report.maxDrawdown.p25)}</td>
+        <td>{percent(report.maxDrawdown.p50)}</td>
+        <td>{percent(report.maxDrawdown.p75)}</td>
+        <td>{percent(report.maxDrawdown.p95)}</td>
+        <td>{percent(report.maxDrawdown.mean)}</td>
+      </tr>
+      </tbody>
+    </Table>
+    <Text size="xs">
+      Risk of ruin ({percent(report.ruinThreshold)} loss): {percent(report.riskOfRuin)}
+    </Text>
+  </div>;
+};
+
 const ReportDetails = (props: ReportDetailsProps) => {
   const [reportSummary, setReportSummary] = useState<ReportSummary>()
   useEffect(() => {
@@ -222,6 +274,8 @@ const ReportDetails = (props: ReportDetailsProps) => {
         </Grid.Col>
       </Grid>
 
+      {reportSummary.monteCarlo ? <MonteCarloDetails report={reportSummary.monteCarlo}/> : null}
+
       {
         /*
         <Grid>
@@ -33,6 +33,38 @@ export interface ReportSummary {
   finalTotalBalances: BalanceMap;
   symbolReports: SymbolReport[];
   manifests: Manifest[];
+  monteCarlo?: MonteCarloReport;
+  monteCarloFile?: string;
+}
+
+export interface MonteCarloDistribution {
+  mean: number;
+  p5: number;
+  p25: number;
+  p50: number;
+  p75: number;
+  p95: number;
+}
+
+export interface MonteCarloSample {
+  finalEquity: number;
+  maxDrawdown: number;
+  ruined?: boolean;
+}
+
+export interface MonteCarloReport {
+  method: string;
+  simulations: number;
+  numOfTrades: number;
+  seed: number;
+  slippage: number;
+  feeJitter: number;
+  ruinThreshold: number;
+  initialEquity: number;
+  finalEquity: MonteCarloDistribution;
+  maxDrawdown: MonteCarloDistribution;
+  riskOfRuin: number;
+  samples?: MonteCarloSample[];
 }
 
 export interface SymbolReport {
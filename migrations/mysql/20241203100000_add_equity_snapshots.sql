@@ -0,0 +1,24 @@
+-- +up
+CREATE TABLE `equity_snapshots`
+(
+    `gid`             BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+
+    `session`         VARCHAR(30)     NOT NULL,
+    `exchange`        VARCHAR(20)     NOT NULL,
+    `time`            DATETIME(3)     NOT NULL,
+    `quote_currency`  VARCHAR(10)     NOT NULL,
+
+    -- net_value is the account net value in the quote currency
+    `net_value`       DECIMAL(32, 8)  NOT NULL,
+    `high_water_mark` DECIMAL(32, 8)  NOT NULL,
+
+    -- drawdown ratios from the high-water mark
+    `drawdown`        DECIMAL(32, 8)  NOT NULL,
+    `max_drawdown`    DECIMAL(32, 8)  NOT NULL,
+
+    PRIMARY KEY (`gid`),
+    INDEX `session_time` (`session`, `time`)
+);
+
+-- +down
+DROP TABLE IF EXISTS `equity_snapshots`;
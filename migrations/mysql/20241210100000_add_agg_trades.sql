@@ -0,0 +1,22 @@
+-- +up
+CREATE TABLE `agg_trades`
+(
+    `gid`            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+
+    `exchange`       VARCHAR(20)     NOT NULL,
+    `symbol`         VARCHAR(32)     NOT NULL,
+    `agg_trade_id`   BIGINT UNSIGNED NOT NULL,
+    `price`          DECIMAL(32, 8)  NOT NULL,
+    `quantity`       DECIMAL(32, 8)  NOT NULL,
+    `first_trade_id` BIGINT UNSIGNED NOT NULL,
+    `last_trade_id`  BIGINT UNSIGNED NOT NULL,
+    `is_buyer_maker` BOOLEAN         NOT NULL DEFAULT FALSE,
+    `traded_at`      DATETIME(3)     NOT NULL,
+
+    PRIMARY KEY (`gid`),
+    UNIQUE KEY `agg_trade_id` (`exchange`, `symbol`, `agg_trade_id`),
+    INDEX `symbol_traded_at` (`exchange`, `symbol`, `traded_at`)
+);
+
+-- +down
+DROP TABLE IF EXISTS `agg_trades`;
@@ -0,0 +1,55 @@
+-- +up
+CREATE TABLE `balance_snapshots`
+(
+    `gid`       BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+
+    `session`   VARCHAR(30)     NOT NULL,
+    `exchange`  VARCHAR(20)     NOT NULL,
+    `time`      DATETIME(3)     NOT NULL,
+    `asset`     VARCHAR(24)     NOT NULL,
+
+    `available` DECIMAL(32, 8)  NOT NULL,
+    `locked`    DECIMAL(32, 8)  NOT NULL,
+    `borrowed`  DECIMAL(32, 8)  NOT NULL,
+    `interest`  DECIMAL(32, 8)  NOT NULL,
+
+    -- net_asset = available + locked - borrowed - interest
+    `net_asset` DECIMAL(32, 8)  NOT NULL,
+
+    PRIMARY KEY (`gid`),
+    INDEX `session_time` (`session`, `time`)
+);
+
+CREATE TABLE `account_statements`
+(
+    `gid`           BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+
+    `session`       VARCHAR(30)     NOT NULL,
+    `exchange`      VARCHAR(20)     NOT NULL,
+    `asset`         VARCHAR(24)     NOT NULL,
+    `start_time`    DATETIME(3)     NOT NULL,
+    `end_time`      DATETIME(3)     NOT NULL,
+
+    -- the net assets of the balance snapshots
+    `start_balance` DECIMAL(32, 8)  NOT NULL,
+    `end_balance`   DECIMAL(32, 8)  NOT NULL,
+
+    -- the flows from the synced records between the snapshots
+    `trade`         DECIMAL(32, 8)  NOT NULL,
+    `fee`           DECIMAL(32, 8)  NOT NULL,
+    `deposit`       DECIMAL(32, 8)  NOT NULL,
+    `withdraw`      DECIMAL(32, 8)  NOT NULL,
+    `reward`        DECIMAL(32, 8)  NOT NULL,
+    `interest`      DECIMAL(32, 8)  NOT NULL,
+
+    -- difference = end_balance - (start_balance + flows)
+    `difference`    DECIMAL(32, 8)  NOT NULL,
+    `reconciled`    BOOLEAN         NOT NULL DEFAULT TRUE,
+
+    PRIMARY KEY (`gid`),
+    INDEX `session_end_time` (`session`, `end_time`)
+);
+
+-- +down
+DROP TABLE IF EXISTS `account_statements`;
+DROP TABLE IF EXISTS `balance_snapshots`;
@@ -0,0 +1,23 @@
+-- +up
+CREATE TABLE `equity_snapshots`
+(
+    `gid`             INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    `session`         VARCHAR(30)    NOT NULL,
+    `exchange`        VARCHAR(20)    NOT NULL,
+    `time`            DATETIME(3)    NOT NULL,
+    `quote_currency`  VARCHAR(10)    NOT NULL,
+
+    -- net_value is the account net value in the quote currency
+    `net_value`       DECIMAL(32, 8) NOT NULL,
+    `high_water_mark` DECIMAL(32, 8) NOT NULL,
+
+    -- drawdown ratios from the high-water mark
+    `drawdown`        DECIMAL(32, 8) NOT NULL,
+    `max_drawdown`    DECIMAL(32, 8) NOT NULL
+);
+CREATE INDEX equity_snapshots_session_time ON equity_snapshots (session, time);
+
+-- +down
+DROP INDEX IF EXISTS equity_snapshots_session_time;
+DROP TABLE IF EXISTS `equity_snapshots`;
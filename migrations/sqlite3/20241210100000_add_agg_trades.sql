@@ -0,0 +1,22 @@
+-- +up
+CREATE TABLE `agg_trades`
+(
+    `gid`            INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    `exchange`       VARCHAR(20)    NOT NULL,
+    `symbol`         VARCHAR(32)    NOT NULL,
+    `agg_trade_id`   INTEGER        NOT NULL,
+    `price`          DECIMAL(32, 8) NOT NULL,
+    `quantity`       DECIMAL(32, 8) NOT NULL,
+    `first_trade_id` INTEGER        NOT NULL,
+    `last_trade_id`  INTEGER        NOT NULL,
+    `is_buyer_maker` BOOLEAN        NOT NULL DEFAULT FALSE,
+    `traded_at`      DATETIME(3)    NOT NULL
+);
+CREATE UNIQUE INDEX agg_trades_agg_trade_id ON agg_trades (exchange, symbol, agg_trade_id);
+CREATE INDEX agg_trades_symbol_traded_at ON agg_trades (exchange, symbol, traded_at);
+
+-- +down
+DROP INDEX IF EXISTS agg_trades_symbol_traded_at;
+DROP INDEX IF EXISTS agg_trades_agg_trade_id;
+DROP TABLE IF EXISTS `agg_trades`;
@@ -6,6 +6,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -13,6 +14,9 @@ type AverageCostCalculator struct {
 	TradingFeeCurrency string
 	Market             types.Market
 	ExchangeFee        *types.ExchangeFee
+
+	// PriceSolver is optional, it's used to value the fees paid in the other currencies (like BNB) in the quote currency
+	PriceSolver pricesolver.PriceSolver
 }
 
 func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, currentPrice fixedpoint.Value) *AverageCostPnLReport {
@@ -73,6 +77,8 @@ func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, c
 			continue
 		}
 
+		c.updateFeeAverageCost(position, trade.FeeCurrency)
+
 		profit, netProfit, madeProfit := position.AddTrade(trade)
 		if madeProfit {
 			totalProfit = totalProfit.Add(profit)
@@ -127,3 +133,18 @@ func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, c
 		CurrencyFees: currencyFees,
 	}
 }
+
+// updateFeeAverageCost sets the fee cost of the fee currency that is neither the base nor the quote currency
+func (c *AverageCostCalculator) updateFeeAverageCost(position *types.Position, feeCurrency string) {
+	if c.PriceSolver == nil || feeCurrency == "" || feeCurrency == c.Market.QuoteCurrency || feeCurrency == c.Market.BaseCurrency {
+		return
+	}
+
+	if _, ok := position.FeeAverageCosts[feeCurrency]; ok {
+		return
+	}
+
+	if price, ok := c.PriceSolver.ResolvePrice(feeCurrency, c.Market.QuoteCurrency); ok {
+		position.SetFeeAverageCost(feeCurrency, price)
+	}
+}
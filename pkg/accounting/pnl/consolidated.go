@@ -0,0 +1,396 @@
+package pnl
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// UnknownStrategy is the strategy name of the trades that are not made by a recorded strategy instance
+const UnknownStrategy = "unknown"
+
+// StrategyInstance identifies the strategy instance that made a trade
+type StrategyInstance struct {
+	Strategy   string `json:"strategy"`
+	InstanceID string `json:"instanceID"`
+}
+
+// ConsolidatedCalculator calculates the average cost PnL of the assets across the sessions and the symbols.
+//
+// Each trade is converted into two asset flows valued in the reporting currency, e.g. buying BTC with USDT on Binance
+// adds BTC and removes USDT, and selling BTC for TWD on MAX removes BTC and adds TWD, so that the same asset is netted
+// across the sessions and the symbol pairs. The actual trade fees are valued in the reporting currency and deducted from the fee assets.
+type ConsolidatedCalculator struct {
+	// Currency is the reporting currency
+	Currency string
+
+	Markets types.MarketMap
+
+	// PriceSolver resolves the price of the quote currency in the reporting currency at the trade time,
+	// it's updated by the trades in the time order.
+	PriceSolver pricesolver.PriceSolver
+
+	// StrategyResolver returns the strategy instance of the trade, the StrategyID of the trade is used if it's not set
+	StrategyResolver func(trade types.Trade) (StrategyInstance, bool)
+}
+
+// assetPosition is the average cost position of an asset, the average cost is in the reporting currency
+type assetPosition struct {
+	Quantity    fixedpoint.Value
+	AverageCost fixedpoint.Value
+}
+
+// add adds the signed quantity at the price and returns the realized profit
+func (p *assetPosition) add(quantity, price fixedpoint.Value) (profit fixedpoint.Value) {
+	if p.Quantity.IsZero() || p.Quantity.Sign() == quantity.Sign() {
+		total := p.Quantity.Abs().Add(quantity.Abs())
+		p.AverageCost = p.AverageCost.Mul(p.Quantity.Abs()).Add(price.Mul(quantity.Abs())).Div(total)
+		p.Quantity = p.Quantity.Add(quantity)
+		return fixedpoint.Zero
+	}
+
+	closed := fixedpoint.Min(quantity.Abs(), p.Quantity.Abs())
+	profit = price.Sub(p.AverageCost).Mul(closed)
+	if p.Quantity.Sign() < 0 {
+		profit = profit.Neg()
+	}
+
+	reversed := quantity.Abs().Compare(p.Quantity.Abs()) > 0
+	p.Quantity = p.Quantity.Add(quantity)
+	if reversed {
+		p.AverageCost = price
+	} else if p.Quantity.IsZero() {
+		p.AverageCost = fixedpoint.Zero
+	}
+
+	return profit
+}
+
+// deduct removes the quantity at the average cost without the realized profit, it's used for the fees
+func (p *assetPosition) deduct(quantity, price fixedpoint.Value) {
+	if p.Quantity.Sign() > 0 {
+		price = p.AverageCost
+	}
+
+	p.add(quantity.Neg(), price)
+}
+
+// book is the asset positions of a set of trades
+type book struct {
+	currency  string
+	positions map[string]*assetPosition
+}
+
+func newBook(currency string) *book {
+	return &book{currency: currency, positions: make(map[string]*assetPosition)}
+}
+
+func (b *book) position(asset string) *assetPosition {
+	p, ok := b.positions[asset]
+	if !ok {
+		p = &assetPosition{}
+		b.positions[asset] = p
+	}
+
+	return p
+}
+
+func (b *book) add(asset string, quantity, price fixedpoint.Value) fixedpoint.Value {
+	if asset == b.currency || quantity.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	return b.position(asset).add(quantity, price)
+}
+
+func (b *book) deduct(asset string, quantity, price fixedpoint.Value) {
+	if asset == b.currency || quantity.IsZero() {
+		return
+	}
+
+	b.position(asset).deduct(quantity, price)
+}
+
+// flow is the asset change of a trade valued in the reporting currency
+type flow struct {
+	Asset    string
+	Quantity fixedpoint.Value
+	Price    fixedpoint.Value
+	IsFee    bool
+}
+
+// AssetPnL is the consolidated PnL of an asset across the sessions
+type AssetPnL struct {
+	Asset            string           `json:"asset"`
+	Quantity         fixedpoint.Value `json:"quantity"`
+	AverageCost      fixedpoint.Value `json:"averageCost"`
+	CurrentPrice     fixedpoint.Value `json:"currentPrice"`
+	RealizedProfit   fixedpoint.Value `json:"realizedProfit"`
+	UnrealizedProfit fixedpoint.Value `json:"unrealizedProfit"`
+	BuyQuantity      fixedpoint.Value `json:"buyQuantity"`
+	SellQuantity     fixedpoint.Value `json:"sellQuantity"`
+	Fee              fixedpoint.Value `json:"fee"`
+}
+
+// StrategyPnL is the PnL of the trades made by a strategy instance, the positions of each strategy instance are netted separately
+type StrategyPnL struct {
+	StrategyInstance
+
+	NumTrades      int              `json:"numTrades"`
+	Volume         fixedpoint.Value `json:"volume"`
+	RealizedProfit fixedpoint.Value `json:"realizedProfit"`
+	Fee            fixedpoint.Value `json:"fee"`
+	NetProfit      fixedpoint.Value `json:"netProfit"`
+}
+
+// ConsolidatedPnLReport is the PnL of the trades of all the sessions in the reporting currency
+type ConsolidatedPnLReport struct {
+	Currency  string    `json:"currency"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	NumTrades int       `json:"numTrades"`
+
+	// UnpricedTrades is the number of the trades that can not be valued in the reporting currency, they are skipped
+	UnpricedTrades int `json:"unpricedTrades"`
+
+	// UnpricedFees is the fee amounts that can not be valued in the reporting currency
+	UnpricedFees map[string]fixedpoint.Value `json:"unpricedFees,omitempty"`
+
+	RealizedProfit   fixedpoint.Value `json:"realizedProfit"`
+	UnrealizedProfit fixedpoint.Value `json:"unrealizedProfit"`
+	Fee              fixedpoint.Value `json:"fee"`
+	NetProfit        fixedpoint.Value `json:"netProfit"`
+
+	Assets     []AssetPnL    `json:"assets"`
+	Strategies []StrategyPnL `json:"strategies"`
+}
+
+func (c *ConsolidatedCalculator) strategyOf(trade types.Trade) StrategyInstance {
+	if c.StrategyResolver != nil {
+		if instance, ok := c.StrategyResolver(trade); ok {
+			return instance
+		}
+	}
+
+	if trade.StrategyID.Valid && len(trade.StrategyID.String) > 0 {
+		return StrategyInstance{Strategy: trade.StrategyID.String}
+	}
+
+	return StrategyInstance{Strategy: UnknownStrategy}
+}
+
+// flows converts the trade into the asset flows, false is returned if the trade can not be valued
+func (c *ConsolidatedCalculator) flows(trade types.Trade) ([]flow, fixedpoint.Value, bool) {
+	market, ok := c.Markets[trade.Symbol]
+	if !ok {
+		return nil, fixedpoint.Zero, false
+	}
+
+	if c.PriceSolver != nil {
+		c.PriceSolver.UpdateFromTrade(trade)
+	}
+
+	base, quote := market.BaseCurrency, market.QuoteCurrency
+
+	var basePrice, quotePrice fixedpoint.Value
+	switch {
+	case quote == c.Currency:
+		quotePrice = fixedpoint.One
+		basePrice = trade.Price
+	case base == c.Currency:
+		basePrice = fixedpoint.One
+		quotePrice = fixedpoint.One.Div(trade.Price)
+	default:
+		if c.PriceSolver == nil {
+			return nil, fixedpoint.Zero, false
+		}
+
+		price, ok := c.PriceSolver.ResolvePrice(quote, c.Currency)
+		if !ok {
+			return nil, fixedpoint.Zero, false
+		}
+
+		quotePrice = price
+		basePrice = trade.Price.Mul(price)
+	}
+
+	quoteQuantity := trade.QuoteQuantity
+	if quoteQuantity.IsZero() {
+		quoteQuantity = trade.Price.Mul(trade.Quantity)
+	}
+
+	baseQuantity := trade.Quantity
+	if !trade.IsBuyer {
+		baseQuantity = baseQuantity.Neg()
+	} else {
+		quoteQuantity = quoteQuantity.Neg()
+	}
+
+	flows := []flow{
+		{Asset: base, Quantity: baseQuantity, Price: basePrice},
+		{Asset: quote, Quantity: quoteQuantity, Price: quotePrice},
+	}
+
+	volume := trade.Quantity.Mul(basePrice)
+
+	if trade.Fee.Sign() > 0 && len(trade.FeeCurrency) > 0 {
+		var feePrice fixedpoint.Value
+		switch trade.FeeCurrency {
+		case c.Currency:
+			feePrice = fixedpoint.One
+		case base:
+			feePrice = basePrice
+		case quote:
+			feePrice = quotePrice
+		default:
+			if c.PriceSolver != nil {
+				feePrice, _ = c.PriceSolver.ResolvePrice(trade.FeeCurrency, c.Currency)
+			}
+		}
+
+		flows = append(flows, flow{Asset: trade.FeeCurrency, Quantity: trade.Fee, Price: feePrice, IsFee: true})
+	}
+
+	return flows, volume, true
+}
+
+// Calculate calculates the consolidated PnL of the trades, the trades must be sorted by time.
+// The current prices are used to calculate the unrealized profit of the remaining positions, it's optional.
+func (c *ConsolidatedCalculator) Calculate(trades []types.Trade, currentPrices pricesolver.PriceSolver) *ConsolidatedPnLReport {
+	report := &ConsolidatedPnLReport{
+		Currency:     c.Currency,
+		UnpricedFees: make(map[string]fixedpoint.Value),
+	}
+
+	total := newBook(c.Currency)
+	assets := map[string]*AssetPnL{}
+	strategies := map[StrategyInstance]*StrategyPnL{}
+	strategyBooks := map[StrategyInstance]*book{}
+
+	assetOf := func(asset string) *AssetPnL {
+		a, ok := assets[asset]
+		if !ok {
+			a = &AssetPnL{Asset: asset}
+			assets[asset] = a
+		}
+
+		return a
+	}
+
+	tradeKeys := map[types.TradeKey]struct{}{}
+	for _, trade := range trades {
+		if _, exists := tradeKeys[trade.Key()]; exists {
+			log.Warnf("duplicated trade: %+v", trade)
+			continue
+		}
+
+		tradeKeys[trade.Key()] = struct{}{}
+
+		flows, volume, ok := c.flows(trade)
+		if !ok {
+			log.Warnf("unable to value the trade in %s, skipped: %+v", c.Currency, trade)
+			report.UnpricedTrades++
+			continue
+		}
+
+		if report.NumTrades == 0 {
+			report.StartTime = trade.Time.Time()
+		}
+
+		report.NumTrades++
+		report.EndTime = trade.Time.Time()
+
+		instance := c.strategyOf(trade)
+		st, ok := strategies[instance]
+		if !ok {
+			st = &StrategyPnL{StrategyInstance: instance}
+			strategies[instance] = st
+			strategyBooks[instance] = newBook(c.Currency)
+		}
+
+		stBook := strategyBooks[instance]
+
+		st.NumTrades++
+		st.Volume = st.Volume.Add(volume)
+
+		for _, f := range flows {
+			if f.Asset == c.Currency && !f.IsFee {
+				continue
+			}
+
+			a := assetOf(f.Asset)
+			if f.IsFee {
+				if f.Price.IsZero() {
+					report.UnpricedFees[f.Asset] = report.UnpricedFees[f.Asset].Add(f.Quantity)
+				}
+
+				fee := f.Quantity.Mul(f.Price)
+				a.Fee = a.Fee.Add(fee)
+				st.Fee = st.Fee.Add(fee)
+				report.Fee = report.Fee.Add(fee)
+				total.deduct(f.Asset, f.Quantity, f.Price)
+				stBook.deduct(f.Asset, f.Quantity, f.Price)
+				continue
+			}
+
+			if f.Quantity.Sign() > 0 {
+				a.BuyQuantity = a.BuyQuantity.Add(f.Quantity)
+			} else {
+				a.SellQuantity = a.SellQuantity.Add(f.Quantity.Neg())
+			}
+
+			profit := total.add(f.Asset, f.Quantity, f.Price)
+			a.RealizedProfit = a.RealizedProfit.Add(profit)
+			report.RealizedProfit = report.RealizedProfit.Add(profit)
+			st.RealizedProfit = st.RealizedProfit.Add(stBook.add(f.Asset, f.Quantity, f.Price))
+		}
+	}
+
+	for asset, a := range assets {
+		if asset == c.Currency {
+			continue
+		}
+
+		p := total.position(asset)
+		a.Quantity = p.Quantity
+		a.AverageCost = p.AverageCost
+
+		if currentPrices != nil {
+			if price, ok := currentPrices.ResolvePrice(asset, c.Currency); ok {
+				a.CurrentPrice = price
+				a.UnrealizedProfit = price.Sub(p.AverageCost).Mul(p.Quantity)
+				report.UnrealizedProfit = report.UnrealizedProfit.Add(a.UnrealizedProfit)
+			}
+		}
+	}
+
+	for _, a := range assets {
+		report.Assets = append(report.Assets, *a)
+	}
+
+	sort.Slice(report.Assets, func(i, j int) bool {
+		return report.Assets[i].Asset < report.Assets[j].Asset
+	})
+
+	for _, st := range strategies {
+		st.NetProfit = st.RealizedProfit.Sub(st.Fee)
+		report.Strategies = append(report.Strategies, *st)
+	}
+
+	sort.Slice(report.Strategies, func(i, j int) bool {
+		a, b := report.Strategies[i], report.Strategies[j]
+		if a.Strategy == b.Strategy {
+			return a.InstanceID < b.InstanceID
+		}
+
+		return a.Strategy < b.Strategy
+	})
+
+	report.NetProfit = report.RealizedProfit.Sub(report.Fee)
+	return report
+}
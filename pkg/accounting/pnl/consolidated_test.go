@@ -0,0 +1,111 @@
+package pnl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	. "github.com/c9s/bbgo/pkg/testing/testhelper"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestConsolidatedCalculator(t *testing.T) {
+	markets := types.MarketMap{
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"BTCTWD":  {Symbol: "BTCTWD", BaseCurrency: "BTC", QuoteCurrency: "TWD"},
+		"USDTTWD": {Symbol: "USDTTWD", BaseCurrency: "USDT", QuoteCurrency: "TWD"},
+		"BNBUSDT": {Symbol: "BNBUSDT", BaseCurrency: "BNB", QuoteCurrency: "USDT"},
+	}
+
+	now := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	solver := pricesolver.NewGraphPriceSolver(markets, 0)
+	solver.UpdateQuote("max", "USDTTWD", Number(32), Number(1_000_000), now)
+	solver.UpdateQuote("binance", "BNBUSDT", Number(300), Number(1_000_000), now)
+
+	trades := []types.Trade{
+		{
+			ID: 1, Exchange: types.ExchangeBinance, Symbol: "BTCUSDT", Side: types.SideTypeBuy, IsBuyer: true,
+			Price: Number(20000), Quantity: Number(2), QuoteQuantity: Number(40000),
+			Fee: Number(0.1), FeeCurrency: "BNB",
+			Time: types.Time(now),
+		},
+		{
+			// sell BTC for TWD, 800000 TWD = 25000 USDT
+			ID: 2, Exchange: types.ExchangeMax, Symbol: "BTCTWD", Side: types.SideTypeSell,
+			Price: Number(800000), Quantity: Number(1), QuoteQuantity: Number(800000),
+			Fee: Number(3200), FeeCurrency: "TWD",
+			Time: types.Time(now.Add(time.Hour)),
+		},
+		{
+			// the duplicated trade is skipped
+			ID: 2, Exchange: types.ExchangeMax, Symbol: "BTCTWD", Side: types.SideTypeSell,
+			Price: Number(800000), Quantity: Number(1), QuoteQuantity: Number(800000),
+			Time: types.Time(now.Add(time.Hour)),
+		},
+		{
+			ID: 3, Exchange: types.ExchangeMax, Symbol: "BTCTWD", Side: types.SideTypeSell,
+			Price: Number(640000), Quantity: Number(0.5), QuoteQuantity: Number(320000),
+			Time: types.Time(now.Add(2 * time.Hour)),
+		},
+	}
+
+	calculator := &ConsolidatedCalculator{
+		Currency:    "USDT",
+		Markets:     markets,
+		PriceSolver: solver,
+		StrategyResolver: func(trade types.Trade) (StrategyInstance, bool) {
+			if trade.ID == 3 {
+				return StrategyInstance{Strategy: "grid", InstanceID: "grid-BTCTWD"}, true
+			}
+
+			return StrategyInstance{}, false
+		},
+	}
+
+	currentPrices := pricesolver.NewGraphPriceSolver(markets, 0)
+	currentPrices.UpdateQuote("binance", "BTCUSDT", Number(30000), Number(1_000_000), now)
+
+	report := calculator.Calculate(trades, currentPrices)
+	assert.Equal(t, 3, report.NumTrades)
+	assert.Equal(t, 0, report.UnpricedTrades)
+
+	// BTC: (25000 - 20000) * 1 + (20000 - 20000) * 0.5
+	assert.Equal(t, Number(5000), report.RealizedProfit)
+
+	// 0.1 BNB * 300 + 3200 TWD / 32
+	assert.Equal(t, Number(130), report.Fee)
+	assert.Equal(t, Number(4870), report.NetProfit)
+
+	// the remaining 0.5 BTC
+	assert.Equal(t, Number(5000), report.UnrealizedProfit)
+
+	if assert.Len(t, report.Assets, 3) {
+		assert.Equal(t, "BNB", report.Assets[0].Asset)
+		assert.Equal(t, Number(-0.1), report.Assets[0].Quantity)
+
+		btc := report.Assets[1]
+		assert.Equal(t, "BTC", btc.Asset)
+		assert.Equal(t, Number(0.5), btc.Quantity)
+		assert.Equal(t, Number(20000), btc.AverageCost)
+		assert.Equal(t, Number(30000), btc.CurrentPrice)
+
+		twd := report.Assets[2]
+		assert.Equal(t, "TWD", twd.Asset)
+		assert.Equal(t, Number(1116800), twd.Quantity)
+	}
+
+	if assert.Len(t, report.Strategies, 2) {
+		assert.Equal(t, "grid", report.Strategies[0].Strategy)
+		assert.Equal(t, 1, report.Strategies[0].NumTrades)
+		assert.Equal(t, Number(10000), report.Strategies[0].Volume)
+
+		// the trades of the unknown strategy are netted in its own positions
+		assert.Equal(t, UnknownStrategy, report.Strategies[1].Strategy)
+		assert.Equal(t, 2, report.Strategies[1].NumTrades)
+		assert.Equal(t, Number(5000), report.Strategies[1].RealizedProfit)
+		assert.Equal(t, Number(130), report.Strategies[1].Fee)
+	}
+}
@@ -98,3 +98,56 @@ func (report AverageCostPnLReport) SlackAttachment() slack.Attachment {
 		FooterIcon: "",
 	}
 }
+
+func (report *ConsolidatedPnLReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(report, "", "  ")
+}
+
+func (report ConsolidatedPnLReport) Print() {
+	color.Green("TRADES: %d (%v ~ %v)", report.NumTrades, report.StartTime, report.EndTime)
+	if report.UnpricedTrades > 0 {
+		color.Red("UNPRICED TRADES: %d", report.UnpricedTrades)
+	}
+
+	for currency, fee := range report.UnpricedFees {
+		color.Red("UNPRICED FEE: %s %s", fee.String(), currency)
+	}
+
+	color.Green("ASSETS (%s):", report.Currency)
+	for _, a := range report.Assets {
+		color.Green(" - %s: quantity %s, average cost %s, current price %s, realized %s, unrealized %s, fee %s",
+			a.Asset, a.Quantity.String(), a.AverageCost.String(), a.CurrentPrice.String(),
+			a.RealizedProfit.String(), a.UnrealizedProfit.String(), a.Fee.String())
+	}
+
+	color.Green("STRATEGIES (%s):", report.Currency)
+	for _, st := range report.Strategies {
+		name := st.Strategy
+		if len(st.InstanceID) > 0 {
+			name += " " + st.InstanceID
+		}
+
+		color.Green(" - %s: %d trades, volume %s, realized %s, fee %s, net %s",
+			name, st.NumTrades, st.Volume.String(), st.RealizedProfit.String(), st.Fee.String(), st.NetProfit.String())
+	}
+
+	color.Green("FEE: %s %s", report.Fee.String(), report.Currency)
+
+	if report.RealizedProfit.Sign() > 0 {
+		color.Green("REALIZED PROFIT: %s %s", report.RealizedProfit.String(), report.Currency)
+	} else {
+		color.Red("REALIZED PROFIT: %s %s", report.RealizedProfit.String(), report.Currency)
+	}
+
+	if report.NetProfit.Sign() > 0 {
+		color.Green("NET PROFIT: %s %s", report.NetProfit.String(), report.Currency)
+	} else {
+		color.Red("NET PROFIT: %s %s", report.NetProfit.String(), report.Currency)
+	}
+
+	if report.UnrealizedProfit.Sign() > 0 {
+		color.Green("UNREALIZED PROFIT: %s %s", report.UnrealizedProfit.String(), report.Currency)
+	} else {
+		color.Red("UNREALIZED PROFIT: %s %s", report.UnrealizedProfit.String(), report.Currency)
+	}
+}
@@ -0,0 +1,553 @@
+package taxlot
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DefaultLongTermPeriod is the holding period of the long-term gains, the lots held more than one year are long-term
+const DefaultLongTermPeriod = 365 * 24 * time.Hour
+
+// PriceFunc returns the price of the asset in the reporting currency at the given time
+type PriceFunc func(asset string, t time.Time) (fixedpoint.Value, bool)
+
+func TradeID(trade types.Trade) string {
+	return fmt.Sprintf("%s:trade:%d", trade.Exchange, trade.ID)
+}
+
+func DepositID(deposit types.Deposit) string {
+	return fmt.Sprintf("%s:deposit:%s", deposit.Exchange, deposit.TransactionID)
+}
+
+func WithdrawID(withdraw types.Withdraw) string {
+	if len(withdraw.TransactionID) == 0 {
+		return fmt.Sprintf("%s:withdraw:%d", withdraw.Exchange, withdraw.GID)
+	}
+
+	return fmt.Sprintf("%s:withdraw:%s", withdraw.Exchange, withdraw.TransactionID)
+}
+
+func RewardID(reward types.Reward) string {
+	return fmt.Sprintf("%s:reward:%s", reward.Exchange, reward.UUID)
+}
+
+// Event is a cost-basis event, only one of the records is set
+type Event struct {
+	Time     time.Time
+	Trade    *types.Trade
+	Deposit  *types.Deposit
+	Withdraw *types.Withdraw
+	Reward   *types.Reward
+}
+
+func NewTradeEvent(trade types.Trade) Event {
+	return Event{Time: trade.Time.Time(), Trade: &trade}
+}
+
+func NewDepositEvent(deposit types.Deposit) Event {
+	return Event{Time: deposit.Time.Time(), Deposit: &deposit}
+}
+
+func NewWithdrawEvent(withdraw types.Withdraw) Event {
+	return Event{Time: withdraw.ApplyTime.Time(), Withdraw: &withdraw}
+}
+
+func NewRewardEvent(reward types.Reward) Event {
+	return Event{Time: reward.CreatedAt.Time(), Reward: &reward}
+}
+
+// SortEvents sorts the events by time, the withdrawals are processed before the deposits at the same time
+func SortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Time.Equal(events[j].Time) {
+			return events[i].Withdraw != nil && events[j].Withdraw == nil
+		}
+
+		return events[i].Time.Before(events[j].Time)
+	})
+}
+
+// Ledger tracks the tax lots of all the assets, the events must be processed in the time order.
+//
+// The buy trades, the rewards and the external deposits open the lots, the sell trades and the non-cash fees
+// dispose the lots. The withdrawn lots are kept in transit with the original cost basis and acquisition time
+// until they are deposited to another exchange, so that the transfers between the sessions are not taxable.
+type Ledger struct {
+	Method Method
+
+	// Currency is the reporting currency, the cost basis and the proceeds are valued in this currency
+	Currency string
+
+	LongTermPeriod time.Duration
+
+	// Markets are used to find the base and quote currencies of the trades
+	Markets types.MarketMap
+
+	// PriceFunc is optional, it's used to value the deposits, the rewards and the fees paid in the other currencies.
+	// The last trade prices are used if it's not set or the price is not found.
+	PriceFunc PriceFunc
+
+	// LotSelections maps the disposal ID (e.g. "binance:trade:123") to the lot IDs for the specific-ID method
+	LotSelections map[string][]string
+
+	lots       map[string][]*Lot
+	inTransit  map[string][]*Lot
+	lastPrices map[string]fixedpoint.Value
+	disposals  []Disposal
+	incomes    []Income
+
+	// unmatched is the disposed or withdrawn quantity that has no lots, it's usually caused by the missing history
+	unmatched map[string]fixedpoint.Value
+
+	// missingPrices counts the valuations without a price
+	missingPrices map[string]int
+}
+
+func NewLedger(method Method, currency string, markets types.MarketMap) *Ledger {
+	return &Ledger{
+		Method:         method,
+		Currency:       currency,
+		LongTermPeriod: DefaultLongTermPeriod,
+		Markets:        markets,
+		lots:           make(map[string][]*Lot),
+		inTransit:      make(map[string][]*Lot),
+		lastPrices:     make(map[string]fixedpoint.Value),
+		unmatched:      make(map[string]fixedpoint.Value),
+		missingPrices:  make(map[string]int),
+	}
+}
+
+func (l *Ledger) Process(event Event) error {
+	switch {
+	case event.Trade != nil:
+		return l.AddTrade(*event.Trade)
+	case event.Deposit != nil:
+		l.AddDeposit(*event.Deposit)
+	case event.Withdraw != nil:
+		l.AddWithdraw(*event.Withdraw)
+	case event.Reward != nil:
+		l.AddReward(*event.Reward)
+	}
+
+	return nil
+}
+
+// Lots returns the open lots of the asset, the lots in transit are not included
+func (l *Ledger) Lots(asset string) (lots []Lot) {
+	for _, lot := range l.lots[asset] {
+		lots = append(lots, *lot)
+	}
+
+	return lots
+}
+
+// InTransit returns the withdrawn lots of the asset that are not deposited yet
+func (l *Ledger) InTransit(asset string) (lots []Lot) {
+	for _, lot := range l.inTransit[asset] {
+		lots = append(lots, *lot)
+	}
+
+	return lots
+}
+
+func (l *Ledger) Disposals() []Disposal {
+	return l.disposals
+}
+
+func (l *Ledger) Incomes() []Income {
+	return l.incomes
+}
+
+// Unmatched returns the disposed or withdrawn quantities that have no lots
+func (l *Ledger) Unmatched() map[string]fixedpoint.Value {
+	return l.unmatched
+}
+
+// MissingPrices returns the number of the valuations without a price by the assets
+func (l *Ledger) MissingPrices() map[string]int {
+	return l.missingPrices
+}
+
+func (l *Ledger) price(asset string, t time.Time) (fixedpoint.Value, bool) {
+	if asset == l.Currency {
+		return fixedpoint.One, true
+	}
+
+	if l.PriceFunc != nil {
+		if price, ok := l.PriceFunc(asset, t); ok && price.Sign() > 0 {
+			return price, true
+		}
+	}
+
+	price, ok := l.lastPrices[asset]
+	return price, ok
+}
+
+// value returns the value of the quantity in the reporting currency, zero is returned if the price is not found
+func (l *Ledger) value(asset string, quantity fixedpoint.Value, t time.Time) fixedpoint.Value {
+	price, ok := l.price(asset, t)
+	if !ok {
+		l.missingPrices[asset]++
+		return fixedpoint.Zero
+	}
+
+	return quantity.Mul(price)
+}
+
+func (l *Ledger) AddTrade(trade types.Trade) error {
+	market, ok := l.Markets[trade.Symbol]
+	if !ok {
+		return fmt.Errorf("market %s not found", trade.Symbol)
+	}
+
+	if trade.Quantity.Sign() <= 0 {
+		return nil
+	}
+
+	base, quote := market.BaseCurrency, market.QuoteCurrency
+	t := trade.Time.Time()
+	id := TradeID(trade)
+
+	quoteQuantity := trade.QuoteQuantity
+	if quoteQuantity.IsZero() {
+		quoteQuantity = trade.Price.Mul(trade.Quantity)
+	}
+
+	var quoteValue fixedpoint.Value
+	switch {
+	case quote == l.Currency:
+		quoteValue = quoteQuantity
+	case base == l.Currency:
+		quoteValue = trade.Quantity
+	default:
+		if price, ok := l.price(quote, t); ok {
+			quoteValue = quoteQuantity.Mul(price)
+		} else {
+			quoteValue = l.value(base, trade.Quantity, t)
+		}
+	}
+
+	if quoteValue.Sign() > 0 {
+		l.lastPrices[base] = quoteValue.Div(trade.Quantity)
+		if quoteQuantity.Sign() > 0 {
+			l.lastPrices[quote] = quoteValue.Div(quoteQuantity)
+		}
+	}
+
+	fee, feeCurrency := trade.Fee, trade.FeeCurrency
+	if fee.Sign() <= 0 || len(feeCurrency) == 0 {
+		fee, feeCurrency = fixedpoint.Zero, ""
+	}
+
+	feeValue := fixedpoint.Zero
+	switch feeCurrency {
+	case "":
+	case l.Currency:
+		feeValue = fee
+	case base:
+		feeValue = quoteValue.Mul(fee).Div(trade.Quantity)
+	case quote:
+		if quoteQuantity.Sign() > 0 {
+			feeValue = quoteValue.Mul(fee).Div(quoteQuantity)
+		}
+	default:
+		feeValue = l.value(feeCurrency, fee, t)
+	}
+
+	if trade.IsBuyer {
+		quantity, cost := trade.Quantity, quoteValue
+		switch feeCurrency {
+		case "":
+		case l.Currency:
+			cost = cost.Add(feeValue)
+		case base:
+			// the fee is deducted from the acquired quantity
+			quantity = quantity.Sub(fee)
+		default:
+			cost = cost.Add(feeValue)
+			l.dispose(feeCurrency, fee, feeValue, t, id, DisposalTypeFee, trade.Exchange)
+		}
+
+		l.dispose(quote, quoteQuantity, quoteValue, t, id, DisposalTypeTrade, trade.Exchange)
+		l.acquire(&Lot{
+			ID:         id,
+			Asset:      base,
+			Exchange:   trade.Exchange,
+			Source:     LotSourceTrade,
+			AcquiredAt: t,
+			Quantity:   quantity,
+			CostBasis:  cost,
+		})
+		return nil
+	}
+
+	proceeds, quoteReceived, quoteCost := quoteValue, quoteQuantity, quoteValue
+	switch feeCurrency {
+	case "":
+	case l.Currency:
+		proceeds = proceeds.Sub(feeValue)
+	case quote:
+		// the fee is deducted from the received quote quantity
+		proceeds = proceeds.Sub(feeValue)
+		quoteReceived = quoteReceived.Sub(fee)
+		quoteCost = quoteCost.Sub(feeValue)
+	default:
+		proceeds = proceeds.Sub(feeValue)
+		l.dispose(feeCurrency, fee, feeValue, t, id, DisposalTypeFee, trade.Exchange)
+	}
+
+	l.dispose(base, trade.Quantity, proceeds, t, id, DisposalTypeTrade, trade.Exchange)
+	l.acquire(&Lot{
+		ID:         id,
+		Asset:      quote,
+		Exchange:   trade.Exchange,
+		Source:     LotSourceTrade,
+		AcquiredAt: t,
+		Quantity:   quoteReceived,
+		CostBasis:  quoteCost,
+	})
+	return nil
+}
+
+// AddDeposit moves the lots in transit to the deposit exchange, the quantity that is not in transit is
+// an external deposit, it opens a new lot with the fair market value as the cost basis.
+func (l *Ledger) AddDeposit(deposit types.Deposit) {
+	switch deposit.Status {
+	case types.DepositRejected, types.DepositCancelled, types.DepositPending:
+		return
+	}
+
+	asset := deposit.Asset
+	if asset == l.Currency || deposit.Amount.Sign() <= 0 {
+		return
+	}
+
+	t := deposit.Time.Time()
+	remaining := deposit.Amount
+	for _, lot := range l.inTransit[asset] {
+		if remaining.Sign() <= 0 {
+			break
+		}
+
+		taken := lot.split(remaining)
+		remaining = remaining.Sub(taken.Quantity)
+		taken.Exchange = deposit.Exchange
+		l.acquire(&taken)
+	}
+
+	l.inTransit[asset] = compactLots(l.inTransit[asset])
+
+	if remaining.Sign() > 0 {
+		l.acquire(&Lot{
+			ID:         DepositID(deposit),
+			Asset:      asset,
+			Exchange:   deposit.Exchange,
+			Source:     LotSourceDeposit,
+			AcquiredAt: t,
+			Quantity:   remaining,
+			CostBasis:  l.value(asset, remaining, t),
+		})
+	}
+}
+
+// AddWithdraw moves the withdrawn lots to the transit pool, the withdrawal fee is disposed without proceeds
+func (l *Ledger) AddWithdraw(withdraw types.Withdraw) {
+	switch withdraw.Status {
+	case types.WithdrawStatusCancelled, types.WithdrawStatusRejected, types.WithdrawStatusFailed:
+		return
+	}
+
+	asset := withdraw.Asset
+	t := withdraw.ApplyTime.Time()
+	id := WithdrawID(withdraw)
+
+	if asset != l.Currency && withdraw.Amount.Sign() > 0 {
+		remaining := withdraw.Amount
+		for _, lot := range l.orderLots(asset, id) {
+			if remaining.Sign() <= 0 {
+				break
+			}
+
+			taken := lot.split(remaining)
+			remaining = remaining.Sub(taken.Quantity)
+			l.inTransit[asset] = append(l.inTransit[asset], &taken)
+		}
+
+		l.lots[asset] = compactLots(l.lots[asset])
+
+		if remaining.Sign() > 0 {
+			l.unmatched[asset] = l.unmatched[asset].Add(remaining)
+		}
+	}
+
+	if withdraw.TransactionFee.Sign() > 0 {
+		feeCurrency := withdraw.TransactionFeeCurrency
+		if len(feeCurrency) == 0 {
+			feeCurrency = asset
+		}
+
+		l.dispose(feeCurrency, withdraw.TransactionFee, fixedpoint.Zero, t, id, DisposalTypeWithdrawFee, withdraw.Exchange)
+	}
+}
+
+// AddReward opens a lot with the fair market value of the reward as the cost basis, the value is also recorded as an income
+func (l *Ledger) AddReward(reward types.Reward) {
+	if reward.Quantity.Sign() <= 0 {
+		return
+	}
+
+	t := reward.CreatedAt.Time()
+	value := l.value(reward.Currency, reward.Quantity, t)
+
+	l.incomes = append(l.incomes, Income{
+		ID:         RewardID(reward),
+		Asset:      reward.Currency,
+		Exchange:   reward.Exchange,
+		Type:       reward.Type,
+		ReceivedAt: t,
+		Quantity:   reward.Quantity,
+		Value:      value,
+	})
+
+	l.acquire(&Lot{
+		ID:         RewardID(reward),
+		Asset:      reward.Currency,
+		Exchange:   reward.Exchange,
+		Source:     LotSourceReward,
+		AcquiredAt: t,
+		Quantity:   reward.Quantity,
+		CostBasis:  value,
+	})
+}
+
+func (l *Ledger) acquire(lot *Lot) {
+	if lot.Asset == l.Currency || lot.Quantity.Sign() <= 0 {
+		return
+	}
+
+	if lot.CostBasis.Sign() < 0 {
+		lot.CostBasis = fixedpoint.Zero
+	}
+
+	l.lots[lot.Asset] = append(l.lots[lot.Asset], lot)
+}
+
+// orderLots returns the open lots of the asset in the disposal order
+func (l *Ledger) orderLots(asset, disposalID string) []*Lot {
+	lots := append([]*Lot(nil), l.lots[asset]...)
+	if l.Method != MethodSpecificID {
+		sortLots(lots, l.Method)
+		return lots
+	}
+
+	sortLots(lots, MethodFIFO)
+
+	var selected, rest []*Lot
+	selection := l.LotSelections[disposalID]
+	for _, lotID := range selection {
+		for _, lot := range lots {
+			if lot.ID == lotID {
+				selected = append(selected, lot)
+			}
+		}
+	}
+
+	for _, lot := range lots {
+		isSelected := false
+		for _, s := range selected {
+			isSelected = isSelected || s == lot
+		}
+
+		if !isSelected {
+			rest = append(rest, lot)
+		}
+	}
+
+	return append(selected, rest...)
+}
+
+func (l *Ledger) term(acquiredAt, disposedAt time.Time) Term {
+	period := l.LongTermPeriod
+	if period == 0 {
+		period = DefaultLongTermPeriod
+	}
+
+	if disposedAt.Sub(acquiredAt) > period {
+		return TermLong
+	}
+
+	return TermShort
+}
+
+// dispose disposes the quantity from the lots, the proceeds are allocated to the lots by the quantity
+func (l *Ledger) dispose(
+	asset string, quantity, proceeds fixedpoint.Value, t time.Time, id string, disposalType DisposalType,
+	exchange types.ExchangeName,
+) {
+	if asset == l.Currency || quantity.Sign() <= 0 {
+		return
+	}
+
+	var pieces []Lot
+	remaining := quantity
+	for _, lot := range l.orderLots(asset, id) {
+		if remaining.Sign() <= 0 {
+			break
+		}
+
+		taken := lot.split(remaining)
+		remaining = remaining.Sub(taken.Quantity)
+		pieces = append(pieces, taken)
+	}
+
+	l.lots[asset] = compactLots(l.lots[asset])
+
+	if remaining.Sign() > 0 {
+		// there is no lot for the quantity, the cost basis is zero
+		l.unmatched[asset] = l.unmatched[asset].Add(remaining)
+		pieces = append(pieces, Lot{
+			Asset:      asset,
+			AcquiredAt: t,
+			Quantity:   remaining,
+		})
+	}
+
+	allocated := fixedpoint.Zero
+	for i, piece := range pieces {
+		pieceProceeds := proceeds.Mul(piece.Quantity).Div(quantity)
+		if i == len(pieces)-1 {
+			pieceProceeds = proceeds.Sub(allocated)
+		}
+
+		allocated = allocated.Add(pieceProceeds)
+		l.disposals = append(l.disposals, Disposal{
+			ID:         id,
+			Type:       disposalType,
+			Asset:      asset,
+			Exchange:   exchange,
+			LotID:      piece.ID,
+			AcquiredAt: piece.AcquiredAt,
+			DisposedAt: t,
+			Quantity:   piece.Quantity,
+			Proceeds:   pieceProceeds,
+			CostBasis:  piece.CostBasis,
+			Gain:       pieceProceeds.Sub(piece.CostBasis),
+			Term:       l.term(piece.AcquiredAt, t),
+		})
+	}
+}
+
+func compactLots(lots []*Lot) []*Lot {
+	var open []*Lot
+	for _, lot := range lots {
+		if lot.Quantity.Sign() > 0 {
+			open = append(open, lot)
+		}
+	}
+
+	return open
+}
@@ -0,0 +1,315 @@
+package taxlot
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var number = fixedpoint.MustNewFromString
+
+var testMarkets = types.MarketMap{
+	"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+	"ETHBTC":  {Symbol: "ETHBTC", BaseCurrency: "ETH", QuoteCurrency: "BTC"},
+}
+
+var testStartTime = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func testTrade(id uint64, symbol string, isBuyer bool, price, quantity string, days int) types.Trade {
+	p, q := number(price), number(quantity)
+	return types.Trade{
+		ID:            id,
+		Exchange:      types.ExchangeBinance,
+		Symbol:        symbol,
+		IsBuyer:       isBuyer,
+		Price:         p,
+		Quantity:      q,
+		QuoteQuantity: p.Mul(q),
+		Time:          types.Time(testStartTime.AddDate(0, 0, days)),
+	}
+}
+
+func processTrades(t *testing.T, ledger *Ledger, trades ...types.Trade) {
+	for _, trade := range trades {
+		assert.NoError(t, ledger.Process(NewTradeEvent(trade)))
+	}
+}
+
+func TestLedger_Methods(t *testing.T) {
+	trades := []types.Trade{
+		testTrade(1, "BTCUSDT", true, "10000", "1", 0),
+		testTrade(2, "BTCUSDT", true, "30000", "1", 1),
+		testTrade(3, "BTCUSDT", true, "20000", "1", 2),
+		testTrade(4, "BTCUSDT", false, "25000", "1.5", 3),
+	}
+
+	tests := []struct {
+		method     Method
+		selections map[string][]string
+		lotIDs     []string
+		gain       string
+	}{
+		// 1 BTC of lot 1 (10000) and 0.5 BTC of lot 2 (15000)
+		{MethodFIFO, nil, []string{"binance:trade:1", "binance:trade:2"}, "12500"},
+		// 1 BTC of lot 3 (20000) and 0.5 BTC of lot 2 (15000)
+		{MethodLIFO, nil, []string{"binance:trade:3", "binance:trade:2"}, "2500"},
+		// 1 BTC of lot 2 (30000) and 0.5 BTC of lot 3 (10000)
+		{MethodHIFO, nil, []string{"binance:trade:2", "binance:trade:3"}, "-2500"},
+		// 1 BTC of lot 3 (20000) and 0.5 BTC of lot 1 (5000)
+		{MethodSpecificID, map[string][]string{"binance:trade:4": {"binance:trade:3"}}, []string{"binance:trade:3", "binance:trade:1"}, "12500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			ledger := NewLedger(tt.method, "USDT", testMarkets)
+			ledger.LotSelections = tt.selections
+			processTrades(t, ledger, trades...)
+
+			disposals := ledger.Disposals()
+			if !assert.Len(t, disposals, 2) {
+				return
+			}
+
+			gain := fixedpoint.Zero
+			proceeds := fixedpoint.Zero
+			for i, d := range disposals {
+				assert.Equal(t, tt.lotIDs[i], d.LotID)
+				assert.Equal(t, "binance:trade:4", d.ID)
+				assert.Equal(t, TermShort, d.Term)
+				gain = gain.Add(d.Gain)
+				proceeds = proceeds.Add(d.Proceeds)
+			}
+
+			assert.Equal(t, number(tt.gain), gain)
+			assert.Equal(t, number("37500"), proceeds)
+
+			remaining := fixedpoint.Zero
+			for _, lot := range ledger.Lots("BTC") {
+				remaining = remaining.Add(lot.Quantity)
+			}
+			assert.Equal(t, number("1.5"), remaining)
+			assert.Empty(t, ledger.Unmatched())
+		})
+	}
+}
+
+func TestLedger_Fees(t *testing.T) {
+	ledger := NewLedger(MethodFIFO, "USDT", testMarkets)
+	ledger.PriceFunc = func(asset string, t time.Time) (fixedpoint.Value, bool) {
+		if asset == "BNB" {
+			return number("300"), true
+		}
+
+		return fixedpoint.Zero, false
+	}
+
+	// the BNB fee is added to the cost basis
+	buy := testTrade(1, "BTCUSDT", true, "10000", "1", 0)
+	buy.Fee, buy.FeeCurrency = number("0.01"), "BNB"
+
+	// the base fee is deducted from the acquired quantity
+	buy2 := testTrade(2, "BTCUSDT", true, "10000", "1", 1)
+	buy2.Fee, buy2.FeeCurrency = number("0.001"), "BTC"
+
+	// the quote fee is deducted from the proceeds
+	sell := testTrade(3, "BTCUSDT", false, "20000", "1", 2)
+	sell.Fee, sell.FeeCurrency = number("20"), "USDT"
+
+	processTrades(t, ledger, buy, buy2, sell)
+
+	lots := ledger.Lots("BTC")
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, number("0.999"), lots[0].Quantity)
+		assert.Equal(t, number("10000"), lots[0].CostBasis)
+	}
+
+	disposals := ledger.Disposals()
+	if assert.Len(t, disposals, 2) {
+		// the BNB fee has no lot
+		assert.Equal(t, DisposalTypeFee, disposals[0].Type)
+		assert.Equal(t, "BNB", disposals[0].Asset)
+		assert.Equal(t, number("3"), disposals[0].Proceeds)
+		assert.Equal(t, "", disposals[0].LotID)
+
+		assert.Equal(t, DisposalTypeTrade, disposals[1].Type)
+		assert.Equal(t, number("19980"), disposals[1].Proceeds)
+		assert.Equal(t, number("10003"), disposals[1].CostBasis)
+		assert.Equal(t, number("9977"), disposals[1].Gain)
+	}
+
+	assert.Equal(t, number("0.01"), ledger.Unmatched()["BNB"])
+}
+
+func TestLedger_CryptoToCrypto(t *testing.T) {
+	ledger := NewLedger(MethodFIFO, "USDT", testMarkets)
+	processTrades(t, ledger,
+		testTrade(1, "BTCUSDT", true, "10000", "1", 0),
+		testTrade(2, "BTCUSDT", true, "20000", "1", 1),
+		// BTC is disposed at the last price 20000, ETH is acquired with the value of the disposed BTC
+		testTrade(3, "ETHBTC", true, "0.05", "10", 2),
+	)
+
+	disposals := ledger.Disposals()
+	if assert.Len(t, disposals, 1) {
+		assert.Equal(t, "BTC", disposals[0].Asset)
+		assert.Equal(t, number("0.5"), disposals[0].Quantity)
+		assert.Equal(t, number("10000"), disposals[0].Proceeds)
+		assert.Equal(t, number("5000"), disposals[0].CostBasis)
+	}
+
+	lots := ledger.Lots("ETH")
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, number("10"), lots[0].Quantity)
+		assert.Equal(t, number("10000"), lots[0].CostBasis)
+	}
+}
+
+func TestLedger_Transfer(t *testing.T) {
+	ledger := NewLedger(MethodFIFO, "USDT", testMarkets)
+	processTrades(t, ledger, testTrade(1, "BTCUSDT", true, "10000", "1", 0))
+
+	events := []Event{
+		NewDepositEvent(types.Deposit{
+			Exchange:      types.ExchangeMax,
+			Asset:         "BTC",
+			Amount:        number("0.5"),
+			TransactionID: "tx1",
+			Time:          types.Time(testStartTime.AddDate(0, 0, 10)),
+		}),
+		NewWithdrawEvent(types.Withdraw{
+			Exchange:       types.ExchangeBinance,
+			Asset:          "BTC",
+			Amount:         number("0.5"),
+			TransactionID:  "tx1",
+			TransactionFee: number("0.001"),
+			ApplyTime:      types.Time(testStartTime.AddDate(0, 0, 10)),
+		}),
+	}
+
+	SortEvents(events)
+	for _, event := range events {
+		assert.NoError(t, ledger.Process(event))
+	}
+
+	lots := ledger.Lots("BTC")
+	if assert.Len(t, lots, 2) {
+		assert.Equal(t, types.ExchangeBinance, lots[0].Exchange)
+		assert.Equal(t, number("0.499"), lots[0].Quantity)
+
+		// the deposited lot keeps the acquisition time and the cost basis
+		assert.Equal(t, types.ExchangeMax, lots[1].Exchange)
+		assert.Equal(t, number("0.5"), lots[1].Quantity)
+		assert.Equal(t, number("5000"), lots[1].CostBasis)
+		assert.Equal(t, testStartTime, lots[1].AcquiredAt)
+	}
+
+	assert.Empty(t, ledger.InTransit("BTC"))
+
+	disposals := ledger.Disposals()
+	if assert.Len(t, disposals, 1) {
+		assert.Equal(t, DisposalTypeWithdrawFee, disposals[0].Type)
+		assert.Equal(t, number("0.001"), disposals[0].Quantity)
+		assert.Equal(t, number("-10"), disposals[0].Gain)
+	}
+}
+
+func TestLedger_RewardAndExternalDeposit(t *testing.T) {
+	ledger := NewLedger(MethodFIFO, "USDT", testMarkets)
+	ledger.PriceFunc = func(asset string, t time.Time) (fixedpoint.Value, bool) {
+		switch asset {
+		case "MAX":
+			return number("0.5"), true
+		case "BTC":
+			return number("20000"), true
+		}
+
+		return fixedpoint.Zero, false
+	}
+
+	assert.NoError(t, ledger.Process(NewRewardEvent(types.Reward{
+		UUID:      "r1",
+		Exchange:  types.ExchangeMax,
+		Type:      types.RewardCommission,
+		Currency:  "MAX",
+		Quantity:  number("100"),
+		CreatedAt: types.Time(testStartTime),
+	})))
+
+	assert.NoError(t, ledger.Process(NewDepositEvent(types.Deposit{
+		Exchange:      types.ExchangeMax,
+		Asset:         "BTC",
+		Amount:        number("0.1"),
+		TransactionID: "tx2",
+		Time:          types.Time(testStartTime),
+	})))
+
+	incomes := ledger.Incomes()
+	if assert.Len(t, incomes, 1) {
+		assert.Equal(t, number("50"), incomes[0].Value)
+	}
+
+	lots := ledger.Lots("MAX")
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, LotSourceReward, lots[0].Source)
+		assert.Equal(t, number("50"), lots[0].CostBasis)
+	}
+
+	lots = ledger.Lots("BTC")
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, LotSourceDeposit, lots[0].Source)
+		assert.Equal(t, "max:deposit:tx2", lots[0].ID)
+		assert.Equal(t, number("2000"), lots[0].CostBasis)
+	}
+}
+
+func TestReport(t *testing.T) {
+	ledger := NewLedger(MethodFIFO, "USDT", testMarkets)
+	processTrades(t, ledger,
+		testTrade(1, "BTCUSDT", true, "10000", "1", 0),
+		testTrade(2, "BTCUSDT", true, "20000", "1", 200),
+		// 2023-06-01, lot 1 is held more than one year
+		testTrade(3, "BTCUSDT", false, "30000", "1.5", 516),
+		testTrade(4, "BTCUSDT", false, "30000", "0.1", 800),
+	)
+
+	report := NewReport(ledger, 2023)
+	if !assert.Len(t, report.Disposals, 2) {
+		return
+	}
+
+	assert.Equal(t, TermLong, report.Disposals[0].Term)
+	assert.Equal(t, TermShort, report.Disposals[1].Term)
+	assert.Equal(t, number("20000"), report.LongTermGain)
+	assert.Equal(t, number("5000"), report.ShortTermGain)
+	assert.Equal(t, number("45000"), report.TotalProceeds)
+	assert.Equal(t, number("20000"), report.TotalCostBasis)
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if assert.NoError(t, err) && assert.Len(t, records, 3) {
+		assert.Equal(t, disposalCsvHeader, records[0])
+		assert.Equal(t, "long", records[1][5])
+		assert.Equal(t, "binance:trade:1", records[1][10])
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	method, err := ParseMethod("HIFO")
+	assert.NoError(t, err)
+	assert.Equal(t, MethodHIFO, method)
+
+	method, err = ParseMethod("specific-id")
+	assert.NoError(t, err)
+	assert.Equal(t, MethodSpecificID, method)
+
+	_, err = ParseMethod("average")
+	assert.Error(t, err)
+}
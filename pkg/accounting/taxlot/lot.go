@@ -0,0 +1,158 @@
+// Package taxlot tracks the tax lots of the assets across the exchange sessions,
+// and calculates the realized gains of the disposals with the FIFO, LIFO, HIFO or specific-ID lot selection.
+package taxlot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type Method string
+
+const (
+	// MethodFIFO disposes the earliest acquired lots first
+	MethodFIFO Method = "fifo"
+
+	// MethodLIFO disposes the latest acquired lots first
+	MethodLIFO Method = "lifo"
+
+	// MethodHIFO disposes the lots with the highest unit cost first
+	MethodHIFO Method = "hifo"
+
+	// MethodSpecificID disposes the lots specified by the lot selections, the remaining quantity is disposed with FIFO
+	MethodSpecificID Method = "specific"
+)
+
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(strings.ToLower(s)); m {
+	case MethodFIFO, MethodLIFO, MethodHIFO, MethodSpecificID:
+		return m, nil
+	case "specific-id", "specificid":
+		return MethodSpecificID, nil
+	}
+
+	return "", fmt.Errorf("unknown tax lot method: %q, valid methods are: fifo, lifo, hifo, specific", s)
+}
+
+type LotSource string
+
+const (
+	LotSourceTrade   LotSource = "trade"
+	LotSourceDeposit LotSource = "deposit"
+	LotSourceReward  LotSource = "reward"
+)
+
+// Lot is an acquisition of the asset, the cost basis is valued in the reporting currency
+type Lot struct {
+	ID         string             `json:"id"`
+	Asset      string             `json:"asset"`
+	Exchange   types.ExchangeName `json:"exchange"`
+	Source     LotSource          `json:"source"`
+	AcquiredAt time.Time          `json:"acquiredAt"`
+
+	// Quantity is the remaining quantity of the lot
+	Quantity fixedpoint.Value `json:"quantity"`
+
+	// CostBasis is the cost basis of the remaining quantity
+	CostBasis fixedpoint.Value `json:"costBasis"`
+}
+
+func (l *Lot) UnitCost() fixedpoint.Value {
+	if l.Quantity.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	return l.CostBasis.Div(l.Quantity)
+}
+
+// split takes the quantity from the lot, and returns the taken part with the proportional cost basis
+func (l *Lot) split(quantity fixedpoint.Value) Lot {
+	q := fixedpoint.Min(quantity, l.Quantity)
+	cost := l.CostBasis
+	if q.Compare(l.Quantity) < 0 {
+		cost = l.CostBasis.Mul(q).Div(l.Quantity)
+	}
+
+	taken := *l
+	taken.Quantity = q
+	taken.CostBasis = cost
+
+	l.Quantity = l.Quantity.Sub(q)
+	l.CostBasis = l.CostBasis.Sub(cost)
+	return taken
+}
+
+type Term string
+
+const (
+	TermShort Term = "short"
+	TermLong  Term = "long"
+)
+
+type DisposalType string
+
+const (
+	// DisposalTypeTrade is the asset sold or spent in a trade
+	DisposalTypeTrade DisposalType = "trade"
+
+	// DisposalTypeFee is the asset spent as the trading fee
+	DisposalTypeFee DisposalType = "fee"
+
+	// DisposalTypeWithdrawFee is the asset spent as the withdrawal fee, there is no proceeds
+	DisposalTypeWithdrawFee DisposalType = "withdrawFee"
+)
+
+// Disposal is the realized gain of a disposed lot, a disposal event can dispose multiple lots
+type Disposal struct {
+	ID       string             `json:"id"`
+	Type     DisposalType       `json:"type"`
+	Asset    string             `json:"asset"`
+	Exchange types.ExchangeName `json:"exchange"`
+
+	// LotID is empty if there is no lot for the disposed quantity, the cost basis is zero in this case
+	LotID      string    `json:"lotID"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	DisposedAt time.Time `json:"disposedAt"`
+
+	Quantity  fixedpoint.Value `json:"quantity"`
+	Proceeds  fixedpoint.Value `json:"proceeds"`
+	CostBasis fixedpoint.Value `json:"costBasis"`
+	Gain      fixedpoint.Value `json:"gain"`
+	Term      Term             `json:"term"`
+}
+
+// Income is the fair market value of the received rewards
+type Income struct {
+	ID         string             `json:"id"`
+	Asset      string             `json:"asset"`
+	Exchange   types.ExchangeName `json:"exchange"`
+	Type       types.RewardType   `json:"type"`
+	ReceivedAt time.Time          `json:"receivedAt"`
+	Quantity   fixedpoint.Value   `json:"quantity"`
+	Value      fixedpoint.Value   `json:"value"`
+}
+
+// sortLots sorts the lots in the disposal order of the method
+func sortLots(lots []*Lot, method Method) {
+	switch method {
+	case MethodLIFO:
+		sort.SliceStable(lots, func(i, j int) bool {
+			return lots[i].AcquiredAt.After(lots[j].AcquiredAt)
+		})
+
+	case MethodHIFO:
+		sort.SliceStable(lots, func(i, j int) bool {
+			return lots[i].UnitCost().Compare(lots[j].UnitCost()) > 0
+		})
+
+	default:
+		sort.SliceStable(lots, func(i, j int) bool {
+			return lots[i].AcquiredAt.Before(lots[j].AcquiredAt)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package taxlot
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// Report is the realized gains and the incomes of a tax year
+type Report struct {
+	Year     int    `json:"year"`
+	Currency string `json:"currency"`
+	Method   Method `json:"method"`
+
+	Disposals []Disposal `json:"disposals"`
+	Incomes   []Income   `json:"incomes"`
+
+	ShortTermGain  fixedpoint.Value `json:"shortTermGain"`
+	LongTermGain   fixedpoint.Value `json:"longTermGain"`
+	TotalProceeds  fixedpoint.Value `json:"totalProceeds"`
+	TotalCostBasis fixedpoint.Value `json:"totalCostBasis"`
+	Income         fixedpoint.Value `json:"income"`
+}
+
+// NewReport collects the disposals and the incomes of the year from the ledger, the year is in the local time zone
+func NewReport(ledger *Ledger, year int) *Report {
+	report := &Report{
+		Year:     year,
+		Currency: ledger.Currency,
+		Method:   ledger.Method,
+	}
+
+	inYear := func(t time.Time) bool {
+		return t.Local().Year() == year
+	}
+
+	for _, disposal := range ledger.Disposals() {
+		if !inYear(disposal.DisposedAt) {
+			continue
+		}
+
+		report.Disposals = append(report.Disposals, disposal)
+		report.TotalProceeds = report.TotalProceeds.Add(disposal.Proceeds)
+		report.TotalCostBasis = report.TotalCostBasis.Add(disposal.CostBasis)
+		if disposal.Term == TermLong {
+			report.LongTermGain = report.LongTermGain.Add(disposal.Gain)
+		} else {
+			report.ShortTermGain = report.ShortTermGain.Add(disposal.Gain)
+		}
+	}
+
+	for _, income := range ledger.Incomes() {
+		if !inYear(income.ReceivedAt) {
+			continue
+		}
+
+		report.Incomes = append(report.Incomes, income)
+		report.Income = report.Income.Add(income.Value)
+	}
+
+	return report
+}
+
+var disposalCsvHeader = []string{
+	"disposed_at", "acquired_at", "asset", "exchange", "type", "term",
+	"quantity", "proceeds", "cost_basis", "gain", "lot_id", "disposal_id",
+}
+
+// WriteCSV writes the realized gains of the disposals in CSV
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(disposalCsvHeader); err != nil {
+		return err
+	}
+
+	for _, d := range r.Disposals {
+		if err := writer.Write([]string{
+			d.DisposedAt.Format(time.RFC3339),
+			d.AcquiredAt.Format(time.RFC3339),
+			d.Asset,
+			string(d.Exchange),
+			string(d.Type),
+			string(d.Term),
+			d.Quantity.String(),
+			d.Proceeds.String(),
+			d.CostBasis.String(),
+			d.Gain.String(),
+			d.LotID,
+			d.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+var incomeCsvHeader = []string{
+	"received_at", "asset", "exchange", "type", "quantity", "value", "income_id",
+}
+
+// WriteIncomeCSV writes the reward incomes in CSV
+func (r *Report) WriteIncomeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(incomeCsvHeader); err != nil {
+		return err
+	}
+
+	for _, income := range r.Incomes {
+		if err := writer.Write([]string{
+			income.ReceivedAt.Format(time.RFC3339),
+			income.Asset,
+			string(income.Exchange),
+			string(income.Type),
+			income.Quantity.String(),
+			income.Value.String(),
+			income.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,35 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// aggTradeFeed replays the aggregated trades of a symbol from the database as the market trades
+type aggTradeFeed struct {
+	C <-chan types.AggTrade
+
+	next *types.AggTrade
+}
+
+// emitUntil emits the trades with the time before or equal to the given time in the time order
+func (f *aggTradeFeed) emitUntil(until time.Time, emit func(trade types.Trade)) {
+	for {
+		if f.next == nil {
+			trade, ok := <-f.C
+			if !ok {
+				return
+			}
+
+			f.next = &trade
+		}
+
+		if f.next.Time.After(until) {
+			return
+		}
+
+		emit(f.next.Trade())
+		f.next = nil
+	}
+}
@@ -0,0 +1,109 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestAggTradeFeed_emitUntil(t *testing.T) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c := make(chan types.AggTrade, 3)
+	for i, offset := range []time.Duration{10 * time.Second, time.Minute - time.Millisecond, time.Minute + time.Second} {
+		c <- types.AggTrade{
+			Exchange:     types.ExchangeBinance,
+			Symbol:       "BTCUSDT",
+			ID:           uint64(i + 1),
+			Price:        fixedpoint.NewFromInt(20000),
+			Quantity:     fixedpoint.NewFromFloat(0.1),
+			IsBuyerMaker: i == 0,
+			Time:         types.Time(startTime.Add(offset)),
+		}
+	}
+	close(c)
+
+	feed := &aggTradeFeed{C: c}
+
+	var trades []types.Trade
+	emit := func(trade types.Trade) {
+		trades = append(trades, trade)
+	}
+
+	feed.emitUntil(startTime.Add(time.Minute-time.Millisecond), emit)
+	if assert.Len(t, trades, 2) {
+		assert.Equal(t, types.SideTypeSell, trades[0].Side)
+		assert.Equal(t, types.SideTypeBuy, trades[1].Side)
+		assert.Equal(t, "2000", trades[1].QuoteQuantity.String())
+	}
+
+	feed.emitUntil(startTime.Add(2*time.Minute-time.Millisecond), emit)
+	assert.Len(t, trades, 3)
+
+	// no more trades
+	feed.emitUntil(startTime.Add(time.Hour), emit)
+	assert.Len(t, trades, 3)
+}
+
+func TestExchange_ConsumeKLine_TradeKLines(t *testing.T) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c := make(chan types.AggTrade, 3)
+	for i, offset := range []time.Duration{10 * time.Second, 90 * time.Second, 130 * time.Second} {
+		c <- types.AggTrade{
+			Exchange: types.ExchangeBinance,
+			Symbol:   "BTCUSDT",
+			ID:       uint64(i + 1),
+			Price:    fixedpoint.NewFromInt(20000 + int64(i)*100),
+			Quantity: fixedpoint.NewFromFloat(0.1),
+			Time:     types.Time(startTime.Add(offset)),
+		}
+	}
+	close(c)
+
+	e := &Exchange{
+		MarketDataStream: &types.StandardStream{},
+		matchingBooks: map[string]*SimplePriceMatching{
+			"BTCUSDT": {
+				account:      getTestAccount(),
+				Market:       getTestMarket(),
+				closedOrders: make(map[uint64]types.Order),
+			},
+		},
+		aggTradeFeeds: map[string]*aggTradeFeed{"BTCUSDT": {C: c}},
+		Src:           &ExchangeDataSource{},
+	}
+	e.addTradeKLineAggregator("BTCUSDT", types.Interval("2m"))
+
+	var trades int
+	var kLines []types.KLine
+	e.MarketDataStream.OnMarketTrade(func(trade types.Trade) { trades++ })
+	e.MarketDataStream.OnKLineClosed(func(k types.KLine) {
+		if k.Interval == types.Interval("2m") {
+			kLines = append(kLines, k)
+		}
+	})
+
+	for i := 0; i < 3; i++ {
+		e.ConsumeKLine(newKLine("BTCUSDT", types.Interval1m, startTime.Add(time.Duration(i)*time.Minute), 20000, 20100, 19900, 20000), types.Interval1m)
+	}
+
+	// the 2m kline is closed by the 1m kline reaching its end, before the trade of the next period is emitted
+	assert.Equal(t, 2, trades)
+	if assert.Len(t, kLines, 1) {
+		k := kLines[0]
+		assert.Equal(t, startTime, k.StartTime.Time())
+		assert.Equal(t, "20000", k.Open.String())
+		assert.Equal(t, "20100", k.Close.String())
+		assert.Equal(t, "0.2", k.Volume.String())
+		assert.Equal(t, uint64(2), k.NumberOfTrades)
+	}
+
+	e.ConsumeKLine(newKLine("BTCUSDT", types.Interval1m, startTime.Add(3*time.Minute), 20000, 20100, 19900, 20000), types.Interval1m)
+	assert.Equal(t, 3, trades)
+	assert.Len(t, kLines, 1)
+}
@@ -0,0 +1,279 @@
+package backtest
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// periodsPerYear is used to annualize the daily returns, the crypto markets trade every day
+const periodsPerYear = 365.0
+
+// BenchmarkReport is the back-test performance relative to a benchmark,
+// the ratios are calculated from the daily returns of the total equity curve and the benchmark curve.
+type BenchmarkReport struct {
+	Name    string                     `json:"name"`
+	Type    bbgo.BacktestBenchmarkType `json:"type"`
+	Symbols []string                   `json:"symbols"`
+
+	// Return is the total return of the benchmark
+	Return fixedpoint.Value `json:"return"`
+
+	// ExcessReturn is the total return of the back-test minus the total return of the benchmark
+	ExcessReturn fixedpoint.Value `json:"excessReturn"`
+
+	// Alpha is the annualized return that is not explained by the benchmark
+	Alpha fixedpoint.Value `json:"alpha"`
+
+	// Beta is the sensitivity of the daily returns to the benchmark daily returns
+	Beta fixedpoint.Value `json:"beta"`
+
+	// InformationRatio is the annualized excess return divided by the tracking error
+	InformationRatio fixedpoint.Value `json:"informationRatio"`
+
+	// TrackingError is the annualized standard deviation of the daily excess returns
+	TrackingError fixedpoint.Value `json:"trackingError"`
+}
+
+func (r *BenchmarkReport) Print() {
+	color.Green("BENCHMARK %s %v", r.Name, r.Symbols)
+	color.Green("  RETURN: %s", r.Return.FormatPercentage(2))
+
+	if r.ExcessReturn.Sign() >= 0 {
+		color.Green("  EXCESS RETURN: %s", r.ExcessReturn.FormatPercentage(2))
+	} else {
+		color.Red("  EXCESS RETURN: %s", r.ExcessReturn.FormatPercentage(2))
+	}
+
+	if r.Alpha.Sign() >= 0 {
+		color.Green("  ALPHA: %s", r.Alpha.FormatString(4))
+	} else {
+		color.Red("  ALPHA: %s", r.Alpha.FormatString(4))
+	}
+
+	color.Green("  BETA: %s", r.Beta.FormatString(4))
+	color.Green("  INFORMATION RATIO: %s", r.InformationRatio.FormatString(4))
+	color.Green("  TRACKING ERROR: %s", r.TrackingError.FormatString(4))
+}
+
+// Benchmark tracks the value of an equal-weight buy-and-hold portfolio of the benchmark symbols,
+// the value starts from 1.0 at the open price of the first kline of each symbol.
+type Benchmark struct {
+	Name    string
+	Type    bbgo.BacktestBenchmarkType
+	Symbols []string
+
+	startPrices map[string]fixedpoint.Value
+	lastPrices  map[string]fixedpoint.Value
+	recorder    *EquityRecorder
+
+	mu sync.Mutex
+}
+
+func NewBenchmark(config bbgo.BacktestBenchmark, backtestSymbols []string) (*Benchmark, error) {
+	symbols, err := config.GetSymbols(backtestSymbols)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := config.Type
+	if len(typ) == 0 {
+		typ = bbgo.BacktestBenchmarkTypeBuyAndHold
+	}
+
+	return &Benchmark{
+		Name:        config.GetName(symbols),
+		Type:        typ,
+		Symbols:     symbols,
+		startPrices: make(map[string]fixedpoint.Value),
+		lastPrices:  make(map[string]fixedpoint.Value),
+		recorder:    NewEquityRecorder(),
+	}, nil
+}
+
+// Update updates the benchmark value with the closed kline,
+// the value is recorded after all the benchmark symbols have received the first kline.
+func (b *Benchmark) Update(k types.KLine) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.hasSymbol(k.Symbol) {
+		return
+	}
+
+	if _, ok := b.startPrices[k.Symbol]; !ok {
+		if k.Open.Sign() <= 0 {
+			return
+		}
+
+		b.startPrices[k.Symbol] = k.Open
+	}
+
+	b.lastPrices[k.Symbol] = k.Close
+
+	if len(b.startPrices) < len(b.Symbols) {
+		return
+	}
+
+	b.recorder.Record(k.EndTime.Time(), b.value(), false)
+}
+
+func (b *Benchmark) hasSymbol(symbol string) bool {
+	for _, s := range b.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *Benchmark) value() fixedpoint.Value {
+	sum := fixedpoint.Zero
+	for symbol, startPrice := range b.startPrices {
+		sum = sum.Add(b.lastPrices[symbol].Div(startPrice))
+	}
+
+	return sum.Div(fixedpoint.NewFromInt(int64(len(b.startPrices))))
+}
+
+// Curve returns the recorded benchmark values
+func (b *Benchmark) Curve() []EquityPoint {
+	return b.recorder.EquityCurve()
+}
+
+// Compare calculates the performance of the equity curve relative to the benchmark,
+// only the days that both curves have the values are compared.
+func (b *Benchmark) Compare(equityCurve []EquityPoint) BenchmarkReport {
+	report := BenchmarkReport{
+		Name:    b.Name,
+		Type:    b.Type,
+		Symbols: b.Symbols,
+	}
+
+	portfolio, benchmark := alignDaily(equityCurve, b.Curve())
+	if len(portfolio) < 2 {
+		return report
+	}
+
+	first, last := 0, len(portfolio)-1
+	if portfolio[first] <= 0 || benchmark[first] <= 0 {
+		return report
+	}
+
+	portfolioReturn := portfolio[last]/portfolio[first] - 1.0
+	benchmarkReturn := benchmark[last]/benchmark[first] - 1.0
+	report.Return = fixedpoint.NewFromFloat(benchmarkReturn)
+	report.ExcessReturn = fixedpoint.NewFromFloat(portfolioReturn - benchmarkReturn)
+
+	rp, rb := returns(portfolio), returns(benchmark)
+	if len(rp) < 2 {
+		return report
+	}
+
+	excess := make([]float64, len(rp))
+	for i := range rp {
+		excess[i] = rp[i] - rb[i]
+	}
+
+	beta := 0.0
+	if v := variance(rb); v > 0 {
+		beta = covariance(rp, rb) / v
+	}
+
+	alpha := (mean(rp) - beta*mean(rb)) * periodsPerYear
+	trackingError := math.Sqrt(variance(excess)) * math.Sqrt(periodsPerYear)
+	informationRatio := 0.0
+	if trackingError > 0 {
+		informationRatio = mean(excess) * periodsPerYear / trackingError
+	}
+
+	report.Alpha = fixedpoint.NewFromFloat(alpha)
+	report.Beta = fixedpoint.NewFromFloat(beta)
+	report.TrackingError = fixedpoint.NewFromFloat(trackingError)
+	report.InformationRatio = fixedpoint.NewFromFloat(informationRatio)
+	return report
+}
+
+// alignDaily resamples both curves to the last values of each day, and returns the values of the common days
+func alignDaily(a, b []EquityPoint) (av, bv []float64) {
+	const day = 24 * time.Hour
+
+	bDaily := make(map[time.Time]float64)
+	for _, p := range b {
+		bDaily[p.Time.Truncate(day)] = p.Equity.Float64()
+	}
+
+	var days []time.Time
+	aDaily := make(map[time.Time]float64)
+	for _, p := range a {
+		d := p.Time.Truncate(day)
+		if _, ok := aDaily[d]; !ok {
+			days = append(days, d)
+		}
+
+		aDaily[d] = p.Equity.Float64()
+	}
+
+	for _, d := range days {
+		if v, ok := bDaily[d]; ok {
+			av = append(av, aDaily[d])
+			bv = append(bv, v)
+		}
+	}
+
+	return av, bv
+}
+
+func returns(values []float64) []float64 {
+	rs := make([]float64, 0, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		if values[i-1] == 0 {
+			rs = append(rs, 0)
+			continue
+		}
+
+		rs = append(rs, values[i]/values[i-1]-1.0)
+	}
+
+	return rs
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+// covariance returns the sample covariance
+func covariance(a, b []float64) float64 {
+	if len(a) < 2 {
+		return 0
+	}
+
+	ma, mb := mean(a), mean(b)
+	sum := 0.0
+	for i := range a {
+		sum += (a[i] - ma) * (b[i] - mb)
+	}
+
+	return sum / float64(len(a)-1)
+}
+
+// variance returns the sample variance
+func variance(values []float64) float64 {
+	return covariance(values, values)
+}
@@ -0,0 +1,136 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestNewBenchmark(t *testing.T) {
+	b, err := NewBenchmark(bbgo.BacktestBenchmark{}, []string{"BTCUSDT"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, bbgo.BacktestBenchmarkTypeBuyAndHold, b.Type)
+		assert.Equal(t, []string{"BTCUSDT"}, b.Symbols)
+		assert.Equal(t, "buyAndHold:BTCUSDT", b.Name)
+	}
+
+	b, err = NewBenchmark(bbgo.BacktestBenchmark{Name: "majors", Type: bbgo.BacktestBenchmarkTypeBasket, Symbols: []string{"BTCUSDT", "ETHUSDT"}}, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "majors", b.Name)
+		assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, b.Symbols)
+	}
+
+	_, err = NewBenchmark(bbgo.BacktestBenchmark{Type: bbgo.BacktestBenchmarkTypeSymbol}, nil)
+	assert.Error(t, err)
+
+	_, err = NewBenchmark(bbgo.BacktestBenchmark{Type: "index"}, nil)
+	assert.Error(t, err)
+}
+
+func TestBenchmark_Update(t *testing.T) {
+	b, err := NewBenchmark(bbgo.BacktestBenchmark{Type: bbgo.BacktestBenchmarkTypeBasket, Symbols: []string{"BTCUSDT", "ETHUSDT"}}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	kline := func(symbol string, i int, open, close float64) types.KLine {
+		return types.KLine{
+			Symbol:   symbol,
+			Interval: types.Interval1h,
+			EndTime:  types.Time(startTime.Add(time.Duration(i) * time.Hour)),
+			Open:     fixedpoint.NewFromFloat(open),
+			Close:    fixedpoint.NewFromFloat(close),
+			Closed:   true,
+		}
+	}
+
+	b.Update(kline("BTCUSDT", 1, 100, 110))
+	b.Update(kline("LTCUSDT", 1, 10, 20))
+	assert.Empty(t, b.Curve(), "the value is recorded after all the symbols are started")
+
+	b.Update(kline("ETHUSDT", 1, 10, 9))
+	b.Update(kline("BTCUSDT", 2, 110, 120))
+	b.Update(kline("ETHUSDT", 2, 9, 12))
+
+	curve := b.Curve()
+	if assert.Len(t, curve, 2) {
+		// (110/100 + 9/10) / 2
+		assert.Equal(t, "1", curve[0].Equity.String())
+		// (120/100 + 12/10) / 2
+		assert.Equal(t, "1.2", curve[1].Equity.String())
+	}
+}
+
+func TestBenchmark_Compare(t *testing.T) {
+	b, err := NewBenchmark(bbgo.BacktestBenchmark{Type: bbgo.BacktestBenchmarkTypeSymbol, Symbol: "BTCUSDT"}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := []float64{100, 102, 99, 103, 101, 104}
+
+	var equityCurve []EquityPoint
+	for i, price := range prices {
+		open := prices[0]
+		if i > 0 {
+			open = prices[i-1]
+		}
+
+		endTime := startTime.Add(time.Duration(i) * 24 * time.Hour)
+		b.Update(types.KLine{
+			Symbol:   "BTCUSDT",
+			Interval: types.Interval1h,
+			EndTime:  types.Time(endTime),
+			Open:     fixedpoint.NewFromFloat(open),
+			Close:    fixedpoint.NewFromFloat(price),
+			Closed:   true,
+		})
+
+		// the portfolio holds 2x of the benchmark
+		equityCurve = append(equityCurve, EquityPoint{
+			Time:   endTime,
+			Equity: fixedpoint.NewFromFloat(2 * price),
+		})
+	}
+
+	report := b.Compare(equityCurve)
+	assert.Equal(t, "symbol:BTCUSDT", report.Name)
+	assert.InDelta(t, 0.04, report.Return.Float64(), 1e-6)
+	assert.InDelta(t, 0.0, report.ExcessReturn.Float64(), 1e-6)
+	assert.InDelta(t, 1.0, report.Beta.Float64(), 1e-6)
+	assert.InDelta(t, 0.0, report.Alpha.Float64(), 1e-6)
+	assert.InDelta(t, 0.0, report.TrackingError.Float64(), 1e-6)
+	assert.InDelta(t, 0.0, report.InformationRatio.Float64(), 1e-6)
+
+	// the portfolio has a constant excess return of 0.1% per day
+	equityCurve = equityCurve[:0]
+	equity := 1000.0
+	for i := range prices {
+		if i > 0 {
+			equity *= prices[i] / prices[i-1] * 1.001
+		}
+
+		equityCurve = append(equityCurve, EquityPoint{
+			Time:   startTime.Add(time.Duration(i) * 24 * time.Hour),
+			Equity: fixedpoint.NewFromFloat(equity),
+		})
+	}
+
+	report = b.Compare(equityCurve)
+	assert.True(t, report.ExcessReturn.Sign() > 0)
+	assert.True(t, report.Alpha.Sign() > 0)
+	assert.InDelta(t, 1.0, report.Beta.Float64(), 0.01)
+	assert.True(t, report.InformationRatio.Sign() > 0)
+
+	// no overlapped days
+	report = b.Compare([]EquityPoint{{Time: startTime.AddDate(1, 0, 0), Equity: fixedpoint.NewFromFloat(1000)}})
+	assert.True(t, report.Alpha.IsZero())
+	assert.True(t, report.Return.IsZero())
+}
@@ -74,6 +74,15 @@ type Exchange struct {
 
 	markets types.MarketMap
 
+	// kLineAggregators build the subscribed kline intervals that are not provided by the source exchange
+	kLineAggregators []*types.KLineAggregator
+
+	// tradeKLineAggregators build the klines of the trade source subscriptions from the replayed market trades
+	tradeKLineAggregators []*types.KLineAggregator
+
+	// aggTradeFeeds replay the imported aggregated trades of the symbols that subscribe the market trade channel
+	aggTradeFeeds map[string]*aggTradeFeed
+
 	Src *ExchangeDataSource
 }
 
@@ -351,13 +360,44 @@ func (e *Exchange) SubscribeMarketData(
 		loadedIntervals[it] = struct{}{}
 	}
 
+	supportedIntervals := types.SupportedIntervals
+	if provider, ok := e.publicExchange.(types.CustomIntervalProvider); ok {
+		supportedIntervals = provider.SupportedInterval()
+	}
+
+	e.kLineAggregators = nil
+	e.tradeKLineAggregators = nil
+
+	var marketTradeSymbols []string
+
 	// collect subscriptions
 	for _, sub := range e.MarketDataStream.GetSubscriptions() {
 		loadedSymbols[sub.Symbol] = struct{}{}
 
 		switch sub.Channel {
 		case types.KLineChannel:
-			loadedIntervals[sub.Options.Interval] = struct{}{}
+			interval := sub.Options.Interval
+			if sub.Options.Source == types.KLineSourceTrade {
+				e.addTradeKLineAggregator(sub.Symbol, interval)
+				marketTradeSymbols = append(marketTradeSymbols, sub.Symbol)
+				continue
+			}
+
+			if _, ok := supportedIntervals[interval]; !ok {
+				if baseInterval, ok := supportedIntervals.BaseInterval(interval); ok {
+					log.Infof("aggregating %s %s klines from the %s klines", sub.Symbol, interval, baseInterval)
+
+					aggregator := types.NewKLineAggregator(sub.Symbol, interval, baseInterval)
+					aggregator.OnKLineClosed(e.emitKLineClosed)
+					e.kLineAggregators = append(e.kLineAggregators, aggregator)
+					interval = baseInterval
+				}
+			}
+
+			loadedIntervals[interval] = struct{}{}
+
+		case types.MarketTradeChannel:
+			marketTradeSymbols = append(marketTradeSymbols, sub.Symbol)
 
 		default:
 			// Since Environment is not yet been injected at this point, no hard error
@@ -396,6 +436,8 @@ func (e *Exchange) SubscribeMarketData(
 		return c, nil
 	}
 
+	e.subscribeAggTrades(startTime, endTime, marketTradeSymbols)
+
 	klineC, errC := e.srv.QueryKLinesCh(startTime, endTime, e.publicExchange, symbols, intervals)
 	go func() {
 		if err := <-errC; err != nil {
@@ -405,6 +447,41 @@ func (e *Exchange) SubscribeMarketData(
 	return klineC, nil
 }
 
+// addTradeKLineAggregator builds the klines of the interval from the replayed market trades of the symbol,
+// the periods are closed by the required klines in ConsumeKLine
+func (e *Exchange) addTradeKLineAggregator(symbol string, interval types.Interval) {
+	log.Infof("building %s %s klines from the aggregated trades", symbol, interval)
+
+	aggregator := types.NewKLineAggregator(symbol, interval, "")
+	aggregator.BindStream(e.MarketDataStream)
+	aggregator.OnKLineClosed(e.emitKLineClosed)
+	e.tradeKLineAggregators = append(e.tradeKLineAggregators, aggregator)
+}
+
+// subscribeAggTrades replays the aggregated trades imported by the import-data command as the market trades,
+// the trades are emitted with the klines of the required interval, see ConsumeKLine
+func (e *Exchange) subscribeAggTrades(startTime, endTime time.Time, symbols []string) {
+	e.aggTradeFeeds = make(map[string]*aggTradeFeed)
+
+	aggTradeService := service.NewAggTradeService(e.srv.DB)
+	for _, symbol := range symbols {
+		if _, ok := e.aggTradeFeeds[symbol]; ok {
+			continue
+		}
+
+		log.Infof("replaying the %s %s aggregated trades as the market trades", e.publicExchange.Name(), symbol)
+
+		tradeC, errC := aggTradeService.QueryCh(context.Background(), e.publicExchange.Name(), symbol, startTime, endTime)
+		go func(symbol string) {
+			if err := <-errC; err != nil {
+				log.WithError(err).Errorf("backtest %s aggregated trade feed error", symbol)
+			}
+		}(symbol)
+
+		e.aggTradeFeeds[symbol] = &aggTradeFeed{C: tradeC}
+	}
+}
+
 func (e *Exchange) ConsumeKLine(k types.KLine, requiredInterval types.Interval) {
 	matching, ok := e.matchingBook(k.Symbol)
 	if !ok {
@@ -424,10 +501,23 @@ func (e *Exchange) ConsumeKLine(k types.KLine, requiredInterval types.Interval)
 		// here we generate trades and order updates
 		matching.processKLine(requiredKline)
 		matching.nextKLine = &k
+
+		// the market trades are emitted after the matching, so the orders submitted on the trades are matched by the next kline
+		if feed, ok := e.aggTradeFeeds[k.Symbol]; ok {
+			feed.emitUntil(requiredKline.EndTime.Time(), e.MarketDataStream.EmitMarketTrade)
+		}
+
 		for _, kline := range matching.klineCache {
-			e.MarketDataStream.EmitKLineClosed(kline)
-			for _, h := range e.Src.Callbacks {
-				h(kline, e.Src)
+			e.emitKLineClosed(kline)
+			for _, aggregator := range e.kLineAggregators {
+				aggregator.HandleKLineClosed(kline)
+			}
+		}
+
+		// the trades until the end of the required kline are emitted, so the trade-built period ending with it can be closed
+		for _, aggregator := range e.tradeKLineAggregators {
+			if aggregator.Symbol == k.Symbol {
+				aggregator.Tick(requiredKline.EndTime.Time())
 			}
 		}
 		// reset the paramcache
@@ -436,6 +526,13 @@ func (e *Exchange) ConsumeKLine(k types.KLine, requiredInterval types.Interval)
 	matching.klineCache[k.Interval] = k
 }
 
+func (e *Exchange) emitKLineClosed(kline types.KLine) {
+	e.MarketDataStream.EmitKLineClosed(kline)
+	for _, h := range e.Src.Callbacks {
+		h(kline, e.Src)
+	}
+}
+
 func (e *Exchange) CloseMarketData() error {
 	if err := e.MarketDataStream.Close(); err != nil {
 		log.WithError(err).Error("stream close error")
@@ -0,0 +1,316 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type MonteCarloMethod string
+
+const (
+	// MonteCarloMethodShuffle re-orders the realized profits, the final equity only changes with the randomized costs
+	MonteCarloMethodShuffle MonteCarloMethod = "shuffle"
+
+	// MonteCarloMethodBootstrap resamples the trade returns with replacement and compounds them
+	MonteCarloMethodBootstrap MonteCarloMethod = "bootstrap"
+)
+
+func ParseMonteCarloMethod(s string) (MonteCarloMethod, error) {
+	switch m := MonteCarloMethod(strings.ToLower(s)); m {
+	case MonteCarloMethodShuffle, MonteCarloMethodBootstrap:
+		return m, nil
+	}
+
+	return "", fmt.Errorf("unknown monte carlo method: %q, valid methods are: shuffle, bootstrap", s)
+}
+
+type MonteCarloConfig struct {
+	Method      MonteCarloMethod
+	Simulations int
+
+	// Slippage is the max extra cost ratio of the trade quote quantity, the cost of each trade is randomized in [0, Slippage]
+	Slippage fixedpoint.Value
+
+	// FeeJitter randomizes the fee of each trade in [fee * (1 - FeeJitter), fee * (1 + FeeJitter)]
+	FeeJitter fixedpoint.Value
+
+	// RuinThreshold is the loss ratio of the initial equity that is considered as ruin, 0.5 means losing 50% of the equity
+	RuinThreshold fixedpoint.Value
+
+	// Seed is the random seed, the current time is used if it's zero
+	Seed int64
+}
+
+// MonteCarloTrade is a realized profit of the back-test
+type MonteCarloTrade struct {
+	Time          time.Time        `json:"time"`
+	NetProfit     fixedpoint.Value `json:"netProfit"`
+	QuoteQuantity fixedpoint.Value `json:"quoteQuantity"`
+	Fee           fixedpoint.Value `json:"fee"`
+}
+
+func NewMonteCarloTrade(profit *types.Profit) MonteCarloTrade {
+	return MonteCarloTrade{
+		Time:          profit.TradedAt,
+		NetProfit:     profit.NetProfit,
+		QuoteQuantity: profit.QuoteQuantity,
+		Fee:           profit.FeeInUSD,
+	}
+}
+
+// MonteCarloDistribution is the distribution percentiles of the simulation results
+type MonteCarloDistribution struct {
+	Mean fixedpoint.Value `json:"mean"`
+	P5   fixedpoint.Value `json:"p5"`
+	P25  fixedpoint.Value `json:"p25"`
+	P50  fixedpoint.Value `json:"p50"`
+	P75  fixedpoint.Value `json:"p75"`
+	P95  fixedpoint.Value `json:"p95"`
+}
+
+func newMonteCarloDistribution(values []float64) MonteCarloDistribution {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	return MonteCarloDistribution{
+		Mean: fixedpoint.NewFromFloat(mean(sorted)),
+		P5:   fixedpoint.NewFromFloat(percentile(sorted, 0.05)),
+		P25:  fixedpoint.NewFromFloat(percentile(sorted, 0.25)),
+		P50:  fixedpoint.NewFromFloat(percentile(sorted, 0.5)),
+		P75:  fixedpoint.NewFromFloat(percentile(sorted, 0.75)),
+		P95:  fixedpoint.NewFromFloat(percentile(sorted, 0.95)),
+	}
+}
+
+func (d MonteCarloDistribution) String() string {
+	return fmt.Sprintf("P5: %s P25: %s P50: %s P75: %s P95: %s MEAN: %s",
+		d.P5.FormatString(4), d.P25.FormatString(4), d.P50.FormatString(4),
+		d.P75.FormatString(4), d.P95.FormatString(4), d.Mean.FormatString(4))
+}
+
+// percentile returns the linear interpolated percentile of the sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+type MonteCarloSample struct {
+	FinalEquity fixedpoint.Value `json:"finalEquity"`
+	MaxDrawdown fixedpoint.Value `json:"maxDrawdown"`
+	Ruined      bool             `json:"ruined,omitempty"`
+}
+
+// MonteCarloReport is the robustness analysis of the realized profit sequence
+type MonteCarloReport struct {
+	Method        MonteCarloMethod `json:"method"`
+	Simulations   int              `json:"simulations"`
+	NumOfTrades   int              `json:"numOfTrades"`
+	Seed          int64            `json:"seed"`
+	Slippage      fixedpoint.Value `json:"slippage"`
+	FeeJitter     fixedpoint.Value `json:"feeJitter"`
+	RuinThreshold fixedpoint.Value `json:"ruinThreshold"`
+
+	InitialEquity fixedpoint.Value `json:"initialEquity"`
+
+	FinalEquity MonteCarloDistribution `json:"finalEquity"`
+	MaxDrawdown MonteCarloDistribution `json:"maxDrawdown"`
+
+	// RiskOfRuin is the ratio of the simulations that hit the ruin threshold
+	RiskOfRuin fixedpoint.Value `json:"riskOfRuin"`
+
+	// Samples are the results of each simulation, they are only written to the monte carlo report file
+	Samples []MonteCarloSample `json:"samples,omitempty"`
+}
+
+func (r *MonteCarloReport) Print() {
+	color.Green("MONTE CARLO (%s, %d SIMULATIONS OF %d TRADES, SEED %d)", r.Method, r.Simulations, r.NumOfTrades, r.Seed)
+	color.Green("  FINAL EQUITY: %s", r.FinalEquity.String())
+	color.Red("  MAX DRAWDOWN: %s", r.MaxDrawdown.String())
+	color.Red("  RISK OF RUIN (%s LOSS): %s", r.RuinThreshold.FormatPercentage(2), r.RiskOfRuin.FormatPercentage(2))
+}
+
+// WithoutSamples returns a copy of the report without the samples for the summary report
+func (r *MonteCarloReport) WithoutSamples() *MonteCarloReport {
+	c := *r
+	c.Samples = nil
+	return &c
+}
+
+// RunMonteCarlo simulates the equity paths from the realized profits of the back-test
+func RunMonteCarlo(initialEquity fixedpoint.Value, trades []MonteCarloTrade, config MonteCarloConfig) (*MonteCarloReport, error) {
+	if config.Simulations <= 0 {
+		return nil, fmt.Errorf("the number of monte carlo simulations should be positive, got %d", config.Simulations)
+	}
+
+	if initialEquity.Sign() <= 0 {
+		return nil, fmt.Errorf("the initial equity should be positive, got %s", initialEquity.String())
+	}
+
+	if len(trades) == 0 {
+		return nil, fmt.Errorf("no realized profits to simulate")
+	}
+
+	if len(config.Method) == 0 {
+		config.Method = MonteCarloMethodShuffle
+	}
+
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+
+	trades = append([]MonteCarloTrade(nil), trades...)
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time)
+	})
+
+	sim := newMonteCarloSimulator(initialEquity.Float64(), trades, config)
+
+	report := &MonteCarloReport{
+		Method:        config.Method,
+		Simulations:   config.Simulations,
+		NumOfTrades:   len(trades),
+		Seed:          config.Seed,
+		Slippage:      config.Slippage,
+		FeeJitter:     config.FeeJitter,
+		RuinThreshold: config.RuinThreshold,
+		InitialEquity: initialEquity,
+		Samples:       make([]MonteCarloSample, 0, config.Simulations),
+	}
+
+	finalEquities := make([]float64, config.Simulations)
+	maxDrawdowns := make([]float64, config.Simulations)
+	ruined := 0
+	for i := 0; i < config.Simulations; i++ {
+		finalEquity, maxDrawdown, isRuined := sim.run()
+		finalEquities[i] = finalEquity
+		maxDrawdowns[i] = maxDrawdown
+		if isRuined {
+			ruined++
+		}
+
+		report.Samples = append(report.Samples, MonteCarloSample{
+			FinalEquity: fixedpoint.NewFromFloat(finalEquity),
+			MaxDrawdown: fixedpoint.NewFromFloat(maxDrawdown),
+			Ruined:      isRuined,
+		})
+	}
+
+	report.FinalEquity = newMonteCarloDistribution(finalEquities)
+	report.MaxDrawdown = newMonteCarloDistribution(maxDrawdowns)
+	report.RiskOfRuin = fixedpoint.NewFromFloat(float64(ruined) / float64(config.Simulations))
+	return report, nil
+}
+
+type monteCarloSimulator struct {
+	method        MonteCarloMethod
+	initialEquity float64
+	ruinEquity    float64
+	slippage      float64
+	feeJitter     float64
+
+	profits, quoteQuantities, fees []float64
+
+	// equities are the equities before each trade of the back-test, they are used to convert the profits to the returns
+	equities []float64
+
+	order []int
+	rand  *rand.Rand
+}
+
+func newMonteCarloSimulator(initialEquity float64, trades []MonteCarloTrade, config MonteCarloConfig) *monteCarloSimulator {
+	s := &monteCarloSimulator{
+		method:        config.Method,
+		initialEquity: initialEquity,
+		slippage:      config.Slippage.Float64(),
+		feeJitter:     config.FeeJitter.Float64(),
+		rand:          rand.New(rand.NewSource(config.Seed)),
+		order:         make([]int, len(trades)),
+	}
+
+	if config.RuinThreshold.Sign() > 0 {
+		s.ruinEquity = initialEquity * (1.0 - config.RuinThreshold.Float64())
+	}
+
+	equity := initialEquity
+	for i, trade := range trades {
+		s.profits = append(s.profits, trade.NetProfit.Float64())
+		s.quoteQuantities = append(s.quoteQuantities, trade.QuoteQuantity.Abs().Float64())
+		s.fees = append(s.fees, trade.Fee.Abs().Float64())
+		s.equities = append(s.equities, equity)
+		s.order[i] = i
+		equity += trade.NetProfit.Float64()
+	}
+
+	return s
+}
+
+// cost returns the randomized extra cost of the trade, the cost can be negative if the fee is jittered down
+func (s *monteCarloSimulator) cost(i int) float64 {
+	c := 0.0
+	if s.slippage > 0 {
+		c += s.quoteQuantities[i] * s.slippage * s.rand.Float64()
+	}
+
+	if s.feeJitter > 0 {
+		c += s.fees[i] * s.feeJitter * (2.0*s.rand.Float64() - 1.0)
+	}
+
+	return c
+}
+
+func (s *monteCarloSimulator) run() (finalEquity, maxDrawdown float64, ruined bool) {
+	if s.method == MonteCarloMethodShuffle {
+		s.rand.Shuffle(len(s.order), func(i, j int) {
+			s.order[i], s.order[j] = s.order[j], s.order[i]
+		})
+	}
+
+	equity := s.initialEquity
+	peak := equity
+	for n := range s.order {
+		switch s.method {
+		case MonteCarloMethodBootstrap:
+			i := s.rand.Intn(len(s.order))
+			if s.equities[i] <= 0 {
+				continue
+			}
+
+			equity *= 1.0 + (s.profits[i]-s.cost(i))/s.equities[i]
+
+		default:
+			i := s.order[n]
+			equity += s.profits[i] - s.cost(i)
+		}
+
+		if equity > peak {
+			peak = equity
+		} else if peak > 0 {
+			maxDrawdown = math.Max(maxDrawdown, (peak-equity)/peak)
+		}
+
+		if equity <= 0 || (s.ruinEquity > 0 && equity <= s.ruinEquity) {
+			ruined = true
+		}
+	}
+
+	return equity, maxDrawdown, ruined
+}
@@ -0,0 +1,127 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func newTestMonteCarloTrades(profits ...float64) (trades []MonteCarloTrade) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, profit := range profits {
+		trades = append(trades, MonteCarloTrade{
+			Time:          startTime.Add(time.Duration(i) * time.Hour),
+			NetProfit:     fixedpoint.NewFromFloat(profit),
+			QuoteQuantity: fixedpoint.NewFromFloat(1000),
+			Fee:           fixedpoint.NewFromFloat(1),
+		})
+	}
+
+	return trades
+}
+
+func TestParseMonteCarloMethod(t *testing.T) {
+	m, err := ParseMonteCarloMethod("Bootstrap")
+	assert.NoError(t, err)
+	assert.Equal(t, MonteCarloMethodBootstrap, m)
+
+	_, err = ParseMonteCarloMethod("permutation")
+	assert.Error(t, err)
+}
+
+func TestRunMonteCarlo_Shuffle(t *testing.T) {
+	trades := newTestMonteCarloTrades(100, -200, 50, -300, 400, 100)
+	report, err := RunMonteCarlo(fixedpoint.NewFromFloat(1000), trades, MonteCarloConfig{
+		Method:        MonteCarloMethodShuffle,
+		Simulations:   200,
+		RuinThreshold: fixedpoint.NewFromFloat(0.5),
+		Seed:          1,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, report.Samples, 200)
+	assert.Equal(t, 6, report.NumOfTrades)
+
+	// without the randomized costs, the shuffled profits always end with the same equity
+	assert.Equal(t, "1150", report.FinalEquity.P5.String())
+	assert.Equal(t, "1150", report.FinalEquity.P95.String())
+
+	// the drawdown depends on the order of the profits
+	assert.True(t, report.MaxDrawdown.P95.Compare(report.MaxDrawdown.P5) > 0)
+	assert.True(t, report.MaxDrawdown.P50.Sign() > 0)
+
+	// -200 and -300 in a row loses 50% of the initial equity
+	assert.True(t, report.RiskOfRuin.Sign() > 0)
+	assert.True(t, report.RiskOfRuin.Compare(fixedpoint.One) < 0)
+
+	summary := report.WithoutSamples()
+	assert.Empty(t, summary.Samples)
+	assert.Len(t, report.Samples, 200)
+}
+
+func TestRunMonteCarlo_Costs(t *testing.T) {
+	trades := newTestMonteCarloTrades(100, -50, 80, -20)
+	config := MonteCarloConfig{
+		Method:      MonteCarloMethodShuffle,
+		Simulations: 100,
+		Slippage:    fixedpoint.NewFromFloat(0.001),
+		FeeJitter:   fixedpoint.NewFromFloat(0.5),
+		Seed:        42,
+	}
+
+	report, err := RunMonteCarlo(fixedpoint.NewFromFloat(1000), trades, config)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// each trade costs at most 1000 * 0.001 + 1 * 0.5
+	assert.True(t, report.FinalEquity.P95.Float64() <= 1110+4*0.5)
+	assert.True(t, report.FinalEquity.P5.Float64() >= 1110-4*1.5)
+	assert.True(t, report.FinalEquity.P5.Compare(report.FinalEquity.P95) < 0)
+
+	// the same seed reproduces the same result
+	again, err := RunMonteCarlo(fixedpoint.NewFromFloat(1000), trades, config)
+	if assert.NoError(t, err) {
+		assert.Equal(t, report.FinalEquity, again.FinalEquity)
+	}
+}
+
+func TestRunMonteCarlo_Bootstrap(t *testing.T) {
+	trades := newTestMonteCarloTrades(100, -100, 100, -100, 100)
+	report, err := RunMonteCarlo(fixedpoint.NewFromFloat(1000), trades, MonteCarloConfig{
+		Method:      MonteCarloMethodBootstrap,
+		Simulations: 500,
+		Seed:        7,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, report.FinalEquity.P5.Compare(report.FinalEquity.P95) < 0)
+	assert.True(t, report.FinalEquity.P5.Float64() > 0)
+	assert.True(t, report.RiskOfRuin.IsZero())
+}
+
+func TestRunMonteCarlo_Errors(t *testing.T) {
+	_, err := RunMonteCarlo(fixedpoint.NewFromFloat(1000), nil, MonteCarloConfig{Simulations: 10})
+	assert.Error(t, err)
+
+	_, err = RunMonteCarlo(fixedpoint.Zero, newTestMonteCarloTrades(1), MonteCarloConfig{Simulations: 10})
+	assert.Error(t, err)
+
+	_, err = RunMonteCarlo(fixedpoint.NewFromFloat(1000), newTestMonteCarloTrades(1), MonteCarloConfig{})
+	assert.Error(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+	assert.Equal(t, 1.0, percentile(values, 0))
+	assert.Equal(t, 3.0, percentile(values, 0.5))
+	assert.Equal(t, 5.0, percentile(values, 1))
+	assert.InDelta(t, 1.2, percentile(values, 0.05), 1e-9)
+}
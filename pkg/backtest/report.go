@@ -51,6 +51,19 @@ type SummaryReport struct {
 	SymbolReports []SessionSymbolReport `json:"symbolReports,omitempty"`
 
 	Manifests Manifests `json:"manifests,omitempty"`
+
+	// RiskMetrics are calculated from the total equity curve of all sessions
+	RiskMetrics
+
+	// EquityCurveFile is the equity curve json file in the report directory
+	EquityCurveFile string `json:"equityCurveFile,omitempty"`
+
+	// Benchmarks are the performance relative to the configured benchmarks
+	Benchmarks []BenchmarkReport `json:"benchmarks,omitempty"`
+
+	// MonteCarlo is the robustness analysis of the realized profits, the samples are written to MonteCarloFile
+	MonteCarlo     *MonteCarloReport `json:"monteCarlo,omitempty"`
+	MonteCarloFile string            `json:"monteCarloFile,omitempty"`
 }
 
 func ReadSummaryReport(filename string) (*SummaryReport, error) {
@@ -82,6 +95,12 @@ type SessionSymbolReport struct {
 	Sortino         fixedpoint.Value          `json:"sortinoRatio"`
 	ProfitFactor    fixedpoint.Value          `json:"profitFactor"`
 	WinningRatio    fixedpoint.Value          `json:"winningRatio"`
+
+	// RiskMetrics are calculated from the equity curve of the symbol
+	RiskMetrics
+
+	// EquityCurveFile is the equity curve json file in the report directory
+	EquityCurveFile string `json:"equityCurveFile,omitempty"`
 }
 
 func (r *SessionSymbolReport) InitialEquityValue() fixedpoint.Value {
@@ -132,6 +151,8 @@ func (r *SessionSymbolReport) Print(wantBaseAssetBaseline bool) {
 		color.Red("REALIZED SORTINO RATIO: %s", r.Sortino.FormatString(4))
 	}
 
+	r.RiskMetrics.Print()
+
 	if wantBaseAssetBaseline {
 		if r.LastPrice.Compare(r.StartPrice) > 0 {
 			color.Green("%s BASE ASSET PERFORMANCE: +%s (= (%s - %s) / %s)",
@@ -151,6 +172,24 @@ func (r *SessionSymbolReport) Print(wantBaseAssetBaseline bool) {
 	}
 }
 
+// Print prints the risk metrics, nothing is printed if there is no equity snapshot
+func (m *RiskMetrics) Print() {
+	if m.MaxDrawdown.IsZero() && m.ExposureTime.IsZero() && m.AverageTradeDuration == 0 {
+		return
+	}
+
+	color.Red("MAX DRAWDOWN: %s (DURATION: %s)", m.MaxDrawdown.FormatPercentage(2), m.MaxDrawdownDuration)
+
+	if m.CalmarRatio.Sign() > 0 {
+		color.Green("CALMAR RATIO: %s", m.CalmarRatio.FormatString(4))
+	} else {
+		color.Red("CALMAR RATIO: %s", m.CalmarRatio.FormatString(4))
+	}
+
+	color.Green("EXPOSURE TIME: %s", m.ExposureTime.FormatPercentage(2))
+	color.Green("AVERAGE TRADE DURATION: %s", m.AverageTradeDuration)
+}
+
 const SessionTimeFormat = "2006-01-02T15_04"
 
 // FormatSessionName returns the back-test session name
@@ -0,0 +1,202 @@
+package backtest
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// EquityPoint is an account snapshot of the equity curve, the equity is evaluated in the quote currency
+type EquityPoint struct {
+	Time     time.Time        `json:"time"`
+	Equity   fixedpoint.Value `json:"equity"`
+	Drawdown fixedpoint.Value `json:"drawdown"`
+
+	// Exposed is true if there is an opened position at the time
+	Exposed bool `json:"exposed,omitempty"`
+}
+
+// RiskMetrics are the risk metrics calculated from the equity curve
+type RiskMetrics struct {
+	// MaxDrawdown is the max drawdown ratio from the high-water mark, 0.1 means 10%
+	MaxDrawdown fixedpoint.Value `json:"maxDrawdown"`
+
+	// MaxDrawdownDuration is the longest duration from a high-water mark to the recovery (or the end of the back-test)
+	MaxDrawdownDuration time.Duration `json:"maxDrawdownDuration"`
+
+	// CalmarRatio is the annualized return divided by the max drawdown
+	CalmarRatio fixedpoint.Value `json:"calmarRatio"`
+
+	// ExposureTime is the ratio of the time that has an opened position
+	ExposureTime fixedpoint.Value `json:"exposureTime"`
+
+	// AverageTradeDuration is the average duration from a position opened to the position closed
+	AverageTradeDuration time.Duration `json:"averageTradeDuration"`
+}
+
+// EquityRecorder records the equity curve from the periodic account snapshots,
+// and the holding durations from the position updates.
+type EquityRecorder struct {
+	points  []EquityPoint
+	tracker types.DrawdownTracker
+
+	// lastTracker is the tracker state before the last point, it's restored when the last point is replaced
+	lastTracker types.DrawdownTracker
+
+	positionOpenedAt time.Time
+	tradeDurations   []time.Duration
+
+	mu sync.Mutex
+}
+
+func NewEquityRecorder() *EquityRecorder {
+	return &EquityRecorder{}
+}
+
+// Record adds an equity snapshot, the snapshot at the same time of the last one replaces the last one
+func (r *EquityRecorder) Record(t time.Time, equity fixedpoint.Value, exposed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n := len(r.points); n > 0 && r.points[n-1].Time.Equal(t) {
+		r.points = r.points[:n-1]
+		r.tracker = r.lastTracker
+	}
+
+	r.lastTracker = r.tracker
+	drawdown := r.tracker.Update(equity)
+	r.points = append(r.points, EquityPoint{
+		Time:     t,
+		Equity:   equity,
+		Drawdown: drawdown,
+		Exposed:  exposed,
+	})
+}
+
+// UpdatePosition updates the holding duration with the position, it should be called on every position update
+func (r *EquityRecorder) UpdatePosition(position *types.Position) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	opened := !position.IsClosed() && !position.IsDust()
+
+	// the position is closed or reversed
+	if !r.positionOpenedAt.IsZero() && (!opened || !position.OpenedAt.Equal(r.positionOpenedAt)) {
+		r.tradeDurations = append(r.tradeDurations, position.ChangedAt.Sub(r.positionOpenedAt))
+		r.positionOpenedAt = time.Time{}
+	}
+
+	if opened && r.positionOpenedAt.IsZero() {
+		r.positionOpenedAt = position.OpenedAt
+	}
+}
+
+// EquityCurve returns a copy of the recorded equity points
+func (r *EquityRecorder) EquityCurve() []EquityPoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]EquityPoint(nil), r.points...)
+}
+
+// Metrics calculates the risk metrics from the recorded equity curve
+func (r *EquityRecorder) Metrics() RiskMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return RiskMetrics{
+		MaxDrawdown:          r.tracker.MaxDrawdown,
+		MaxDrawdownDuration:  maxDrawdownDuration(r.points),
+		CalmarRatio:          calmarRatio(r.points, r.tracker.MaxDrawdown),
+		ExposureTime:         exposureTime(r.points),
+		AverageTradeDuration: averageDuration(r.tradeDurations),
+	}
+}
+
+// WriteEquityCurve writes the equity curve json file
+func (r *EquityRecorder) WriteEquityCurve(filename string) error {
+	return util.WriteJsonFile(filename, r.EquityCurve())
+}
+
+func maxDrawdownDuration(points []EquityPoint) (maxDuration time.Duration) {
+	if len(points) == 0 {
+		return 0
+	}
+
+	highWaterMark := points[0].Equity
+	highWaterMarkTime := points[0].Time
+	for _, p := range points[1:] {
+		if p.Equity.Compare(highWaterMark) >= 0 {
+			highWaterMark = p.Equity
+			highWaterMarkTime = p.Time
+			continue
+		}
+
+		if d := p.Time.Sub(highWaterMarkTime); d > maxDuration {
+			maxDuration = d
+		}
+	}
+
+	return maxDuration
+}
+
+// calmarRatio returns the annualized return divided by the max drawdown, zero if there is no drawdown
+func calmarRatio(points []EquityPoint, maxDrawdown fixedpoint.Value) fixedpoint.Value {
+	if len(points) < 2 || maxDrawdown.Sign() <= 0 {
+		return fixedpoint.Zero
+	}
+
+	first, last := points[0], points[len(points)-1]
+	duration := last.Time.Sub(first.Time)
+	if duration <= 0 || first.Equity.Sign() <= 0 || last.Equity.Sign() < 0 {
+		return fixedpoint.Zero
+	}
+
+	const year = 365 * 24 * time.Hour
+	totalReturn := last.Equity.Div(first.Equity).Float64()
+	annualizedReturn := math.Pow(totalReturn, float64(year)/float64(duration)) - 1.0
+	if math.IsInf(annualizedReturn, 0) || math.IsNaN(annualizedReturn) {
+		return fixedpoint.Zero
+	}
+
+	return fixedpoint.NewFromFloat(annualizedReturn).Div(maxDrawdown)
+}
+
+// exposureTime returns the time-weighted ratio of the snapshots that have an opened position
+func exposureTime(points []EquityPoint) fixedpoint.Value {
+	if len(points) < 2 {
+		return fixedpoint.Zero
+	}
+
+	var exposed, total time.Duration
+	for i := 1; i < len(points); i++ {
+		d := points[i].Time.Sub(points[i-1].Time)
+		total += d
+		if points[i-1].Exposed {
+			exposed += d
+		}
+	}
+
+	if total <= 0 {
+		return fixedpoint.Zero
+	}
+
+	return fixedpoint.NewFromFloat(float64(exposed) / float64(total))
+}
+
+func averageDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+
+	return sum / time.Duration(len(durations))
+}
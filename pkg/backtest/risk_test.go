@@ -0,0 +1,97 @@
+package backtest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestEquityRecorder_Metrics(t *testing.T) {
+	recorder := NewEquityRecorder()
+
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	equities := []float64{1000, 1100, 990, 880, 1050, 1200, 1140}
+	exposed := []bool{false, true, true, false, false, true, false}
+	for i, equity := range equities {
+		recorder.Record(startTime.Add(time.Duration(i)*24*time.Hour), fixedpoint.NewFromFloat(equity), exposed[i])
+	}
+
+	// the snapshot at the same time replaces the last one
+	recorder.Record(startTime.Add(6*24*time.Hour), fixedpoint.NewFromFloat(1140), false)
+	assert.Len(t, recorder.EquityCurve(), 7)
+
+	metrics := recorder.Metrics()
+	assert.Equal(t, "0.2", metrics.MaxDrawdown.String())
+
+	// from the high-water mark at day 1 to the recovery at day 5, the last drawdown lasts 1 day
+	assert.Equal(t, 3*24*time.Hour, metrics.MaxDrawdownDuration)
+
+	// 3 of 6 days are exposed
+	assert.Equal(t, "0.5", metrics.ExposureTime.String())
+	assert.True(t, metrics.CalmarRatio.Sign() > 0)
+
+	curve := recorder.EquityCurve()
+	assert.Equal(t, "0.2", curve[3].Drawdown.String())
+	assert.Equal(t, "0.05", curve[6].Drawdown.String())
+}
+
+func TestEquityRecorder_UpdatePosition(t *testing.T) {
+	recorder := NewEquityRecorder()
+	market := types.Market{
+		Symbol:        "BTCUSDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		MinQuantity:   fixedpoint.NewFromFloat(0.0001),
+		MinNotional:   fixedpoint.NewFromFloat(1),
+		StepSize:      fixedpoint.NewFromFloat(0.0001),
+	}
+
+	position := types.NewPositionFromMarket(market)
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	addTrade := func(side types.SideType, quantity float64, t time.Duration) {
+		position.AddTrade(types.Trade{
+			Symbol:        "BTCUSDT",
+			Side:          side,
+			Price:         fixedpoint.NewFromFloat(20000),
+			Quantity:      fixedpoint.NewFromFloat(quantity),
+			QuoteQuantity: fixedpoint.NewFromFloat(20000 * quantity),
+			Time:          types.Time(startTime.Add(t)),
+		})
+		recorder.UpdatePosition(position)
+	}
+
+	addTrade(types.SideTypeBuy, 0.1, 0)
+	addTrade(types.SideTypeBuy, 0.1, time.Hour)
+	addTrade(types.SideTypeSell, 0.2, 2*time.Hour)
+	addTrade(types.SideTypeSell, 0.1, 3*time.Hour)
+	addTrade(types.SideTypeBuy, 0.1, 7*time.Hour)
+
+	assert.Equal(t, 3*time.Hour, recorder.Metrics().AverageTradeDuration)
+}
+
+func TestSummaryReport_RiskMetricsJSON(t *testing.T) {
+	report := SummaryReport{
+		RiskMetrics: RiskMetrics{
+			MaxDrawdown:          fixedpoint.NewFromFloat(0.1),
+			MaxDrawdownDuration:  36 * time.Hour,
+			AverageTradeDuration: time.Hour,
+		},
+		EquityCurveFile: "equity_curve.json",
+	}
+
+	data, err := json.Marshal(report)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var decoded SummaryReport
+	if assert.NoError(t, json.Unmarshal(data, &decoded)) {
+		assert.Equal(t, report.RiskMetrics, decoded.RiskMetrics)
+		assert.Equal(t, "equity_curve.json", decoded.EquityCurveFile)
+	}
+}
@@ -0,0 +1,397 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultAccountStatementSpec = "@daily"
+
+var defaultAccountStatementTolerance = fixedpoint.NewFromFloat(1e-8)
+
+// AccountReconciler snapshots the session balances periodically and checks the balance changes
+// between two snapshots against the synced trades, fees, deposits, withdrawals, rewards and margin interests.
+type AccountReconciler struct {
+	Config *AccountStatementConfig
+
+	StatementService *service.StatementService
+
+	// Sync is optional, it's called before the reconciliation to sync the records from the exchanges
+	Sync func(ctx context.Context) error
+
+	sessions map[string]*ExchangeSession
+}
+
+func NewAccountReconciler(config *AccountStatementConfig, sessions map[string]*ExchangeSession) (*AccountReconciler, error) {
+	if len(config.When) == 0 {
+		config.When = defaultAccountStatementSpec
+	}
+
+	if _, err := cron.ParseStandard(config.When); err != nil {
+		return nil, fmt.Errorf("invalid account statement cron spec %q: %w", config.When, err)
+	}
+
+	if config.Tolerance.IsZero() {
+		config.Tolerance = defaultAccountStatementTolerance
+	}
+
+	reconciledSessions := make(map[string]*ExchangeSession)
+	if len(config.Sessions) > 0 {
+		for _, name := range config.Sessions {
+			if session, ok := sessions[name]; ok {
+				reconciledSessions[name] = session
+			} else {
+				log.Warnf("account statement: session %s is not defined", name)
+			}
+		}
+	} else {
+		for name, session := range sessions {
+			if session.PublicOnly {
+				continue
+			}
+
+			reconciledSessions[name] = session
+		}
+	}
+
+	for name, session := range reconciledSessions {
+		// the futures positions are not recorded in the balances, the balance changes can not be reconciled
+		if session.Futures || session.IsolatedFutures {
+			log.Warnf("account statement: futures session %s is not supported, skipped", name)
+			delete(reconciledSessions, name)
+		}
+	}
+
+	if err := checkAccountRecordSessions(reconciledSessions); err != nil {
+		return nil, err
+	}
+
+	return &AccountReconciler{
+		Config:   config,
+		sessions: reconciledSessions,
+	}, nil
+}
+
+// accountRecordKey returns the key of the synced records that belong to the session account,
+// the synced records are only distinguished by the exchange, and the trades by the margin and the isolated margin symbol
+func accountRecordKey(session *ExchangeSession) string {
+	switch {
+	case session.IsolatedMargin:
+		return fmt.Sprintf("%s isolated margin %s", session.ExchangeName, session.IsolatedMarginSymbol)
+	case session.Margin:
+		return fmt.Sprintf("%s margin", session.ExchangeName)
+	}
+
+	return fmt.Sprintf("%s spot", session.ExchangeName)
+}
+
+// checkAccountRecordSessions returns an error if the sessions share the same account records,
+// e.g., two spot sessions of the same exchange with different API keys or sub-accounts,
+// since the records of the other account would be counted in the statements of both sessions.
+func checkAccountRecordSessions(sessions map[string]*ExchangeSession) error {
+	sessionNames := make(map[string][]string)
+	for name, session := range sessions {
+		key := accountRecordKey(session)
+		sessionNames[key] = append(sessionNames[key], name)
+	}
+
+	keys := make([]string, 0, len(sessionNames))
+	for key := range sessionNames {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		names := sessionNames[key]
+		if len(names) < 2 {
+			continue
+		}
+
+		sort.Strings(names)
+		return fmt.Errorf("account statement: sessions %s share the %s records, "+
+			"the synced records can not be separated by session, please reconcile only one of them in accountStatement.sessions",
+			strings.Join(names, ", "), key)
+	}
+
+	return nil
+}
+
+// Run takes the initial snapshots of the sessions that have no previous snapshot,
+// and then reconciles the sessions on the cron schedule until the context is canceled.
+func (r *AccountReconciler) Run(ctx context.Context) {
+	for name, session := range r.sessions {
+		last, err := r.StatementService.QueryLastSnapshots(ctx, name)
+		if err != nil {
+			log.WithError(err).Errorf("[%s] unable to query the last balance snapshots", name)
+			continue
+		}
+
+		if len(last) > 0 {
+			continue
+		}
+
+		if _, err := r.snapshot(ctx, session); err != nil {
+			log.WithError(err).Errorf("[%s] unable to take the initial balance snapshot", name)
+		}
+	}
+
+	c := cron.New()
+	if _, err := c.AddFunc(r.Config.When, func() { r.reconcileAll(ctx) }); err != nil {
+		log.WithError(err).Errorf("unable to schedule the account statement job")
+		return
+	}
+
+	c.Start()
+	<-ctx.Done()
+	c.Stop()
+}
+
+func (r *AccountReconciler) reconcileAll(ctx context.Context) {
+	if r.Sync != nil {
+		if err := r.Sync(ctx); err != nil {
+			log.WithError(err).Errorf("unable to sync before the account reconciliation")
+		}
+	}
+
+	for name, session := range r.sessions {
+		if _, err := r.Reconcile(ctx, session); err != nil {
+			log.WithError(err).Errorf("[%s] unable to reconcile the account", name)
+		}
+	}
+}
+
+func (r *AccountReconciler) snapshot(ctx context.Context, session *ExchangeSession) ([]types.AssetBalanceSnapshot, error) {
+	account, err := session.UpdateAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := types.NewAssetBalanceSnapshots(session.Name, session.ExchangeName, types.Time(time.Now()), account.Balances())
+	if err := r.StatementService.InsertSnapshots(snapshots); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// Reconcile takes a new balance snapshot of the session, builds the account statements from the previous snapshot,
+// stores them and notifies the discrepancies. No statement is built if there is no previous snapshot.
+func (r *AccountReconciler) Reconcile(ctx context.Context, session *ExchangeSession) ([]types.AccountStatement, error) {
+	last, err := r.StatementService.QueryLastSnapshots(ctx, session.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := r.snapshot(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(last) == 0 || len(current) == 0 {
+		return nil, nil
+	}
+
+	startTime := last[0].Time.Time()
+	endTime := current[0].Time.Time()
+
+	records, err := r.StatementService.QueryAccountRecords(ctx, session.ExchangeName, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := accountFlows(session, session.Markets(), records)
+	statements := buildAccountStatements(last, current, flows, r.Config.Tolerance)
+
+	var discrepancies []types.AccountStatement
+	for _, statement := range statements {
+		if err := r.StatementService.Insert(statement); err != nil {
+			return statements, err
+		}
+
+		if !statement.Reconciled {
+			discrepancies = append(discrepancies, statement)
+		}
+	}
+
+	if len(discrepancies) > 0 {
+		var lines []string
+		for _, statement := range discrepancies {
+			lines = append(lines, fmt.Sprintf("%s: expected %s, actual %s, difference %s",
+				statement.Asset,
+				statement.Expected().String(),
+				statement.EndBalance.String(),
+				statement.Difference.String()))
+		}
+
+		Notify("⚠️ %s account statement from %s to %s has %d unreconciled assets, there might be missed trades or unsynced transfers:\n%s",
+			session.Name,
+			startTime.Format(time.RFC3339),
+			endTime.Format(time.RFC3339),
+			len(discrepancies),
+			strings.Join(lines, "\n"),
+			SeverityWarn)
+	}
+
+	return statements, nil
+}
+
+// accountFlows sums the balance changes of the records by asset, only the records belong to the session account are included:
+// the margin interests are only for the margin sessions, and the transfers and the rewards are only for the spot sessions.
+func accountFlows(session *ExchangeSession, markets types.MarketMap, records *service.AccountRecords) map[string]*types.AccountFlow {
+	flows := make(map[string]*types.AccountFlow)
+	flow := func(asset string) *types.AccountFlow {
+		f, ok := flows[asset]
+		if !ok {
+			f = &types.AccountFlow{}
+			flows[asset] = f
+		}
+		return f
+	}
+
+	for _, trade := range records.Trades {
+		if trade.IsFutures || trade.IsMargin != session.Margin {
+			continue
+		}
+
+		if trade.IsIsolated != session.IsolatedMargin || (session.IsolatedMargin && trade.Symbol != session.IsolatedMarginSymbol) {
+			continue
+		}
+
+		market, ok := markets[trade.Symbol]
+		if !ok {
+			log.Warnf("account statement: market %s not found, trade %d is skipped", trade.Symbol, trade.ID)
+			continue
+		}
+
+		base, quote := flow(market.BaseCurrency), flow(market.QuoteCurrency)
+		if trade.IsBuyer {
+			base.Trade = base.Trade.Add(trade.Quantity)
+			quote.Trade = quote.Trade.Sub(trade.QuoteQuantity)
+		} else {
+			base.Trade = base.Trade.Sub(trade.Quantity)
+			quote.Trade = quote.Trade.Add(trade.QuoteQuantity)
+		}
+
+		if len(trade.FeeCurrency) > 0 && !trade.Fee.IsZero() {
+			fee := flow(trade.FeeCurrency)
+			fee.Fee = fee.Fee.Add(trade.Fee)
+		}
+	}
+
+	if session.Margin {
+		for _, interest := range records.MarginInterests {
+			if session.IsolatedMargin && interest.IsolatedSymbol != session.IsolatedMarginSymbol {
+				continue
+			}
+
+			if !session.IsolatedMargin && len(interest.IsolatedSymbol) > 0 {
+				continue
+			}
+
+			f := flow(interest.Asset)
+			f.Interest = f.Interest.Add(interest.Interest)
+		}
+
+		return flows
+	}
+
+	for _, deposit := range records.Deposits {
+		f := flow(deposit.Asset)
+		f.Deposit = f.Deposit.Add(deposit.Amount)
+	}
+
+	for _, withdraw := range records.Withdraws {
+		f := flow(withdraw.Asset)
+		f.Withdraw = f.Withdraw.Add(withdraw.Amount)
+
+		if !withdraw.TransactionFee.IsZero() {
+			feeCurrency := withdraw.TransactionFeeCurrency
+			if len(feeCurrency) == 0 {
+				feeCurrency = withdraw.Asset
+			}
+
+			fee := flow(feeCurrency)
+			fee.Withdraw = fee.Withdraw.Add(withdraw.TransactionFee)
+		}
+	}
+
+	for _, reward := range records.Rewards {
+		f := flow(reward.Currency)
+		f.Reward = f.Reward.Add(reward.Quantity)
+	}
+
+	return flows
+}
+
+// buildAccountStatements builds the statements of the assets in the start snapshots, the end snapshots or the flows,
+// the statements are sorted by the asset
+func buildAccountStatements(
+	start, end []types.AssetBalanceSnapshot, flows map[string]*types.AccountFlow, tolerance fixedpoint.Value,
+) []types.AccountStatement {
+	if len(start) == 0 || len(end) == 0 {
+		return nil
+	}
+
+	startBalances := make(map[string]fixedpoint.Value)
+	for _, snapshot := range start {
+		startBalances[snapshot.Asset] = snapshot.NetAsset
+	}
+
+	endBalances := make(map[string]fixedpoint.Value)
+	for _, snapshot := range end {
+		endBalances[snapshot.Asset] = snapshot.NetAsset
+	}
+
+	assets := make(map[string]struct{})
+	for asset := range startBalances {
+		assets[asset] = struct{}{}
+	}
+	for asset := range endBalances {
+		assets[asset] = struct{}{}
+	}
+	for asset := range flows {
+		assets[asset] = struct{}{}
+	}
+
+	var statements []types.AccountStatement
+	for asset := range assets {
+		statement := types.AccountStatement{
+			Session:      end[0].Session,
+			Exchange:     end[0].Exchange,
+			Asset:        asset,
+			StartTime:    start[0].Time,
+			EndTime:      end[0].Time,
+			StartBalance: startBalances[asset],
+			EndBalance:   endBalances[asset],
+		}
+
+		if f, ok := flows[asset]; ok {
+			statement.AccountFlow = *f
+		}
+
+		// skip the empty balances that have no activity
+		if statement.StartBalance.IsZero() && statement.EndBalance.IsZero() && statement.AccountFlow.IsZero() {
+			continue
+		}
+
+		statement.Reconcile(tolerance)
+		statements = append(statements, statement)
+	}
+
+	sort.Slice(statements, func(i, j int) bool {
+		return statements[i].Asset < statements[j].Asset
+	})
+
+	return statements
+}
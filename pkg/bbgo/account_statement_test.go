@@ -0,0 +1,127 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
+	. "github.com/c9s/bbgo/pkg/testing/testhelper"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestNewAccountReconciler(t *testing.T) {
+	sessions := map[string]*ExchangeSession{
+		"binance": {Name: "binance"},
+		"futures": {Name: "futures", Futures: true},
+		"public":  {Name: "public", PublicOnly: true},
+	}
+
+	reconciler, err := NewAccountReconciler(&AccountStatementConfig{}, sessions)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "@daily", reconciler.Config.When)
+		assert.Equal(t, defaultAccountStatementTolerance, reconciler.Config.Tolerance)
+		assert.Len(t, reconciler.sessions, 1)
+		assert.Contains(t, reconciler.sessions, "binance")
+	}
+
+	_, err = NewAccountReconciler(&AccountStatementConfig{When: "every day"}, sessions)
+	assert.Error(t, err)
+
+	// the sessions of the same exchange and the same account type can not be separated by the synced records
+	sessions = map[string]*ExchangeSession{
+		"binance":     {Name: "binance", ExchangeName: types.ExchangeBinance},
+		"binance-sub": {Name: "binance-sub", ExchangeName: types.ExchangeBinance, SubAccount: "sub"},
+		"margin":      {Name: "margin", ExchangeName: types.ExchangeBinance, Margin: true},
+		"max":         {Name: "max", ExchangeName: types.ExchangeMax},
+	}
+
+	_, err = NewAccountReconciler(&AccountStatementConfig{}, sessions)
+	assert.ErrorContains(t, err, "sessions binance, binance-sub share the binance spot records")
+
+	reconciler, err = NewAccountReconciler(&AccountStatementConfig{Sessions: []string{"binance", "margin", "max"}}, sessions)
+	if assert.NoError(t, err) {
+		assert.Len(t, reconciler.sessions, 3)
+	}
+}
+
+func TestAccountStatement_reconcile(t *testing.T) {
+	markets := types.MarketMap{
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+	}
+
+	now := time.Now()
+	session := &ExchangeSession{Name: "binance"}
+
+	records := &service.AccountRecords{
+		Trades: []types.Trade{
+			{
+				ID: 1, Symbol: "BTCUSDT", Side: types.SideTypeBuy, IsBuyer: true,
+				Quantity: Number(0.1), QuoteQuantity: Number(2000),
+				Fee: Number(0.0001), FeeCurrency: "BTC",
+			},
+			{
+				// margin trades belong to the margin session
+				ID: 2, Symbol: "BTCUSDT", Side: types.SideTypeSell, IsMargin: true,
+				Quantity: Number(1), QuoteQuantity: Number(20000),
+			},
+		},
+		Deposits: []types.Deposit{
+			{Asset: "USDT", Amount: Number(500)},
+		},
+		Withdraws: []types.Withdraw{
+			{Asset: "ETH", Amount: Number(1), TransactionFee: Number(0.01), TransactionFeeCurrency: "ETH"},
+		},
+		Rewards: []types.Reward{
+			{Currency: "BNB", Quantity: Number(0.5)},
+		},
+		MarginInterests: []types.MarginInterest{
+			{Asset: "USDT", Interest: Number(1)},
+		},
+	}
+
+	flows := accountFlows(session, markets, records)
+	assert.Equal(t, Number(0.1), flows["BTC"].Trade)
+	assert.Equal(t, Number(0.0001), flows["BTC"].Fee)
+	assert.Equal(t, Number(-2000), flows["USDT"].Trade)
+	assert.Equal(t, Number(500), flows["USDT"].Deposit)
+	assert.True(t, flows["USDT"].Interest.IsZero())
+	assert.Equal(t, Number(1.01), flows["ETH"].Withdraw)
+	assert.Equal(t, Number(0.5), flows["BNB"].Reward)
+
+	start := []types.AssetBalanceSnapshot{
+		{Session: "binance", Time: types.Time(now.Add(-24 * time.Hour)), Asset: "BTC", NetAsset: Number(1)},
+		{Session: "binance", Time: types.Time(now.Add(-24 * time.Hour)), Asset: "USDT", NetAsset: Number(10000)},
+		{Session: "binance", Time: types.Time(now.Add(-24 * time.Hour)), Asset: "ETH", NetAsset: Number(2)},
+		{Session: "binance", Time: types.Time(now.Add(-24 * time.Hour)), Asset: "DOGE", NetAsset: fixedpoint.Zero},
+	}
+
+	end := []types.AssetBalanceSnapshot{
+		{Session: "binance", Time: types.Time(now), Asset: "BTC", NetAsset: Number(1.0999)},
+		// an unsynced transfer of 100 USDT
+		{Session: "binance", Time: types.Time(now), Asset: "USDT", NetAsset: Number(8400)},
+		{Session: "binance", Time: types.Time(now), Asset: "ETH", NetAsset: Number(0.99)},
+		{Session: "binance", Time: types.Time(now), Asset: "BNB", NetAsset: Number(0.5)},
+		{Session: "binance", Time: types.Time(now), Asset: "DOGE", NetAsset: fixedpoint.Zero},
+	}
+
+	statements := buildAccountStatements(start, end, flows, defaultAccountStatementTolerance)
+	if assert.Len(t, statements, 4) {
+		assert.Equal(t, "BNB", statements[0].Asset)
+		assert.True(t, statements[0].Reconciled)
+
+		assert.Equal(t, "BTC", statements[1].Asset)
+		assert.True(t, statements[1].Reconciled)
+
+		assert.Equal(t, "ETH", statements[2].Asset)
+		assert.True(t, statements[2].Reconciled)
+
+		usdt := statements[3]
+		assert.Equal(t, "USDT", usdt.Asset)
+		assert.False(t, usdt.Reconciled)
+		assert.Equal(t, Number(8500), usdt.Expected())
+		assert.Equal(t, Number(-100), usdt.Difference)
+	}
+}
@@ -20,14 +20,14 @@ var maxIsolatedMarginLeverage = fixedpoint.NewFromInt(10)
 var maxCrossMarginLeverage = fixedpoint.NewFromInt(3)
 
 type AccountValueCalculator struct {
-	priceSolver   *pricesolver.SimplePriceSolver
+	priceSolver   pricesolver.PriceSolver
 	session       *ExchangeSession
 	quoteCurrency string
 }
 
 func NewAccountValueCalculator(
 	session *ExchangeSession,
-	priceSolver *pricesolver.SimplePriceSolver,
+	priceSolver pricesolver.PriceSolver,
 	quoteCurrency string,
 ) *AccountValueCalculator {
 	return &AccountValueCalculator{
@@ -49,13 +49,7 @@ func (c *AccountValueCalculator) UpdatePrices(ctx context.Context) error {
 			continue
 		}
 
-		symbol := currency + c.quoteCurrency
-		reversedSymbol := c.quoteCurrency + currency
-		if _, ok := markets[symbol]; ok {
-			symbols = append(symbols, symbol)
-		} else if _, ok2 := markets[reversedSymbol]; ok2 {
-			symbols = append(symbols, reversedSymbol)
-		}
+		symbols = append(symbols, pricesolver.ConversionSymbols(markets, currency, c.quoteCurrency)...)
 	}
 
 	return c.priceSolver.UpdateFromTickers(ctx, c.session.Exchange, symbols...)
@@ -91,7 +85,7 @@ func (c *AccountValueCalculator) NetValue() fixedpoint.Value {
 
 func totalValueInQuote(
 	balances types.BalanceMap,
-	priceSolver *pricesolver.SimplePriceSolver,
+	priceSolver pricesolver.PriceSolver,
 	quoteCurrency string,
 	algo func(prev fixedpoint.Value, b types.Balance, price fixedpoint.Value) fixedpoint.Value,
 ) (totalValue fixedpoint.Value) {
@@ -111,7 +105,7 @@ func totalValueInQuote(
 
 func calculateNetValueInQuote(
 	balances types.BalanceMap,
-	priceSolver *pricesolver.SimplePriceSolver,
+	priceSolver pricesolver.PriceSolver,
 	quoteCurrency string,
 ) fixedpoint.Value {
 	return totalValueInQuote(balances, priceSolver, quoteCurrency, func(
@@ -214,7 +208,7 @@ func CalculateBaseQuantity(
 	if len(restBalances) == 1 && types.IsUSDFiatCurrency(market.QuoteCurrency) {
 		totalUsdValue = aggregateUsdNetValue(balances)
 	} else if len(restBalances) > 1 {
-		priceSolver := pricesolver.NewSimplePriceResolver(session.Markets())
+		priceSolver := pricesolver.NewGraphPriceSolver(session.Markets(), 0)
 		accountValue := NewAccountValueCalculator(session, priceSolver, "USDT")
 		if err := accountValue.UpdatePrices(context.Background()); err != nil {
 			return fixedpoint.Zero, err
@@ -322,7 +316,7 @@ func CalculateQuoteQuantity(
 	}
 
 	// using leverage -- starts from here
-	priceSolver := pricesolver.NewSimplePriceResolver(session.Markets())
+	priceSolver := pricesolver.NewGraphPriceSolver(session.Markets(), 0)
 
 	accountValue := NewAccountValueCalculator(session, priceSolver, quoteCurrency)
 	if err := accountValue.UpdatePrices(ctx); err != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/c9s/bbgo/pkg/datatype"
 	"github.com/c9s/bbgo/pkg/dynamic"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/interact"
 	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -76,6 +77,59 @@ type SlackNotificationRouting struct {
 	PnL         string `json:"pnL,omitempty" yaml:"pnL,omitempty"`
 }
 
+type DiscordNotification struct {
+	// DefaultChannel is the id of the default channel
+	DefaultChannel    string `json:"defaultChannel,omitempty" yaml:"defaultChannel,omitempty"`
+	ErrorChannel      string `json:"errorChannel,omitempty" yaml:"errorChannel,omitempty"`
+	QueueSize         int    `json:"queueSize,omitempty" yaml:"queueSize,omitempty"`
+	EnableInteraction bool   `json:"enableInteraction,omitempty" yaml:"enableInteraction,omitempty"`
+
+	// SymbolChannels routes the notifications of the symbols matched by the patterns to the channels
+	SymbolChannels map[string]string `json:"symbolChannels,omitempty" yaml:"symbolChannels,omitempty"`
+
+	// SessionChannels routes the notifications of the sessions matched by the patterns to the channels
+	SessionChannels map[string]string `json:"sessionChannels,omitempty" yaml:"sessionChannels,omitempty"`
+}
+
+type WebhookNotification struct {
+	URL string `json:"url" yaml:"url"`
+
+	// Secret is the key of the HMAC-SHA256 signature, WEBHOOK_SECRET is used if it's not set
+	Secret  string            `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	BatchSize     int            `json:"batchSize,omitempty" yaml:"batchSize,omitempty"`
+	FlushInterval types.Duration `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty"`
+	MaxRetries    *int           `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+	QueueSize     int            `json:"queueSize,omitempty" yaml:"queueSize,omitempty"`
+
+	// Timeout is the timeout of each request, including the requests that flush the queued events on shutdown
+	Timeout types.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Switches filters the objects sent to the webhook, all objects are sent if it's not set
+	Switches *NotificationSwitches `json:"switches,omitempty" yaml:"switches,omitempty"`
+}
+
+type EmailNotification struct {
+	Host     string `json:"host" yaml:"host"`
+	Port     int    `json:"port" yaml:"port"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+
+	// Password is the password of the SMTP user, SMTP_PASSWORD is used if it's not set
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+
+	From          string   `json:"from" yaml:"from"`
+	To            []string `json:"to" yaml:"to"`
+	SubjectPrefix string   `json:"subjectPrefix,omitempty" yaml:"subjectPrefix,omitempty"`
+
+	// DigestInterval enables the digest mode, the notifications are sent in one email every interval
+	DigestInterval types.Duration `json:"digestInterval,omitempty" yaml:"digestInterval,omitempty"`
+	QueueSize      int            `json:"queueSize,omitempty" yaml:"queueSize,omitempty"`
+
+	// Switches filters the objects sent by email, all objects are sent if it's not set
+	Switches *NotificationSwitches `json:"switches,omitempty" yaml:"switches,omitempty"`
+}
+
 type TelegramNotification struct {
 	Broadcast bool `json:"broadcast" yaml:"broadcast"`
 }
@@ -87,10 +141,36 @@ type NotificationSwitches struct {
 	SubmitOrder bool `json:"submitOrder" yaml:"submitOrder"`
 }
 
+// Allow checks if the object is allowed by the switches, the objects not covered by the switches are always allowed
+func (s *NotificationSwitches) Allow(obj interface{}) bool {
+	if s == nil {
+		return true
+	}
+
+	switch obj.(type) {
+	case types.Trade, *types.Trade:
+		return s.Trade
+	case types.Order, *types.Order:
+		return s.OrderUpdate
+	case types.SubmitOrder, *types.SubmitOrder:
+		return s.SubmitOrder
+	case types.Position, *types.Position:
+		return s.Position
+	}
+
+	return true
+}
+
 type NotificationConfig struct {
 	Slack    *SlackNotification    `json:"slack,omitempty" yaml:"slack,omitempty"`
 	Telegram *TelegramNotification `json:"telegram,omitempty" yaml:"telegram,omitempty"`
+	Discord  *DiscordNotification  `json:"discord,omitempty" yaml:"discord,omitempty"`
+	Webhook  *WebhookNotification  `json:"webhook,omitempty" yaml:"webhook,omitempty"`
+	Email    *EmailNotification    `json:"email,omitempty" yaml:"email,omitempty"`
 	Switches *NotificationSwitches `json:"switches" yaml:"switches"`
+
+	// Throttle applies the severity filter, the deduplication, the rate limit and the quiet hours to all notifiers
+	Throttle *NotificationThrottleConfig `json:"throttle,omitempty" yaml:"throttle,omitempty"`
 }
 
 type LoggingConfig struct {
@@ -153,6 +233,71 @@ type Backtest struct {
 
 	// sync 1 second interval KLines
 	SyncSecKLines bool `json:"syncSecKLines,omitempty" yaml:"syncSecKLines,omitempty"`
+
+	// Benchmarks are the baselines that the back-test performance is compared with
+	Benchmarks []BacktestBenchmark `json:"benchmarks,omitempty" yaml:"benchmarks,omitempty"`
+}
+
+type BacktestBenchmarkType string
+
+const (
+	// BacktestBenchmarkTypeBuyAndHold buys and holds the back-test symbols with the equal weight
+	BacktestBenchmarkTypeBuyAndHold BacktestBenchmarkType = "buyAndHold"
+
+	// BacktestBenchmarkTypeBasket buys and holds the given symbols with the equal weight
+	BacktestBenchmarkTypeBasket BacktestBenchmarkType = "basket"
+
+	// BacktestBenchmarkTypeSymbol buys and holds the given symbol
+	BacktestBenchmarkTypeSymbol BacktestBenchmarkType = "symbol"
+)
+
+type BacktestBenchmark struct {
+	Name string                `json:"name,omitempty" yaml:"name,omitempty"`
+	Type BacktestBenchmarkType `json:"type" yaml:"type"`
+
+	// Symbol is used by the symbol benchmark
+	Symbol string `json:"symbol,omitempty" yaml:"symbol,omitempty"`
+
+	// Symbols is used by the basket benchmark
+	Symbols []string `json:"symbols,omitempty" yaml:"symbols,omitempty"`
+}
+
+// GetSymbols returns the symbols of the benchmark, the back-test symbols are used for the buy-and-hold benchmark
+func (b *BacktestBenchmark) GetSymbols(backtestSymbols []string) ([]string, error) {
+	switch b.Type {
+	case BacktestBenchmarkTypeBuyAndHold, "":
+		return backtestSymbols, nil
+
+	case BacktestBenchmarkTypeBasket:
+		if len(b.Symbols) == 0 {
+			return nil, fmt.Errorf("basket benchmark %q requires symbols", b.Name)
+		}
+
+		return b.Symbols, nil
+
+	case BacktestBenchmarkTypeSymbol:
+		if len(b.Symbol) == 0 {
+			return nil, fmt.Errorf("symbol benchmark %q requires symbol", b.Name)
+		}
+
+		return []string{b.Symbol}, nil
+	}
+
+	return nil, fmt.Errorf("unknown benchmark type: %q", b.Type)
+}
+
+// GetName returns the benchmark name, the type and the symbols are used if the name is not set
+func (b *BacktestBenchmark) GetName(symbols []string) string {
+	if len(b.Name) > 0 {
+		return b.Name
+	}
+
+	typ := b.Type
+	if len(typ) == 0 {
+		typ = BacktestBenchmarkTypeBuyAndHold
+	}
+
+	return string(typ) + ":" + strings.Join(symbols, ",")
 }
 
 func (b *Backtest) GetAccount(n string) BacktestAccount {
@@ -319,6 +464,48 @@ type SyncConfig struct {
 	} `json:"userDataStream,omitempty" yaml:"userDataStream,omitempty"`
 }
 
+type EquityTrackingConfig struct {
+	// Interval is the interval of the net value snapshots, defaults to 5m
+	Interval types.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+
+	// QuoteCurrency is the currency used for evaluating the net value, defaults to USDT
+	QuoteCurrency string `json:"quoteCurrency,omitempty" yaml:"quoteCurrency,omitempty"`
+
+	// Sessions to track, if ignored, all defined sessions will be tracked
+	Sessions []string `json:"sessions,omitempty" yaml:"sessions,omitempty"`
+
+	// DrawdownAlerts is the list of the drawdown ratios to notify, for example, 0.05 means 5% from the high-water mark
+	DrawdownAlerts []fixedpoint.Value `json:"drawdownAlerts,omitempty" yaml:"drawdownAlerts,omitempty"`
+
+	// MaxSnapshots is the number of the snapshots kept in memory for each session, defaults to 1440
+	MaxSnapshots int `json:"maxSnapshots,omitempty" yaml:"maxSnapshots,omitempty"`
+}
+
+type AccountStatementConfig struct {
+	// When is the cron spec of the reconciliation, defaults to @daily
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+
+	// Sessions to reconcile, if ignored, all defined sessions except the futures sessions will be reconciled
+	Sessions []string `json:"sessions,omitempty" yaml:"sessions,omitempty"`
+
+	// Tolerance is the max absolute difference of an asset balance that is considered as reconciled, defaults to 1e-8
+	Tolerance fixedpoint.Value `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+
+	// Sync syncs the trades and the transfers with the sync config before the reconciliation
+	Sync bool `json:"sync,omitempty" yaml:"sync,omitempty"`
+}
+
+type InteractionConfig struct {
+	// Users maps the messenger accounts to the roles, the role-based authorization is enabled if any user is defined
+	Users []interact.User `json:"users,omitempty" yaml:"users,omitempty"`
+
+	// Commands overrides the required roles of the commands, e.g., "/emergencystop": admin
+	Commands map[string]interact.Role `json:"commands,omitempty" yaml:"commands,omitempty"`
+
+	// AuditLogFile is the file that the audit records are appended to, the audit records are always written to the logger
+	AuditLogFile string `json:"auditLogFile,omitempty" yaml:"auditLogFile,omitempty"`
+}
+
 type GoogleSpreadSheetServiceConfig struct {
 	JsonTokenFile string `json:"jsonTokenFile" yaml:"jsonTokenFile"`
 	SpreadSheetID string `json:"spreadSheetId" yaml:"spreadSheetId"`
@@ -383,6 +570,12 @@ type Config struct {
 	CrossExchangeStrategies []CrossExchangeStrategy `json:"-" yaml:"-"`
 
 	PnLReporters []PnLReporterConfig `json:"reportPnL,omitempty" yaml:"reportPnL,omitempty"`
+
+	EquityTracking *EquityTrackingConfig `json:"equityTracking,omitempty" yaml:"equityTracking,omitempty"`
+
+	AccountStatement *AccountStatementConfig `json:"accountStatement,omitempty" yaml:"accountStatement,omitempty"`
+
+	Interaction *InteractionConfig `json:"interaction,omitempty" yaml:"interaction,omitempty"`
 }
 
 func (c *Config) Map() (map[string]interface{}, error) {
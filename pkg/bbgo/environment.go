@@ -7,7 +7,10 @@ import (
 	"image/png"
 	stdlog "log"
 	"math/rand"
+	"net"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,12 +22,16 @@ import (
 	"github.com/spf13/viper"
 	"gopkg.in/tucnak/telebot.v2"
 
+	"github.com/c9s/bbgo/pkg/discord/discordapi"
 	"github.com/c9s/bbgo/pkg/envvar"
 	"github.com/c9s/bbgo/pkg/exchange"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/interact"
+	"github.com/c9s/bbgo/pkg/notifier/discordnotifier"
+	"github.com/c9s/bbgo/pkg/notifier/emailnotifier"
 	"github.com/c9s/bbgo/pkg/notifier/slacknotifier"
 	"github.com/c9s/bbgo/pkg/notifier/telegramnotifier"
+	"github.com/c9s/bbgo/pkg/notifier/webhooknotifier"
 	"github.com/c9s/bbgo/pkg/service"
 	googleservice "github.com/c9s/bbgo/pkg/service/google"
 	"github.com/c9s/bbgo/pkg/slack/slacklog"
@@ -92,6 +99,8 @@ type Environment struct {
 	AccountService    *service.AccountService
 	WithdrawService   *service.WithdrawService
 	DepositService    *service.DepositService
+	EquityService     *service.EquityService
+	StatementService  *service.StatementService
 	PersistentService *service.PersistenceServiceFacade
 
 	// external services
@@ -111,6 +120,11 @@ type Environment struct {
 	loggingConfig     *LoggingConfig
 	environmentConfig *EnvironmentConfig
 
+	equityTracker *EquityTracker
+
+	// switchedNotifiers are the notifiers with their own notification switches
+	switchedNotifiers []*SwitchedNotifier
+
 	sessions map[string]*ExchangeSession
 }
 
@@ -220,6 +234,8 @@ func (environ *Environment) ConfigureDatabaseDriver(ctx context.Context, driver
 	environ.MarginService = &service.MarginService{DB: db}
 	environ.WithdrawService = &service.WithdrawService{DB: db}
 	environ.DepositService = &service.DepositService{DB: db}
+	environ.EquityService = &service.EquityService{DB: db}
+	environ.StatementService = &service.StatementService{DB: db}
 	environ.SyncService = &service.SyncService{
 		TradeService:    environ.TradeService,
 		OrderService:    environ.OrderService,
@@ -423,8 +439,15 @@ func (environ *Environment) Connect(ctx context.Context) error {
 		if len(session.Subscriptions) == 0 {
 			logger.Warnf("exchange session %s has no subscriptions", session.Name)
 		} else {
+			subscriptions := session.Subscriptions
+
+			// the back-test exchange aggregates the custom kline intervals and the trade-built klines by itself
+			if environ.BacktestService == nil {
+				subscriptions = session.marketDataSubscriptions(ctx)
+			}
+
 			// add the subscribe requests to the stream
-			for _, s := range session.Subscriptions {
+			for _, s := range subscriptions {
 				logger.Infof("subscribing %s %s %v", s.Symbol, s.Channel, s.Options)
 				session.MarketDataStream.Subscribe(s.Channel, s.Symbol, s.Options)
 			}
@@ -446,6 +469,57 @@ func (environ *Environment) Connect(ctx context.Context) error {
 	return nil
 }
 
+// BindEquityTracking starts the equity tracker of the sessions with the given config
+func (environ *Environment) BindEquityTracking(ctx context.Context, config *EquityTrackingConfig) error {
+	// skip this if we are running back-test
+	if environ.BacktestService != nil {
+		return nil
+	}
+
+	tracker := NewEquityTracker(config, environ.sessions)
+	tracker.EquityService = environ.EquityService
+	if err := tracker.Load(ctx); err != nil {
+		return err
+	}
+
+	environ.equityTracker = tracker
+	go tracker.Run(ctx)
+	return nil
+}
+
+// EquityTracker returns the equity tracker, returns nil if equity tracking is not configured
+func (environ *Environment) EquityTracker() *EquityTracker {
+	return environ.equityTracker
+}
+
+// BindAccountStatement starts the account reconciler of the sessions with the account statement config,
+// the user config is used for syncing the records before the reconciliation
+func (environ *Environment) BindAccountStatement(ctx context.Context, userConfig *Config) error {
+	// skip this if we are running back-test
+	if environ.BacktestService != nil {
+		return nil
+	}
+
+	if environ.StatementService == nil {
+		return fmt.Errorf("account statement requires the database to be configured")
+	}
+
+	reconciler, err := NewAccountReconciler(userConfig.AccountStatement, environ.sessions)
+	if err != nil {
+		return err
+	}
+
+	reconciler.StatementService = environ.StatementService
+	if userConfig.AccountStatement.Sync {
+		reconciler.Sync = func(ctx context.Context) error {
+			return environ.Sync(ctx, userConfig)
+		}
+	}
+
+	go reconciler.Run(ctx)
+	return nil
+}
+
 func (environ *Environment) IsSyncing() (status SyncStatus) {
 	environ.syncStatusMutex.Lock()
 	status = environ.syncStatus
@@ -672,6 +746,10 @@ func (environ *Environment) ConfigureNotificationSystem(ctx context.Context, use
 		return err
 	}
 
+	if err := environ.setupAccessControl(userConfig.Interaction); err != nil {
+		return err
+	}
+
 	// setup slack
 	slackToken := viper.GetString("slack-token")
 	if len(slackToken) > 0 && userConfig.Notifications != nil {
@@ -686,12 +764,48 @@ func (environ *Environment) ConfigureNotificationSystem(ctx context.Context, use
 		}
 	}
 
+	// check if discord bot token is defined
+	discordBotToken := viper.GetString("discord-bot-token")
+	if len(discordBotToken) > 0 {
+		environ.setupDiscord(userConfig, discordBotToken, persistence)
+	}
+
+	if conf := userConfig.Notifications.Webhook; conf != nil {
+		environ.setupWebhook(ctx, conf)
+	}
+
+	if conf := userConfig.Notifications.Email; conf != nil {
+		if err := environ.setupEmail(ctx, conf); err != nil {
+			return err
+		}
+	}
+
 	if userConfig.Notifications != nil {
 		if err := environ.ConfigureNotification(userConfig.Notifications); err != nil {
 			return err
 		}
 	}
 
+	if conf := userConfig.Notifications.Throttle; conf != nil {
+		if err := environ.setupNotificationThrottle(ctx, conf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// throttleSummaryInterval is the interval to check the closed windows of the notification throttle
+const throttleSummaryInterval = 10 * time.Second
+
+func (environ *Environment) setupNotificationThrottle(ctx context.Context, conf *NotificationThrottleConfig) error {
+	throttle, err := NewNotificationThrottle(*conf)
+	if err != nil {
+		return err
+	}
+
+	Notification.SetThrottle(throttle)
+	go Notification.RunThrottleSummary(ctx, throttleSummaryInterval)
 	return nil
 }
 
@@ -718,6 +832,140 @@ func (environ *Environment) ConfigureNotification(config *NotificationConfig) er
 		}
 	}
 
+	// the notifiers with their own switches receive the updates that are not enabled by the global switches
+	for _, notifier := range environ.switchedNotifiers {
+		environ.bindSwitchedNotifier(notifier, config.Switches)
+	}
+
+	return nil
+}
+
+func (environ *Environment) bindSwitchedNotifier(notifier *SwitchedNotifier, globalSwitches *NotificationSwitches) {
+	if notifier.Switches == nil {
+		return
+	}
+
+	if notifier.Switches.Trade && (globalSwitches == nil || !globalSwitches.Trade) {
+		for _, session := range environ.sessions {
+			session.UserDataStream.OnTradeUpdate(func(trade types.Trade) {
+				notifier.Notify(trade)
+			})
+		}
+	}
+
+	if notifier.Switches.OrderUpdate && (globalSwitches == nil || !globalSwitches.OrderUpdate) {
+		for _, session := range environ.sessions {
+			session.UserDataStream.OnOrderUpdate(func(order types.Order) {
+				notifier.Notify(order)
+			})
+		}
+	}
+}
+
+func (environ *Environment) addSwitchedNotifier(notifier Notifier, switches *NotificationSwitches) {
+	switched := &SwitchedNotifier{
+		Notifier: notifier,
+		Switches: switches,
+	}
+
+	environ.switchedNotifiers = append(environ.switchedNotifiers, switched)
+	Notification.AddNotifier(switched)
+}
+
+func (environ *Environment) setupWebhook(ctx context.Context, conf *WebhookNotification) {
+	if conf.URL == "" {
+		log.Warn("webhook notification url is not configured, skipping")
+		return
+	}
+
+	secret := conf.Secret
+	if secret == "" {
+		secret = viper.GetString("webhook-secret")
+	}
+
+	var opts = []webhooknotifier.Option{
+		webhooknotifier.OptionBatch(conf.BatchSize, conf.FlushInterval.Duration()),
+	}
+
+	if secret != "" {
+		opts = append(opts, webhooknotifier.OptionSecret(secret))
+	}
+
+	if len(conf.Headers) > 0 {
+		opts = append(opts, webhooknotifier.OptionHeaders(conf.Headers))
+	}
+
+	if conf.MaxRetries != nil {
+		opts = append(opts, webhooknotifier.OptionRetry(*conf.MaxRetries, 0))
+	}
+
+	if conf.QueueSize > 0 {
+		opts = append(opts, webhooknotifier.OptionQueueSize(conf.QueueSize))
+	}
+
+	if conf.Timeout > 0 {
+		opts = append(opts, webhooknotifier.OptionTimeout(conf.Timeout.Duration()))
+	}
+
+	log.Infof("adding webhook notifier: %s", conf.URL)
+
+	// the worker is not stopped by the trading context, so that the notifications of the graceful shutdown are delivered,
+	// it's stopped by CloseNotifiers
+	environ.addSwitchedNotifier(webhooknotifier.New(context.WithoutCancel(ctx), conf.URL, opts...), conf.Switches)
+}
+
+// CloseNotifiers flushes the queued notifications and stops the notifiers that deliver them asynchronously,
+// e.g., the webhook notifier. It should be called after the graceful shutdown.
+func (environ *Environment) CloseNotifiers(ctx context.Context) {
+	for _, switched := range environ.switchedNotifiers {
+		closer, ok := switched.Notifier.(interface {
+			Close(ctx context.Context) error
+		})
+		if !ok {
+			continue
+		}
+
+		if err := closer.Close(ctx); err != nil {
+			log.WithError(err).Errorf("notifier close error")
+		}
+	}
+}
+
+func (environ *Environment) setupEmail(ctx context.Context, conf *EmailNotification) error {
+	if conf.Host == "" || conf.From == "" || len(conf.To) == 0 {
+		return fmt.Errorf("email notification requires host, from and to")
+	}
+
+	port := conf.Port
+	if port == 0 {
+		port = 587
+	}
+
+	var opts []emailnotifier.Option
+	if conf.Username != "" {
+		password := conf.Password
+		if password == "" {
+			password = viper.GetString("smtp-password")
+		}
+
+		opts = append(opts, emailnotifier.OptionAuth(conf.Username, password))
+	}
+
+	if conf.SubjectPrefix != "" {
+		opts = append(opts, emailnotifier.OptionSubjectPrefix(conf.SubjectPrefix))
+	}
+
+	if conf.DigestInterval > 0 {
+		opts = append(opts, emailnotifier.OptionDigest(conf.DigestInterval.Duration()))
+	}
+
+	if conf.QueueSize > 0 {
+		opts = append(opts, emailnotifier.OptionQueueSize(conf.QueueSize))
+	}
+
+	addr := net.JoinHostPort(conf.Host, strconv.Itoa(port))
+	log.Infof("adding email notifier: %s -> %v", addr, conf.To)
+	environ.addSwitchedNotifier(emailnotifier.New(ctx, addr, conf.From, conf.To, opts...), conf.Switches)
 	return nil
 }
 
@@ -819,6 +1067,33 @@ func (environ *Environment) setupInteraction(persistence service.PersistenceServ
 	return nil
 }
 
+// setupAccessControl enables the role-based authorization of the interact commands if any user is configured
+func (environ *Environment) setupAccessControl(conf *InteractionConfig) error {
+	if conf == nil || len(conf.Users) == 0 {
+		return nil
+	}
+
+	ac, err := interact.NewAccessControl(conf.Users, conf.Commands)
+	if err != nil {
+		return err
+	}
+
+	ac.AuditLoggers = append(ac.AuditLoggers, &interact.LogAuditLogger{})
+
+	if len(conf.AuditLogFile) > 0 {
+		fileLogger, err := interact.NewFileAuditLogger(conf.AuditLogFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open the interact audit log file %s", conf.AuditLogFile)
+		}
+
+		ac.AuditLoggers = append(ac.AuditLoggers, fileLogger)
+	}
+
+	log.Infof("interact role-based authorization is enabled with %d users", len(ac.Users))
+	interact.SetAccessControl(ac)
+	return nil
+}
+
 func (environ *Environment) getAuthStore(persistence service.PersistenceService) service.Store {
 	id := getAuthStoreID()
 	return persistence.NewStore("bbgo", "auth", id)
@@ -899,6 +1174,120 @@ func (environ *Environment) setupSlack(userConfig *Config, slackToken string, pe
 	}
 }
 
+func (environ *Environment) setupDiscord(userConfig *Config, discordBotToken string, persistence service.PersistenceService) {
+	conf := userConfig.Notifications.Discord
+	if conf == nil {
+		return
+	}
+
+	log.Debugf("adding discord notifier with default channel: %s", conf.DefaultChannel)
+
+	var client = discordapi.NewClient(discordBotToken)
+
+	var notifierOpts = []discordnotifier.NotifyOption{
+		discordnotifier.OptionChannelRouter(environ.discordChannelRouter(conf)),
+	}
+
+	if conf.QueueSize > 0 {
+		notifierOpts = append(notifierOpts, discordnotifier.OptionQueueSize(conf.QueueSize))
+	}
+
+	var notifier = discordnotifier.New(client, conf.DefaultChannel, notifierOpts...)
+	Notification.AddNotifier(notifier)
+
+	if conf.ErrorChannel != "" {
+		log.Debugf("found discord error channel configured, setting up log hook...")
+		log.AddHook(discordnotifier.NewLogHook(notifier, conf.ErrorChannel))
+	}
+
+	if !conf.EnableInteraction {
+		return
+	}
+
+	var messenger = interact.NewDiscord(client, discordapi.NewGateway(discordBotToken))
+
+	var sessions = interact.DiscordSessionMap{}
+	var sessionStore = persistence.NewStore("bbgo", "discord")
+	if err := sessionStore.Load(&sessions); err != nil {
+		if err != service.ErrPersistenceNotExists {
+			log.WithError(err).Errorf("unexpected persistence error")
+		}
+	} else {
+		messenger.RestoreSessions(sessions)
+	}
+
+	messenger.OnAuthorized(func(userSession *interact.DiscordSession) {
+		log.Infof("user session %s got authorized, saving discord sessions...", userSession.User.ID)
+		if err := sessionStore.Save(messenger.Sessions()); err != nil {
+			log.WithError(err).Errorf("discord session save error")
+		}
+	})
+
+	interact.AddMessenger(messenger)
+}
+
+// discordChannelRouter routes the notification objects to the discord channels by the symbol and the session patterns,
+// the session of the object is resolved by the exchange name of the object.
+func (environ *Environment) discordChannelRouter(conf *DiscordNotification) discordnotifier.ChannelRouter {
+	symbolRouter := NewPatternChannelRouter(conf.SymbolChannels)
+	sessionRouter := NewPatternChannelRouter(conf.SessionChannels)
+
+	return func(obj interface{}) (string, bool) {
+		symbol, exchange := notificationSource(obj)
+		if symbol != "" {
+			if channel, ok := symbolRouter.Route(symbol); ok {
+				return channel, true
+			}
+		}
+
+		if exchange == "" {
+			return "", false
+		}
+
+		var sessionNames []string
+		for name, session := range environ.sessions {
+			if session.ExchangeName == exchange {
+				sessionNames = append(sessionNames, name)
+			}
+		}
+
+		sort.Strings(sessionNames)
+		for _, name := range sessionNames {
+			if channel, ok := sessionRouter.Route(name); ok {
+				return channel, true
+			}
+		}
+
+		return "", false
+	}
+}
+
+// notificationSource returns the symbol and the exchange of the notification object
+func notificationSource(obj interface{}) (symbol string, exchange types.ExchangeName) {
+	switch o := obj.(type) {
+	case types.Trade:
+		return o.Symbol, o.Exchange
+	case *types.Trade:
+		return o.Symbol, o.Exchange
+	case types.Order:
+		return o.Symbol, o.Exchange
+	case *types.Order:
+		return o.Symbol, o.Exchange
+	case types.SubmitOrder:
+		return o.Symbol, ""
+	case *types.SubmitOrder:
+		return o.Symbol, ""
+	case types.Profit:
+		return o.Symbol, o.Exchange
+	case *types.Profit:
+		return o.Symbol, o.Exchange
+	case *types.Position:
+		return o.Symbol, ""
+	}
+
+	return "", ""
+}
+
 func (environ *Environment) setupTelegram(
 	userConfig *Config, telegramBotToken string, persistence service.PersistenceService,
 ) error {
@@ -0,0 +1,273 @@
+package bbgo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultEquityTrackingInterval = 5 * time.Minute
+
+const defaultEquityMaxSnapshots = 1440
+
+// EquityTracker takes the net value snapshots of the exchange sessions periodically
+// and computes the high-water mark and the drawdowns of the equity curves.
+type EquityTracker struct {
+	Config *EquityTrackingConfig
+
+	// EquityService is optional, the snapshots are only kept in memory if it's nil
+	EquityService *service.EquityService
+
+	sessions map[string]*ExchangeSession
+
+	mu        sync.Mutex
+	trackers  map[string]*types.DrawdownTracker
+	snapshots map[string][]types.EquitySnapshot
+
+	// alerted stores the drawdown alert thresholds that were notified for each session
+	alerted map[string]map[int]bool
+}
+
+func NewEquityTracker(config *EquityTrackingConfig, sessions map[string]*ExchangeSession) *EquityTracker {
+	if config.Interval == 0 {
+		config.Interval = types.Duration(defaultEquityTrackingInterval)
+	}
+
+	if len(config.QuoteCurrency) == 0 {
+		config.QuoteCurrency = "USDT"
+	}
+
+	if config.MaxSnapshots == 0 {
+		config.MaxSnapshots = defaultEquityMaxSnapshots
+	}
+
+	trackedSessions := make(map[string]*ExchangeSession)
+	if len(config.Sessions) > 0 {
+		for _, name := range config.Sessions {
+			if session, ok := sessions[name]; ok {
+				trackedSessions[name] = session
+			} else {
+				log.Warnf("equity tracking: session %s is not defined", name)
+			}
+		}
+	} else {
+		for name, session := range sessions {
+			// public only sessions have no account balances
+			if session.PublicOnly {
+				continue
+			}
+
+			trackedSessions[name] = session
+		}
+	}
+
+	// sort the alert thresholds so that we can notify the deepest threshold crossed
+	sort.Slice(config.DrawdownAlerts, func(i, j int) bool {
+		return config.DrawdownAlerts[i].Compare(config.DrawdownAlerts[j]) < 0
+	})
+
+	return &EquityTracker{
+		Config:    config,
+		sessions:  trackedSessions,
+		trackers:  make(map[string]*types.DrawdownTracker),
+		snapshots: make(map[string][]types.EquitySnapshot),
+		alerted:   make(map[string]map[int]bool),
+	}
+}
+
+// Load restores the high-water marks and the max drawdowns from the latest snapshots in the database
+func (t *EquityTracker) Load(ctx context.Context) error {
+	if t.EquityService == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for name := range t.sessions {
+		last, err := t.EquityService.QueryLast(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if last == nil {
+			continue
+		}
+
+		t.trackers[name] = &types.DrawdownTracker{
+			HighWaterMark: last.HighWaterMark,
+			Drawdown:      last.Drawdown,
+			MaxDrawdown:   last.MaxDrawdown,
+		}
+	}
+
+	return nil
+}
+
+func (t *EquityTracker) Run(ctx context.Context) {
+	t.snapshotAll(ctx)
+
+	ticker := time.NewTicker(t.Config.Interval.Duration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			t.snapshotAll(ctx)
+		}
+	}
+}
+
+func (t *EquityTracker) snapshotAll(ctx context.Context) {
+	for name, session := range t.sessions {
+		if _, err := t.Snapshot(ctx, session); err != nil {
+			log.WithError(err).Errorf("[%s] unable to take the equity snapshot", name)
+		}
+	}
+}
+
+// Snapshot evaluates the net value of the given session, updates the drawdowns and records the snapshot
+func (t *EquityTracker) Snapshot(ctx context.Context, session *ExchangeSession) (*types.EquitySnapshot, error) {
+	if _, err := session.UpdateAccount(ctx); err != nil {
+		return nil, err
+	}
+
+	priceSolver := pricesolver.NewGraphPriceSolver(session.Markets(), 0)
+	calculator := NewAccountValueCalculator(session, priceSolver, t.Config.QuoteCurrency)
+	if err := calculator.UpdatePrices(ctx); err != nil {
+		return nil, err
+	}
+
+	netValue := calculator.NetValue()
+	snapshot := t.record(session.Name, session.ExchangeName, time.Now(), netValue)
+
+	if t.EquityService != nil {
+		if err := t.EquityService.Insert(*snapshot); err != nil {
+			return snapshot, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (t *EquityTracker) record(
+	sessionName string, exchangeName types.ExchangeName, now time.Time, netValue fixedpoint.Value,
+) *types.EquitySnapshot {
+	t.mu.Lock()
+	tracker, ok := t.trackers[sessionName]
+	if !ok {
+		tracker = &types.DrawdownTracker{}
+		t.trackers[sessionName] = tracker
+	}
+
+	tracker.Update(netValue)
+
+	snapshot := types.EquitySnapshot{
+		Session:       sessionName,
+		Exchange:      exchangeName,
+		Time:          types.Time(now),
+		QuoteCurrency: t.Config.QuoteCurrency,
+		NetValue:      netValue,
+		HighWaterMark: tracker.HighWaterMark,
+		Drawdown:      tracker.Drawdown,
+		MaxDrawdown:   tracker.MaxDrawdown,
+	}
+
+	snapshots := append(t.snapshots[sessionName], snapshot)
+	if len(snapshots) > t.Config.MaxSnapshots {
+		snapshots = snapshots[len(snapshots)-t.Config.MaxSnapshots:]
+	}
+	t.snapshots[sessionName] = snapshots
+
+	alert, crossed := t.checkDrawdownAlert(sessionName, tracker.Drawdown)
+	t.mu.Unlock()
+
+	labels := []string{sessionName, exchangeName.String(), t.Config.QuoteCurrency}
+	metricsEquityNetValue.WithLabelValues(labels...).Set(netValue.Float64())
+	metricsEquityHighWaterMark.WithLabelValues(labels...).Set(snapshot.HighWaterMark.Float64())
+	metricsEquityDrawdown.WithLabelValues(labels...).Set(snapshot.Drawdown.Float64())
+	metricsEquityMaxDrawdown.WithLabelValues(labels...).Set(snapshot.MaxDrawdown.Float64())
+
+	if crossed {
+		Notify("⚠️ %s drawdown %s exceeds %s, net value %s %s (high-water mark %s %s)",
+			sessionName,
+			snapshot.Drawdown.FormatPercentage(2),
+			alert.FormatPercentage(2),
+			netValue.String(), t.Config.QuoteCurrency,
+			snapshot.HighWaterMark.String(), t.Config.QuoteCurrency,
+			SeverityWarn)
+	}
+
+	return &snapshot
+}
+
+// checkDrawdownAlert returns the deepest threshold newly crossed by the drawdown,
+// the thresholds are re-armed once the drawdown recovers below them.
+func (t *EquityTracker) checkDrawdownAlert(sessionName string, drawdown fixedpoint.Value) (alert fixedpoint.Value, crossed bool) {
+	alerted, ok := t.alerted[sessionName]
+	if !ok {
+		alerted = make(map[int]bool)
+		t.alerted[sessionName] = alerted
+	}
+
+	for i, threshold := range t.Config.DrawdownAlerts {
+		if drawdown.Compare(threshold) < 0 {
+			delete(alerted, i)
+			continue
+		}
+
+		if !alerted[i] {
+			alerted[i] = true
+			alert = threshold
+			crossed = true
+		}
+	}
+
+	return alert, crossed
+}
+
+// Stats returns the current drawdown stats of the given session
+func (t *EquityTracker) Stats(sessionName string) (types.DrawdownTracker, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracker, ok := t.trackers[sessionName]
+	if !ok {
+		return types.DrawdownTracker{}, false
+	}
+
+	return *tracker, true
+}
+
+// Snapshots returns the snapshots kept in memory of the given session since the given time
+func (t *EquityTracker) Snapshots(sessionName string, since time.Time) []types.EquitySnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var snapshots []types.EquitySnapshot
+	for _, snapshot := range t.snapshots[sessionName] {
+		if snapshot.Time.Time().Before(since) {
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots
+}
+
+func (t *EquityTracker) IsTracking(sessionName string) bool {
+	_, ok := t.sessions[sessionName]
+	return ok
+}
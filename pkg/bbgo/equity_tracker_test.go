@@ -0,0 +1,68 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestEquityTracker_record(t *testing.T) {
+	tracker := NewEquityTracker(&EquityTrackingConfig{
+		DrawdownAlerts: []fixedpoint.Value{
+			fixedpoint.NewFromFloat(0.2),
+			fixedpoint.NewFromFloat(0.1),
+		},
+		MaxSnapshots: 3,
+	}, map[string]*ExchangeSession{})
+
+	assert.Equal(t, "USDT", tracker.Config.QuoteCurrency)
+	assert.Equal(t, "0.1", tracker.Config.DrawdownAlerts[0].String())
+
+	now := time.Now()
+	values := []float64{1000.0, 1200.0, 1080.0, 900.0, 1150.0}
+	for i, v := range values {
+		tracker.record("binance", types.ExchangeBinance, now.Add(time.Duration(i)*time.Minute), fixedpoint.NewFromFloat(v))
+	}
+
+	stats, ok := tracker.Stats("binance")
+	if assert.True(t, ok) {
+		assert.Equal(t, "1200", stats.HighWaterMark.String())
+		assert.Equal(t, "0.25", stats.MaxDrawdown.String())
+	}
+
+	snapshots := tracker.Snapshots("binance", now)
+	if assert.Len(t, snapshots, 3) {
+		assert.Equal(t, "1080", snapshots[0].NetValue.String())
+		assert.Equal(t, "0.1", snapshots[0].Drawdown.String())
+	}
+
+	// the drawdown recovered below all the thresholds, so all of them are re-armed
+	assert.Empty(t, tracker.alerted["binance"])
+}
+
+func TestEquityTracker_checkDrawdownAlert(t *testing.T) {
+	tracker := NewEquityTracker(&EquityTrackingConfig{
+		DrawdownAlerts: []fixedpoint.Value{
+			fixedpoint.NewFromFloat(0.05),
+			fixedpoint.NewFromFloat(0.1),
+		},
+	}, map[string]*ExchangeSession{})
+
+	alert, crossed := tracker.checkDrawdownAlert("binance", fixedpoint.NewFromFloat(0.12))
+	assert.True(t, crossed)
+	assert.Equal(t, "0.1", alert.String())
+
+	_, crossed = tracker.checkDrawdownAlert("binance", fixedpoint.NewFromFloat(0.11))
+	assert.False(t, crossed)
+
+	_, crossed = tracker.checkDrawdownAlert("binance", fixedpoint.NewFromFloat(0.07))
+	assert.False(t, crossed)
+
+	alert, crossed = tracker.checkDrawdownAlert("binance", fixedpoint.NewFromFloat(0.1))
+	assert.True(t, crossed)
+	assert.Equal(t, "0.1", alert.String())
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/indicator/v2/expr"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -18,6 +19,7 @@ type IndicatorSet struct {
 	// caches
 	kLines      map[types.Interval]*indicatorv2.KLineStream
 	closePrices map[types.Interval]*indicatorv2.PriceStream
+	bars        map[types.BarConfig]*indicatorv2.BarStream
 }
 
 func NewIndicatorSet(symbol string, stream types.Stream, store *MarketDataStore) *IndicatorSet {
@@ -28,6 +30,7 @@ func NewIndicatorSet(symbol string, stream types.Stream, store *MarketDataStore)
 
 		kLines:      make(map[types.Interval]*indicatorv2.KLineStream),
 		closePrices: make(map[types.Interval]*indicatorv2.PriceStream),
+		bars:        make(map[types.BarConfig]*indicatorv2.BarStream),
 	}
 }
 
@@ -47,6 +50,28 @@ func (i *IndicatorSet) KLines(interval types.Interval) *indicatorv2.KLineStream
 	return kLines
 }
 
+// Bars returns the alternative bar stream (tick, volume, dollar, range or renko bars) of the given config.
+// The bars are built from the market trades, so the market trade channel should be subscribed,
+// or from the klines of the configured interval, which are back-filled from the market data store.
+// In back-test, the market trades are replayed from the aggregated trades imported by `bbgo import-data --format binance-aggtrades`.
+func (i *IndicatorSet) Bars(config types.BarConfig) *indicatorv2.BarStream {
+	if bars, ok := i.bars[config]; ok {
+		return bars
+	}
+
+	bars := indicatorv2.Bars(i.stream, i.Symbol, config)
+	if config.Interval != "" {
+		if kLinesWindow, ok := i.store.KLinesOfInterval(config.Interval); ok {
+			bars.BackFillKLines(*kLinesWindow)
+		} else {
+			logrus.Warnf("market data store %s kline history not found, unable to backfill the %s bars", config.Interval, config)
+		}
+	}
+
+	i.bars[config] = bars
+	return bars
+}
+
 func (i *IndicatorSet) OPEN(interval types.Interval) *indicatorv2.PriceStream {
 	return indicatorv2.OpenPrices(i.KLines(interval))
 }
@@ -112,3 +137,139 @@ func (i *IndicatorSet) ATRP(interval types.Interval, window int) *indicatorv2.AT
 func (i *IndicatorSet) ADX(interval types.Interval, window int) *indicatorv2.ADXStream {
 	return indicatorv2.ADX(i.KLines(interval), window)
 }
+
+func (i *IndicatorSet) AD(interval types.Interval) *indicatorv2.ADStream {
+	return indicatorv2.AD(i.KLines(interval))
+}
+
+func (i *IndicatorSet) ALMA(iw types.IntervalWindow, offset float64, sigma int) *indicatorv2.ALMAStream {
+	return indicatorv2.ALMA(i.CLOSE(iw.Interval), iw.Window, offset, sigma)
+}
+
+func (i *IndicatorSet) DEMA(iw types.IntervalWindow) *indicatorv2.DEMAStream {
+	return indicatorv2.DEMA(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) TEMA(iw types.IntervalWindow) *indicatorv2.TEMAStream {
+	return indicatorv2.TEMA(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) TMA(iw types.IntervalWindow) *indicatorv2.TMAStream {
+	return indicatorv2.TMA(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) ZLEMA(iw types.IntervalWindow) *indicatorv2.ZLEMAStream {
+	return indicatorv2.ZLEMA(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) HULL(iw types.IntervalWindow) *indicatorv2.HULLStream {
+	return indicatorv2.HULL(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) GMA(iw types.IntervalWindow) *indicatorv2.GMAStream {
+	return indicatorv2.GMA(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) GHFilter(iw types.IntervalWindow) *indicatorv2.GHFilterStream {
+	return indicatorv2.GHFilter(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) KalmanFilter(iw types.IntervalWindow, additionalSmoothWindow int) *indicatorv2.KalmanFilterStream {
+	return indicatorv2.KalmanFilter(i.CLOSE(iw.Interval), iw.Window, additionalSmoothWindow)
+}
+
+func (i *IndicatorSet) SSF(iw types.IntervalWindow, poles int) *indicatorv2.SSFStream {
+	return indicatorv2.SSF(i.CLOSE(iw.Interval), iw.Window, poles)
+}
+
+func (i *IndicatorSet) VIDYA(iw types.IntervalWindow) *indicatorv2.VIDYAStream {
+	return indicatorv2.VIDYA(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) TSI(interval types.Interval, fastWindow, slowWindow int) *indicatorv2.TSIStream {
+	return indicatorv2.TSI(i.CLOSE(interval), fastWindow, slowWindow)
+}
+
+func (i *IndicatorSet) TILL(iw types.IntervalWindow, volumeFactor float64) *indicatorv2.TILLStream {
+	return indicatorv2.TILL(i.CLOSE(iw.Interval), iw.Window, volumeFactor)
+}
+
+func (i *IndicatorSet) FisherTransform(iw types.IntervalWindow) *indicatorv2.FisherTransformStream {
+	return indicatorv2.FisherTransform(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) Drift(iw types.IntervalWindow) *indicatorv2.DriftStream {
+	return indicatorv2.Drift(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) WeightedDrift(iw types.IntervalWindow) *indicatorv2.WeightedDriftStream {
+	return indicatorv2.WeightedDrift(i.KLines(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) WWMA(iw types.IntervalWindow) *indicatorv2.WWMAStream {
+	return indicatorv2.WWMA(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) VWMA(iw types.IntervalWindow) *indicatorv2.VWMAStream {
+	return indicatorv2.VWMA(i.KLines(iw.Interval), iw.Window)
+}
+
+// VWAP returns the volume weighted average price stream, the window 0 means the cumulative VWAP
+func (i *IndicatorSet) VWAP(iw types.IntervalWindow) *indicatorv2.VWAPStream {
+	return indicatorv2.VWAP(i.KLines(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) OBV(interval types.Interval) *indicatorv2.OBVStream {
+	return indicatorv2.OBV(i.KLines(interval))
+}
+
+func (i *IndicatorSet) EMV(iw types.IntervalWindow, scale float64) *indicatorv2.EMVStream {
+	return indicatorv2.EMV(i.KLines(iw.Interval), iw.Window, scale)
+}
+
+func (i *IndicatorSet) KlingerOscillator(interval types.Interval, fastWindow, slowWindow int) *indicatorv2.KlingerOscillatorStream {
+	return indicatorv2.KlingerOscillator(i.KLines(interval), fastWindow, slowWindow)
+}
+
+func (i *IndicatorSet) LinReg(iw types.IntervalWindow) *indicatorv2.LinRegStream {
+	return indicatorv2.LinReg(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) PSAR(iw types.IntervalWindow) *indicatorv2.PSARStream {
+	return indicatorv2.PSAR(i.KLines(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) DMI(iw types.IntervalWindow, adxSmoothing int) *indicatorv2.DMIStream {
+	return indicatorv2.DMI(i.KLines(iw.Interval), iw.Window, adxSmoothing)
+}
+
+func (i *IndicatorSet) Volatility(iw types.IntervalWindow) *indicatorv2.VolatilityStream {
+	return indicatorv2.Volatility(i.CLOSE(iw.Interval), iw.Window)
+}
+
+func (i *IndicatorSet) Supertrend(iw types.IntervalWindow, atrMultiplier float64) *indicatorv2.SupertrendStream {
+	return indicatorv2.Supertrend(i.KLines(iw.Interval), iw.Window, atrMultiplier)
+}
+
+func (i *IndicatorSet) PivotSupertrend(iw types.IntervalWindow, pivotWindow int, atrMultiplier float64) *indicatorv2.PivotSupertrendStream {
+	return indicatorv2.PivotSupertrend(i.KLines(iw.Interval), iw.Window, pivotWindow, atrMultiplier)
+}
+
+func (i *IndicatorSet) UTBotAlert(iw types.IntervalWindow, keyValue float64) *indicatorv2.UTBotAlertStream {
+	return indicatorv2.UTBotAlert(i.KLines(iw.Interval), iw.Window, keyValue)
+}
+
+func (i *IndicatorSet) Line(interval types.Interval, startIndex int, startValue float64, endIndex int, endValue float64) *indicatorv2.LineStream {
+	return indicatorv2.Line(i.KLines(interval), startIndex, startValue, endIndex, endValue)
+}
+
+func (i *IndicatorSet) VolumeProfile(iw types.IntervalWindow, delta float64) *indicatorv2.VolumeProfileStream {
+	return indicatorv2.VolumeProfile(i.KLines(iw.Interval), iw.Window, delta)
+}
+
+// Expr compiles the indicator expression on the klines of the given interval, for example:
+//
+//	cross_over(ema(close, 12), ema(close, 26)) and rsi(close, 14) < 70
+func (i *IndicatorSet) Expr(interval types.Interval, expression string) (*expr.Stream, error) {
+	return expr.Compile(expression, i.KLines(interval))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path"
 	"reflect"
+	"sync"
 
 	"github.com/c9s/bbgo/pkg/dynamic"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
@@ -24,6 +25,12 @@ type PositionReader interface {
 	CurrentPosition() *types.Position
 }
 
+// ActiveOrdersReader is implemented by the strategies that expose their active orders,
+// the strategy-scoped users can only cancel the active orders of the strategies they can access
+type ActiveOrdersReader interface {
+	ActiveOrders() *ActiveOrderBook
+}
+
 type closePositionContext struct {
 	signature  string
 	closer     PositionCloser
@@ -44,6 +51,12 @@ type CoreInteraction struct {
 	exchangeStrategies    map[string]SingleExchangeStrategy
 	closePositionContext  closePositionContext
 	modifyPositionContext modifyPositionContext
+
+	// orderEntryContexts and openOrdersContexts are keyed by the interact session ID,
+	// so that the users can enter the orders concurrently
+	orderEntryContexts map[string]*orderEntryContext
+	openOrdersContexts map[string]*openOrdersContext
+	orderContextMu     sync.Mutex
 }
 
 func NewCoreInteraction(environment *Environment, trader *Trader) *CoreInteraction {
@@ -51,6 +64,8 @@ func NewCoreInteraction(environment *Environment, trader *Trader) *CoreInteracti
 		environment:        environment,
 		trader:             trader,
 		exchangeStrategies: make(map[string]SingleExchangeStrategy),
+		orderEntryContexts: make(map[string]*orderEntryContext),
+		openOrdersContexts: make(map[string]*openOrdersContext),
 	}
 }
 
@@ -92,45 +107,35 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
-	i.PrivateCommand("/balances", "Show balances", func(reply interact.Reply) error {
+	i.PrivateCommand("/balances", "Show balances", func(sessionName string, reply interact.Reply, session interact.Session) error {
+		// the session can be given as the argument, e.g., /balances binance
+		if len(sessionName) > 0 {
+			// return to the origin state since the session selection is not needed
+			session.SetState(session.GetOriginState())
+			return it.replyBalances(sessionName, reply)
+		}
+
 		reply.Message("Please select an exchange session")
 		for name := range it.environment.Sessions() {
 			reply.AddButton(name, "session", name)
 		}
 		return nil
 	}).Next(func(sessionName string, reply interact.Reply) error {
-		session, ok := it.environment.Session(sessionName)
-		if !ok {
-			reply.Message(fmt.Sprintf("Session %s not found", sessionName))
-			return fmt.Errorf("session %s not found", sessionName)
-		}
-
-		message := "Your balances\n"
-		balances := session.GetAccount().Balances()
-		for _, balance := range balances {
-			if balance.Total().IsZero() {
-				continue
-			}
-
-			message += "- " + balance.String() + "\n"
-		}
-
-		reply.Message(message)
-		return nil
+		return it.replyBalances(sessionName, reply)
 	})
 
-	i.PrivateCommand("/position", "Show Position", func(reply interact.Reply) error {
+	i.PrivateCommand("/position", "Show Position", func(reply interact.Reply, session interact.Session) error {
 		// it.trader.exchangeStrategies
 		// send symbol options
-		if strategies, err := filterStrategiesByInterface(it.exchangeStrategies, (*PositionReader)(nil)); err == nil && len(strategies) > 0 {
+		if strategies, err := filterStrategiesByInterface(it.scopedStrategies(i, session), (*PositionReader)(nil)); err == nil && len(strategies) > 0 {
 			reply.AddMultipleButtons(generateStrategyButtonsForm(strategies))
 			reply.Message("Please choose one strategy")
 		} else {
 			reply.Message("No any strategy supports PositionReader")
 		}
 		return nil
-	}).Cycle(func(signature string, reply interact.Reply) error {
-		strategy, ok := it.exchangeStrategies[signature]
+	}).Cycle(func(signature string, reply interact.Reply, session interact.Session) error {
+		strategy, ok := it.findStrategy(i, session, signature)
 		if !ok {
 			reply.Message("Strategy not found")
 			return fmt.Errorf("strategy %s not found", signature)
@@ -158,8 +163,8 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
-	i.PrivateCommand("/resetposition", "Reset position", func(reply interact.Reply) error {
-		strategies, err := filterStrategies(it.exchangeStrategies, func(s SingleExchangeStrategy) bool {
+	i.PrivateCommand("/resetposition", "Reset position", func(reply interact.Reply, session interact.Session) error {
+		strategies, err := filterStrategies(it.scopedStrategies(i, session), func(s SingleExchangeStrategy) bool {
 			return testInterface(s, (*PositionResetter)(nil)) || hasTypeField(s, &types.Position{})
 		})
 
@@ -170,8 +175,8 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 			reply.Message("No strategy supports PositionResetter interface")
 		}
 		return nil
-	}).Next(func(signature string, reply interact.Reply) error {
-		strategy, ok := it.exchangeStrategies[signature]
+	}).Next(func(signature string, reply interact.Reply, session interact.Session) error {
+		strategy, ok := it.findStrategy(i, session, signature)
 		if !ok {
 			reply.Message("Strategy not found")
 			return fmt.Errorf("strategy %s not found", signature)
@@ -201,18 +206,18 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return err
 	})
 
-	i.PrivateCommand("/closeposition", "Close position", func(reply interact.Reply) error {
+	i.PrivateCommand("/closeposition", "Close position", func(reply interact.Reply, session interact.Session) error {
 		// it.trader.exchangeStrategies
 		// send symbol options
-		if strategies, err := filterStrategiesByInterface(it.exchangeStrategies, (*PositionCloser)(nil)); err == nil && len(strategies) > 0 {
+		if strategies, err := filterStrategiesByInterface(it.scopedStrategies(i, session), (*PositionCloser)(nil)); err == nil && len(strategies) > 0 {
 			reply.AddMultipleButtons(generateStrategyButtonsForm(strategies))
 			reply.Message("Please choose one strategy")
 		} else {
 			reply.Message("No strategy supports PositionCloser interface")
 		}
 		return nil
-	}).Next(func(signature string, reply interact.Reply) error {
-		strategy, ok := it.exchangeStrategies[signature]
+	}).Next(func(signature string, reply interact.Reply, session interact.Session) error {
+		strategy, ok := it.findStrategy(i, session, signature)
 		if !ok {
 			reply.Message("Strategy not found")
 			return fmt.Errorf("strategy %s not found", signature)
@@ -270,24 +275,24 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
-	i.PrivateCommand("/status", "Strategy Status", func(reply interact.Reply) error {
+	i.PrivateCommand("/status", "Strategy Status", func(reply interact.Reply, session interact.Session) error {
 		// it.trader.exchangeStrategies
 		// send symbol options
-		if strategies, err := filterStrategiesByInterface(it.exchangeStrategies, (*StrategyStatusReader)(nil)); err == nil && len(strategies) > 0 {
+		if strategies, err := filterStrategiesByInterface(it.scopedStrategies(i, session), (*StrategyStatusReader)(nil)); err == nil && len(strategies) > 0 {
 			reply.AddMultipleButtons(generateStrategyButtonsForm(strategies))
 			reply.Message("Please choose a strategy")
 		} else {
 			reply.Message("No strategy supports StrategyStatusReader")
 		}
 		return nil
-	}).Next(func(signature string, reply interact.Reply) error {
+	}).Next(func(signature string, reply interact.Reply, session interact.Session) error {
 		defer func() {
 			if kc, ok := reply.(interact.KeyboardController); ok {
 				kc.RemoveKeyboard()
 			}
 		}()
 
-		strategy, ok := it.exchangeStrategies[signature]
+		strategy, ok := it.findStrategy(i, session, signature)
 		if !ok {
 			reply.Message("Strategy not found")
 			return fmt.Errorf("strategy %s not found", signature)
@@ -310,24 +315,24 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
-	i.PrivateCommand("/suspend", "Suspend Strategy", func(reply interact.Reply) error {
+	i.PrivateCommand("/suspend", "Suspend Strategy", func(reply interact.Reply, session interact.Session) error {
 		// it.trader.exchangeStrategies
 		// send symbol options
-		if strategies, err := filterStrategiesByInterface(it.exchangeStrategies, (*StrategyToggler)(nil)); err == nil && len(strategies) > 0 {
+		if strategies, err := filterStrategiesByInterface(it.scopedStrategies(i, session), (*StrategyToggler)(nil)); err == nil && len(strategies) > 0 {
 			reply.AddMultipleButtons(generateStrategyButtonsForm(strategies))
 			reply.Message("Please choose one strategy")
 		} else {
 			reply.Message("No strategy supports StrategyToggler")
 		}
 		return nil
-	}).Next(func(signature string, reply interact.Reply) error {
+	}).Next(func(signature string, reply interact.Reply, session interact.Session) error {
 		defer func() {
 			if kc, ok := reply.(interact.KeyboardController); ok {
 				kc.RemoveKeyboard()
 			}
 		}()
 
-		strategy, ok := it.exchangeStrategies[signature]
+		strategy, ok := it.findStrategy(i, session, signature)
 		if !ok {
 			reply.Message("Strategy not found")
 			return fmt.Errorf("strategy %s not found", signature)
@@ -354,24 +359,24 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
-	i.PrivateCommand("/resume", "Resume Strategy", func(reply interact.Reply) error {
+	i.PrivateCommand("/resume", "Resume Strategy", func(reply interact.Reply, session interact.Session) error {
 		// it.trader.exchangeStrategies
 		// send symbol options
-		if strategies, err := filterStrategiesByInterface(it.exchangeStrategies, (*StrategyToggler)(nil)); err == nil && len(strategies) > 0 {
+		if strategies, err := filterStrategiesByInterface(it.scopedStrategies(i, session), (*StrategyToggler)(nil)); err == nil && len(strategies) > 0 {
 			reply.AddMultipleButtons(generateStrategyButtonsForm(strategies))
 			reply.Message("Please choose one strategy")
 		} else {
 			reply.Message("No strategy supports StrategyToggler")
 		}
 		return nil
-	}).Next(func(signature string, reply interact.Reply) error {
+	}).Next(func(signature string, reply interact.Reply, session interact.Session) error {
 		defer func() {
 			if kc, ok := reply.(interact.KeyboardController); ok {
 				kc.RemoveKeyboard()
 			}
 		}()
 
-		strategy, ok := it.exchangeStrategies[signature]
+		strategy, ok := it.findStrategy(i, session, signature)
 		if !ok {
 			reply.Message("Strategy not found")
 			return fmt.Errorf("strategy %s not found", signature)
@@ -398,18 +403,18 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
-	i.PrivateCommand("/emergencystop", "Emergency Stop", func(reply interact.Reply) error {
+	i.PrivateCommand("/emergencystop", "Emergency Stop", func(reply interact.Reply, session interact.Session) error {
 		// it.trader.exchangeStrategies
 		// send symbol options
-		if strategies, err := filterStrategiesByInterface(it.exchangeStrategies, (*EmergencyStopper)(nil)); err == nil && len(strategies) > 0 {
+		if strategies, err := filterStrategiesByInterface(it.scopedStrategies(i, session), (*EmergencyStopper)(nil)); err == nil && len(strategies) > 0 {
 			reply.AddMultipleButtons(generateStrategyButtonsForm(strategies))
 			reply.Message("Please choose one strategy")
 		} else {
 			reply.Message("No strategy supports EmergencyStopper")
 		}
 		return nil
-	}).Next(func(signature string, reply interact.Reply) error {
-		strategy, ok := it.exchangeStrategies[signature]
+	}).Next(func(signature string, reply interact.Reply, session interact.Session) error {
+		strategy, ok := it.findStrategy(i, session, signature)
 		if !ok {
 			reply.Message("Strategy not found")
 			return fmt.Errorf("strategy %s not found", signature)
@@ -435,18 +440,18 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 	})
 
 	// Position updater
-	i.PrivateCommand("/modifyposition", "Modify Strategy Position", func(reply interact.Reply) error {
+	i.PrivateCommand("/modifyposition", "Modify Strategy Position", func(reply interact.Reply, session interact.Session) error {
 		// it.trader.exchangeStrategies
 		// send symbol options
-		if strategies, err := filterStrategiesByField(it.exchangeStrategies, "Position", reflect.TypeOf(&types.Position{})); err == nil && len(strategies) > 0 {
+		if strategies, err := filterStrategiesByField(it.scopedStrategies(i, session), "Position", reflect.TypeOf(&types.Position{})); err == nil && len(strategies) > 0 {
 			reply.AddMultipleButtons(generateStrategyButtonsForm(strategies))
 			reply.Message("Please choose one strategy")
 		} else {
 			reply.Message("No strategy supports Position Modify")
 		}
 		return nil
-	}).Next(func(signature string, reply interact.Reply) error {
-		strategy, ok := it.exchangeStrategies[signature]
+	}).Next(func(signature string, reply interact.Reply, session interact.Session) error {
+		strategy, ok := it.findStrategy(i, session, signature)
 		if !ok {
 			reply.Message("Strategy not found")
 			return fmt.Errorf("strategy %s not found", signature)
@@ -507,6 +512,51 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		reply.Message(fmt.Sprintf("Position of strategy %s modified.", it.modifyPositionContext.signature))
 		return nil
 	})
+
+	it.orderCommands(i)
+}
+
+// scopedStrategies returns the strategies that the user of the session can access
+func (it *CoreInteraction) scopedStrategies(i *interact.Interact, session interact.Session) map[string]SingleExchangeStrategy {
+	strategies := make(map[string]SingleExchangeStrategy)
+	for signature, strategy := range it.exchangeStrategies {
+		if i.CanAccessStrategy(session, signature) {
+			strategies[signature] = strategy
+		}
+	}
+
+	return strategies
+}
+
+// findStrategy returns the strategy of the signature if the user of the session can access it
+func (it *CoreInteraction) findStrategy(i *interact.Interact, session interact.Session, signature string) (SingleExchangeStrategy, bool) {
+	if !i.CanAccessStrategy(session, signature) {
+		return nil, false
+	}
+
+	strategy, ok := it.exchangeStrategies[signature]
+	return strategy, ok
+}
+
+func (it *CoreInteraction) replyBalances(sessionName string, reply interact.Reply) error {
+	session, ok := it.environment.Session(sessionName)
+	if !ok {
+		reply.Message(fmt.Sprintf("Session %s not found", sessionName))
+		return fmt.Errorf("session %s not found", sessionName)
+	}
+
+	message := "Your balances\n"
+	balances := session.GetAccount().Balances()
+	for _, balance := range balances {
+		if balance.Total().IsZero() {
+			continue
+		}
+
+		message += "- " + balance.String() + "\n"
+	}
+
+	reply.Message(message)
+	return nil
 }
 
 func (it *CoreInteraction) Initialize() error {
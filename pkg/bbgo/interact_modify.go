@@ -20,13 +20,20 @@ func RegisterModifier(s interface{}) {
 	var currVal interface{}
 	var mapping map[string]string
 	// currently we only allow users to modify the first layer of fields
-	RegisterCommand("/modify", "Modify config", func(reply interact.Reply) {
+	RegisterCommand("/modify", "Modify config", func(reply interact.Reply, session interact.Session) error {
+		// the command is not bound to a strategy signature, so the users that are restricted to some strategies can not use it
+		if interact.Default().IsStrategyScoped(session) {
+			reply.Message("Permission denied, /modify is not allowed for the users that are restricted to some strategies")
+			return fmt.Errorf("/modify is not allowed for the strategy-scoped users")
+		}
+
 		reply.Message("Please choose the field name in config to modify:")
 		mapping = make(map[string]string)
 		dynamic.GetModifiableFields(val, func(tagName, name string) {
 			mapping[tagName] = name
 			reply.AddButton(tagName, tagName, tagName)
 		})
+		return nil
 	}).Next(func(target string, reply interact.Reply) error {
 		targetName = mapping[target]
 		field, ok := dynamic.GetModifiableField(val, targetName)
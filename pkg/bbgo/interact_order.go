@@ -0,0 +1,465 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/interact"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// manualOrderTag is the tag of the orders submitted from the interact commands
+const manualOrderTag = "interact"
+
+type orderEntryContext struct {
+	session *ExchangeSession
+	side    types.SideType
+	symbol  string
+
+	// price is zero for the market order
+	price fixedpoint.Value
+	order types.SubmitOrder
+}
+
+type openOrdersContext struct {
+	session *ExchangeSession
+	symbol  string
+	orders  []types.Order
+}
+
+// orderCommands registers the manual trading commands, they are private commands so the user needs to be authorized first
+func (it *CoreInteraction) orderCommands(i *interact.Interact) {
+	it.orderEntryCommand(i, "/buy", "Submit a buy order", types.SideTypeBuy)
+	it.orderEntryCommand(i, "/sell", "Submit a sell order", types.SideTypeSell)
+
+	i.PrivateCommand("/orders", "Show open orders", func(reply interact.Reply) error {
+		it.replyTradingSessions(reply)
+		return nil
+	}).Next(func(sessionName string, reply interact.Reply, is interact.Session) error {
+		session, err := it.selectTradingSession(sessionName, reply)
+		if err != nil {
+			return err
+		}
+
+		it.setOpenOrdersContext(is, &openOrdersContext{session: session})
+		reply.Message("Please enter the symbol")
+		return nil
+	}).Next(func(symbol string, reply interact.Reply, is interact.Session) error {
+		ctx, err := it.getOpenOrdersContext(is, reply)
+		if err != nil {
+			return err
+		}
+
+		session := ctx.session
+		symbol = strings.ToUpper(symbol)
+		if _, ok := session.Market(symbol); !ok {
+			reply.Message(fmt.Sprintf("Market %s not found in session %s", symbol, session.Name))
+			return fmt.Errorf("market %s not found", symbol)
+		}
+
+		orders, err := session.Exchange.QueryOpenOrders(context.Background(), symbol)
+		if err != nil {
+			reply.Message(fmt.Sprintf("Failed to query the open orders, %s", err.Error()))
+			return err
+		}
+
+		orders = it.filterScopedOrders(i, is, session.Name, orders)
+
+		if len(orders) == 0 {
+			reply.Message(fmt.Sprintf("No open orders of %s in session %s", symbol, session.Name))
+			return nil
+		}
+
+		reply.Message(formatOpenOrders(orders))
+		return nil
+	})
+
+	i.PrivateCommand("/cancel", "Cancel open orders", func(reply interact.Reply) error {
+		it.replyTradingSessions(reply)
+		return nil
+	}).Next(func(sessionName string, reply interact.Reply, is interact.Session) error {
+		session, err := it.selectTradingSession(sessionName, reply)
+		if err != nil {
+			return err
+		}
+
+		it.setOpenOrdersContext(is, &openOrdersContext{session: session})
+		reply.Message("Please enter the symbol")
+		return nil
+	}).Next(func(symbol string, reply interact.Reply, is interact.Session) error {
+		ctx, err := it.getOpenOrdersContext(is, reply)
+		if err != nil {
+			return err
+		}
+
+		session := ctx.session
+		symbol = strings.ToUpper(symbol)
+		if _, ok := session.Market(symbol); !ok {
+			reply.Message(fmt.Sprintf("Market %s not found in session %s", symbol, session.Name))
+			return fmt.Errorf("market %s not found", symbol)
+		}
+
+		orders, err := session.Exchange.QueryOpenOrders(context.Background(), symbol)
+		if err != nil {
+			reply.Message(fmt.Sprintf("Failed to query the open orders, %s", err.Error()))
+			return err
+		}
+
+		orders = it.filterScopedOrders(i, is, session.Name, orders)
+
+		if len(orders) == 0 {
+			reply.Message(fmt.Sprintf("No open orders of %s in session %s", symbol, session.Name))
+			return fmt.Errorf("no open orders of %s", symbol)
+		}
+
+		ctx.symbol = symbol
+		ctx.orders = orders
+
+		reply.Message(formatOpenOrders(orders) + "\nPlease choose or enter the order ID to cancel")
+		for _, order := range orders {
+			id := strconv.FormatUint(order.OrderID, 10)
+			reply.AddButton(id, "order", id)
+		}
+		reply.AddButton("all", "order", "all")
+		return nil
+	}).Next(func(orderID string, reply interact.Reply, is interact.Session) error {
+		ctx, err := it.getOpenOrdersContext(is, reply)
+		if err != nil {
+			return err
+		}
+
+		var toCancel []types.Order
+		if orderID == "all" {
+			toCancel = ctx.orders
+		} else {
+			for _, order := range ctx.orders {
+				if strconv.FormatUint(order.OrderID, 10) == orderID {
+					toCancel = append(toCancel, order)
+				}
+			}
+		}
+
+		if len(toCancel) == 0 {
+			reply.Message(fmt.Sprintf("Order %s is not an open order of %s", orderID, ctx.symbol))
+			return fmt.Errorf("order %s not found", orderID)
+		}
+
+		if kc, ok := reply.(interact.KeyboardController); ok {
+			kc.RemoveKeyboard()
+		}
+
+		it.deleteOpenOrdersContext(is)
+
+		if err := ctx.session.Exchange.CancelOrders(context.Background(), toCancel...); err != nil {
+			reply.Message(fmt.Sprintf("Failed to cancel the orders, %s", err.Error()))
+			return err
+		}
+
+		reply.Message(fmt.Sprintf("%d %s orders canceled.", len(toCancel), ctx.symbol))
+		return nil
+	})
+}
+
+func (it *CoreInteraction) orderEntryCommand(i *interact.Interact, command, desc string, side types.SideType) {
+	i.PrivateCommand(command, desc, func(reply interact.Reply, is interact.Session) error {
+		// the manual orders are not bound to any strategy, so the users that are restricted to some strategies can not submit them
+		if i.IsStrategyScoped(is) {
+			reply.Message(fmt.Sprintf("Permission denied, %s is not allowed for the users that are restricted to some strategies", command))
+			return fmt.Errorf("%s is not allowed for the strategy-scoped users", command)
+		}
+
+		it.setOrderEntryContext(is, &orderEntryContext{side: side})
+		it.replyTradingSessions(reply)
+		return nil
+	}).Next(func(sessionName string, reply interact.Reply, is interact.Session) error {
+		ctx, err := it.getOrderEntryContext(is, reply)
+		if err != nil {
+			return err
+		}
+
+		session, err := it.selectTradingSession(sessionName, reply)
+		if err != nil {
+			return err
+		}
+
+		ctx.session = session
+		reply.Message("Please enter the symbol")
+		return nil
+	}).Next(func(symbol string, reply interact.Reply, is interact.Session) error {
+		ctx, err := it.getOrderEntryContext(is, reply)
+		if err != nil {
+			return err
+		}
+
+		session := ctx.session
+		symbol = strings.ToUpper(symbol)
+		if _, ok := session.Market(symbol); !ok {
+			reply.Message(fmt.Sprintf("Market %s not found in session %s, please enter the symbol again", symbol, session.Name))
+			return fmt.Errorf("market %s not found", symbol)
+		}
+
+		ctx.symbol = symbol
+
+		message := "Please enter the limit price, or choose market for the market order"
+		if ticker, err := session.Exchange.QueryTicker(context.Background(), symbol); err == nil {
+			message = fmt.Sprintf("%s bid %s / ask %s / last %s\n", symbol, ticker.Buy.String(), ticker.Sell.String(), ticker.Last.String()) + message
+		}
+
+		reply.Message(message)
+		reply.AddButton("market", "price", "market")
+		return nil
+	}).Next(func(priceStr string, reply interact.Reply, is interact.Session) error {
+		ctx, err := it.getOrderEntryContext(is, reply)
+		if err != nil {
+			return err
+		}
+
+		price := fixedpoint.Zero
+		if priceStr != "market" {
+			price, err = fixedpoint.NewFromString(priceStr)
+			if err != nil || price.Sign() <= 0 {
+				reply.Message(fmt.Sprintf("%q is not a valid price, please enter the price again", priceStr))
+				return fmt.Errorf("invalid price %q", priceStr)
+			}
+		}
+
+		ctx.price = price
+
+		if kc, ok := reply.(interact.KeyboardController); ok {
+			kc.RemoveKeyboard()
+		}
+
+		reply.Message("Please enter the quantity")
+		return nil
+	}).Next(func(quantityStr string, reply interact.Reply, is interact.Session) error {
+		ctx, err := it.getOrderEntryContext(is, reply)
+		if err != nil {
+			return err
+		}
+
+		quantity, err := fixedpoint.NewFromString(quantityStr)
+		if err != nil {
+			reply.Message(fmt.Sprintf("%q is not a valid quantity, please enter the quantity again", quantityStr))
+			return err
+		}
+
+		submitOrder := types.SubmitOrder{
+			Symbol:   ctx.symbol,
+			Side:     ctx.side,
+			Type:     types.OrderTypeLimit,
+			Price:    ctx.price,
+			Quantity: quantity,
+			Tag:      manualOrderTag,
+		}
+
+		// the last price is used for checking the min notional of the market order
+		refPrice := ctx.price
+		if ctx.price.IsZero() {
+			submitOrder.Type = types.OrderTypeMarket
+
+			ticker, err := ctx.session.Exchange.QueryTicker(context.Background(), ctx.symbol)
+			if err != nil {
+				reply.Message(fmt.Sprintf("Failed to query the ticker of %s, %s", ctx.symbol, err.Error()))
+				return err
+			}
+
+			refPrice = ticker.Last
+		}
+
+		submitOrder, err = formatManualOrder(ctx.session, submitOrder, refPrice)
+		if err != nil {
+			reply.Message(fmt.Sprintf("Invalid order, %s, please enter the quantity again", err.Error()))
+			return err
+		}
+
+		ctx.order = submitOrder
+
+		reply.Message(fmt.Sprintf("Please confirm the order on %s:\n%s", ctx.session.Name, submitOrder.PlainText()))
+		reply.AddButton("Confirm", "confirm", "yes")
+		reply.AddButton("Cancel", "confirm", "no")
+		return nil
+	}).Next(func(confirm string, reply interact.Reply, is interact.Session) error {
+		if kc, ok := reply.(interact.KeyboardController); ok {
+			kc.RemoveKeyboard()
+		}
+
+		ctx, err := it.getOrderEntryContext(is, reply)
+		if err != nil {
+			return err
+		}
+
+		// the context is consumed by the confirmation, so that the order can not be confirmed twice
+		it.deleteOrderEntryContext(is)
+
+		if confirm != "yes" {
+			reply.Message("Order is canceled")
+			return nil
+		}
+
+		createdOrder, err := ctx.session.Exchange.SubmitOrder(context.Background(), ctx.order)
+		if err != nil {
+			reply.Message(fmt.Sprintf("Failed to submit the order, %s", err.Error()))
+			return err
+		}
+
+		reply.Message(fmt.Sprintf("Order submitted: %s", createdOrder.PlainText()))
+		return nil
+	})
+}
+
+// filterScopedOrders returns the orders that are the active orders of the strategies in the session
+// that the user can access, the orders are not filtered if the user is not restricted to some strategies.
+func (it *CoreInteraction) filterScopedOrders(i *interact.Interact, is interact.Session, sessionName string, orders []types.Order) []types.Order {
+	if !i.IsStrategyScoped(is) {
+		return orders
+	}
+
+	var books []*ActiveOrderBook
+	for signature, strategy := range it.scopedStrategies(i, is) {
+		if !strings.HasPrefix(signature, sessionName+".") {
+			continue
+		}
+
+		if reader, ok := strategy.(ActiveOrdersReader); ok {
+			if book := reader.ActiveOrders(); book != nil {
+				books = append(books, book)
+			}
+		}
+	}
+
+	var scoped []types.Order
+	for _, order := range orders {
+		for _, book := range books {
+			if book.Exists(order) {
+				scoped = append(scoped, order)
+				break
+			}
+		}
+	}
+
+	return scoped
+}
+
+func (it *CoreInteraction) setOrderEntryContext(session interact.Session, ctx *orderEntryContext) {
+	it.orderContextMu.Lock()
+	it.orderEntryContexts[session.ID()] = ctx
+	it.orderContextMu.Unlock()
+}
+
+func (it *CoreInteraction) deleteOrderEntryContext(session interact.Session) {
+	it.orderContextMu.Lock()
+	delete(it.orderEntryContexts, session.ID())
+	it.orderContextMu.Unlock()
+}
+
+// getOrderEntryContext returns the order entry context of the session,
+// the context is missing if the command is not started from the beginning in this session.
+func (it *CoreInteraction) getOrderEntryContext(session interact.Session, reply interact.Reply) (*orderEntryContext, error) {
+	it.orderContextMu.Lock()
+	ctx, ok := it.orderEntryContexts[session.ID()]
+	it.orderContextMu.Unlock()
+
+	if !ok {
+		reply.Message("The order entry is expired, please start the command again")
+		return nil, fmt.Errorf("order entry context of session %s not found", session.ID())
+	}
+
+	return ctx, nil
+}
+
+func (it *CoreInteraction) setOpenOrdersContext(session interact.Session, ctx *openOrdersContext) {
+	it.orderContextMu.Lock()
+	it.openOrdersContexts[session.ID()] = ctx
+	it.orderContextMu.Unlock()
+}
+
+func (it *CoreInteraction) deleteOpenOrdersContext(session interact.Session) {
+	it.orderContextMu.Lock()
+	delete(it.openOrdersContexts, session.ID())
+	it.orderContextMu.Unlock()
+}
+
+func (it *CoreInteraction) getOpenOrdersContext(session interact.Session, reply interact.Reply) (*openOrdersContext, error) {
+	it.orderContextMu.Lock()
+	ctx, ok := it.openOrdersContexts[session.ID()]
+	it.orderContextMu.Unlock()
+
+	if !ok || ctx.session == nil {
+		reply.Message("The command is expired, please start the command again")
+		return nil, fmt.Errorf("open orders context of session %s not found", session.ID())
+	}
+
+	return ctx, nil
+}
+
+// replyTradingSessions asks the user to choose one of the sessions that have the private user data
+func (it *CoreInteraction) replyTradingSessions(reply interact.Reply) {
+	var names []string
+	for name, session := range it.environment.Sessions() {
+		if session.PublicOnly {
+			continue
+		}
+
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reply.Message("Please select an exchange session")
+	for _, name := range names {
+		reply.AddButton(name, "session", name)
+	}
+}
+
+func (it *CoreInteraction) selectTradingSession(sessionName string, reply interact.Reply) (*ExchangeSession, error) {
+	session, ok := it.environment.Session(sessionName)
+	if !ok || session.PublicOnly {
+		reply.Message(fmt.Sprintf("Session %s not found", sessionName))
+		return nil, fmt.Errorf("session %s not found", sessionName)
+	}
+
+	if kc, ok := reply.(interact.KeyboardController); ok {
+		kc.RemoveKeyboard()
+	}
+
+	return session, nil
+}
+
+// formatManualOrder formats the order with the session market, truncates the price and the quantity by the market precision,
+// and checks the min quantity and the min notional with the given reference price
+func formatManualOrder(session *ExchangeSession, order types.SubmitOrder, refPrice fixedpoint.Value) (types.SubmitOrder, error) {
+	order, err := session.FormatOrder(order)
+	if err != nil {
+		return order, err
+	}
+
+	market := order.Market
+	if order.Type == types.OrderTypeLimit {
+		order.Price = market.TruncatePrice(order.Price)
+		if order.Price.Sign() <= 0 {
+			return order, fmt.Errorf("price %s is less than the tick size %s", order.Price.String(), market.TickSize.String())
+		}
+	}
+
+	order.Quantity = market.TruncateQuantity(order.Quantity)
+	if order.Quantity.Sign() <= 0 || order.Quantity.Compare(market.MinQuantity) < 0 {
+		return order, fmt.Errorf("quantity %s is less than the min quantity %s", order.Quantity.String(), market.MinQuantity.String())
+	}
+
+	if notional := order.Quantity.Mul(refPrice); notional.Compare(market.MinNotional) < 0 {
+		return order, fmt.Errorf("order amount %s is less than the min notional %s", notional.String(), market.MinNotional.String())
+	}
+
+	return order, nil
+}
+
+func formatOpenOrders(orders []types.Order) string {
+	message := fmt.Sprintf("%d open orders:\n", len(orders))
+	for _, order := range orders {
+		message += fmt.Sprintf("- #%d %s\n", order.OrderID, order.PlainText())
+	}
+	return message
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/interact"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -56,3 +57,96 @@ func Test_testInterface(t *testing.T) {
 	ok := testInterface(s, (*PositionCloser)(nil))
 	assert.True(t, ok)
 }
+
+func Test_formatManualOrder(t *testing.T) {
+	session := &ExchangeSession{}
+	session.SetMarkets(types.MarketMap{
+		"BTCUSDT": {
+			Symbol:          "BTCUSDT",
+			BaseCurrency:    "BTC",
+			QuoteCurrency:   "USDT",
+			PricePrecision:  2,
+			VolumePrecision: 4,
+			TickSize:        fixedpoint.NewFromFloat(0.01),
+			StepSize:        fixedpoint.NewFromFloat(0.0001),
+			MinQuantity:     fixedpoint.NewFromFloat(0.0001),
+			MinNotional:     fixedpoint.NewFromFloat(10),
+		},
+	})
+
+	order, err := formatManualOrder(session, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(20000.123),
+		Quantity: fixedpoint.NewFromFloat(0.12345),
+	}, fixedpoint.NewFromFloat(20000.123))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "BTCUSDT", order.Market.Symbol)
+		assert.Equal(t, "20000.12", order.Price.String())
+		assert.Equal(t, "0.1234", order.Quantity.String())
+	}
+
+	// 0.0004 * 20000 = 8 < min notional
+	_, err = formatManualOrder(session, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: fixedpoint.NewFromFloat(0.0004),
+	}, fixedpoint.NewFromFloat(20000))
+	assert.Error(t, err)
+
+	_, err = formatManualOrder(session, types.SubmitOrder{
+		Symbol:   "ETHUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: fixedpoint.NewFromFloat(1),
+	}, fixedpoint.NewFromFloat(2000))
+	assert.Error(t, err)
+}
+
+type myOrderStrategy struct {
+	myStrategy
+
+	orderBook *ActiveOrderBook
+}
+
+func (m *myOrderStrategy) ActiveOrders() *ActiveOrderBook {
+	return m.orderBook
+}
+
+func Test_filterScopedOrders(t *testing.T) {
+	grid := &myOrderStrategy{myStrategy: myStrategy{Symbol: "BTCUSDT"}, orderBook: NewActiveOrderBook("BTCUSDT")}
+	grid.orderBook.Add(types.Order{OrderID: 1, Status: types.OrderStatusNew, SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT"}})
+
+	it := &CoreInteraction{
+		exchangeStrategies: map[string]SingleExchangeStrategy{
+			"binance.mystrategy:BTCUSDT": grid,
+			"max.mystrategy:BTCUSDT":     &myOrderStrategy{orderBook: NewActiveOrderBook("BTCUSDT")},
+		},
+	}
+
+	ac, err := interact.NewAccessControl([]interact.User{
+		{Name: "alice", Role: interact.RoleAdmin, Slack: "U1"},
+		{Name: "bob", Role: interact.RoleOperator, Slack: "U2", Strategies: []string{`^binance\.mystrategy`}},
+	}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	i := interact.New()
+	i.SetAccessControl(ac)
+
+	orders := []types.Order{{OrderID: 1}, {OrderID: 2}}
+	alice := &interact.SlackSession{UserID: "U1", ChannelID: "C1"}
+	bob := &interact.SlackSession{UserID: "U2", ChannelID: "C1"}
+
+	assert.Len(t, it.filterScopedOrders(i, alice, "binance", orders), 2)
+
+	if scoped := it.filterScopedOrders(i, bob, "binance", orders); assert.Len(t, scoped, 1) {
+		assert.Equal(t, uint64(1), scoped[0].OrderID)
+	}
+
+	// the order IDs of the other sessions are not matched
+	assert.Empty(t, it.filterScopedOrders(i, bob, "max", orders))
+}
@@ -160,6 +160,38 @@ var (
 			"currency",    // for balance
 		},
 	)
+
+	metricsEquityNetValue = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_equity_net_value",
+			Help: "bbgo exchange session net value in the quote currency",
+		},
+		[]string{"session", "exchange", "quote_currency"},
+	)
+
+	metricsEquityHighWaterMark = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_equity_high_water_mark",
+			Help: "bbgo exchange session net value high-water mark",
+		},
+		[]string{"session", "exchange", "quote_currency"},
+	)
+
+	metricsEquityDrawdown = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_equity_drawdown",
+			Help: "bbgo exchange session drawdown ratio from the high-water mark",
+		},
+		[]string{"session", "exchange", "quote_currency"},
+	)
+
+	metricsEquityMaxDrawdown = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_equity_max_drawdown",
+			Help: "bbgo exchange session max drawdown ratio",
+		},
+		[]string{"session", "exchange", "quote_currency"},
+	)
 )
 
 func init() {
@@ -175,5 +207,9 @@ func init() {
 		metricsTradesTotal,
 		metricsTradingVolume,
 		metricsLastUpdateTimeMetrics,
+		metricsEquityNetValue,
+		metricsEquityHighWaterMark,
+		metricsEquityDrawdown,
+		metricsEquityMaxDrawdown,
 	)
 }
@@ -2,6 +2,8 @@ package bbgo
 
 import (
 	"bytes"
+	"context"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -47,10 +49,31 @@ func (n *NullNotifier) SendPhoto(buffer *bytes.Buffer) {}
 
 func (n *NullNotifier) SendPhotoTo(channel string, buffer *bytes.Buffer) {}
 
+// SwitchedNotifier only sends the objects allowed by the switches
+type SwitchedNotifier struct {
+	Notifier
+
+	Switches *NotificationSwitches
+}
+
+func (n *SwitchedNotifier) Notify(obj interface{}, args ...interface{}) {
+	if n.Switches.Allow(obj) {
+		n.Notifier.Notify(obj, args...)
+	}
+}
+
+func (n *SwitchedNotifier) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	if n.Switches.Allow(obj) {
+		n.Notifier.NotifyTo(channel, obj, args...)
+	}
+}
+
 type Notifiability struct {
 	notifiers       []Notifier
 	liveNotePosters []LiveNotePoster
 
+	throttle *NotificationThrottle
+
 	SessionChannelRouter *PatternChannelRouter `json:"-"`
 	SymbolChannelRouter  *PatternChannelRouter `json:"-"`
 	ObjectChannelRouter  *ObjectChannelRouter  `json:"-"`
@@ -89,18 +112,68 @@ func (m *Notifiability) AddNotifier(notifier Notifier) {
 	}
 }
 
+// SetThrottle sets the throttle that filters the notifications before they're fanned out to the notifiers
+func (m *Notifiability) SetThrottle(throttle *NotificationThrottle) {
+	m.throttle = throttle
+}
+
+// RunThrottleSummary sends the summaries of the suppressed notifications to the notifiers every interval
+func (m *Notifiability) RunThrottleSummary(ctx context.Context, interval time.Duration) {
+	if m.throttle == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			for _, summary := range m.throttle.Summarize(now) {
+				for _, n := range m.notifiers {
+					n.Notify("%s", summary)
+				}
+			}
+		}
+	}
+}
+
+// allow parses the notification options from the args and checks the throttle
+func (m *Notifiability) allow(obj interface{}, args []interface{}) ([]interface{}, bool) {
+	options, args := parseNotificationOptions(args)
+	if m.throttle == nil {
+		return args, true
+	}
+
+	return args, m.throttle.Allow(time.Now(), obj, args, options)
+}
+
 func (m *Notifiability) Notify(obj interface{}, args ...interface{}) {
-	if str, ok := obj.(string); ok {
+	args, ok := m.allow(obj, args)
+
+	if str, isStr := obj.(string); isStr {
 		simpleArgs := util.FilterSimpleArgs(args)
 		logrus.Infof(str, simpleArgs...)
 	}
 
+	if !ok {
+		return
+	}
+
 	for _, n := range m.notifiers {
 		n.Notify(obj, args...)
 	}
 }
 
 func (m *Notifiability) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	args, ok := m.allow(obj, args)
+	if !ok {
+		return
+	}
+
 	for _, n := range m.notifiers {
 		n.NotifyTo(channel, obj, args...)
 	}
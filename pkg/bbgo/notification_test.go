@@ -0,0 +1,46 @@
+package bbgo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type recordNotifier struct {
+	objects []interface{}
+}
+
+func (n *recordNotifier) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	n.objects = append(n.objects, obj)
+}
+
+func (n *recordNotifier) Notify(obj interface{}, args ...interface{}) {
+	n.NotifyTo("", obj, args...)
+}
+
+func (n *recordNotifier) SendPhotoTo(channel string, buffer *bytes.Buffer) {}
+
+func (n *recordNotifier) SendPhoto(buffer *bytes.Buffer) {}
+
+func TestSwitchedNotifier(t *testing.T) {
+	recorder := &recordNotifier{}
+	notifier := &SwitchedNotifier{
+		Notifier: recorder,
+		Switches: &NotificationSwitches{Trade: true, Position: false},
+	}
+
+	notifier.Notify("hello")
+	notifier.Notify(types.Trade{Symbol: "BTCUSDT"})
+	notifier.Notify(&types.Position{Symbol: "BTCUSDT"})
+	notifier.NotifyTo("orders", types.Order{})
+
+	assert.Len(t, recorder.objects, 2)
+
+	// nil switches allow everything
+	notifier.Switches = nil
+	notifier.Notify(&types.Position{Symbol: "BTCUSDT"})
+	assert.Len(t, recorder.objects, 3)
+}
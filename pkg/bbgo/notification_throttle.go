@@ -0,0 +1,361 @@
+package bbgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// NotificationSeverity is the severity level of a notification,
+// pass it in the notification arguments, e.g., bbgo.Notify("%s disconnected", session, bbgo.SeverityWarn)
+type NotificationSeverity int
+
+const (
+	SeverityInfo NotificationSeverity = iota
+	SeverityWarn
+	SeverityCritical
+)
+
+func (s NotificationSeverity) String() string {
+	switch s {
+	case SeverityWarn:
+		return "warn"
+	case SeverityCritical:
+		return "critical"
+	}
+
+	return "info"
+}
+
+func ParseNotificationSeverity(s string) (NotificationSeverity, error) {
+	switch strings.ToLower(s) {
+	case "", "info":
+		return SeverityInfo, nil
+	case "warn", "warning":
+		return SeverityWarn, nil
+	case "critical":
+		return SeverityCritical, nil
+	}
+
+	return SeverityInfo, fmt.Errorf("invalid notification severity: %q", s)
+}
+
+func (s NotificationSeverity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *NotificationSeverity) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	severity, err := ParseNotificationSeverity(str)
+	if err != nil {
+		return err
+	}
+
+	*s = severity
+	return nil
+}
+
+func (s *NotificationSeverity) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	severity, err := ParseNotificationSeverity(str)
+	if err != nil {
+		return err
+	}
+
+	*s = severity
+	return nil
+}
+
+// NotificationKey is the key of the deduplication and the rate limiting,
+// the notifications with the same key are considered as similar messages.
+// Pass it in the notification arguments, e.g., bbgo.Notify("%s reconnecting", session, bbgo.NotificationKey("reconnect"))
+type NotificationKey string
+
+type NotificationRateLimit struct {
+	// Interval is the window of the rate limit
+	Interval types.Duration `json:"interval" yaml:"interval"`
+
+	// Burst is the max number of the similar notifications in the window
+	Burst int `json:"burst" yaml:"burst"`
+}
+
+type QuietHours struct {
+	// Start and End are the time of the day in HH:MM format, e.g., 23:00 and 07:00
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+
+	// Timezone is the location name, e.g., Asia/Taipei, the local timezone is used if it's empty
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+// NotificationThrottleConfig controls how the notifications are fanned out to the notifiers.
+// The critical notifications bypass the deduplication, the rate limit and the quiet hours.
+type NotificationThrottleConfig struct {
+	// MinSeverity drops the notifications below the severity
+	MinSeverity NotificationSeverity `json:"minSeverity,omitempty" yaml:"minSeverity,omitempty"`
+
+	// DedupWindow drops the identical text messages (or the notifications with the same key) in the window
+	DedupWindow types.Duration `json:"dedupWindow,omitempty" yaml:"dedupWindow,omitempty"`
+
+	// RateLimit limits the similar text messages, which have the same format or the same key
+	RateLimit *NotificationRateLimit `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+
+	// QuietHours suppresses the non-critical notifications in the period
+	QuietHours *QuietHours `json:"quietHours,omitempty" yaml:"quietHours,omitempty"`
+}
+
+// notificationOptions are the options parsed from the notification arguments
+type notificationOptions struct {
+	severity NotificationSeverity
+	key      NotificationKey
+}
+
+// parseNotificationOptions removes the severity and the key from the arguments,
+// so that they won't be passed to the notifiers as the format arguments
+func parseNotificationOptions(args []interface{}) (options notificationOptions, pureArgs []interface{}) {
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case NotificationSeverity:
+			options.severity = a
+		case NotificationKey:
+			options.key = a
+		default:
+			pureArgs = append(pureArgs, arg)
+		}
+	}
+
+	return options, pureArgs
+}
+
+type throttleState struct {
+	windowStart time.Time
+	window      time.Duration
+	count       int
+	suppressed  int
+	sample      string
+	duplicated  bool
+}
+
+// NotificationThrottle decides if a notification should be sent, and summarizes the suppressed notifications
+type NotificationThrottle struct {
+	config NotificationThrottleConfig
+
+	location        *time.Location
+	quietStart      time.Duration
+	quietEnd        time.Duration
+	quietSuppressed int
+
+	states    map[string]*throttleState
+	summaries []string
+	mu        sync.Mutex
+}
+
+func NewNotificationThrottle(config NotificationThrottleConfig) (*NotificationThrottle, error) {
+	throttle := &NotificationThrottle{
+		config:   config,
+		location: time.Local,
+		states:   make(map[string]*throttleState),
+	}
+
+	if config.RateLimit != nil && (config.RateLimit.Interval <= 0 || config.RateLimit.Burst <= 0) {
+		return nil, fmt.Errorf("notification rate limit requires positive interval and burst")
+	}
+
+	if q := config.QuietHours; q != nil {
+		var err error
+		if q.Timezone != "" {
+			throttle.location, err = time.LoadLocation(q.Timezone)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if throttle.quietStart, err = parseTimeOfDay(q.Start); err != nil {
+			return nil, err
+		}
+
+		if throttle.quietEnd, err = parseTimeOfDay(q.End); err != nil {
+			return nil, err
+		}
+	}
+
+	return throttle, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of the day %q, expecting HH:MM: %w", s, err)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// IsQuiet checks if the time is in the quiet hours, the quiet hours could cross midnight
+func (t *NotificationThrottle) IsQuiet(now time.Time) bool {
+	if t.config.QuietHours == nil || t.quietStart == t.quietEnd {
+		return false
+	}
+
+	local := now.In(t.location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	if t.quietStart < t.quietEnd {
+		return offset >= t.quietStart && offset < t.quietEnd
+	}
+
+	return offset >= t.quietStart || offset < t.quietEnd
+}
+
+// Allow checks if the notification should be sent
+func (t *NotificationThrottle) Allow(now time.Time, obj interface{}, args []interface{}, options notificationOptions) bool {
+	if options.severity < t.config.MinSeverity {
+		return false
+	}
+
+	if options.severity >= SeverityCritical {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.IsQuiet(now) {
+		t.quietSuppressed++
+		return false
+	}
+
+	text, isText := notificationText(obj, args)
+
+	// only the text messages and the objects with keys are deduplicated and rate limited,
+	// the objects like trades and orders are events that should not be considered as duplicates
+	if !isText && options.key == "" {
+		return true
+	}
+
+	if window := t.config.DedupWindow.Duration(); window > 0 {
+		dedupKey := "dedup:" + text
+		if options.key != "" {
+			dedupKey = "dedup:" + string(options.key)
+		}
+
+		state := t.state(now, dedupKey, window, true)
+		state.count++
+		if state.count > 1 {
+			state.suppressed++
+			state.sample = text
+			return false
+		}
+	}
+
+	if limit := t.config.RateLimit; limit != nil {
+		state := t.state(now, "rate:"+similarityKey(obj, options), limit.Interval.Duration(), false)
+		state.count++
+		if state.count > limit.Burst {
+			state.suppressed++
+			state.sample = text
+			return false
+		}
+	}
+
+	return true
+}
+
+// state returns the state of the key, the state of the closed window is summarized and replaced by a new one
+func (t *NotificationThrottle) state(now time.Time, key string, window time.Duration, duplicated bool) *throttleState {
+	state, ok := t.states[key]
+	if ok && now.Sub(state.windowStart) < state.window {
+		return state
+	}
+
+	if ok {
+		t.close(key, state)
+	}
+
+	state = &throttleState{windowStart: now, window: window, duplicated: duplicated}
+	t.states[key] = state
+	return state
+}
+
+// close removes the state and adds the summary if there are suppressed notifications
+func (t *NotificationThrottle) close(key string, state *throttleState) {
+	delete(t.states, key)
+
+	if state.suppressed == 0 {
+		return
+	}
+
+	kind := "similar"
+	if state.duplicated {
+		kind = "duplicate"
+	}
+
+	t.summaries = append(t.summaries,
+		fmt.Sprintf("%d %s messages suppressed in the last %s: %s", state.suppressed, kind, state.window, state.sample))
+}
+
+// Summarize returns the summaries of the suppressed notifications whose windows are closed,
+// and the summary of the notifications suppressed in the quiet hours after the quiet hours end.
+func (t *NotificationThrottle) Summarize(now time.Time) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, state := range t.states {
+		if now.Sub(state.windowStart) >= state.window {
+			t.close(key, state)
+		}
+	}
+
+	summaries := t.summaries
+	t.summaries = nil
+	sort.Strings(summaries)
+
+	if t.quietSuppressed > 0 && !t.IsQuiet(now) {
+		summaries = append(summaries, fmt.Sprintf("%d notifications suppressed during the quiet hours", t.quietSuppressed))
+		t.quietSuppressed = 0
+	}
+
+	return summaries
+}
+
+// notificationText renders the text message, isText is false if the notification is an object
+func notificationText(obj interface{}, args []interface{}) (text string, isText bool) {
+	switch a := obj.(type) {
+	case string:
+		return fmt.Sprintf(a, util.FilterSimpleArgs(args)...), true
+	case types.PlainText:
+		return a.PlainText(), false
+	case types.Stringer:
+		return a.String(), false
+	}
+
+	return fmt.Sprintf("%T", obj), false
+}
+
+// similarityKey returns the key of the similar messages, the messages with the same format are similar
+func similarityKey(obj interface{}, options notificationOptions) string {
+	if options.key != "" {
+		return string(options.key)
+	}
+
+	if format, ok := obj.(string); ok {
+		return format
+	}
+
+	return reflect.TypeOf(obj).String()
+}
@@ -0,0 +1,154 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestNotificationThrottleConfig_YAML(t *testing.T) {
+	var config NotificationThrottleConfig
+	err := yaml.Unmarshal([]byte(`
+minSeverity: warn
+dedupWindow: 1m
+rateLimit:
+  interval: 10s
+  burst: 3
+quietHours:
+  start: "23:00"
+  end: "07:00"
+  timezone: UTC
+`), &config)
+	if assert.NoError(t, err) {
+		assert.Equal(t, SeverityWarn, config.MinSeverity)
+		assert.Equal(t, time.Minute, config.DedupWindow.Duration())
+		assert.Equal(t, 3, config.RateLimit.Burst)
+		assert.Equal(t, "23:00", config.QuietHours.Start)
+	}
+}
+
+func TestParseNotificationOptions(t *testing.T) {
+	options, args := parseNotificationOptions([]interface{}{"binance", SeverityCritical, NotificationKey("reconnect"), 1})
+	assert.Equal(t, SeverityCritical, options.severity)
+	assert.Equal(t, NotificationKey("reconnect"), options.key)
+	assert.Equal(t, []interface{}{"binance", 1}, args)
+}
+
+func TestNotificationThrottle_MinSeverity(t *testing.T) {
+	throttle, err := NewNotificationThrottle(NotificationThrottleConfig{MinSeverity: SeverityWarn})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	now := time.Now()
+	assert.False(t, throttle.Allow(now, "hello", nil, notificationOptions{}))
+	assert.True(t, throttle.Allow(now, "hello", nil, notificationOptions{severity: SeverityWarn}))
+	assert.True(t, throttle.Allow(now, "hello", nil, notificationOptions{severity: SeverityCritical}))
+}
+
+func TestNotificationThrottle_Dedup(t *testing.T) {
+	throttle, err := NewNotificationThrottle(NotificationThrottleConfig{DedupWindow: types.Duration(time.Minute)})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	args := []interface{}{"binance"}
+	assert.True(t, throttle.Allow(now, "%s disconnected", args, notificationOptions{}))
+	assert.False(t, throttle.Allow(now.Add(time.Second), "%s disconnected", args, notificationOptions{}))
+	assert.False(t, throttle.Allow(now.Add(2*time.Second), "%s disconnected", args, notificationOptions{}))
+	assert.True(t, throttle.Allow(now.Add(2*time.Second), "%s disconnected", []interface{}{"max"}, notificationOptions{}))
+
+	// the objects without keys are not deduplicated
+	assert.True(t, throttle.Allow(now, types.Trade{ID: 1}, nil, notificationOptions{}))
+	assert.True(t, throttle.Allow(now, types.Trade{ID: 1}, nil, notificationOptions{}))
+
+	// critical notifications bypass the deduplication
+	assert.True(t, throttle.Allow(now.Add(3*time.Second), "%s disconnected", args, notificationOptions{severity: SeverityCritical}))
+
+	assert.Empty(t, throttle.Summarize(now.Add(30*time.Second)))
+	assert.Equal(t, []string{
+		"2 duplicate messages suppressed in the last 1m0s: binance disconnected",
+	}, throttle.Summarize(now.Add(time.Minute)))
+
+	// the window is reopened
+	assert.True(t, throttle.Allow(now.Add(time.Minute), "%s disconnected", args, notificationOptions{}))
+}
+
+func TestNotificationThrottle_RateLimit(t *testing.T) {
+	throttle, err := NewNotificationThrottle(NotificationThrottleConfig{
+		RateLimit: &NotificationRateLimit{Interval: types.Duration(10 * time.Second), Burst: 2},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		allowed := throttle.Allow(now.Add(time.Duration(i)*time.Second), "order %d rejected", []interface{}{i}, notificationOptions{})
+		assert.Equal(t, i < 2, allowed, "message %d", i)
+	}
+
+	// the messages with the same key are similar
+	options := notificationOptions{key: "reconnect"}
+	assert.True(t, throttle.Allow(now, "session %s reconnecting", []interface{}{"binance"}, options))
+	assert.True(t, throttle.Allow(now, "session %s stream reconnecting", []interface{}{"max"}, options))
+	assert.False(t, throttle.Allow(now, "session %s reconnecting", []interface{}{"okex"}, options))
+
+	// the suppressed messages are summarized when the next window is opened by a new message
+	assert.True(t, throttle.Allow(now.Add(11*time.Second), "order %d rejected", []interface{}{6}, notificationOptions{}))
+	assert.Equal(t, []string{
+		"1 similar messages suppressed in the last 10s: session okex reconnecting",
+		"3 similar messages suppressed in the last 10s: order 4 rejected",
+	}, throttle.Summarize(now.Add(12*time.Second)))
+}
+
+func TestNotificationThrottle_QuietHours(t *testing.T) {
+	throttle, err := NewNotificationThrottle(NotificationThrottleConfig{
+		QuietHours: &QuietHours{Start: "23:00", End: "07:00", Timezone: "UTC"},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	night := time.Date(2023, 1, 1, 23, 30, 0, 0, time.UTC)
+	morning := time.Date(2023, 1, 2, 6, 59, 0, 0, time.UTC)
+	day := time.Date(2023, 1, 2, 7, 0, 0, 0, time.UTC)
+
+	assert.True(t, throttle.IsQuiet(night))
+	assert.True(t, throttle.IsQuiet(morning))
+	assert.False(t, throttle.IsQuiet(day))
+
+	assert.False(t, throttle.Allow(night, "hello", nil, notificationOptions{}))
+	assert.False(t, throttle.Allow(morning, types.Trade{}, nil, notificationOptions{}))
+	assert.True(t, throttle.Allow(morning, "liquidated", nil, notificationOptions{severity: SeverityCritical}))
+
+	assert.Empty(t, throttle.Summarize(morning))
+	assert.Equal(t, []string{"2 notifications suppressed during the quiet hours"}, throttle.Summarize(day))
+	assert.True(t, throttle.Allow(day, "hello", nil, notificationOptions{}))
+
+	_, err = NewNotificationThrottle(NotificationThrottleConfig{QuietHours: &QuietHours{Start: "25:00", End: "07:00"}})
+	assert.Error(t, err)
+}
+
+func TestNotifiability_Throttle(t *testing.T) {
+	recorder := &recordNotifier{}
+	notification := &Notifiability{}
+	notification.AddNotifier(recorder)
+
+	throttle, err := NewNotificationThrottle(NotificationThrottleConfig{DedupWindow: types.Duration(time.Minute)})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	notification.SetThrottle(throttle)
+	notification.Notify("hello %s", "world", SeverityWarn)
+	notification.Notify("hello %s", "world")
+	notification.NotifyTo("#alerts", "hello %s", "world", NotificationKey("hello"))
+
+	assert.Equal(t, []interface{}{"hello %s", "hello %s"}, recorder.objects)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -61,6 +63,10 @@ type ExchangeSession struct {
 	// This option is exchange-specific, currently only Bitget exchange reads this option
 	PrivateChannelSymbols []string `json:"privateChannelSymbols,omitempty" yaml:"privateChannelSymbols,omitempty"`
 
+	// RecordStreamDir is the directory for recording the raw websocket messages of the session streams,
+	// the records can be replayed with types.ReplayStream
+	RecordStreamDir string `json:"recordStreamDir,omitempty" yaml:"recordStreamDir,omitempty"`
+
 	Margin               bool   `json:"margin,omitempty" yaml:"margin"`
 	IsolatedMargin       bool   `json:"isolatedMargin,omitempty" yaml:"isolatedMargin,omitempty"`
 	IsolatedMarginSymbol string `json:"isolatedMarginSymbol,omitempty" yaml:"isolatedMarginSymbol,omitempty"`
@@ -69,6 +75,10 @@ type ExchangeSession struct {
 	IsolatedFutures       bool   `json:"isolatedFutures,omitempty" yaml:"isolatedFutures,omitempty"`
 	IsolatedFuturesSymbol string `json:"isolatedFuturesSymbol,omitempty" yaml:"isolatedFuturesSymbol,omitempty"`
 
+	// OptionUnderlyings are the underlying assets of the option markets to load into the session, e.g. BTC, ETH,
+	// the exchange must implement types.OptionMarketDataService
+	OptionUnderlyings []string `json:"optionUnderlyings,omitempty" yaml:"optionUnderlyings,omitempty"`
+
 	// ---------------------------
 	// Runtime fields
 	// ---------------------------
@@ -219,6 +229,39 @@ func (session *ExchangeSession) setAccount(a *types.Account) {
 	session.accountMutex.Unlock()
 }
 
+// setupStreamRecorders creates the recorders of the market data stream and the user data stream
+func (session *ExchangeSession) setupStreamRecorders() error {
+	if err := os.MkdirAll(session.RecordStreamDir, 0755); err != nil {
+		return err
+	}
+
+	streams := map[string]types.Stream{"market": session.MarketDataStream}
+	if !session.PublicOnly {
+		streams["user"] = session.UserDataStream
+	}
+
+	now := time.Now()
+	for name, stream := range streams {
+		setter, ok := stream.(types.StreamRecorderSetter)
+		if !ok {
+			session.logger.Warnf("%s stream of %s does not support recording", name, session.ExchangeName)
+			continue
+		}
+
+		filename := filepath.Join(session.RecordStreamDir,
+			fmt.Sprintf("%s-%s-%s.jsonl.gz", session.Name, name, now.Format("20060102-150405")))
+		recorder, err := types.NewStreamRecorder(filename)
+		if err != nil {
+			return err
+		}
+
+		session.logger.Infof("recording %s stream to %s", name, filename)
+		setter.SetRecorder(recorder)
+	}
+
+	return nil
+}
+
 // Init initializes the basic data structure and market information by its exchange.
 // Note that the subscribed symbols are not loaded in this stage.
 func (session *ExchangeSession) Init(ctx context.Context, environ *Environment) error {
@@ -256,6 +299,12 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 
 	session.markets = markets
 
+	if len(session.OptionUnderlyings) > 0 {
+		if err := session.loadOptionMarkets(ctx); err != nil {
+			return err
+		}
+	}
+
 	if feeRateProvider, ok := session.Exchange.(types.ExchangeDefaultFeeRates); ok {
 		defaultFeeRates := feeRateProvider.DefaultFeeRates()
 		if session.MakerFeeRate.IsZero() {
@@ -276,6 +325,12 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 		amountProtectExchange.SetModifyOrderAmountForFee(fees)
 	}
 
+	if len(session.RecordStreamDir) > 0 {
+		if err := session.setupStreamRecorders(); err != nil {
+			return err
+		}
+	}
+
 	if session.UseHeikinAshi {
 		session.MarketDataStream = &types.HeikinAshiStream{
 			StandardStreamEmitter: session.MarketDataStream.(types.StandardStreamEmitter),
@@ -521,14 +576,23 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 
 	if !(environ.environmentConfig != nil && environ.environmentConfig.DisableHistoryKLinePreload) {
 		for interval := range klineSubscriptions {
+			// the intervals that the exchange does not provide are aggregated from the klines of their base interval
+			queryInterval := interval
+			var aggregator *types.KLineAggregator
+			if baseInterval, ok := session.kLineBaseInterval(interval); ok {
+				queryInterval = baseInterval
+				aggregator = types.NewKLineAggregator(symbol, interval, baseInterval)
+				aggregator.OnKLineClosed(marketDataStore.AddKLine)
+			}
+
 			// avoid querying the last unclosed kline
 			endTime := environ.startTime
 			var i int64
 			for i = 0; i < KLinePreloadLimit; i += 1000 {
-				var duration time.Duration = time.Duration(-i * int64(interval.Duration()))
+				var duration time.Duration = time.Duration(-i * int64(queryInterval.Duration()))
 				e := endTime.Add(duration)
 
-				kLines, err := session.Exchange.QueryKLines(ctx, symbol, interval, types.KLineQueryOptions{
+				kLines, err := session.Exchange.QueryKLines(ctx, symbol, queryInterval, types.KLineQueryOptions{
 					EndTime: &e,
 					Limit:   1000, // indicators need at least 100
 				})
@@ -548,6 +612,11 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 				}
 
 				for _, k := range kLines {
+					if aggregator != nil {
+						aggregator.HandleKLineClosed(k)
+						continue
+					}
+
 					// let market data store trigger the update, so that the indicator could be updated too.
 					marketDataStore.AddKLine(k)
 				}
@@ -694,6 +763,42 @@ func (session *ExchangeSession) SetMarkets(markets types.MarketMap) {
 	session.markets = markets
 }
 
+// loadOptionMarkets adds the option markets of the underlying assets to the session markets,
+// the option markets are not cached since the contracts are listed and expired frequently.
+func (session *ExchangeSession) loadOptionMarkets(ctx context.Context) error {
+	service, ok := session.Exchange.(types.OptionMarketDataService)
+	if !ok {
+		return fmt.Errorf("exchange %s does not support option markets", session.ExchangeName.String())
+	}
+
+	for _, underlying := range session.OptionUnderlyings {
+		markets, err := service.QueryOptionMarkets(ctx, underlying)
+		if err != nil {
+			return fmt.Errorf("unable to query the %s option markets: %w", underlying, err)
+		}
+
+		session.logger.Infof("loaded %d %s option markets", len(markets), underlying)
+		session.AddMarkets(markets)
+	}
+
+	return nil
+}
+
+// AddMarkets merges the given markets into the session markets, e.g., the dated futures contracts or the options
+// that are not returned by QueryMarkets. The existing markets of the same symbols are replaced.
+func (session *ExchangeSession) AddMarkets(markets types.MarketMap) {
+	merged := make(types.MarketMap, len(session.markets)+len(markets))
+	for symbol, market := range session.markets {
+		merged[symbol] = market
+	}
+
+	for symbol, market := range markets {
+		merged[symbol] = market
+	}
+
+	session.markets = merged
+}
+
 // Subscribe save the subscription info, later it will be assigned to the stream
 func (session *ExchangeSession) Subscribe(
 	channel types.Channel, symbol string, options types.SubscribeOptions,
@@ -714,6 +819,89 @@ func (session *ExchangeSession) Subscribe(
 	return session
 }
 
+// supportedIntervals returns the kline intervals provided by the exchange
+func (session *ExchangeSession) supportedIntervals() types.IntervalMap {
+	if provider, ok := session.Exchange.(types.CustomIntervalProvider); ok {
+		return provider.SupportedInterval()
+	}
+
+	return types.SupportedIntervals
+}
+
+// kLineBaseInterval returns the base interval to aggregate the given kline interval from,
+// it returns false if the exchange provides the interval or the interval can not be aggregated.
+func (session *ExchangeSession) kLineBaseInterval(interval types.Interval) (types.Interval, bool) {
+	intervals := session.supportedIntervals()
+	if _, ok := intervals[interval]; ok {
+		return "", false
+	}
+
+	return intervals.BaseInterval(interval)
+}
+
+// marketDataSubscriptions returns the subscriptions to be sent to the market data stream.
+// The kline subscriptions of the intervals that the exchange does not provide are replaced by their base interval subscriptions,
+// the kline subscriptions of the trade source are replaced by the market trade subscriptions,
+// and the kline aggregators that build the replaced intervals are bound to the market data stream.
+func (session *ExchangeSession) marketDataSubscriptions(ctx context.Context) map[types.Subscription]types.Subscription {
+	emitter, isEmitter := session.MarketDataStream.(types.StandardStreamEmitter)
+
+	var tradeAggregators []*types.KLineAggregator
+
+	subscriptions := make(map[types.Subscription]types.Subscription, len(session.Subscriptions))
+	for _, sub := range session.Subscriptions {
+		if sub.Channel == types.KLineChannel && isEmitter {
+			if sub.Options.Source == types.KLineSourceTrade {
+				log.Infof("[%s] building %s %s klines from the market trades", session.Name, sub.Symbol, sub.Options.Interval)
+
+				aggregator := types.NewKLineAggregator(sub.Symbol, sub.Options.Interval, "")
+				aggregator.BindStream(session.MarketDataStream)
+				aggregator.OnKLine(emitter.EmitKLine)
+				aggregator.OnKLineClosed(emitter.EmitKLineClosed)
+				tradeAggregators = append(tradeAggregators, aggregator)
+
+				sub = types.Subscription{Symbol: sub.Symbol, Channel: types.MarketTradeChannel}
+			} else if baseInterval, ok := session.kLineBaseInterval(sub.Options.Interval); ok {
+				log.Infof("[%s] aggregating %s %s klines from the %s klines", session.Name, sub.Symbol, sub.Options.Interval, baseInterval)
+
+				aggregator := types.NewKLineAggregator(sub.Symbol, sub.Options.Interval, baseInterval)
+				aggregator.BindStream(session.MarketDataStream)
+				aggregator.OnKLine(emitter.EmitKLine)
+				aggregator.OnKLineClosed(emitter.EmitKLineClosed)
+
+				sub.Options.Interval = baseInterval
+			}
+		}
+
+		subscriptions[sub] = sub
+	}
+
+	if len(tradeAggregators) > 0 {
+		go tickKLineAggregators(ctx, tradeAggregators)
+	}
+
+	return subscriptions
+}
+
+// tickKLineAggregators closes the periods of the trade-built klines when the time reaches their end,
+// since the trades of the next period might not come.
+func tickKLineAggregators(ctx context.Context, aggregators []*types.KLineAggregator) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			for _, aggregator := range aggregators {
+				aggregator.Tick(now)
+			}
+		}
+	}
+}
+
 func (session *ExchangeSession) FormatOrder(order types.SubmitOrder) (types.SubmitOrder, error) {
 	market, ok := session.Market(order.Symbol)
 	if !ok {
@@ -1046,7 +1234,7 @@ func (session *ExchangeSession) bindUserDataStreamMetrics(stream types.Stream) {
 
 func (session *ExchangeSession) bindConnectionStatusNotification(stream types.Stream, streamName string) {
 	stream.OnDisconnect(func() {
-		Notify("session %s %s stream disconnected", session.Name, streamName)
+		Notify("session %s %s stream disconnected", session.Name, streamName, SeverityWarn)
 	})
 	stream.OnConnect(func() {
 		Notify("session %s %s stream connected", session.Name, streamName)
@@ -1,10 +1,15 @@
 package bbgo
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -42,3 +47,97 @@ func Test_findPossibleMarketSymbols(t *testing.T) {
 		}
 	})
 }
+
+type optionMarketsExchange struct {
+	types.Exchange
+}
+
+func (e *optionMarketsExchange) QueryOptionMarkets(ctx context.Context, underlying string) (types.MarketMap, error) {
+	return types.MarketMap{
+		underlying + "-USD-241227-100000-C": {Symbol: underlying + "-USD-241227-100000-C", BaseCurrency: underlying},
+	}, nil
+}
+
+func (e *optionMarketsExchange) QueryOptionTickers(ctx context.Context, underlying string) (map[string]types.OptionTicker, error) {
+	return nil, nil
+}
+
+func TestExchangeSession_loadOptionMarkets(t *testing.T) {
+	session := &ExchangeSession{
+		OptionUnderlyings: []string{"BTC", "ETH"},
+		Exchange:          &optionMarketsExchange{},
+		logger:            logrus.StandardLogger(),
+	}
+	session.SetMarkets(types.MarketMap{"BTCUSDT": {Symbol: "BTCUSDT"}})
+
+	if assert.NoError(t, session.loadOptionMarkets(context.Background())) {
+		_, ok := session.Market("BTCUSDT")
+		assert.True(t, ok)
+
+		_, ok = session.Market("BTC-USD-241227-100000-C")
+		assert.True(t, ok)
+
+		_, ok = session.Market("ETH-USD-241227-100000-C")
+		assert.True(t, ok)
+	}
+}
+
+func TestExchangeSession_tradeKLines(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &types.StandardStream{}
+	session := &ExchangeSession{
+		Name:             "binance",
+		MarketDataStream: &types.BacktestStream{StandardStreamEmitter: stream},
+		Subscriptions:    make(map[types.Subscription]types.Subscription),
+		usedSymbols:      make(map[string]struct{}),
+	}
+	session.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval1m})
+	session.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: "2m", Source: types.KLineSourceTrade})
+
+	// the trade source subscription is replaced by the market trade subscription
+	subscriptions := session.marketDataSubscriptions(ctx)
+	assert.Len(t, subscriptions, 2)
+	assert.Contains(t, subscriptions, types.Subscription{Symbol: "BTCUSDT", Channel: types.KLineChannel, Options: types.SubscribeOptions{Interval: types.Interval1m}})
+	assert.Contains(t, subscriptions, types.Subscription{Symbol: "BTCUSDT", Channel: types.MarketTradeChannel})
+
+	var mu sync.Mutex
+	var kLines []types.KLine
+	session.MarketDataStream.OnKLineClosed(func(k types.KLine) {
+		mu.Lock()
+		kLines = append(kLines, k)
+		mu.Unlock()
+	})
+
+	startTime := types.Interval("2m").Truncate(time.Now()).Add(-4 * time.Minute)
+	trade := func(offset time.Duration, price float64) types.Trade {
+		return types.Trade{
+			Symbol:   "BTCUSDT",
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.One,
+			Time:     types.Time(startTime.Add(offset)),
+		}
+	}
+
+	// the period is closed by the trade of the next period
+	stream.EmitMarketTrade(trade(10*time.Second, 100))
+	stream.EmitMarketTrade(trade(70*time.Second, 110))
+	stream.EmitMarketTrade(trade(130*time.Second, 105))
+
+	mu.Lock()
+	if assert.Len(t, kLines, 1) {
+		assert.Equal(t, types.Interval("2m"), kLines[0].Interval)
+		assert.Equal(t, startTime, kLines[0].StartTime.Time())
+		assert.Equal(t, "110", kLines[0].Close.String())
+		assert.Equal(t, "2", kLines[0].Volume.String())
+	}
+	mu.Unlock()
+
+	// the last period is closed by the ticker since its end time has passed
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(kLines) == 2 && kLines[1].StartTime.Time().Equal(startTime.Add(2*time.Minute))
+	}, 3*time.Second, 50*time.Millisecond)
+}
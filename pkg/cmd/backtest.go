@@ -46,6 +46,12 @@ func init() {
 	BacktestCmd.Flags().Bool("force", false, "force execution without confirm")
 	BacktestCmd.Flags().String("output", "", "the report output directory")
 	BacktestCmd.Flags().Bool("subdir", false, "generate report in the sub-directory of the output directory")
+	BacktestCmd.Flags().Int("monte-carlo", 0, "run the monte carlo simulations of the realized profits after the backtest")
+	BacktestCmd.Flags().String("monte-carlo-method", string(backtest.MonteCarloMethodShuffle), "the monte carlo method: shuffle (re-order the profits) or bootstrap (resample the trade returns)")
+	BacktestCmd.Flags().Float64("monte-carlo-slippage", 0, "the max random slippage ratio of the trade quote quantity, e.g., 0.0005")
+	BacktestCmd.Flags().Float64("monte-carlo-fee-jitter", 0, "the random fee jitter ratio, e.g., 0.2 randomizes the fee between 80% and 120%")
+	BacktestCmd.Flags().Float64("monte-carlo-ruin", 0.5, "the loss ratio of the initial equity that is considered as ruin")
+	BacktestCmd.Flags().Int64("monte-carlo-seed", 0, "the random seed of the monte carlo simulations, the current time is used if it's zero")
 	RootCmd.AddCommand(BacktestCmd)
 }
 
@@ -124,6 +130,11 @@ var BacktestCmd = &cobra.Command{
 			return err
 		}
 
+		monteCarloConfig, err := parseMonteCarloFlags(cmd)
+		if err != nil {
+			return err
+		}
+
 		userConfig, err := bbgo.Load(configFile, true)
 		if err != nil {
 			return err
@@ -133,6 +144,26 @@ var BacktestCmd = &cobra.Command{
 			return errors.New("backtest config is not defined")
 		}
 
+		var benchmarks []*backtest.Benchmark
+		for _, benchmarkConfig := range userConfig.Backtest.Benchmarks {
+			benchmark, err := backtest.NewBenchmark(benchmarkConfig, userConfig.Backtest.Symbols)
+			if err != nil {
+				return err
+			}
+
+			benchmarks = append(benchmarks, benchmark)
+		}
+
+		// the benchmark symbols also need the kline data
+		var syncSymbols = append([]string(nil), userConfig.Backtest.Symbols...)
+		for _, benchmark := range benchmarks {
+			for _, symbol := range benchmark.Symbols {
+				if !util.StringSliceContains(syncSymbols, symbol) {
+					syncSymbols = append(syncSymbols, symbol)
+				}
+			}
+		}
+
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
@@ -224,14 +255,14 @@ var BacktestCmd = &cobra.Command{
 		}
 
 		if wantSync {
-			log.Infof("starting synchronization: %v", userConfig.Backtest.Symbols)
-			if err := sync(ctx, userConfig, backtestService, sourceExchanges, syncFromTime, endTime); err != nil {
+			log.Infof("starting synchronization: %v", syncSymbols)
+			if err := sync(ctx, userConfig, backtestService, sourceExchanges, syncSymbols, syncFromTime, endTime); err != nil {
 				return err
 			}
 			log.Info("synchronization done")
 
 			if shouldVerify {
-				err := verify(userConfig, backtestService, sourceExchanges, syncFromTime, endTime)
+				err := verify(backtestService, sourceExchanges, syncSymbols, syncFromTime, endTime)
 				if err != nil {
 					return err
 				}
@@ -306,6 +337,17 @@ var BacktestCmd = &cobra.Command{
 			return err
 		}
 
+		// subscribe the benchmark symbols, so that the kline data will be loaded from the database
+		for _, benchmark := range benchmarks {
+			for _, symbol := range benchmark.Symbols {
+				for _, session := range environ.Sessions() {
+					if _, ok := session.Market(symbol); ok {
+						session.Subscribe(types.KLineChannel, symbol, types.SubscribeOptions{Interval: types.Interval1h})
+					}
+				}
+			}
+		}
+
 		if err := trader.Run(ctx); err != nil {
 			return err
 		}
@@ -321,6 +363,10 @@ var BacktestCmd = &cobra.Command{
 		var runID = userConfig.GetSignature() + "_" + uuid.NewString()
 		var reportDir = outputDirectory
 		var sessionTradeStats = make(map[string]map[string]*types.TradeStats)
+		var sessionEquityRecorders = make(map[string]map[string]*backtest.EquityRecorder)
+		var sessionPositions = make(map[string]map[string]*types.Position)
+		var summaryEquityRecorder = backtest.NewEquityRecorder()
+		var monteCarloTrades []backtest.MonteCarloTrade
 
 		// for each exchange session, iterate the positions and
 		// allocate trade collector to calculate the tradeStats
@@ -328,6 +374,8 @@ var BacktestCmd = &cobra.Command{
 		for _, exSource := range exchangeSources {
 			sessionName := exSource.Session.Name
 			tradeStatsMap := make(map[string]*types.TradeStats)
+			equityRecorderMap := make(map[string]*backtest.EquityRecorder)
+			positionMap := make(map[string]*types.Position)
 			for usedSymbol := range exSource.Session.Positions() {
 				market, _ := exSource.Session.Market(usedSymbol)
 				position := types.NewPositionFromMarket(market)
@@ -342,16 +390,63 @@ var BacktestCmd = &cobra.Command{
 						return
 					}
 					tradeStats.Add(profit)
+					monteCarloTrades = append(monteCarloTrades, backtest.NewMonteCarloTrade(profit))
 				})
 				tradeStatsMap[usedSymbol] = tradeStats
 
+				equityRecorder := backtest.NewEquityRecorder()
+				tradeCollector.OnPositionUpdate(equityRecorder.UpdatePosition)
+				equityRecorderMap[usedSymbol] = equityRecorder
+				positionMap[usedSymbol] = position
+
 				orderStore.BindStream(exSource.Session.UserDataStream)
 				tradeCollector.BindStream(exSource.Session.UserDataStream)
 				tradeCollectorList = append(tradeCollectorList, tradeCollector)
 			}
 			sessionTradeStats[sessionName] = tradeStatsMap
+			sessionEquityRecorders[sessionName] = equityRecorderMap
+			sessionPositions[sessionName] = positionMap
 		}
 
+		// equity snapshot -- record per 1h kline for the risk metrics
+		var sessionEquities = make(map[string]fixedpoint.Value)
+		var exposures = make(map[string]bool)
+		kLineHandlers = append(kLineHandlers, func(k types.KLine, exSource *backtest.ExchangeDataSource) {
+			if k.Interval != types.Interval1h || !k.Closed {
+				return
+			}
+
+			for _, benchmark := range benchmarks {
+				benchmark.Update(k)
+			}
+
+			session := exSource.Session
+			balances := session.GetAccount().Balances()
+			snapshotTime := k.EndTime.Time()
+
+			if recorder, ok := sessionEquityRecorders[session.Name][k.Symbol]; ok {
+				if market, ok := session.Market(k.Symbol); ok {
+					exposed := sessionPositions[session.Name][k.Symbol].IsOpened(k.Close)
+					exposures[session.Name+"."+k.Symbol] = exposed
+					recorder.Record(snapshotTime, backtest.InQuoteAsset(balances, market, k.Close), exposed)
+				}
+			}
+
+			sessionEquities[session.Name] = balances.Assets(session.AllLastPrices(), snapshotTime).InUSD()
+
+			totalEquity := fixedpoint.Zero
+			for _, equity := range sessionEquities {
+				totalEquity = totalEquity.Add(equity)
+			}
+
+			anyExposed := false
+			for _, exposed := range exposures {
+				anyExposed = anyExposed || exposed
+			}
+
+			summaryEquityRecorder.Record(snapshotTime, totalEquity, anyExposed)
+		})
+
 		kLineHandlers = append(kLineHandlers, func(k types.KLine, _ *backtest.ExchangeDataSource) {
 			if k.Interval == types.Interval1d && k.Closed {
 				for _, collector := range tradeCollectorList {
@@ -557,6 +652,17 @@ var BacktestCmd = &cobra.Command{
 					return err
 				}
 
+				if recorder, ok := sessionEquityRecorders[session.Name][symbol]; ok {
+					symbolReport.RiskMetrics = recorder.Metrics()
+
+					if generatingReport {
+						symbolReport.EquityCurveFile = fmt.Sprintf("equity_curve_%s_%s.json", session.Name, symbol)
+						if err := recorder.WriteEquityCurve(filepath.Join(reportDir, symbolReport.EquityCurveFile)); err != nil {
+							return errors.Wrapf(err, "can not write equity curve json file")
+						}
+					}
+				}
+
 				summaryReport.Symbols = append(summaryReport.Symbols, symbol)
 				summaryReport.SymbolReports = append(summaryReport.SymbolReports, *symbolReport)
 				summaryReport.TotalProfit = symbolReport.PnL.Profit
@@ -576,7 +682,41 @@ var BacktestCmd = &cobra.Command{
 			}
 		}
 
+		summaryReport.RiskMetrics = summaryEquityRecorder.Metrics()
+
+		equityCurve := summaryEquityRecorder.EquityCurve()
+		for _, benchmark := range benchmarks {
+			summaryReport.Benchmarks = append(summaryReport.Benchmarks, benchmark.Compare(equityCurve))
+		}
+
+		var monteCarloReport *backtest.MonteCarloReport
+		if monteCarloConfig.Simulations > 0 {
+			initialEquity := summaryReport.InitialEquityValue
+			if len(equityCurve) > 0 {
+				initialEquity = equityCurve[0].Equity
+			}
+
+			monteCarloReport, err = backtest.RunMonteCarlo(initialEquity, monteCarloTrades, monteCarloConfig)
+			if err != nil {
+				log.WithError(err).Warnf("unable to run the monte carlo simulations")
+			} else {
+				summaryReport.MonteCarlo = monteCarloReport.WithoutSamples()
+			}
+		}
+
 		if generatingReport {
+			summaryReport.EquityCurveFile = "equity_curve.json"
+			if err := summaryEquityRecorder.WriteEquityCurve(filepath.Join(reportDir, summaryReport.EquityCurveFile)); err != nil {
+				return errors.Wrapf(err, "can not write equity curve json file")
+			}
+
+			if monteCarloReport != nil {
+				summaryReport.MonteCarloFile = "monte_carlo.json"
+				if err := util.WriteJsonFile(filepath.Join(reportDir, summaryReport.MonteCarloFile), monteCarloReport); err != nil {
+					return errors.Wrapf(err, "can not write monte carlo report json file")
+				}
+			}
+
 			summaryReportFile := filepath.Join(reportDir, "summary.json")
 
 			// output summary report filepath to stdout, so that our optimizer can read from it
@@ -608,6 +748,14 @@ var BacktestCmd = &cobra.Command{
 			color.Green("END TIME: %s\n", endTime.Format(time.RFC1123))
 			color.Green("INITIAL TOTAL BALANCE: %v\n", initTotalBalances)
 			color.Green("FINAL TOTAL BALANCE: %v\n", finalTotalBalances)
+			summaryReport.RiskMetrics.Print()
+			for _, benchmarkReport := range summaryReport.Benchmarks {
+				benchmarkReport.Print()
+			}
+
+			if summaryReport.MonteCarlo != nil {
+				summaryReport.MonteCarlo.Print()
+			}
 			for _, symbolReport := range summaryReport.SymbolReports {
 				symbolReport.Print(wantBaseAssetBaseline)
 			}
@@ -617,6 +765,52 @@ var BacktestCmd = &cobra.Command{
 	},
 }
 
+func parseMonteCarloFlags(cmd *cobra.Command) (config backtest.MonteCarloConfig, err error) {
+	config.Simulations, err = cmd.Flags().GetInt("monte-carlo")
+	if err != nil {
+		return config, err
+	}
+
+	if config.Simulations < 0 {
+		return config, fmt.Errorf("--monte-carlo should not be negative, got %d", config.Simulations)
+	}
+
+	methodStr, err := cmd.Flags().GetString("monte-carlo-method")
+	if err != nil {
+		return config, err
+	}
+
+	config.Method, err = backtest.ParseMonteCarloMethod(methodStr)
+	if err != nil {
+		return config, err
+	}
+
+	slippage, err := cmd.Flags().GetFloat64("monte-carlo-slippage")
+	if err != nil {
+		return config, err
+	}
+
+	feeJitter, err := cmd.Flags().GetFloat64("monte-carlo-fee-jitter")
+	if err != nil {
+		return config, err
+	}
+
+	ruin, err := cmd.Flags().GetFloat64("monte-carlo-ruin")
+	if err != nil {
+		return config, err
+	}
+
+	if slippage < 0 || feeJitter < 0 || ruin < 0 || ruin > 1 {
+		return config, fmt.Errorf("invalid monte carlo options: slippage %f, fee jitter %f, ruin %f", slippage, feeJitter, ruin)
+	}
+
+	config.Slippage = fixedpoint.NewFromFloat(slippage)
+	config.FeeJitter = fixedpoint.NewFromFloat(feeJitter)
+	config.RuinThreshold = fixedpoint.NewFromFloat(ruin)
+	config.Seed, err = cmd.Flags().GetInt64("monte-carlo-seed")
+	return config, err
+}
+
 func createSymbolReport(
 	userConfig *bbgo.Config, session *bbgo.ExchangeSession, symbol string, trades []types.Trade,
 	intervalProfit *types.IntervalProfitCollector,
@@ -692,11 +886,11 @@ func createSymbolReport(
 }
 
 func verify(
-	userConfig *bbgo.Config, backtestService *service.BacktestService,
-	sourceExchanges map[types.ExchangeName]types.Exchange, startTime, endTime time.Time,
+	backtestService *service.BacktestService,
+	sourceExchanges map[types.ExchangeName]types.Exchange, symbols []string, startTime, endTime time.Time,
 ) error {
 	for _, sourceExchange := range sourceExchanges {
-		err := backtestService.Verify(sourceExchange, userConfig.Backtest.Symbols, startTime, endTime)
+		err := backtestService.Verify(sourceExchange, symbols, startTime, endTime)
 		if err != nil {
 			return err
 		}
@@ -736,9 +930,9 @@ func getExchangeIntervals(ex types.Exchange) types.IntervalMap {
 
 func sync(
 	ctx context.Context, userConfig *bbgo.Config, backtestService *service.BacktestService,
-	sourceExchanges map[types.ExchangeName]types.Exchange, syncFrom, syncTo time.Time,
+	sourceExchanges map[types.ExchangeName]types.Exchange, symbols []string, syncFrom, syncTo time.Time,
 ) error {
-	for _, symbol := range userConfig.Backtest.Symbols {
+	for _, symbol := range symbols {
 		for _, sourceExchange := range sourceExchanges {
 			var supportIntervals = getExchangeIntervals(sourceExchange)
 
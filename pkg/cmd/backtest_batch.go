@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/optimizer"
+)
+
+func init() {
+	backtestBatchCmd.Flags().String("batch-config", "backtest-batch.yaml", "the batch manifest file of the backtest configs, symbols and date ranges")
+	backtestBatchCmd.Flags().String("output", "output", "backtest report output directory")
+	backtestBatchCmd.Flags().Bool("sync", true, "sync the backtest data of the batch before running the backtests")
+	RootCmd.AddCommand(backtestBatchCmd)
+}
+
+// go run ./cmd/bbgo backtest-batch --batch-config config/backtest-batch.yaml --output output
+var backtestBatchCmd = &cobra.Command{
+	Use:   "backtest-batch",
+	Short: "run the backtests of the configs × symbols × date ranges in parallel and write the comparison report",
+
+	// SilenceUsage is an option to silence usage when an error occurs.
+	SilenceUsage: true,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		batchConfigFilename, err := cmd.Flags().GetString("batch-config")
+		if err != nil {
+			return err
+		}
+
+		outputDirectory, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		wantSync, err := cmd.Flags().GetBool("sync")
+		if err != nil {
+			return err
+		}
+
+		batchConfig, err := optimizer.LoadBatchConfig(batchConfigFilename)
+		if err != nil {
+			return err
+		}
+
+		tasks, err := batchConfig.BuildTasks()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		configDir, err := os.MkdirTemp("", "bbgo-config-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(configDir)
+
+		executor := &optimizer.LocalProcessExecutor{
+			Config:    batchConfig.Executor.LocalExecutorConfig,
+			Bin:       os.Args[0],
+			WorkDir:   ".",
+			ConfigDir: configDir,
+			OutputDir: outputDirectory,
+		}
+
+		// the kline data are synced into the database once, and the kline files are shared in the output directory,
+		// so that the parallel backtests don't sync the same data
+		if wantSync {
+			syncConfigs, err := batchConfig.BuildSyncConfigs()
+			if err != nil {
+				return err
+			}
+
+			for _, syncConfig := range syncConfigs {
+				if err := executor.Prepare(syncConfig); err != nil {
+					return err
+				}
+			}
+		}
+
+		runner := &optimizer.BatchRunner{Executor: executor}
+		results, err := runner.Run(ctx, tasks)
+		if err != nil {
+			return err
+		}
+
+		if err := optimizer.WriteBatchReport(outputDirectory, results); err != nil {
+			return err
+		}
+
+		log.Infof("batch report is written to %s", filepath.Join(outputDirectory, "batch_report.html"))
+		return optimizer.FormatBatchReportTsv(os.Stdout, results)
+	},
+}
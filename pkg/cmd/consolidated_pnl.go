@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/accounting/pnl"
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/cache"
+	"github.com/c9s/bbgo/pkg/pricesolver"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func init() {
+	consolidatedPnLCmd.Flags().StringArray("session", []string{}, "target exchange sessions, all the spot sessions by default")
+	consolidatedPnLCmd.Flags().StringArray("symbol", []string{}, "only include the trades of the symbols")
+	consolidatedPnLCmd.Flags().String("currency", "USDT", "the reporting currency")
+	consolidatedPnLCmd.Flags().String("since", "", "query trades from a time point, one year ago by default")
+	consolidatedPnLCmd.Flags().Bool("sync", false, "sync before loading trades")
+	consolidatedPnLCmd.Flags().String("output", "", "write the report in json to the file")
+	RootCmd.AddCommand(consolidatedPnLCmd)
+}
+
+// go run ./cmd/bbgo consolidated-pnl --session binance --session max --currency USDT --since 2023-01-01
+var consolidatedPnLCmd = &cobra.Command{
+	Use:          "consolidated-pnl",
+	Short:        "Cross-session consolidated PnL Calculator",
+	Long:         "This command nets the same asset across the sessions and the symbols, and calculates the average cost-based profit in one reporting currency by the assets and the strategy instances",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		sessionNames, err := cmd.Flags().GetStringArray("session")
+		if err != nil {
+			return err
+		}
+
+		symbols, err := cmd.Flags().GetStringArray("symbol")
+		if err != nil {
+			return err
+		}
+
+		currency, err := cmd.Flags().GetString("currency")
+		if err != nil {
+			return err
+		}
+
+		wantSync, err := cmd.Flags().GetBool("sync")
+		if err != nil {
+			return err
+		}
+
+		outputFile, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		since := time.Now().AddDate(-1, 0, 0)
+
+		sinceOpt, err := cmd.Flags().GetString("since")
+		if err != nil {
+			return err
+		}
+
+		if sinceOpt != "" {
+			lt, err := types.ParseLooseFormatTime(sinceOpt)
+			if err != nil {
+				return err
+			}
+			since = lt.Time()
+		}
+
+		if userConfig == nil {
+			return fmt.Errorf("user config is not loaded")
+		}
+
+		environ := bbgo.NewEnvironment()
+
+		if err := environ.ConfigureDatabase(ctx, userConfig); err != nil {
+			return err
+		}
+
+		if environ.DatabaseService == nil {
+			return fmt.Errorf("database is not configured, the consolidated pnl needs the synced trades")
+		}
+
+		if err := environ.ConfigureExchangeSessions(userConfig); err != nil {
+			return err
+		}
+
+		if wantSync {
+			if err := environ.Sync(ctx, userConfig); err != nil {
+				return err
+			}
+		}
+
+		sessions, err := selectSpotSessions(environ, sessionNames)
+		if err != nil {
+			return err
+		}
+
+		markets := types.MarketMap{}
+		exchanges := map[types.ExchangeName]types.Exchange{}
+		for _, session := range sessions {
+			sessionMarkets, err := cache.LoadExchangeMarketsWithCache(ctx, session.Exchange)
+			if err != nil {
+				return err
+			}
+
+			for symbol, market := range sessionMarkets {
+				markets[symbol] = market
+			}
+
+			exchanges[session.ExchangeName] = session.Exchange
+		}
+
+		symbolFilter := map[string]struct{}{}
+		for _, symbol := range symbols {
+			symbolFilter[symbol] = struct{}{}
+		}
+
+		var trades []types.Trade
+		for exchangeName := range exchanges {
+			exchangeTrades, err := environ.TradeService.Query(service.QueryTradesOptions{
+				Exchange: exchangeName,
+				Since:    &since,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, trade := range exchangeTrades {
+				if trade.IsFutures {
+					continue
+				}
+
+				if _, ok := symbolFilter[trade.Symbol]; len(symbolFilter) > 0 && !ok {
+					continue
+				}
+
+				trades = append(trades, trade)
+			}
+		}
+
+		if len(trades) == 0 {
+			return fmt.Errorf("empty trades, you need to run sync command to sync the trades from the exchange first")
+		}
+
+		trades = types.SortTradesAscending(trades)
+		log.Infof("%d trades loaded from %d exchanges", len(trades), len(exchanges))
+
+		// the current tickers are used to value the remaining positions, and they are the fallback conversion rates
+		// of the trades if the conversion markets are not traded before
+		currentPrices := pricesolver.NewGraphPriceSolver(markets, 0)
+		tradePrices := pricesolver.NewGraphPriceSolver(markets, 0)
+		for _, exchange := range exchanges {
+			if err := currentPrices.UpdateFromAllTickers(ctx, exchange); err != nil {
+				return err
+			}
+
+			if err := tradePrices.UpdateFromAllTickers(ctx, exchange); err != nil {
+				return err
+			}
+		}
+
+		tradeStrategies, err := environ.PositionService.QueryTradeStrategies(ctx, since)
+		if err != nil {
+			return err
+		}
+
+		calculator := &pnl.ConsolidatedCalculator{
+			Currency:    currency,
+			Markets:     markets,
+			PriceSolver: tradePrices,
+			StrategyResolver: func(trade types.Trade) (pnl.StrategyInstance, bool) {
+				ts, ok := tradeStrategies[trade.Key()]
+				if !ok {
+					return pnl.StrategyInstance{}, false
+				}
+
+				return pnl.StrategyInstance{Strategy: ts.Strategy, InstanceID: ts.StrategyInstanceID}, true
+			},
+		}
+
+		report := calculator.Calculate(trades, currentPrices)
+		report.Print()
+
+		if len(outputFile) > 0 {
+			out, err := report.JSON()
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(outputFile, out, 0644); err != nil {
+				return err
+			}
+
+			log.Infof("consolidated pnl report is written to %s", outputFile)
+		}
+
+		log.Warnf("withdrawal and deposits are not considered in the PnL")
+		return nil
+	},
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/data/archive"
+	"github.com/c9s/bbgo/pkg/exchange"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func init() {
+	ImportDataCmd.Flags().String("exchange", "", "the exchange name of the data, e.g., binance")
+	ImportDataCmd.Flags().Bool("futures", false, "import the data into the futures kline table")
+	ImportDataCmd.Flags().String("symbol", "", "the symbol of the data, e.g., BTCUSDT")
+	ImportDataCmd.Flags().String("interval", "1m", "the kline interval of the data")
+	ImportDataCmd.Flags().String("format", string(archive.FormatBinanceKLines), "the column layout of the archive files: binance-klines, binance-aggtrades or csv, the csv and parquet files are both supported")
+	ImportDataCmd.Flags().Int("batch-size", 1000, "the number of rows inserted in a transaction")
+	ImportDataCmd.Flags().Bool("verify", true, "find the missing time ranges of the imported klines")
+	RootCmd.AddCommand(ImportDataCmd)
+}
+
+// go run ./cmd/bbgo import-data --exchange=binance --symbol=BTCUSDT --interval=1m ./data/BTCUSDT-1m-2023-01.zip
+// go run ./cmd/bbgo import-data --exchange=binance --symbol=BTCUSDT --format=binance-aggtrades ./data/aggTrades
+// go run ./cmd/bbgo import-data --exchange=binance --symbol=BTCUSDT --interval=1h --format=csv ./data/BTCUSDT-1h.parquet
+var ImportDataCmd = &cobra.Command{
+	Use:          "import-data --exchange=[exchange_name] --symbol=[symbol] [--interval=1m] [--format=binance-klines] [files or directories...]",
+	Short:        "import klines and aggregated trades from the bulk archive files into the backtest database",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	PreRunE: cobraInitRequired([]string{
+		"exchange",
+		"symbol",
+	}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		exchangeName, err := cmd.Flags().GetString("exchange")
+		if err != nil {
+			return err
+		}
+
+		futures, err := cmd.Flags().GetBool("futures")
+		if err != nil {
+			return err
+		}
+
+		symbol, err := cmd.Flags().GetString("symbol")
+		if err != nil {
+			return err
+		}
+
+		intervalStr, err := cmd.Flags().GetString("interval")
+		if err != nil {
+			return err
+		}
+
+		formatStr, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		batchSize, err := cmd.Flags().GetInt("batch-size")
+		if err != nil {
+			return err
+		}
+
+		verify, err := cmd.Flags().GetBool("verify")
+		if err != nil {
+			return err
+		}
+
+		if symbol == "" {
+			return fmt.Errorf("--symbol option is required")
+		}
+
+		if batchSize <= 0 {
+			return fmt.Errorf("batch size should be positive, got %d", batchSize)
+		}
+
+		format, err := archive.ParseFormat(formatStr)
+		if err != nil {
+			return err
+		}
+
+		exName, err := types.ValidExchangeName(exchangeName)
+		if err != nil {
+			return err
+		}
+
+		interval := types.Interval(intervalStr)
+		if format != archive.FormatBinanceAggTrades {
+			if _, ok := types.SupportedIntervals[interval]; !ok {
+				return fmt.Errorf("interval %s is not supported", interval)
+			}
+		}
+
+		publicExchange, err := exchange.NewPublic(exName)
+		if err != nil {
+			return err
+		}
+
+		if futures {
+			futuresExchange, ok := publicExchange.(types.FuturesExchange)
+			if !ok {
+				return fmt.Errorf("exchange %s does not support futures", publicExchange.Name())
+			}
+
+			futuresExchange.UseFutures()
+		}
+
+		files, err := archive.Files(args...)
+		if err != nil {
+			return err
+		}
+
+		if len(files) == 0 {
+			return fmt.Errorf("no csv, parquet or zip files found in %v", args)
+		}
+
+		environ := bbgo.NewEnvironment()
+		if err := bbgo.BootstrapBacktestEnvironment(ctx, environ); err != nil {
+			return err
+		}
+
+		if environ.DatabaseService == nil {
+			return errors.New("database service is not enabled, please check your environment variables DB_DRIVER and DB_DSN")
+		}
+
+		if format == archive.FormatBinanceAggTrades {
+			aggTradeService := service.NewAggTradeService(environ.DatabaseService.DB)
+			return importAggTrades(aggTradeService, exName, symbol, files, batchSize)
+		}
+
+		backtestService := &service.BacktestService{DB: environ.DatabaseService.DB}
+		since, until, err := importKLines(ctx, backtestService, publicExchange, format, symbol, interval, files, batchSize)
+		if err != nil {
+			return err
+		}
+
+		if !verify || since.IsZero() {
+			return nil
+		}
+
+		timeRanges, err := backtestService.FindMissingTimeRanges(ctx, publicExchange, symbol, interval, since, until)
+		if err != nil {
+			return err
+		}
+
+		if len(timeRanges) == 0 {
+			log.Infof("%s %s %s klines are complete from %s to %s", exName, symbol, interval, since, until)
+			return nil
+		}
+
+		for _, timeRange := range timeRanges {
+			log.Warnf("%s %s %s klines are missing: %s", exName, symbol, interval, timeRange.String())
+		}
+
+		return fmt.Errorf("found %d missing time ranges, you can fill them with: bbgo backtest --sync --sync-only", len(timeRanges))
+	},
+}
+
+// importKLines imports the klines of the files and returns the time range of the imported klines
+func importKLines(
+	ctx context.Context, backtestService *service.BacktestService, ex types.Exchange, format archive.Format,
+	symbol string, interval types.Interval, files []string, batchSize int,
+) (since, until time.Time, err error) {
+	var total, inserted int
+	for _, path := range files {
+		err = archive.Open(path, func(file archive.File) error {
+			reader := archive.NewKLineReader(file, format, ex.Name(), symbol, interval)
+
+			var batch []types.KLine
+			flush := func() error {
+				n, err := backtestService.ImportKLines(ctx, ex, batch)
+				inserted += n
+				batch = batch[:0]
+				return err
+			}
+
+			for {
+				kline, err := reader.Read()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					return errors.Wrapf(err, "unable to read %s", file.Name)
+				}
+
+				startTime := kline.StartTime.Time()
+				if since.IsZero() || startTime.Before(since) {
+					since = startTime
+				}
+
+				if startTime.After(until) {
+					until = startTime
+				}
+
+				total++
+				batch = append(batch, kline)
+				if len(batch) >= batchSize {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			}
+
+			if err := flush(); err != nil {
+				return err
+			}
+
+			log.Infof("imported %s", file.Name)
+			return nil
+		})
+		if err != nil {
+			return since, until, err
+		}
+	}
+
+	log.Infof("imported %d klines (%d existing klines are skipped)", inserted, total-inserted)
+	return since, until, nil
+}
+
+func importAggTrades(aggTradeService *service.AggTradeService, exName types.ExchangeName, symbol string, files []string, batchSize int) error {
+	var total int
+	for _, path := range files {
+		err := archive.Open(path, func(file archive.File) error {
+			reader := archive.NewAggTradeReader(file, exName, symbol)
+
+			var batch []types.AggTrade
+			for {
+				trade, err := reader.Read()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					return errors.Wrapf(err, "unable to read %s", file.Name)
+				}
+
+				total++
+				batch = append(batch, trade)
+				if len(batch) >= batchSize {
+					if err := aggTradeService.BatchInsert(batch); err != nil {
+						return err
+					}
+
+					batch = batch[:0]
+				}
+			}
+
+			if err := aggTradeService.BatchInsert(batch); err != nil {
+				return err
+			}
+
+			log.Infof("imported %s", file.Name)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("imported %d aggregated trades", total)
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/c9s/bbgo/pkg/accounting/pnl"
 	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/pricesolver"
 	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -189,10 +190,30 @@ var PnLCmd = &cobra.Command{
 			return fmt.Errorf("market not found: %s, %s", symbol, session.Exchange.Name())
 		}
 
+		// value the fees paid in the other currencies by the current prices
+		var feeSymbols []string
+		var feeCurrencies = map[string]struct{}{}
+		for _, trade := range trades {
+			if _, ok := feeCurrencies[trade.FeeCurrency]; ok || trade.FeeCurrency == "" {
+				continue
+			}
+
+			feeCurrencies[trade.FeeCurrency] = struct{}{}
+			if trade.FeeCurrency != market.BaseCurrency && trade.FeeCurrency != market.QuoteCurrency {
+				feeSymbols = append(feeSymbols, pricesolver.ConversionSymbols(session.Markets(), trade.FeeCurrency, market.QuoteCurrency)...)
+			}
+		}
+
+		priceSolver := pricesolver.NewGraphPriceSolver(session.Markets(), 0)
+		if err := priceSolver.UpdateFromTickers(ctx, exchange, feeSymbols...); err != nil {
+			return err
+		}
+
 		currentPrice := currentTick.Last
 		calculator := &pnl.AverageCostCalculator{
 			TradingFeeCurrency: tradingFeeCurrency,
 			Market:             market,
+			PriceSolver:        priceSolver,
 		}
 
 		report := calculator.Calculate(symbol, trades, currentPrice)
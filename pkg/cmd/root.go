@@ -205,6 +205,11 @@ func init() {
 	RootCmd.PersistentFlags().String("telegram-bot-token", "", "telegram bot token from bot father")
 	RootCmd.PersistentFlags().String("telegram-bot-auth-token", "", "telegram auth token")
 
+	RootCmd.PersistentFlags().String("discord-bot-token", "", "discord bot token")
+
+	RootCmd.PersistentFlags().String("webhook-secret", "", "webhook notification hmac secret")
+	RootCmd.PersistentFlags().String("smtp-password", "", "email notification smtp password")
+
 	RootCmd.PersistentFlags().String("binance-api-key", "", "binance api key")
 	RootCmd.PersistentFlags().String("binance-api-secret", "", "binance api secret")
 
@@ -174,6 +174,18 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		return err
 	}
 
+	if userConfig.EquityTracking != nil {
+		if err := environ.BindEquityTracking(tradingCtx, userConfig.EquityTracking); err != nil {
+			return err
+		}
+	}
+
+	if userConfig.AccountStatement != nil {
+		if err := environ.BindAccountStatement(tradingCtx, userConfig); err != nil {
+			return err
+		}
+	}
+
 	if enableWebServer {
 		go func() {
 			s := &server.Server{
@@ -212,6 +224,7 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		log.WithError(err).Errorf("can not save strategy persistence states")
 	}
 
+	environ.CloseNotifiers(shtCtx)
 	cancelShutdown()
 
 	for _, session := range environ.Sessions() {
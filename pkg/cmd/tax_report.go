@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/c9s/bbgo/pkg/accounting/taxlot"
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/cache"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func init() {
+	taxReportCmd.Flags().Int("year", time.Now().Year()-1, "the tax year of the report")
+	taxReportCmd.Flags().String("method", string(taxlot.MethodFIFO), "the lot selection method: fifo, lifo, hifo or specific")
+	taxReportCmd.Flags().String("currency", "USDT", "the reporting currency of the cost basis and the proceeds")
+	taxReportCmd.Flags().StringArray("session", []string{}, "the exchange sessions of the report, all the spot sessions by default")
+	taxReportCmd.Flags().Int("long-term-days", 365, "the holding days of the long-term gains")
+	taxReportCmd.Flags().String("lot-selection", "", "the yaml file of the specific-ID lot selections, maps the disposal IDs to the lot IDs")
+	taxReportCmd.Flags().String("output", "", "the output csv file of the realized gains, stdout by default")
+	taxReportCmd.Flags().String("income-output", "", "the output csv file of the reward incomes")
+	taxReportCmd.Flags().Bool("sync", false, "sync the trades, deposits, withdrawals and rewards before generating the report")
+	RootCmd.AddCommand(taxReportCmd)
+}
+
+// go run ./cmd/bbgo tax-report --year 2023 --method fifo --currency USDT --output gains-2023.csv
+var taxReportCmd = &cobra.Command{
+	Use:          "tax-report",
+	Short:        "calculate the realized short-term and long-term gains of the tax lots across the sessions",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		year, err := cmd.Flags().GetInt("year")
+		if err != nil {
+			return err
+		}
+
+		methodOpt, err := cmd.Flags().GetString("method")
+		if err != nil {
+			return err
+		}
+
+		method, err := taxlot.ParseMethod(methodOpt)
+		if err != nil {
+			return err
+		}
+
+		currency, err := cmd.Flags().GetString("currency")
+		if err != nil {
+			return err
+		}
+
+		sessionNames, err := cmd.Flags().GetStringArray("session")
+		if err != nil {
+			return err
+		}
+
+		longTermDays, err := cmd.Flags().GetInt("long-term-days")
+		if err != nil {
+			return err
+		}
+
+		lotSelectionFile, err := cmd.Flags().GetString("lot-selection")
+		if err != nil {
+			return err
+		}
+
+		outputFile, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		incomeOutputFile, err := cmd.Flags().GetString("income-output")
+		if err != nil {
+			return err
+		}
+
+		wantSync, err := cmd.Flags().GetBool("sync")
+		if err != nil {
+			return err
+		}
+
+		if userConfig == nil {
+			return fmt.Errorf("user config is not loaded")
+		}
+
+		environ := bbgo.NewEnvironment()
+		if err := environ.ConfigureDatabase(ctx, userConfig); err != nil {
+			return err
+		}
+
+		if environ.DatabaseService == nil {
+			return fmt.Errorf("database is not configured, the tax report needs the synced trades")
+		}
+
+		if err := environ.ConfigureExchangeSessions(userConfig); err != nil {
+			return err
+		}
+
+		if wantSync {
+			if err := environ.Sync(ctx, userConfig); err != nil {
+				return err
+			}
+		}
+
+		sessions, err := selectSpotSessions(environ, sessionNames)
+		if err != nil {
+			return err
+		}
+
+		if len(sessions) == 0 {
+			return fmt.Errorf("no spot session is configured")
+		}
+
+		markets := types.MarketMap{}
+		exchanges := map[types.ExchangeName]types.Exchange{}
+		for _, session := range sessions {
+			sessionMarkets, err := cache.LoadExchangeMarketsWithCache(ctx, session.Exchange)
+			if err != nil {
+				return err
+			}
+
+			for symbol, market := range sessionMarkets {
+				markets[symbol] = market
+			}
+
+			// the records are stored by the exchange name, the sessions of the same exchange share the records
+			if _, ok := exchanges[session.ExchangeName]; !ok {
+				exchanges[session.ExchangeName] = session.Exchange
+			}
+		}
+
+		// all the events until the end of the tax year are processed to build the lots
+		until := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.Local)
+
+		var events []taxlot.Event
+		for exchangeName := range exchanges {
+			trades, err := environ.TradeService.Query(service.QueryTradesOptions{
+				Exchange: exchangeName,
+				Until:    &until,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, trade := range trades {
+				if trade.IsFutures {
+					continue
+				}
+
+				events = append(events, taxlot.NewTradeEvent(trade))
+			}
+
+			deposits, err := environ.DepositService.Query(exchangeName)
+			if err != nil {
+				return err
+			}
+
+			for _, deposit := range deposits {
+				if deposit.Time.Time().Before(until) {
+					events = append(events, taxlot.NewDepositEvent(deposit))
+				}
+			}
+
+			withdraws, err := environ.WithdrawService.Query(exchangeName)
+			if err != nil {
+				return err
+			}
+
+			for _, withdraw := range withdraws {
+				if withdraw.ApplyTime.Time().Before(until) {
+					events = append(events, taxlot.NewWithdrawEvent(withdraw))
+				}
+			}
+
+			rewards, err := environ.RewardService.Query(ctx, exchangeName)
+			if err != nil {
+				return err
+			}
+
+			for _, reward := range rewards {
+				if reward.CreatedAt.Time().Before(until) {
+					events = append(events, taxlot.NewRewardEvent(reward))
+				}
+			}
+		}
+
+		if len(events) == 0 {
+			return fmt.Errorf("no trades, deposits, withdrawals or rewards found, you need to run the sync command first")
+		}
+
+		taxlot.SortEvents(events)
+		log.Infof("%d tax events loaded from %d exchanges", len(events), len(exchanges))
+
+		ledger := taxlot.NewLedger(method, currency, markets)
+		ledger.LongTermPeriod = time.Duration(longTermDays) * 24 * time.Hour
+		ledger.PriceFunc = newTaxReportPriceFunc(&service.BacktestService{DB: environ.DatabaseService.DB}, sessions, currency)
+
+		if len(lotSelectionFile) > 0 {
+			data, err := os.ReadFile(lotSelectionFile)
+			if err != nil {
+				return err
+			}
+
+			if err := yaml.Unmarshal(data, &ledger.LotSelections); err != nil {
+				return err
+			}
+		}
+
+		for _, event := range events {
+			if err := ledger.Process(event); err != nil {
+				log.WithError(err).Warnf("skip the tax event at %s", event.Time)
+			}
+		}
+
+		for asset, quantity := range ledger.Unmatched() {
+			log.Warnf("%s %s is disposed or withdrawn without the lots, the cost basis is zero, please check the trade and deposit history", quantity.String(), asset)
+		}
+
+		for asset, count := range ledger.MissingPrices() {
+			log.Warnf("%d valuations of %s have no %s price, the value is zero, please sync the %s%s daily klines", count, asset, currency, asset, currency)
+		}
+
+		report := taxlot.NewReport(ledger, year)
+		log.Infof("%d disposals in tax year %d: short-term gain %s %s, long-term gain %s %s, income %s %s, method %s",
+			len(report.Disposals), year,
+			report.ShortTermGain.String(), currency,
+			report.LongTermGain.String(), currency,
+			report.Income.String(), currency,
+			method)
+
+		if len(incomeOutputFile) > 0 {
+			if err := writeTaxReportFile(incomeOutputFile, report.WriteIncomeCSV); err != nil {
+				return err
+			}
+
+			log.Infof("income report is written to %s", incomeOutputFile)
+		}
+
+		if len(outputFile) == 0 {
+			return report.WriteCSV(os.Stdout)
+		}
+
+		if err := writeTaxReportFile(outputFile, report.WriteCSV); err != nil {
+			return err
+		}
+
+		log.Infof("tax report is written to %s", outputFile)
+		return nil
+	},
+}
+
+// selectSpotSessions returns the sessions of the given names, or all the sessions except the futures sessions
+func selectSpotSessions(environ *bbgo.Environment, sessionNames []string) (sessions []*bbgo.ExchangeSession, err error) {
+	if len(sessionNames) == 0 {
+		for _, session := range environ.Sessions() {
+			if session.Futures || session.IsolatedFutures {
+				continue
+			}
+
+			sessions = append(sessions, session)
+		}
+
+		sort.Slice(sessions, func(i, j int) bool {
+			return sessions[i].Name < sessions[j].Name
+		})
+		return sessions, nil
+	}
+
+	for _, sessionName := range sessionNames {
+		session, ok := environ.Session(sessionName)
+		if !ok {
+			return nil, fmt.Errorf("session %s not found", sessionName)
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// newTaxReportPriceFunc returns the daily close price of the asset in the currency from the synced klines of the first session
+func newTaxReportPriceFunc(backtestService *service.BacktestService, sessions []*bbgo.ExchangeSession, currency string) taxlot.PriceFunc {
+	exchange := sessions[0].Exchange
+	prices := map[string]fixedpoint.Value{}
+	return func(asset string, t time.Time) (fixedpoint.Value, bool) {
+		symbol := asset + currency
+		key := symbol + t.Format("2006-01-02")
+		if price, ok := prices[key]; ok {
+			return price, price.Sign() > 0
+		}
+
+		price := fixedpoint.Zero
+		kLines, err := backtestService.QueryKLinesBackward(exchange, symbol, types.Interval1d, t, 1)
+		if err != nil {
+			log.WithError(err).Debugf("unable to query %s klines", symbol)
+		} else if len(kLines) > 0 && t.Sub(kLines[len(kLines)-1].EndTime.Time()) < 48*time.Hour {
+			price = kLines[len(kLines)-1].Close
+		}
+
+		prices[key] = price
+		return price, price.Sign() > 0
+	}
+}
+
+func writeTaxReportFile(filename string, write func(w io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	return write(f)
+}
@@ -0,0 +1,89 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// AggTradeReader reads the aggregated trades from the csv file or the parquet file of FormatBinanceAggTrades
+type AggTradeReader struct {
+	reader   recordReader
+	exchange types.ExchangeName
+	symbol   string
+
+	line int
+}
+
+func NewAggTradeReader(r io.Reader, exchange types.ExchangeName, symbol string) *AggTradeReader {
+	return &AggTradeReader{
+		reader:   newRecordReader(r),
+		exchange: exchange,
+		symbol:   symbol,
+	}
+}
+
+// Read reads the next aggregated trade, io.EOF is returned at the end of the file
+func (r *AggTradeReader) Read() (types.AggTrade, error) {
+	for {
+		record, err := r.reader.Read()
+		if err != nil {
+			return types.AggTrade{}, err
+		}
+
+		r.line++
+		if r.line == 1 && isHeader(record) {
+			continue
+		}
+
+		trade, err := r.parse(record)
+		if err != nil {
+			return trade, fmt.Errorf("line %d: %w", r.line, err)
+		}
+
+		return trade, nil
+	}
+}
+
+func (r *AggTradeReader) parse(record []string) (trade types.AggTrade, err error) {
+	if len(record) < 7 {
+		return trade, fmt.Errorf("expecting at least 7 columns, got %d", len(record))
+	}
+
+	trade.Exchange = r.exchange
+	trade.Symbol = r.symbol
+
+	if trade.ID, err = strconv.ParseUint(strings.TrimSpace(record[0]), 10, 64); err != nil {
+		return trade, err
+	}
+
+	if trade.Price, err = fixedpoint.NewFromString(strings.TrimSpace(record[1])); err != nil {
+		return trade, err
+	}
+
+	if trade.Quantity, err = fixedpoint.NewFromString(strings.TrimSpace(record[2])); err != nil {
+		return trade, err
+	}
+
+	if trade.FirstTradeID, err = strconv.ParseUint(strings.TrimSpace(record[3]), 10, 64); err != nil {
+		return trade, err
+	}
+
+	if trade.LastTradeID, err = strconv.ParseUint(strings.TrimSpace(record[4]), 10, 64); err != nil {
+		return trade, err
+	}
+
+	t, err := parseTimestamp(record[5])
+	if err != nil {
+		return trade, err
+	}
+
+	trade.Time = types.Time(t)
+
+	trade.IsBuyerMaker, err = strconv.ParseBool(strings.TrimSpace(record[6]))
+	return trade, err
+}
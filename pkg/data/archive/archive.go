@@ -0,0 +1,257 @@
+// Package archive reads the bulk market data archives, e.g., the Binance public data (https://data.binance.vision),
+// the generic OHLCV CSV files and the parquet files, so that the backtest data can be imported without calling the exchange API.
+package archive
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+type Format string
+
+const (
+	// FormatBinanceKLines is the kline CSV of the Binance public data, the columns are:
+	// open_time, open, high, low, close, volume, close_time, quote_volume, count,
+	// taker_buy_volume, taker_buy_quote_volume, ignore
+	FormatBinanceKLines Format = "binance-klines"
+
+	// FormatBinanceAggTrades is the aggregated trade CSV of the Binance public data, the columns are:
+	// agg_trade_id, price, quantity, first_trade_id, last_trade_id, transact_time, is_buyer_maker, [is_best_match]
+	FormatBinanceAggTrades Format = "binance-aggtrades"
+
+	// FormatCSV is the generic OHLCV CSV with the header row, the columns are matched by the header names,
+	// see KLineReader for the supported names
+	FormatCSV Format = "csv"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatBinanceKLines, FormatBinanceAggTrades, FormatCSV:
+		return f, nil
+	case "parquet":
+		return "", fmt.Errorf("the parquet files are detected by the .parquet extension, please set the format to the layout of the columns, e.g., csv")
+	}
+
+	return "", fmt.Errorf("unknown archive format: %q", s)
+}
+
+// File is a csv or parquet file in the archive, a zip file can contain multiple files
+type File struct {
+	Name string
+	io.ReadCloser
+
+	// records is the record reader of the parquet file, it's nil for the csv file
+	records recordReader
+}
+
+// recordReader reads the records of the csv file or the rows of the parquet file
+type recordReader interface {
+	Read() ([]string, error)
+}
+
+// newRecordReader returns the record reader of the parquet file if r is a parquet File, or a csv reader otherwise
+func newRecordReader(r io.Reader) recordReader {
+	if file, ok := r.(File); ok && file.records != nil {
+		return file.records
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	return reader
+}
+
+// Files returns the csv and parquet files of the paths in the name order,
+// the path can be a csv file, a parquet file, a zip file or a directory that contains these files.
+func Files(paths ...string) (files []string, err error) {
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() && isArchiveFile(path) {
+				files = append(files, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func isArchiveFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".parquet", ".zip":
+		return true
+	}
+
+	return false
+}
+
+// Open opens the csv and parquet files of the path, the files in a zip file are read in the name order.
+// The callback is called for each file, and the file is closed after the callback returns.
+func Open(path string, cb func(file File) error) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".zip":
+		// the csv and parquet files in the zip file are opened below
+
+	case ".parquet":
+		f, err := local.NewLocalFileReader(path)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+		return openParquet(path, f, cb)
+
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+		return cb(File{Name: path, ReadCloser: f})
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+
+	defer zr.Close()
+
+	entries := make([]*zip.File, 0, len(zr.File))
+	for _, entry := range zr.File {
+		if !entry.FileInfo().IsDir() && isArchiveEntry(entry.Name) {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	for _, entry := range entries {
+		if err := openZipEntry(path, entry, cb); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isArchiveEntry(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".parquet":
+		return true
+	}
+
+	return false
+}
+
+func openZipEntry(path string, entry *zip.File, cb func(file File) error) error {
+	rc, err := entry.Open()
+	if err != nil {
+		return err
+	}
+
+	defer rc.Close()
+
+	name := path + ":" + entry.Name
+	if strings.ToLower(filepath.Ext(entry.Name)) != ".parquet" {
+		return cb(File{Name: name, ReadCloser: rc})
+	}
+
+	// the parquet file is read from the footer, so the zip entry is loaded into the memory
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+
+	f, err := buffer.NewBufferFile(data)
+	if err != nil {
+		return err
+	}
+
+	return openParquet(name, f, cb)
+}
+
+func openParquet(name string, f source.ParquetFile, cb func(file File) error) error {
+	records, err := newParquetRecordReader(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	defer records.Close()
+	return cb(File{Name: name, ReadCloser: f, records: records})
+}
+
+// parseTimestamp parses the unix timestamp in seconds, milliseconds or microseconds,
+// the Binance spot data uses microseconds since 2025, and the generic csv files may use seconds.
+// The date time formats like RFC3339 and "2006-01-02 15:04:05" (in UTC) are also supported.
+//
+// The time is converted to the local time zone like the klines from the exchange API, since sqlite compares the time as string.
+func parseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		switch {
+		case ts >= 1e17:
+			return time.Unix(0, ts), nil
+		case ts >= 1e14:
+			return time.UnixMicro(ts), nil
+		case ts >= 1e11:
+			return time.UnixMilli(ts), nil
+		default:
+			return time.Unix(ts, 0), nil
+		}
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t.Local(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp: %q", s)
+}
+
+// isHeader checks if the first field of the record is not a number, the Binance futures data has the header row
+func isHeader(record []string) bool {
+	if len(record) == 0 {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(strings.TrimSpace(record[0]), 64)
+	return err != nil
+}
@@ -0,0 +1,128 @@
+package archive
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const binanceKLines = `open_time,open,high,low,close,volume,close_time,quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore
+1672531200000,16541.77,16545.70,16508.39,16529.67,4364.83570,1672534799999,72146999.72,95426,2181.38016,36058362.18,0
+1672534800000,16529.59,16556.80,16525.78,16551.47,3590.06669,1672538399999,59391785.05,80349,1819.15430,30096086.41,0
+`
+
+func TestKLineReader_Binance(t *testing.T) {
+	reader := NewKLineReader(strings.NewReader(binanceKLines), FormatBinanceKLines, types.ExchangeBinance, "BTCUSDT", types.Interval1h)
+	klines, err := reader.ReadAll()
+	if !assert.NoError(t, err) || !assert.Len(t, klines, 2) {
+		return
+	}
+
+	k := klines[0]
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), k.StartTime.Time().Unix())
+	assert.Equal(t, int64(1672534799999), k.EndTime.Time().UnixMilli())
+	assert.Equal(t, "16541.77", k.Open.String())
+	assert.Equal(t, "16529.67", k.Close.String())
+	assert.Equal(t, "2181.38016", k.TakerBuyBaseAssetVolume.String())
+	assert.Equal(t, uint64(95426), k.NumberOfTrades)
+	assert.Equal(t, types.ExchangeBinance, k.Exchange)
+	assert.Equal(t, "BTCUSDT", k.Symbol)
+	assert.True(t, k.Closed)
+}
+
+func TestKLineReader_CSV(t *testing.T) {
+	data := `Date,Open,High,Low,Close,Volume
+2023-01-01 00:00:00,100,110,90,105,10
+1672534800,105,106,101,102,5
+`
+	reader := NewKLineReader(strings.NewReader(data), FormatCSV, types.ExchangeBinance, "BTCUSDT", types.Interval1h)
+	klines, err := reader.ReadAll()
+	if !assert.NoError(t, err) || !assert.Len(t, klines, 2) {
+		return
+	}
+
+	assert.Equal(t, int64(1672531200), klines[0].StartTime.Time().Unix())
+	assert.Equal(t, int64(1672534800), klines[1].StartTime.Time().Unix())
+	assert.Equal(t, time.Hour-time.Millisecond, klines[0].EndTime.Time().Sub(klines[0].StartTime.Time()))
+	assert.Equal(t, "1050", klines[0].QuoteVolume.String())
+
+	_, err = NewKLineReader(strings.NewReader("time,open,close\n"), FormatCSV, types.ExchangeBinance, "BTCUSDT", types.Interval1h).Read()
+	assert.Error(t, err)
+}
+
+func TestAggTradeReader(t *testing.T) {
+	data := `1,16541.77,0.01,10,12,1672531200000123,true,true
+2,16541.78,0.02,13,13,1672531200001000,false,true
+`
+	reader := NewAggTradeReader(strings.NewReader(data), types.ExchangeBinance, "BTCUSDT")
+
+	trade, err := reader.Read()
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(1), trade.ID)
+		assert.Equal(t, "0.01", trade.Quantity.String())
+		assert.Equal(t, uint64(12), trade.LastTradeID)
+		assert.Equal(t, int64(1672531200000123), trade.Time.Time().UnixMicro())
+		assert.True(t, trade.IsBuyerMaker)
+	}
+
+	trade, err = reader.Read()
+	if assert.NoError(t, err) {
+		assert.False(t, trade.IsBuyerMaker)
+	}
+
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestOpen_Zip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "BTCUSDT-1h-2023-01.zip")
+
+	f, err := os.Create(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("BTCUSDT-1h-2023-01.csv")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(binanceKLines))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.txt"), []byte("ignored"), 0644))
+
+	files, err := Files(dir)
+	if !assert.NoError(t, err) || !assert.Equal(t, []string{path}, files) {
+		return
+	}
+
+	var klines []types.KLine
+	err = Open(path, func(file File) error {
+		reader := NewKLineReader(file, FormatBinanceKLines, types.ExchangeBinance, "BTCUSDT", types.Interval1h)
+		ks, err := reader.ReadAll()
+		klines = append(klines, ks...)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Len(t, klines, 2)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("Binance-KLines")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatBinanceKLines, format)
+
+	// the parquet files are detected by the extension
+	_, err = ParseFormat("parquet")
+	assert.Error(t, err)
+}
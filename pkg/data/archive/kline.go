@@ -0,0 +1,233 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// the column names of the generic OHLCV csv, the names are case-insensitive
+var klineColumnNames = map[string][]string{
+	"time":        {"time", "timestamp", "date", "datetime", "open_time", "start_time"},
+	"open":        {"open", "o"},
+	"high":        {"high", "h"},
+	"low":         {"low", "l"},
+	"close":       {"close", "c"},
+	"volume":      {"volume", "vol", "v", "base_volume"},
+	"quoteVolume": {"quote_volume", "quote_asset_volume", "amount"},
+	"trades":      {"trades", "count", "num_trades", "number_of_trades"},
+}
+
+// KLineReader reads the klines from the csv file or the parquet file
+//
+// For FormatCSV, the header row is required, the columns time, open, high, low, close and volume are required,
+// and quote_volume and trades are optional. The time is the kline start time in unix timestamp or date time.
+// The column names of the parquet file are read as the header row.
+type KLineReader struct {
+	reader   recordReader
+	format   Format
+	exchange types.ExchangeName
+	symbol   string
+	interval types.Interval
+
+	columns map[string]int
+	line    int
+}
+
+func NewKLineReader(r io.Reader, format Format, exchange types.ExchangeName, symbol string, interval types.Interval) *KLineReader {
+	return &KLineReader{
+		reader:   newRecordReader(r),
+		format:   format,
+		exchange: exchange,
+		symbol:   symbol,
+		interval: interval,
+	}
+}
+
+// Read reads the next kline, io.EOF is returned at the end of the file
+func (r *KLineReader) Read() (types.KLine, error) {
+	for {
+		record, err := r.reader.Read()
+		if err != nil {
+			return types.KLine{}, err
+		}
+
+		r.line++
+
+		if r.line == 1 {
+			switch r.format {
+			case FormatCSV:
+				if err := r.parseHeader(record); err != nil {
+					return types.KLine{}, err
+				}
+				continue
+
+			case FormatBinanceKLines:
+				if isHeader(record) {
+					continue
+				}
+			}
+		}
+
+		var kline types.KLine
+		switch r.format {
+		case FormatBinanceKLines:
+			kline, err = r.parseBinance(record)
+		case FormatCSV:
+			kline, err = r.parseCSV(record)
+		default:
+			return kline, fmt.Errorf("unsupported kline format: %s", r.format)
+		}
+
+		if err != nil {
+			return kline, fmt.Errorf("line %d: %w", r.line, err)
+		}
+
+		return kline, nil
+	}
+}
+
+// ReadAll reads the klines until the end of the file
+func (r *KLineReader) ReadAll() (klines []types.KLine, err error) {
+	for {
+		kline, err := r.Read()
+		if err == io.EOF {
+			return klines, nil
+		} else if err != nil {
+			return klines, err
+		}
+
+		klines = append(klines, kline)
+	}
+}
+
+func (r *KLineReader) parseHeader(record []string) error {
+	r.columns = make(map[string]int)
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for column, aliases := range klineColumnNames {
+			for _, alias := range aliases {
+				if name == alias {
+					if _, ok := r.columns[column]; !ok {
+						r.columns[column] = i
+					}
+				}
+			}
+		}
+	}
+
+	for _, column := range []string{"time", "open", "high", "low", "close", "volume"} {
+		if _, ok := r.columns[column]; !ok {
+			return fmt.Errorf("csv header column %s is missing, header: %v", column, record)
+		}
+	}
+
+	return nil
+}
+
+func (r *KLineReader) newKLine(startTime time.Time) types.KLine {
+	return types.KLine{
+		Exchange:  r.exchange,
+		Symbol:    r.symbol,
+		Interval:  r.interval,
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(startTime.Add(r.interval.Duration() - time.Millisecond)),
+		Closed:    true,
+	}
+}
+
+func (r *KLineReader) parseBinance(record []string) (kline types.KLine, err error) {
+	if len(record) < 11 {
+		return kline, fmt.Errorf("expecting at least 11 columns, got %d", len(record))
+	}
+
+	startTime, err := parseTimestamp(record[0])
+	if err != nil {
+		return kline, err
+	}
+
+	kline = r.newKLine(startTime)
+
+	values := []*fixedpoint.Value{
+		&kline.Open, &kline.High, &kline.Low, &kline.Close, &kline.Volume,
+	}
+	if err := parseValues(record[1:6], values); err != nil {
+		return kline, err
+	}
+
+	endTime, err := parseTimestamp(record[6])
+	if err != nil {
+		return kline, err
+	}
+
+	kline.EndTime = types.Time(endTime)
+
+	if err := parseValues([]string{record[7], record[9], record[10]}, []*fixedpoint.Value{
+		&kline.QuoteVolume, &kline.TakerBuyBaseAssetVolume, &kline.TakerBuyQuoteAssetVolume,
+	}); err != nil {
+		return kline, err
+	}
+
+	kline.NumberOfTrades, err = strconv.ParseUint(strings.TrimSpace(record[8]), 10, 64)
+	return kline, err
+}
+
+func (r *KLineReader) parseCSV(record []string) (kline types.KLine, err error) {
+	field := func(column string) string {
+		i, ok := r.columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return record[i]
+	}
+
+	startTime, err := parseTimestamp(field("time"))
+	if err != nil {
+		return kline, err
+	}
+
+	kline = r.newKLine(startTime)
+
+	if err := parseValues(
+		[]string{field("open"), field("high"), field("low"), field("close"), field("volume")},
+		[]*fixedpoint.Value{&kline.Open, &kline.High, &kline.Low, &kline.Close, &kline.Volume},
+	); err != nil {
+		return kline, err
+	}
+
+	if s := field("quoteVolume"); s != "" {
+		if kline.QuoteVolume, err = fixedpoint.NewFromString(strings.TrimSpace(s)); err != nil {
+			return kline, err
+		}
+	} else {
+		// estimate the quote volume with the close price
+		kline.QuoteVolume = kline.Volume.Mul(kline.Close)
+	}
+
+	if s := field("trades"); s != "" {
+		if kline.NumberOfTrades, err = strconv.ParseUint(strings.TrimSpace(s), 10, 64); err != nil {
+			return kline, err
+		}
+	}
+
+	return kline, nil
+}
+
+func parseValues(fields []string, values []*fixedpoint.Value) error {
+	for i, s := range fields {
+		v, err := fixedpoint.NewFromString(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+
+		*values[i] = v
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	parquettypes "github.com/xitongsys/parquet-go/types"
+)
+
+// parquetBatchSize is the number of the rows read from the columns at once
+const parquetBatchSize = 4096
+
+type parquetColumn struct {
+	name    string
+	element *parquet.SchemaElement
+}
+
+// parquetRecordReader reads the rows of a flat parquet file as the csv records,
+// the first record is the column names, so that the parquet file can be parsed like a csv file with the header row.
+type parquetRecordReader struct {
+	reader  *reader.ParquetReader
+	columns []parquetColumn
+
+	header    bool
+	remaining int64
+	rows      [][]string
+	pos       int
+}
+
+func newParquetRecordReader(file source.ParquetFile) (*parquetRecordReader, error) {
+	pr, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &parquetRecordReader{
+		reader:    pr,
+		remaining: pr.GetNumRows(),
+	}
+
+	for _, path := range pr.SchemaHandler.ValueColumns {
+		index := pr.SchemaHandler.MapIndex[path]
+		element := pr.SchemaHandler.SchemaElements[index]
+		if element.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+			return nil, fmt.Errorf("parquet column %s is repeated, only the flat columns are supported", path)
+		}
+
+		r.columns = append(r.columns, parquetColumn{
+			name:    pr.SchemaHandler.GetExName(int(index)),
+			element: element,
+		})
+	}
+
+	return r, nil
+}
+
+// Read reads the next record, io.EOF is returned at the end of the file
+func (r *parquetRecordReader) Read() ([]string, error) {
+	if !r.header {
+		r.header = true
+
+		names := make([]string, len(r.columns))
+		for i, column := range r.columns {
+			names[i] = column.name
+		}
+
+		return names, nil
+	}
+
+	if r.pos >= len(r.rows) {
+		if r.remaining <= 0 {
+			return nil, io.EOF
+		}
+
+		if err := r.readRows(); err != nil {
+			return nil, err
+		}
+	}
+
+	row := r.rows[r.pos]
+	r.pos++
+	return row, nil
+}
+
+func (r *parquetRecordReader) readRows() error {
+	num := r.remaining
+	if num > parquetBatchSize {
+		num = parquetBatchSize
+	}
+
+	rows := make([][]string, num)
+	for i := range rows {
+		rows[i] = make([]string, len(r.columns))
+	}
+
+	for i, column := range r.columns {
+		values, _, _, err := r.reader.ReadColumnByIndex(int64(i), num)
+		if err != nil {
+			return err
+		}
+
+		if int64(len(values)) != num {
+			return fmt.Errorf("parquet column %s has %d values, expecting %d", column.name, len(values), num)
+		}
+
+		for j, value := range values {
+			if rows[j][i], err = formatParquetValue(value, column.element); err != nil {
+				return fmt.Errorf("parquet column %s: %w", column.name, err)
+			}
+		}
+	}
+
+	r.remaining -= num
+	r.rows = rows
+	r.pos = 0
+	return nil
+}
+
+// Close closes the files opened by the column buffers
+func (r *parquetRecordReader) Close() {
+	r.reader.ReadStop()
+}
+
+// formatParquetValue formats the value like the csv field, the null value is formatted as an empty string,
+// the dates and the INT96 timestamps are formatted as the date time, and the other timestamps are kept as the unix timestamps.
+func formatParquetValue(value interface{}, element *parquet.SchemaElement) (string, error) {
+	convertedType := element.GetConvertedType()
+	if !element.IsSetConvertedType() {
+		convertedType = -1
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+
+	case bool:
+		return strconv.FormatBool(v), nil
+
+	case int32:
+		switch convertedType {
+		case parquet.ConvertedType_DATE:
+			return time.Unix(int64(v)*86400, 0).UTC().Format("2006-01-02"), nil
+		case parquet.ConvertedType_DECIMAL:
+			return formatDecimal(big.NewInt(int64(v)), element.GetScale()), nil
+		}
+
+		return strconv.FormatInt(int64(v), 10), nil
+
+	case int64:
+		if convertedType == parquet.ConvertedType_DECIMAL {
+			return formatDecimal(big.NewInt(v), element.GetScale()), nil
+		}
+
+		return strconv.FormatInt(v, 10), nil
+
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+
+	case string:
+		if element.GetType() == parquet.Type_INT96 {
+			return parquettypes.INT96ToTime(v).Format(time.RFC3339Nano), nil
+		}
+
+		if convertedType == parquet.ConvertedType_DECIMAL {
+			// the decimal of the byte array is the big-endian two's complement integer
+			i := new(big.Int).SetBytes([]byte(v))
+			if len(v) > 0 && v[0]&0x80 != 0 {
+				i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(len(v)*8)))
+			}
+
+			return formatDecimal(i, element.GetScale()), nil
+		}
+
+		return v, nil
+	}
+
+	return "", fmt.Errorf("unsupported parquet value type %T", value)
+}
+
+func formatDecimal(unscaled *big.Int, scale int32) string {
+	if scale <= 0 {
+		return unscaled.String()
+	}
+
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	return new(big.Rat).SetFrac(unscaled, denom).FloatString(int(scale))
+}
@@ -0,0 +1,182 @@
+package archive
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type parquetOHLCV struct {
+	Timestamp int64   `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Open      float64 `parquet:"name=open, type=DOUBLE"`
+	High      float64 `parquet:"name=high, type=DOUBLE"`
+	Low       float64 `parquet:"name=low, type=DOUBLE"`
+	Close     float64 `parquet:"name=close, type=DOUBLE"`
+	Volume    float64 `parquet:"name=volume, type=DOUBLE"`
+	Trades    *int64  `parquet:"name=trades, type=INT64, repetitiontype=OPTIONAL"`
+}
+
+type parquetAggTrade struct {
+	AggTradeID   int64   `parquet:"name=agg_trade_id, type=INT64"`
+	Price        float64 `parquet:"name=price, type=DOUBLE"`
+	Quantity     float64 `parquet:"name=quantity, type=DOUBLE"`
+	FirstTradeID int64   `parquet:"name=first_trade_id, type=INT64"`
+	LastTradeID  int64   `parquet:"name=last_trade_id, type=INT64"`
+	TransactTime int64   `parquet:"name=transact_time, type=INT64"`
+	IsBuyerMaker bool    `parquet:"name=is_buyer_maker, type=BOOLEAN"`
+}
+
+func writeParquet(t *testing.T, path string, obj interface{}, rows ...interface{}) {
+	f, err := local.NewLocalFileWriter(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	pw, err := writer.NewParquetWriter(f, obj, 1)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for _, row := range rows {
+		assert.NoError(t, pw.Write(row))
+	}
+
+	assert.NoError(t, pw.WriteStop())
+	assert.NoError(t, f.Close())
+}
+
+func TestOpen_ParquetKLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "BTCUSDT-1h.parquet")
+
+	trades := int64(42)
+	var rows []interface{}
+	for i := 0; i < parquetBatchSize+10; i++ {
+		rows = append(rows, parquetOHLCV{
+			Timestamp: 1672531200000 + int64(i)*3600000,
+			Open:      100.5,
+			High:      110,
+			Low:       90,
+			Close:     105.25,
+			Volume:    10,
+		})
+	}
+
+	rows[0] = parquetOHLCV{Timestamp: 1672531200000, Open: 100.5, High: 110, Low: 90, Close: 105.25, Volume: 10, Trades: &trades}
+	writeParquet(t, path, new(parquetOHLCV), rows...)
+
+	files, err := Files(dir)
+	if !assert.NoError(t, err) || !assert.Equal(t, []string{path}, files) {
+		return
+	}
+
+	var klines []types.KLine
+	err = Open(path, func(file File) error {
+		reader := NewKLineReader(file, FormatCSV, types.ExchangeBinance, "BTCUSDT", types.Interval1h)
+		ks, err := reader.ReadAll()
+		klines = append(klines, ks...)
+		return err
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, klines, len(rows)) {
+		return
+	}
+
+	assert.Equal(t, int64(1672531200), klines[0].StartTime.Time().Unix())
+	assert.Equal(t, "100.5", klines[0].Open.String())
+	assert.Equal(t, "105.25", klines[0].Close.String())
+	assert.Equal(t, uint64(42), klines[0].NumberOfTrades)
+	assert.Equal(t, uint64(0), klines[1].NumberOfTrades)
+
+	last := klines[len(klines)-1]
+	assert.Equal(t, int64(1672531200+int64(len(rows)-1)*3600), last.StartTime.Time().Unix())
+}
+
+func TestOpen_ParquetAggTradesInZip(t *testing.T) {
+	dir := t.TempDir()
+	parquetPath := filepath.Join(dir, "BTCUSDT-aggTrades.parquet")
+	writeParquet(t, parquetPath, new(parquetAggTrade),
+		parquetAggTrade{AggTradeID: 1, Price: 16541.77, Quantity: 0.01, FirstTradeID: 10, LastTradeID: 12, TransactTime: 1672531200000, IsBuyerMaker: true},
+		parquetAggTrade{AggTradeID: 2, Price: 16541.78, Quantity: 0.02, FirstTradeID: 13, LastTradeID: 13, TransactTime: 1672531200001},
+	)
+
+	data, err := os.ReadFile(parquetPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	path := filepath.Join(dir, "BTCUSDT-aggTrades.zip")
+	f, err := os.Create(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("BTCUSDT-aggTrades.parquet")
+	assert.NoError(t, err)
+	_, err = w.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+
+	var trades []types.AggTrade
+	err = Open(path, func(file File) error {
+		reader := NewAggTradeReader(file, types.ExchangeBinance, "BTCUSDT")
+		for {
+			trade, err := reader.Read()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			trades = append(trades, trade)
+		}
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, trades, 2) {
+		return
+	}
+
+	assert.Equal(t, uint64(1), trades[0].ID)
+	assert.Equal(t, "16541.77", trades[0].Price.String())
+	assert.Equal(t, uint64(12), trades[0].LastTradeID)
+	assert.Equal(t, int64(1672531200000), trades[0].Time.Time().UnixMilli())
+	assert.True(t, trades[0].IsBuyerMaker)
+	assert.False(t, trades[1].IsBuyerMaker)
+}
+
+func Test_formatParquetValue(t *testing.T) {
+	decimal := parquet.ConvertedType_DECIMAL
+	date := parquet.ConvertedType_DATE
+	scale := int32(2)
+
+	tests := []struct {
+		value   interface{}
+		element *parquet.SchemaElement
+		want    string
+	}{
+		{nil, &parquet.SchemaElement{}, ""},
+		{float64(0.1), &parquet.SchemaElement{}, "0.1"},
+		{int64(1672531200000), &parquet.SchemaElement{}, "1672531200000"},
+		{int32(19358), &parquet.SchemaElement{ConvertedType: &date}, "2023-01-01"},
+		{int64(-12345), &parquet.SchemaElement{ConvertedType: &decimal, Scale: &scale}, "-123.45"},
+		{int32(5), &parquet.SchemaElement{ConvertedType: &decimal, Scale: &scale}, "0.05"},
+		{string([]byte{0xff, 0x85}), &parquet.SchemaElement{ConvertedType: &decimal, Scale: &scale}, "-1.23"},
+		{"BTCUSDT", &parquet.SchemaElement{}, "BTCUSDT"},
+	}
+
+	for _, tt := range tests {
+		got, err := formatParquetValue(tt.value, tt.element)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package discordapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const defaultBaseURL = "https://discord.com/api/v10"
+
+const defaultHTTPTimeout = 15 * time.Second
+
+// maxRetries is the max number of retries when the request is rate limited
+const maxRetries = 3
+
+// APIError is the error response of the discord api
+type APIError struct {
+	StatusCode int     `json:"-"`
+	Code       int     `json:"code"`
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("discord api error: status=%d code=%d message=%s", e.StatusCode, e.Code, e.Message)
+}
+
+// Client is a minimal discord bot api client for the messaging features
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	token string
+}
+
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL: defaultBaseURL,
+		HTTPClient: &http.Client{
+			Timeout: defaultHTTPTimeout,
+		},
+		token: token,
+	}
+}
+
+// CreateMessage posts a message to the channel
+func (c *Client) CreateMessage(ctx context.Context, channelID string, msg MessageCreate) (*Message, error) {
+	var resp Message
+	if err := c.sendJSON(ctx, http.MethodPost, "/channels/"+channelID+"/messages", msg, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// EditMessage edits the message that was posted by the bot
+func (c *Client) EditMessage(ctx context.Context, channelID, messageID string, msg MessageCreate) (*Message, error) {
+	var resp Message
+	if err := c.sendJSON(ctx, http.MethodPatch, "/channels/"+channelID+"/messages/"+messageID, msg, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// PinMessage pins the message in the channel
+func (c *Client) PinMessage(ctx context.Context, channelID, messageID string) error {
+	return c.sendJSON(ctx, http.MethodPut, "/channels/"+channelID+"/pins/"+messageID, nil, nil)
+}
+
+// CreateInteractionResponse responds the interaction, it must be called within 3 seconds after the interaction is received
+func (c *Client) CreateInteractionResponse(ctx context.Context, interaction *Interaction, resp InteractionResponse) error {
+	return c.sendJSON(ctx, http.MethodPost, "/interactions/"+interaction.ID+"/"+interaction.Token+"/callback", resp, nil)
+}
+
+// UploadFile posts a message with the file attachment to the channel
+func (c *Client) UploadFile(ctx context.Context, channelID string, msg MessageCreate, filename string, content []byte) (*Message, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := part.Write(payload); err != nil {
+		return nil, err
+	}
+
+	part, err = writer.CreateFormFile("files[0]", filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := part.Write(content); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var resp Message
+	if err := c.send(ctx, http.MethodPost, "/channels/"+channelID+"/messages", writer.FormDataContentType(), body.Bytes(), &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (c *Client) sendJSON(ctx context.Context, method, path string, payload, out interface{}) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.send(ctx, method, path, "application/json", body, out)
+}
+
+func (c *Client) send(ctx context.Context, method, path, contentType string, body []byte, out interface{}) error {
+	for retry := 0; ; retry++ {
+		err := c.do(ctx, method, path, contentType, body, out)
+		if err == nil {
+			return nil
+		}
+
+		apiErr, ok := err.(*APIError)
+		if !ok || apiErr.StatusCode != http.StatusTooManyRequests || retry >= maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(apiErr.RetryAfter * float64(time.Second))):
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bot "+c.token)
+	req.Header.Set("User-Agent", "DiscordBot (https://github.com/c9s/bbgo, 1.0)")
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if len(data) > 0 {
+			_ = json.Unmarshal(data, apiErr)
+		}
+
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, out)
+}
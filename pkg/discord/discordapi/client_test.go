@@ -0,0 +1,67 @@
+package discordapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CreateMessage(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "Bot token", r.Header.Get("Authorization"))
+		assert.Equal(t, "/channels/123/messages", r.URL.Path)
+
+		// the first request is rate limited
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.01, "global": false}`))
+			return
+		}
+
+		var msg MessageCreate
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		assert.Equal(t, "hello", msg.Content)
+		assert.Equal(t, "BTCUSDT", msg.Embeds[0].Title)
+
+		_, _ = w.Write([]byte(`{"id": "456", "channel_id": "123", "content": "hello"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.BaseURL = server.URL
+
+	msg, err := client.CreateMessage(context.Background(), "123", MessageCreate{
+		Content: "hello",
+		Embeds:  []Embed{{Title: "BTCUSDT"}},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "456", msg.ID)
+		assert.Equal(t, 2, requests)
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Missing Access", "code": 50001}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.BaseURL = server.URL
+
+	err := client.PinMessage(context.Background(), "123", "456")
+	if assert.Error(t, err) {
+		apiErr, ok := err.(*APIError)
+		if assert.True(t, ok) {
+			assert.Equal(t, 50001, apiErr.Code)
+			assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+		}
+	}
+}
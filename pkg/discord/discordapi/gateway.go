@@ -0,0 +1,254 @@
+package discordapi
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultGatewayURL = "wss://gateway.discord.gg/?v=10&encoding=json"
+
+const reconnectCoolDown = 15 * time.Second
+
+var log = logrus.WithField("service", "discord")
+
+// gateway opcodes, see https://discord.com/developers/docs/topics/opcodes-and-status-codes#gateway
+const (
+	opDispatch       = 0
+	opHeartbeat      = 1
+	opIdentify       = 2
+	opReconnect      = 7
+	opInvalidSession = 9
+	opHello          = 10
+	opHeartbeatAck   = 11
+)
+
+// Intent is the gateway intent, see https://discord.com/developers/docs/topics/gateway#gateway-intents
+type Intent int
+
+const (
+	IntentGuildMessages  Intent = 1 << 9
+	IntentDirectMessages Intent = 1 << 12
+	IntentMessageContent Intent = 1 << 15
+)
+
+type gatewayPayload struct {
+	Op       int             `json:"op"`
+	Data     json.RawMessage `json:"d,omitempty"`
+	Sequence *int64          `json:"s,omitempty"`
+	Type     string          `json:"t,omitempty"`
+}
+
+type helloData struct {
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+}
+
+type identifyProperties struct {
+	OS      string `json:"os"`
+	Browser string `json:"browser"`
+	Device  string `json:"device"`
+}
+
+type identifyData struct {
+	Token      string             `json:"token"`
+	Intents    Intent             `json:"intents"`
+	Properties identifyProperties `json:"properties"`
+}
+
+type ReadyEvent struct {
+	User      User   `json:"user"`
+	SessionID string `json:"session_id"`
+}
+
+// Gateway receives the message events and the interaction events from the discord gateway
+//
+//go:generate callbackgen -type Gateway
+type Gateway struct {
+	URL     string
+	Intents Intent
+
+	token string
+
+	conn     *websocket.Conn
+	connMu   sync.Mutex
+	sequence *int64
+
+	readyCallbacks             []func(e ReadyEvent)
+	messageCreateCallbacks     []func(m Message)
+	interactionCreateCallbacks []func(i Interaction)
+}
+
+func NewGateway(token string) *Gateway {
+	return &Gateway{
+		URL:     defaultGatewayURL,
+		Intents: IntentGuildMessages | IntentDirectMessages | IntentMessageContent,
+		token:   token,
+	}
+}
+
+// Run connects to the gateway and reconnects when the connection is closed, it blocks until the context is done.
+func (g *Gateway) Run(ctx context.Context) {
+	for {
+		if err := g.connect(ctx); err != nil {
+			log.WithError(err).Warnf("discord gateway connection error, reconnecting in %s...", reconnectCoolDown)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectCoolDown):
+		}
+	}
+}
+
+func (g *Gateway) connect(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, g.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	g.connMu.Lock()
+	g.conn = conn
+	g.sequence = nil
+	g.connMu.Unlock()
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-connCtx.Done()
+		_ = conn.Close()
+	}()
+
+	for {
+		var payload gatewayPayload
+		if err := conn.ReadJSON(&payload); err != nil {
+			if connCtx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if payload.Sequence != nil {
+			g.connMu.Lock()
+			g.sequence = payload.Sequence
+			g.connMu.Unlock()
+		}
+
+		switch payload.Op {
+		case opHello:
+			var hello helloData
+			if err := json.Unmarshal(payload.Data, &hello); err != nil {
+				return err
+			}
+
+			go g.heartbeat(connCtx, time.Duration(hello.HeartbeatInterval)*time.Millisecond)
+
+			if err := g.write(opIdentify, identifyData{
+				Token:   g.token,
+				Intents: g.Intents,
+				Properties: identifyProperties{
+					OS:      runtime.GOOS,
+					Browser: "bbgo",
+					Device:  "bbgo",
+				},
+			}); err != nil {
+				return err
+			}
+
+		case opHeartbeat:
+			if err := g.writeHeartbeat(); err != nil {
+				return err
+			}
+
+		case opReconnect, opInvalidSession:
+			log.Infof("discord gateway requests reconnecting, op=%d", payload.Op)
+			return nil
+
+		case opHeartbeatAck:
+
+		case opDispatch:
+			g.dispatch(payload)
+		}
+	}
+}
+
+func (g *Gateway) dispatch(payload gatewayPayload) {
+	switch payload.Type {
+	case "READY":
+		var e ReadyEvent
+		if err := json.Unmarshal(payload.Data, &e); err != nil {
+			log.WithError(err).Error("unable to parse the discord ready event")
+			return
+		}
+
+		log.Infof("discord gateway is ready, logged in as %s", e.User.Username)
+		g.EmitReady(e)
+
+	case "MESSAGE_CREATE":
+		var m Message
+		if err := json.Unmarshal(payload.Data, &m); err != nil {
+			log.WithError(err).Error("unable to parse the discord message event")
+			return
+		}
+
+		g.EmitMessageCreate(m)
+
+	case "INTERACTION_CREATE":
+		var i Interaction
+		if err := json.Unmarshal(payload.Data, &i); err != nil {
+			log.WithError(err).Error("unable to parse the discord interaction event")
+			return
+		}
+
+		g.EmitInteractionCreate(i)
+	}
+}
+
+func (g *Gateway) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := g.writeHeartbeat(); err != nil {
+				log.WithError(err).Warn("discord gateway heartbeat error")
+				return
+			}
+		}
+	}
+}
+
+func (g *Gateway) writeHeartbeat() error {
+	g.connMu.Lock()
+	sequence := g.sequence
+	g.connMu.Unlock()
+
+	return g.write(opHeartbeat, sequence)
+}
+
+func (g *Gateway) write(op int, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	g.connMu.Lock()
+	defer g.connMu.Unlock()
+
+	if g.conn == nil {
+		return websocket.ErrCloseSent
+	}
+
+	return g.conn.WriteJSON(gatewayPayload{Op: op, Data: raw})
+}
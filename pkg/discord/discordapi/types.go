@@ -0,0 +1,131 @@
+package discordapi
+
+import "time"
+
+// User is a discord user, see https://discord.com/developers/docs/resources/user#user-object
+type User struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name,omitempty"`
+	Bot        bool   `json:"bot,omitempty"`
+}
+
+// Member is the guild member object, it's attached to the interactions triggered in a guild
+type Member struct {
+	User *User `json:"user,omitempty"`
+}
+
+type EmbedFooter struct {
+	Text string `json:"text"`
+}
+
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// Embed is the rich content of a message, see https://discord.com/developers/docs/resources/message#embed-object
+type Embed struct {
+	Title       string       `json:"title,omitempty"`
+	Description string       `json:"description,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	Color       int          `json:"color,omitempty"`
+	Timestamp   *time.Time   `json:"timestamp,omitempty"`
+	Footer      *EmbedFooter `json:"footer,omitempty"`
+	Fields      []EmbedField `json:"fields,omitempty"`
+}
+
+type ComponentType int
+
+const (
+	ComponentTypeActionRow ComponentType = 1
+	ComponentTypeButton    ComponentType = 2
+)
+
+type ButtonStyle int
+
+const (
+	ButtonStylePrimary   ButtonStyle = 1
+	ButtonStyleSecondary ButtonStyle = 2
+)
+
+// Component is the message component, only the action row and the button are supported.
+// see https://discord.com/developers/docs/interactions/message-components
+type Component struct {
+	Type       ComponentType `json:"type"`
+	Style      ButtonStyle   `json:"style,omitempty"`
+	Label      string        `json:"label,omitempty"`
+	CustomID   string        `json:"custom_id,omitempty"`
+	Components []Component   `json:"components,omitempty"`
+}
+
+type MessageReference struct {
+	MessageID string `json:"message_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+}
+
+type Message struct {
+	ID        string    `json:"id"`
+	ChannelID string    `json:"channel_id"`
+	GuildID   string    `json:"guild_id,omitempty"`
+	Author    *User     `json:"author,omitempty"`
+	Content   string    `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+	Embeds    []Embed   `json:"embeds,omitempty"`
+}
+
+// MessageCreate is the payload of creating or editing a message
+type MessageCreate struct {
+	Content          string            `json:"content,omitempty"`
+	Embeds           []Embed           `json:"embeds,omitempty"`
+	Components       []Component       `json:"components,omitempty"`
+	MessageReference *MessageReference `json:"message_reference,omitempty"`
+}
+
+type InteractionType int
+
+const (
+	InteractionTypePing             InteractionType = 1
+	InteractionTypeApplicationCmd   InteractionType = 2
+	InteractionTypeMessageComponent InteractionType = 3
+)
+
+type InteractionData struct {
+	CustomID      string        `json:"custom_id,omitempty"`
+	ComponentType ComponentType `json:"component_type,omitempty"`
+}
+
+// Interaction is the payload of the INTERACTION_CREATE event
+type Interaction struct {
+	ID        string          `json:"id"`
+	Type      InteractionType `json:"type"`
+	Token     string          `json:"token"`
+	ChannelID string          `json:"channel_id"`
+	GuildID   string          `json:"guild_id,omitempty"`
+	Member    *Member         `json:"member,omitempty"`
+	User      *User           `json:"user,omitempty"`
+	Data      InteractionData `json:"data"`
+}
+
+// Sender returns the user who triggers the interaction,
+// the user field is set in the direct messages and the member field is set in the guilds.
+func (i *Interaction) Sender() *User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+
+	return i.User
+}
+
+type InteractionCallbackType int
+
+const (
+	InteractionCallbackChannelMessage        InteractionCallbackType = 4
+	InteractionCallbackDeferredUpdateMessage InteractionCallbackType = 6
+)
+
+type InteractionResponse struct {
+	Type InteractionCallbackType `json:"type"`
+	Data *MessageCreate          `json:"data,omitempty"`
+}
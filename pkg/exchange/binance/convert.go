@@ -113,9 +113,35 @@ func toGlobalFuturesMarket(symbol futures.Symbol) types.Market {
 		market.TickSize = fixedpoint.MustNewFromString(f.TickSize)
 	}
 
+	market.ContractType = toGlobalContractType(symbol.ContractType)
+
+	// perpetual contracts come with a far-future delivery date (2100-12-25), we only keep the delivery time of the dated contracts
+	if market.ContractType != types.ContractTypePerpetual && symbol.DeliveryDate > 0 {
+		market.DeliveryTime = time.UnixMilli(symbol.DeliveryDate)
+	}
+
 	return market
 }
 
+func toGlobalContractType(contractType futures.ContractType) types.ContractType {
+	switch contractType {
+	case futures.ContractTypePerpetual:
+		return types.ContractTypePerpetual
+	case "CURRENT_MONTH":
+		return types.ContractTypeCurrentMonth
+	case "NEXT_MONTH":
+		return types.ContractTypeNextMonth
+	case "CURRENT_QUARTER":
+		return types.ContractTypeCurrentQuarter
+	case "NEXT_QUARTER":
+		return types.ContractTypeNextQuarter
+	case "":
+		return ""
+	default:
+		return types.ContractTypeDelivery
+	}
+}
+
 // func toGlobalIsolatedMarginAccount(account *binance.IsolatedMarginAccount) *types.IsolatedMarginAccount {
 //	return &types.IsolatedMarginAccount{
 //		TotalAssetOfBTC:     fixedpoint.MustNewFromString(account.TotalNetAssetOfBTC),
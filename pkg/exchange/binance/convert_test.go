@@ -0,0 +1,41 @@
+package binance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_toGlobalFuturesMarket(t *testing.T) {
+	t.Run("perpetual", func(t *testing.T) {
+		market := toGlobalFuturesMarket(futures.Symbol{
+			Symbol:       "BTCUSDT",
+			Pair:         "BTCUSDT",
+			ContractType: futures.ContractTypePerpetual,
+			DeliveryDate: 4133404800000,
+			BaseAsset:    "BTC",
+			QuoteAsset:   "USDT",
+		})
+		assert.Equal(t, types.ContractTypePerpetual, market.ContractType)
+		assert.False(t, market.IsDeliveryContract())
+	})
+
+	t.Run("current quarter", func(t *testing.T) {
+		market := toGlobalFuturesMarket(futures.Symbol{
+			Symbol:       "BTCUSDT_250328",
+			Pair:         "BTCUSDT",
+			ContractType: "CURRENT_QUARTER",
+			DeliveryDate: 1743148800000,
+			BaseAsset:    "BTC",
+			QuoteAsset:   "USDT",
+		})
+		assert.Equal(t, types.ContractTypeCurrentQuarter, market.ContractType)
+		assert.True(t, market.IsDeliveryContract())
+		assert.Equal(t, time.UnixMilli(1743148800000), market.DeliveryTime)
+		assert.Equal(t, 24*time.Hour, market.TimeToDelivery(time.UnixMilli(1743148800000).Add(-24*time.Hour)))
+	})
+}
@@ -56,6 +56,7 @@ func init() {
 	_ = types.Exchange(&Exchange{})
 	_ = types.MarginExchange(&Exchange{})
 	_ = types.FuturesExchange(&Exchange{})
+	_ = types.DeliveryFuturesMarketService(&Exchange{})
 
 	if n, ok := envvar.Int("BINANCE_ORDER_RATE_LIMITER"); ok {
 		orderLimiter = rate.NewLimiter(rate.Every(time.Duration(n)*time.Minute), 2)
@@ -87,6 +88,9 @@ type Exchange struct {
 	client2 *binanceapi.RestClient
 
 	futuresClient2 *binanceapi.FuturesRestClient
+
+	// fundingInfo caches the funding intervals of the futures symbols
+	fundingInfo fundingInfoCache
 }
 
 var timeSetterOnce sync.Once
@@ -290,6 +294,24 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 	return markets, nil
 }
 
+// QueryDeliveryFuturesMarkets queries the dated (delivery) contracts of the USDT-M futures
+func (e *Exchange) QueryDeliveryFuturesMarkets(ctx context.Context) (types.MarketMap, error) {
+	exchangeInfo, err := e.futuresClient.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	markets := types.MarketMap{}
+	for _, symbol := range exchangeInfo.Symbols {
+		market := toGlobalFuturesMarket(symbol)
+		if market.IsDeliveryContract() {
+			markets[symbol.Symbol] = market
+		}
+	}
+
+	return markets, nil
+}
+
 func (e *Exchange) QueryAveragePrice(ctx context.Context, symbol string) (fixedpoint.Value, error) {
 	resp, err := e.client.NewAveragePriceService().Symbol(symbol).Do(ctx)
 	if err != nil {
@@ -1454,7 +1476,18 @@ func (e *Exchange) QueryPremiumIndex(ctx context.Context, symbol string) (*types
 		return nil, err
 	}
 
-	return convertPremiumIndex(indexes[0])
+	index, err := convertPremiumIndex(indexes[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// the funding info only lists the symbols with the adjusted funding interval, the others are settled every 8 hours
+	index.FundingInterval, err = e.fundingInfo.FundingInterval(ctx, symbol, e.queryFundingIntervals)
+	if err != nil {
+		log.WithError(err).Warnf("unable to query the funding info, using the cached or the default funding interval")
+	}
+
+	return index, nil
 }
 
 func (e *Exchange) QueryFundingRateHistory(ctx context.Context, symbol string) (*types.FundingRate, error) {
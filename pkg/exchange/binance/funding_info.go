@@ -0,0 +1,61 @@
+package binance
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// fundingInfoCacheExpiry is the expiry of the funding info cache, the funding intervals are rarely adjusted
+const fundingInfoCacheExpiry = time.Hour
+
+// fundingInfoRetryDelay is the delay before querying the funding info again after a failed query
+const fundingInfoRetryDelay = time.Minute
+
+type fundingInfoFetcher func(ctx context.Context) (map[string]time.Duration, error)
+
+// fundingInfoCache caches the funding intervals of the futures symbols,
+// so that the whole funding info list is not queried for each premium index query.
+type fundingInfoCache struct {
+	mu        sync.Mutex
+	intervals map[string]time.Duration
+	updatedAt time.Time
+	failedAt  time.Time
+}
+
+// FundingInterval returns the funding interval of the symbol, zero is returned if the symbol uses the default funding interval.
+// The funding info is queried only when the cache is empty or stale, the stale intervals are used if the query fails.
+func (c *fundingInfoCache) FundingInterval(ctx context.Context, symbol string, fetch fundingInfoFetcher) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.updatedAt) > fundingInfoCacheExpiry && now.Sub(c.failedAt) > fundingInfoRetryDelay {
+		intervals, err := fetch(ctx)
+		if err != nil {
+			c.failedAt = now
+			return c.intervals[symbol], err
+		}
+
+		c.intervals = intervals
+		c.updatedAt = now
+	}
+
+	return c.intervals[symbol], nil
+}
+
+func (e *Exchange) queryFundingIntervals(ctx context.Context) (map[string]time.Duration, error) {
+	infos, err := e.futuresClient.NewFundingRateInfoService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make(map[string]time.Duration, len(infos))
+	for _, info := range infos {
+		if info.FundingIntervalHours > 0 {
+			intervals[info.Symbol] = time.Duration(info.FundingIntervalHours) * time.Hour
+		}
+	}
+
+	return intervals, nil
+}
@@ -0,0 +1,56 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_fundingInfoCache(t *testing.T) {
+	ctx := context.Background()
+
+	var queries int
+	var queryErr error
+	fetch := func(ctx context.Context) (map[string]time.Duration, error) {
+		queries++
+		if queryErr != nil {
+			return nil, queryErr
+		}
+
+		return map[string]time.Duration{"BTCUSDT": 4 * time.Hour}, nil
+	}
+
+	var cache fundingInfoCache
+
+	interval, err := cache.FundingInterval(ctx, "BTCUSDT", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 4*time.Hour, interval)
+
+	interval, err = cache.FundingInterval(ctx, "ETHUSDT", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), interval)
+	assert.Equal(t, 1, queries, "the cached funding info should be used")
+
+	// the stale intervals are kept when the query fails
+	cache.updatedAt = time.Now().Add(-fundingInfoCacheExpiry - time.Minute)
+	queryErr = errors.New("rate limited")
+	interval, err = cache.FundingInterval(ctx, "BTCUSDT", fetch)
+	assert.Error(t, err)
+	assert.Equal(t, 4*time.Hour, interval)
+	assert.Equal(t, 2, queries)
+
+	// the failed query is not retried immediately
+	interval, err = cache.FundingInterval(ctx, "BTCUSDT", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 4*time.Hour, interval)
+	assert.Equal(t, 2, queries)
+
+	cache.failedAt = time.Now().Add(-fundingInfoRetryDelay - time.Second)
+	queryErr = nil
+	_, err = cache.FundingInterval(ctx, "BTCUSDT", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, queries)
+}
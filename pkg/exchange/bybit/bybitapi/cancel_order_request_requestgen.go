@@ -59,7 +59,7 @@ func (p *CancelOrderRequest) GetParameters() (map[string]interface{}, error) {
 
 	// TEMPLATE check-valid-values
 	switch category {
-	case "spot":
+	case "spot", "linear", "option":
 		params["category"] = category
 
 	default:
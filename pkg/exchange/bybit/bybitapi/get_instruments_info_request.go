@@ -4,14 +4,16 @@ import (
 	"github.com/c9s/requestgen"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
 )
 
 //go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
 //go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
 
 type InstrumentsInfo struct {
-	Category Category     `json:"category"`
-	List     []Instrument `json:"list"`
+	Category       Category     `json:"category"`
+	List           []Instrument `json:"list"`
+	NextPageCursor string       `json:"nextPageCursor"`
 }
 
 type Instrument struct {
@@ -28,20 +30,41 @@ type Instrument struct {
 		MaxOrderQty    fixedpoint.Value `json:"maxOrderQty"`
 		MinOrderAmt    fixedpoint.Value `json:"minOrderAmt"`
 		MaxOrderAmt    fixedpoint.Value `json:"maxOrderAmt"`
+
+		// QtyStep and MinNotionalValue are only available in the linear and the inverse categories
+		QtyStep          fixedpoint.Value `json:"qtyStep"`
+		MinNotionalValue fixedpoint.Value `json:"minNotionalValue"`
 	} `json:"lotSizeFilter"`
 
 	PriceFilter struct {
 		TickSize fixedpoint.Value `json:"tickSize"`
+		MinPrice fixedpoint.Value `json:"minPrice"`
+		MaxPrice fixedpoint.Value `json:"maxPrice"`
 	} `json:"priceFilter"`
+
+	// the following fields are only available in the linear and the inverse categories
+	ContractType ContractType               `json:"contractType"`
+	SettleCoin   string                     `json:"settleCoin"`
+	LaunchTime   types.MillisecondTimestamp `json:"launchTime"`
+	DeliveryTime types.MillisecondTimestamp `json:"deliveryTime"`
+
+	// FundingInterval is the funding interval of the perpetual contracts in minutes
+	FundingInterval int `json:"fundingInterval"`
+
+	// OptionsType is only available in the option category: Call, Put
+	OptionsType string `json:"optionsType"`
 }
 
 //go:generate GetRequest -url "/v5/market/instruments-info" -type GetInstrumentsInfoRequest -responseDataType .InstrumentsInfo
 type GetInstrumentsInfoRequest struct {
 	client requestgen.APIClient
 
-	category Category `param:"category,query" validValues:"spot"`
+	category Category `param:"category,query" validValues:"spot,linear,inverse,option"`
 	symbol   *string  `param:"symbol,query"`
 
+	// baseCoin is only applicable to the linear, the inverse and the option categories, the option category returns BTC by default
+	baseCoin *string `param:"baseCoin,query"`
+
 	// limit is invalid if category spot.
 	limit *uint64 `param:"limit,query"`
 	// cursor is invalid if category spot.
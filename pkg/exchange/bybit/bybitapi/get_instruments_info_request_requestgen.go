@@ -21,6 +21,11 @@ func (g *GetInstrumentsInfoRequest) Symbol(symbol string) *GetInstrumentsInfoReq
 	return g
 }
 
+func (g *GetInstrumentsInfoRequest) BaseCoin(baseCoin string) *GetInstrumentsInfoRequest {
+	g.baseCoin = &baseCoin
+	return g
+}
+
 func (g *GetInstrumentsInfoRequest) Limit(limit uint64) *GetInstrumentsInfoRequest {
 	g.limit = &limit
 	return g
@@ -39,7 +44,7 @@ func (g *GetInstrumentsInfoRequest) GetQueryParameters() (url.Values, error) {
 
 	// TEMPLATE check-valid-values
 	switch category {
-	case "spot":
+	case "spot", "linear", "inverse", "option":
 		params["category"] = category
 
 	default:
@@ -58,6 +63,14 @@ func (g *GetInstrumentsInfoRequest) GetQueryParameters() (url.Values, error) {
 		params["symbol"] = symbol
 	} else {
 	}
+	// check baseCoin field -> json key baseCoin
+	if g.baseCoin != nil {
+		baseCoin := *g.baseCoin
+
+		// assign parameter of baseCoin
+		params["baseCoin"] = baseCoin
+	} else {
+	}
 	// check limit field -> json key limit
 	if g.limit != nil {
 		limit := *g.limit
@@ -72,7 +72,7 @@ func (g *GetOpenOrdersRequest) GetQueryParameters() (url.Values, error) {
 
 	// TEMPLATE check-valid-values
 	switch category {
-	case "spot":
+	case "spot", "linear", "option":
 		params["category"] = category
 
 	default:
@@ -0,0 +1,39 @@
+package bybitapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type OrderBook struct {
+	Symbol string                 `json:"s"`
+	Bids   types.PriceVolumeSlice `json:"b"`
+	Asks   types.PriceVolumeSlice `json:"a"`
+	// UpdateId is the update ID of the snapshot, it's reset to 1 when the service restarts
+	UpdateId int64 `json:"u"`
+	// Seq is the cross sequence, it's only available in the spot, linear and inverse categories
+	Seq       int64                      `json:"seq"`
+	Timestamp types.MillisecondTimestamp `json:"ts"`
+}
+
+//go:generate GetRequest -url "/v5/market/orderbook" -type GetOrderBookRequest -responseDataType .OrderBook
+type GetOrderBookRequest struct {
+	client requestgen.APIClient
+
+	category Category `param:"category,query" validValues:"spot,linear,option"`
+	symbol   string   `param:"symbol,query"`
+
+	// limit is the depth per side, spot: [1, 200], option: [1, 25]
+	limit *uint64 `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetOrderBookRequest() *GetOrderBookRequest {
+	return &GetOrderBookRequest{
+		client:   c,
+		category: CategorySpot,
+	}
+}
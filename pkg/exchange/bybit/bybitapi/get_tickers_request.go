@@ -34,6 +34,22 @@ type Ticker struct {
 	Turnover24H   fixedpoint.Value `json:"turnover24h"`
 	Volume24H     fixedpoint.Value `json:"volume24h"`
 	UsdIndexPrice fixedpoint.Value `json:"usdIndexPrice"`
+
+	// the following fields are only available in the option category
+	Bid1Iv          fixedpoint.Value `json:"bid1Iv"`
+	Ask1Iv          fixedpoint.Value `json:"ask1Iv"`
+	MarkIv          fixedpoint.Value `json:"markIv"`
+	MarkPrice       fixedpoint.Value `json:"markPrice"`
+	IndexPrice      fixedpoint.Value `json:"indexPrice"`
+	UnderlyingPrice fixedpoint.Value `json:"underlyingPrice"`
+	Delta           fixedpoint.Value `json:"delta"`
+	Gamma           fixedpoint.Value `json:"gamma"`
+	Vega            fixedpoint.Value `json:"vega"`
+	Theta           fixedpoint.Value `json:"theta"`
+
+	// the following fields are only available in the linear category
+	FundingRate     fixedpoint.Value           `json:"fundingRate"`
+	NextFundingTime types.MillisecondTimestamp `json:"nextFundingTime"`
 }
 
 // GetTickersRequest without **-responseDataType .InstrumentsInfo** in generation command, because the caller
@@ -43,8 +59,11 @@ type Ticker struct {
 type GetTickersRequest struct {
 	client requestgen.APIClient
 
-	category Category `param:"category,query" validValues:"spot"`
+	category Category `param:"category,query" validValues:"spot,linear,option"`
 	symbol   *string  `param:"symbol,query"`
+
+	// baseCoin is only applicable to the option category, either symbol or baseCoin is required for the options
+	baseCoin *string `param:"baseCoin,query"`
 }
 
 func (c *RestClient) NewGetTickersRequest() *GetTickersRequest {
@@ -21,6 +21,11 @@ func (g *GetTickersRequest) Symbol(symbol string) *GetTickersRequest {
 	return g
 }
 
+func (g *GetTickersRequest) BaseCoin(baseCoin string) *GetTickersRequest {
+	g.baseCoin = &baseCoin
+	return g
+}
+
 // GetQueryParameters builds and checks the query parameters and returns url.Values
 func (g *GetTickersRequest) GetQueryParameters() (url.Values, error) {
 	var params = map[string]interface{}{}
@@ -29,7 +34,7 @@ func (g *GetTickersRequest) GetQueryParameters() (url.Values, error) {
 
 	// TEMPLATE check-valid-values
 	switch category {
-	case "spot":
+	case "spot", "linear", "option":
 		params["category"] = category
 
 	default:
@@ -48,6 +53,14 @@ func (g *GetTickersRequest) GetQueryParameters() (url.Values, error) {
 		params["symbol"] = symbol
 	} else {
 	}
+	// check baseCoin field -> json key baseCoin
+	if g.baseCoin != nil {
+		baseCoin := *g.baseCoin
+
+		// assign parameter of baseCoin
+		params["baseCoin"] = baseCoin
+	} else {
+	}
 
 	query := url.Values{}
 	for _k, _v := range params {
@@ -39,7 +39,19 @@ var (
 type Category string
 
 const (
-	CategorySpot Category = "spot"
+	CategorySpot    Category = "spot"
+	CategoryLinear  Category = "linear"
+	CategoryInverse Category = "inverse"
+	CategoryOption  Category = "option"
+)
+
+type ContractType string
+
+const (
+	ContractTypeLinearPerpetual  ContractType = "LinearPerpetual"
+	ContractTypeLinearFutures    ContractType = "LinearFutures"
+	ContractTypeInversePerpetual ContractType = "InversePerpetual"
+	ContractTypeInverseFutures   ContractType = "InverseFutures"
 )
 
 type Status string
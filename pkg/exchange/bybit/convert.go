@@ -2,9 +2,13 @@ package bybit
 
 import (
 	"fmt"
+	"hash/fnv"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/c9s/bbgo/pkg/exchange/bybit/bybitapi"
 	"github.com/c9s/bbgo/pkg/exchange/bybit/bybitapi/v3"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
@@ -34,6 +38,165 @@ func toGlobalMarket(m bybitapi.Instrument) types.Market {
 	}
 }
 
+// toGlobalDeliveryFuturesMarket converts the dated futures instrument of the linear/inverse category into the market
+func toGlobalDeliveryFuturesMarket(m bybitapi.Instrument) types.Market {
+	return types.Market{
+		Exchange:        types.ExchangeBybit,
+		Symbol:          m.Symbol,
+		LocalSymbol:     m.Symbol,
+		PricePrecision:  m.PriceFilter.TickSize.NumFractionalDigits(),
+		VolumePrecision: m.LotSizeFilter.QtyStep.NumFractionalDigits(),
+		QuoteCurrency:   m.QuoteCoin,
+		BaseCurrency:    m.BaseCoin,
+		MinNotional:     m.LotSizeFilter.MinNotionalValue,
+		MinAmount:       m.LotSizeFilter.MinNotionalValue,
+
+		// quantity
+		MinQuantity: m.LotSizeFilter.MinOrderQty,
+		MaxQuantity: m.LotSizeFilter.MaxOrderQty,
+		StepSize:    m.LotSizeFilter.QtyStep,
+
+		// price
+		MinPrice: m.PriceFilter.MinPrice,
+		MaxPrice: m.PriceFilter.MaxPrice,
+		TickSize: m.PriceFilter.TickSize,
+
+		ContractType: types.ContractTypeDelivery,
+		DeliveryTime: m.DeliveryTime.Time(),
+	}
+}
+
+// toGlobalPerpetualMarket converts the linear perpetual instrument, the quantity is in the base coin like the spot market
+func toGlobalPerpetualMarket(m bybitapi.Instrument) types.Market {
+	market := toGlobalDeliveryFuturesMarket(m)
+	market.ContractType = types.ContractTypePerpetual
+	market.DeliveryTime = time.Time{}
+	return market
+}
+
+func toGlobalOptionType(optionsType string) (types.OptionType, error) {
+	switch optionsType {
+	case "Call":
+		return types.OptionTypeCall, nil
+	case "Put":
+		return types.OptionTypePut, nil
+	}
+
+	return "", fmt.Errorf("unexpected options type: %s", optionsType)
+}
+
+// isOptionSymbol returns true if the symbol is in the option format, e.g. BTC-27DEC24-100000-C
+func isOptionSymbol(symbol string) bool {
+	return len(strings.Split(symbol, "-")) >= 4
+}
+
+// toGlobalID converts the order id or the exec id into the uint64 id,
+// the ids of the spot are numbers (e.g. 1468264727470772736), and the ids of the linear futures and the options
+// are UUIDs (e.g. 42f4f364-82e1-49d3-ad1d-cd8cf9aa308d) which are hashed into uint64, the original id is kept in Order.UUID.
+func toGlobalID(id string) (uint64, error) {
+	if _, err := uuid.Parse(id); err == nil {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(id))
+		return h.Sum64(), nil
+	}
+
+	return strconv.ParseUint(id, 10, 64)
+}
+
+// toLocalCategory returns the category of the symbol by the symbol format, the orders of the queried open orders
+// do not carry the market, so the category can only be inferred from the symbol:
+// the options are in the format BTC-27DEC24-100000-C, the linear dated futures are in the format BTCUSDT-27DEC24.
+func toLocalCategory(symbol string) bybitapi.Category {
+	switch {
+	case isOptionSymbol(symbol):
+		return bybitapi.CategoryOption
+	case strings.Contains(symbol, "-"):
+		return bybitapi.CategoryLinear
+	default:
+		return bybitapi.CategorySpot
+	}
+}
+
+// parseOptionStrikePrice parses the strike price from the option symbol, e.g. BTC-27DEC24-100000-C or BTC-27DEC24-100000-C-USDT,
+// since the instruments API does not provide the strike price.
+func parseOptionStrikePrice(symbol string) (fixedpoint.Value, error) {
+	if !isOptionSymbol(symbol) {
+		return fixedpoint.Zero, fmt.Errorf("unexpected option symbol: %s", symbol)
+	}
+
+	return fixedpoint.NewFromString(strings.Split(symbol, "-")[2])
+}
+
+// toGlobalOptionMarket converts the instrument of the option category into the market,
+// the quantity of the options is in the base coin, so the contract value is one.
+func toGlobalOptionMarket(m bybitapi.Instrument) (types.Market, error) {
+	optionType, err := toGlobalOptionType(m.OptionsType)
+	if err != nil {
+		return types.Market{}, err
+	}
+
+	strikePrice, err := parseOptionStrikePrice(m.Symbol)
+	if err != nil {
+		return types.Market{}, err
+	}
+
+	return types.Market{
+		Exchange:        types.ExchangeBybit,
+		Symbol:          m.Symbol,
+		LocalSymbol:     m.Symbol,
+		PricePrecision:  m.PriceFilter.TickSize.NumFractionalDigits(),
+		VolumePrecision: m.LotSizeFilter.QtyStep.NumFractionalDigits(),
+		QuoteCurrency:   m.QuoteCoin,
+		BaseCurrency:    m.BaseCoin,
+
+		// quantity
+		MinQuantity: m.LotSizeFilter.MinOrderQty,
+		MaxQuantity: m.LotSizeFilter.MaxOrderQty,
+		StepSize:    m.LotSizeFilter.QtyStep,
+
+		// price
+		MinPrice: m.PriceFilter.MinPrice,
+		MaxPrice: m.PriceFilter.MaxPrice,
+		TickSize: m.PriceFilter.TickSize,
+
+		DeliveryTime:  m.DeliveryTime.Time(),
+		ContractValue: fixedpoint.One,
+		OptionType:    optionType,
+		StrikePrice:   strikePrice,
+	}, nil
+}
+
+func toGlobalOptionTicker(stats bybitapi.Ticker, time time.Time) types.OptionTicker {
+	return types.OptionTicker{
+		Ticker:          toGlobalTicker(stats, time),
+		Symbol:          stats.Symbol,
+		MarkPrice:       stats.MarkPrice,
+		UnderlyingPrice: stats.UnderlyingPrice,
+		MarkIV:          stats.MarkIv,
+		BidIV:           stats.Bid1Iv,
+		AskIV:           stats.Ask1Iv,
+		Greeks: types.OptionGreeks{
+			Delta: stats.Delta,
+			Gamma: stats.Gamma,
+			Vega:  stats.Vega,
+			Theta: stats.Theta,
+		},
+	}
+}
+
+// toGlobalPremiumIndex converts the ticker of the linear perpetual contract into the premium index,
+// the funding interval of the instrument is in minutes.
+func toGlobalPremiumIndex(stats bybitapi.Ticker, instrument bybitapi.Instrument, t time.Time) *types.PremiumIndex {
+	return &types.PremiumIndex{
+		Symbol:          stats.Symbol,
+		MarkPrice:       stats.MarkPrice,
+		LastFundingRate: stats.FundingRate,
+		NextFundingTime: stats.NextFundingTime.Time(),
+		Time:            t,
+		FundingInterval: time.Duration(instrument.FundingInterval) * time.Minute,
+	}
+}
+
 func toGlobalTicker(stats bybitapi.Ticker, time time.Time) types.Ticker {
 	return types.Ticker{
 		Volume: stats.Volume24H,
@@ -68,10 +231,7 @@ func toGlobalOrder(order bybitapi.Order) (*types.Order, error) {
 		return nil, err
 	}
 
-	// linear and inverse : 42f4f364-82e1-49d3-ad1d-cd8cf9aa308d (UUID format)
-	// spot : 1468264727470772736 (only numbers)
-	// Now we only use spot trading.
-	orderIdNum, err := strconv.ParseUint(order.OrderId, 10, 64)
+	orderIdNum, err := toGlobalID(order.OrderId)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected order id: %s, err: %w", order.OrderId, err)
 	}
@@ -332,11 +492,11 @@ func toGlobalTrade(trade bybitapi.Trade) (*types.Trade, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unexpected side: %s, err: %w", trade.Side, err)
 	}
-	orderIdNum, err := strconv.ParseUint(trade.OrderId, 10, 64)
+	orderIdNum, err := toGlobalID(trade.OrderId)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected order id: %s, err: %w", trade.OrderId, err)
 	}
-	tradeIdNum, err := strconv.ParseUint(trade.ExecId, 10, 64)
+	tradeIdNum, err := toGlobalID(trade.ExecId)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected trade id: %s, err: %w", trade.ExecId, err)
 	}
@@ -41,27 +41,14 @@ func TestToGlobalMarket(t *testing.T) {
 		Innovation:    "0",
 		Status:        bybitapi.StatusTrading,
 		MarginTrading: "both",
-		LotSizeFilter: struct {
-			BasePrecision  fixedpoint.Value `json:"basePrecision"`
-			QuotePrecision fixedpoint.Value `json:"quotePrecision"`
-			MinOrderQty    fixedpoint.Value `json:"minOrderQty"`
-			MaxOrderQty    fixedpoint.Value `json:"maxOrderQty"`
-			MinOrderAmt    fixedpoint.Value `json:"minOrderAmt"`
-			MaxOrderAmt    fixedpoint.Value `json:"maxOrderAmt"`
-		}{
-			BasePrecision:  fixedpoint.NewFromFloat(0.000001),
-			QuotePrecision: fixedpoint.NewFromFloat(0.00000001),
-			MinOrderQty:    fixedpoint.NewFromFloat(0.000048),
-			MaxOrderQty:    fixedpoint.NewFromFloat(71.73956243),
-			MinOrderAmt:    fixedpoint.NewFromInt(1),
-			MaxOrderAmt:    fixedpoint.NewFromInt(2000000),
-		},
-		PriceFilter: struct {
-			TickSize fixedpoint.Value `json:"tickSize"`
-		}{
-			TickSize: fixedpoint.NewFromFloat(0.01),
-		},
 	}
+	inst.LotSizeFilter.BasePrecision = fixedpoint.NewFromFloat(0.000001)
+	inst.LotSizeFilter.QuotePrecision = fixedpoint.NewFromFloat(0.00000001)
+	inst.LotSizeFilter.MinOrderQty = fixedpoint.NewFromFloat(0.000048)
+	inst.LotSizeFilter.MaxOrderQty = fixedpoint.NewFromFloat(71.73956243)
+	inst.LotSizeFilter.MinOrderAmt = fixedpoint.NewFromInt(1)
+	inst.LotSizeFilter.MaxOrderAmt = fixedpoint.NewFromInt(2000000)
+	inst.PriceFilter.TickSize = fixedpoint.NewFromFloat(0.01)
 
 	exp := types.Market{
 		Symbol:          inst.Symbol,
@@ -83,6 +70,106 @@ func TestToGlobalMarket(t *testing.T) {
 	assert.Equal(t, toGlobalMarket(inst), exp)
 }
 
+func TestToGlobalDeliveryFuturesMarket(t *testing.T) {
+	inst := bybitapi.Instrument{
+		Symbol:       "BTCUSDT-27DEC24",
+		BaseCoin:     "BTC",
+		QuoteCoin:    "USDT",
+		Status:       bybitapi.StatusTrading,
+		ContractType: bybitapi.ContractTypeLinearFutures,
+		SettleCoin:   "USDT",
+		DeliveryTime: types.NewMillisecondTimestampFromInt(1735286400000),
+	}
+	inst.LotSizeFilter.MinOrderQty = fixedpoint.NewFromFloat(0.001)
+	inst.LotSizeFilter.MaxOrderQty = fixedpoint.NewFromInt(500)
+	inst.LotSizeFilter.QtyStep = fixedpoint.NewFromFloat(0.001)
+	inst.LotSizeFilter.MinNotionalValue = fixedpoint.NewFromInt(5)
+	inst.PriceFilter.TickSize = fixedpoint.NewFromFloat(0.5)
+	inst.PriceFilter.MinPrice = fixedpoint.NewFromFloat(0.5)
+	inst.PriceFilter.MaxPrice = fixedpoint.NewFromInt(1999999)
+
+	market := toGlobalDeliveryFuturesMarket(inst)
+	assert.Equal(t, types.ExchangeBybit, market.Exchange)
+	assert.Equal(t, "BTCUSDT-27DEC24", market.Symbol)
+	assert.Equal(t, 3, market.VolumePrecision)
+	assert.Equal(t, 1, market.PricePrecision)
+	assert.Equal(t, inst.LotSizeFilter.QtyStep, market.StepSize)
+	assert.Equal(t, inst.LotSizeFilter.MinNotionalValue, market.MinNotional)
+	assert.Equal(t, types.ContractTypeDelivery, market.ContractType)
+	assert.True(t, market.IsDeliveryContract())
+	assert.Equal(t, time.UnixMilli(1735286400000), market.DeliveryTime)
+}
+
+func TestToGlobalOptionMarket(t *testing.T) {
+	inst := bybitapi.Instrument{
+		Symbol:       "BTC-27DEC24-100000-C",
+		BaseCoin:     "BTC",
+		QuoteCoin:    "USD",
+		Status:       bybitapi.StatusTrading,
+		SettleCoin:   "USDC",
+		OptionsType:  "Call",
+		DeliveryTime: types.NewMillisecondTimestampFromInt(1735286400000),
+	}
+	inst.LotSizeFilter.MinOrderQty = fixedpoint.NewFromFloat(0.01)
+	inst.LotSizeFilter.MaxOrderQty = fixedpoint.NewFromInt(500)
+	inst.LotSizeFilter.QtyStep = fixedpoint.NewFromFloat(0.01)
+	inst.PriceFilter.TickSize = fixedpoint.NewFromInt(5)
+	inst.PriceFilter.MinPrice = fixedpoint.NewFromInt(5)
+	inst.PriceFilter.MaxPrice = fixedpoint.NewFromInt(10000000)
+
+	market, err := toGlobalOptionMarket(inst)
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC-27DEC24-100000-C", market.Symbol)
+	assert.True(t, market.IsOption())
+	assert.Equal(t, types.OptionTypeCall, market.OptionType)
+	assert.Equal(t, fixedpoint.NewFromInt(100000), market.StrikePrice)
+	assert.Equal(t, fixedpoint.One, market.ContractValue)
+	assert.Equal(t, 2, market.VolumePrecision)
+	assert.Equal(t, time.UnixMilli(1735286400000), market.DeliveryTime)
+
+	inst.Symbol = "BTCUSDT-27DEC24"
+	_, err = toGlobalOptionMarket(inst)
+	assert.Error(t, err)
+
+	inst.Symbol = "BTC-27DEC24-100000-C"
+	inst.OptionsType = ""
+	_, err = toGlobalOptionMarket(inst)
+	assert.Error(t, err)
+}
+
+func TestToGlobalOptionTicker(t *testing.T) {
+	ticker := bybitapi.Ticker{
+		Symbol:          "BTC-27DEC24-100000-C",
+		Bid1Price:       fixedpoint.NewFromInt(2950),
+		Ask1Price:       fixedpoint.NewFromInt(3010),
+		LastPrice:       fixedpoint.NewFromInt(2980),
+		Bid1Iv:          fixedpoint.NewFromFloat(0.5031),
+		Ask1Iv:          fixedpoint.NewFromFloat(0.5542),
+		MarkIv:          fixedpoint.NewFromFloat(0.5286),
+		MarkPrice:       fixedpoint.NewFromFloat(2975.5),
+		UnderlyingPrice: fixedpoint.NewFromFloat(95123.5),
+		Delta:           fixedpoint.NewFromFloat(0.4521),
+		Gamma:           fixedpoint.NewFromFloat(0.00002),
+		Vega:            fixedpoint.NewFromFloat(80.12),
+		Theta:           fixedpoint.NewFromFloat(-61.23),
+	}
+
+	timeNow := time.Now()
+	optionTicker := toGlobalOptionTicker(ticker, timeNow)
+	assert.Equal(t, toGlobalTicker(ticker, timeNow), optionTicker.Ticker)
+	assert.Equal(t, ticker.MarkPrice, optionTicker.MarkPrice)
+	assert.Equal(t, ticker.UnderlyingPrice, optionTicker.UnderlyingPrice)
+	assert.Equal(t, ticker.MarkIv, optionTicker.MarkIV)
+	assert.Equal(t, ticker.Bid1Iv, optionTicker.BidIV)
+	assert.Equal(t, ticker.Ask1Iv, optionTicker.AskIV)
+	assert.Equal(t, ticker.Delta, optionTicker.Greeks.Delta)
+	assert.Equal(t, ticker.Theta, optionTicker.Greeks.Theta)
+
+	// 0.4521 * 2 contracts
+	market := types.Market{ContractValue: fixedpoint.One}
+	assert.Equal(t, fixedpoint.NewFromFloat(0.9042), optionTicker.PositionDelta(market, fixedpoint.NewFromInt(2)))
+}
+
 func TestToGlobalTicker(t *testing.T) {
 	// sample
 	//{
@@ -864,3 +951,71 @@ func Test_toGlobalKLines(t *testing.T) {
 
 	assert.Equal(t, toGlobalKLines(symbol, interval, resp.List), expKlines)
 }
+
+func Test_toLocalCategory(t *testing.T) {
+	assert.Equal(t, bybitapi.CategorySpot, toLocalCategory("BTCUSDT"))
+	assert.Equal(t, bybitapi.CategoryLinear, toLocalCategory("BTCUSDT-27DEC24"))
+	assert.Equal(t, bybitapi.CategoryOption, toLocalCategory("BTC-27DEC24-100000-C"))
+
+	// the spot symbols are the linear perpetual contracts in the futures mode
+	e := &Exchange{}
+	e.UseFutures()
+	assert.Equal(t, bybitapi.CategoryLinear, e.category("BTCUSDT"))
+	assert.Equal(t, bybitapi.CategoryLinear, e.category("BTCUSDT-27DEC24"))
+	assert.Equal(t, bybitapi.CategoryOption, e.category("BTC-27DEC24-100000-C"))
+}
+
+func Test_toGlobalPerpetualMarket(t *testing.T) {
+	instrument := bybitapi.Instrument{
+		Symbol:       "ETHUSDT",
+		BaseCoin:     "ETH",
+		QuoteCoin:    "USDT",
+		ContractType: bybitapi.ContractTypeLinearPerpetual,
+	}
+	instrument.LotSizeFilter.QtyStep = fixedpoint.NewFromFloat(0.01)
+	instrument.LotSizeFilter.MinOrderQty = fixedpoint.NewFromFloat(0.01)
+	instrument.LotSizeFilter.MinNotionalValue = fixedpoint.NewFromFloat(5)
+	instrument.PriceFilter.TickSize = fixedpoint.NewFromFloat(0.01)
+
+	market := toGlobalPerpetualMarket(instrument)
+	assert.Equal(t, "ETHUSDT", market.Symbol)
+	assert.Equal(t, types.ContractTypePerpetual, market.ContractType)
+	assert.False(t, market.IsDeliveryContract())
+	assert.Equal(t, "0.01", market.StepSize.String())
+	assert.Equal(t, "5", market.MinNotional.String())
+}
+
+func Test_toGlobalID(t *testing.T) {
+	id, err := toGlobalID("1468264727470772736")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1468264727470772736), id)
+
+	id, err = toGlobalID("42f4f364-82e1-49d3-ad1d-cd8cf9aa308d")
+	assert.NoError(t, err)
+	assert.NotZero(t, id)
+
+	id2, err := toGlobalID("42f4f364-82e1-49d3-ad1d-cd8cf9aa308d")
+	assert.NoError(t, err)
+	assert.Equal(t, id, id2, "the hashed id should be stable")
+
+	_, err = toGlobalID("ABCD3123")
+	assert.Error(t, err)
+}
+
+func Test_toGlobalPremiumIndex(t *testing.T) {
+	ticker := bybitapi.Ticker{
+		Symbol:          "ETHUSDT",
+		MarkPrice:       fixedpoint.NewFromFloat(2250.5),
+		FundingRate:     fixedpoint.NewFromFloat(0.0001),
+		NextFundingTime: types.MillisecondTimestamp(time.UnixMilli(1703073600000)),
+	}
+	instrument := bybitapi.Instrument{Symbol: "ETHUSDT", FundingInterval: 240}
+	now := time.UnixMilli(1703059200000)
+
+	index := toGlobalPremiumIndex(ticker, instrument, now)
+	assert.Equal(t, "ETHUSDT", index.Symbol)
+	assert.Equal(t, "2250.5", index.MarkPrice.String())
+	assert.Equal(t, 4*time.Hour, index.FundingInterval)
+	assert.Equal(t, now, index.Time)
+	assert.Equal(t, "0.0002", index.NormalizedFundingRate(types.DefaultFundingInterval).String())
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"go.uber.org/multierr"
 	"golang.org/x/time/rate"
@@ -21,6 +21,8 @@ const (
 	defaultQueryLimit      = 50
 	defaultQueryTradeLimit = 100
 	defaultKLineLimit      = 1000
+	defaultDepthLimit      = 200
+	optionDepthLimit       = 25
 
 	queryTradeDurationLimit = 7 * 24 * time.Hour
 
@@ -46,6 +48,11 @@ var (
 	_ types.ExchangeTradeService      = &Exchange{}
 	_ types.Exchange                  = &Exchange{}
 	_ types.ExchangeOrderQueryService = &Exchange{}
+
+	_ types.DeliveryFuturesMarketService = &Exchange{}
+	_ types.OptionMarketDataService      = &Exchange{}
+	_ types.PremiumIndexService          = &Exchange{}
+	_ types.FuturesExchange              = &Exchange{}
 )
 
 type Exchange struct {
@@ -58,6 +65,9 @@ type Exchange struct {
 	// fee rate to get the fee currency.
 	// https://bybit-exchange.github.io/docs/v5/enum#spot-fee-currency-instruction
 	FeeRatePoller
+
+	// FuturesSettings switches the session to the linear perpetual contracts, the symbols are the same as the spot symbols
+	types.FuturesSettings
 }
 
 func New(key, secret string) (*Exchange, error) {
@@ -96,11 +106,25 @@ func (e *Exchange) PlatformFeeCurrency() string {
 	return ""
 }
 
+// category returns the category of the symbol, the symbols without the delivery date are the linear perpetual contracts in the futures mode
+func (e *Exchange) category(symbol string) bybitapi.Category {
+	category := toLocalCategory(symbol)
+	if category == bybitapi.CategorySpot && e.IsFutures {
+		return bybitapi.CategoryLinear
+	}
+
+	return category
+}
+
 func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 	if err := sharedRateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
 	}
 
+	if e.IsFutures {
+		return e.queryPerpetualMarkets(ctx)
+	}
+
 	instruments, err := e.client.NewGetInstrumentsInfoRequest().Do(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get instruments, err: %v", err)
@@ -114,12 +138,182 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 	return marketMap, nil
 }
 
+func (e *Exchange) queryPerpetualMarkets(ctx context.Context) (types.MarketMap, error) {
+	instruments, err := e.client.NewGetInstrumentsInfoRequest().Category(bybitapi.CategoryLinear).Limit(1000).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linear instruments, err: %v", err)
+	}
+
+	marketMap := types.MarketMap{}
+	for _, s := range instruments.List {
+		if s.ContractType != bybitapi.ContractTypeLinearPerpetual {
+			continue
+		}
+
+		marketMap.Add(toGlobalPerpetualMarket(s))
+	}
+
+	return marketMap, nil
+}
+
+// QueryDeliveryFuturesMarkets queries the dated futures contracts of the linear category,
+// the inverse futures are excluded since their quantity is in USD instead of the base coin.
+func (e *Exchange) QueryDeliveryFuturesMarkets(ctx context.Context) (types.MarketMap, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	instruments, err := e.client.NewGetInstrumentsInfoRequest().Category(bybitapi.CategoryLinear).Limit(1000).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linear instruments, err: %v", err)
+	}
+
+	marketMap := types.MarketMap{}
+	for _, s := range instruments.List {
+		if s.ContractType != bybitapi.ContractTypeLinearFutures {
+			continue
+		}
+
+		marketMap.Add(toGlobalDeliveryFuturesMarket(s))
+	}
+
+	return marketMap, nil
+}
+
+// QueryOptionMarkets queries the option instruments of the base coin, e.g. BTC
+func (e *Exchange) QueryOptionMarkets(ctx context.Context, underlying string) (types.MarketMap, error) {
+	marketMap := types.MarketMap{}
+
+	cursor := ""
+	for {
+		if err := sharedRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+		}
+
+		req := e.client.NewGetInstrumentsInfoRequest().
+			Category(bybitapi.CategoryOption).
+			BaseCoin(underlying).
+			Limit(1000)
+		if len(cursor) > 0 {
+			req.Cursor(cursor)
+		}
+
+		instruments, err := req.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get option instruments, err: %v", err)
+		}
+
+		for _, s := range instruments.List {
+			market, err := toGlobalOptionMarket(s)
+			if err != nil {
+				log.WithError(err).Warnf("skip option instrument %s", s.Symbol)
+				continue
+			}
+
+			marketMap.Add(market)
+		}
+
+		if len(instruments.NextPageCursor) == 0 {
+			break
+		}
+		cursor = instruments.NextPageCursor
+	}
+
+	return marketMap, nil
+}
+
+// QueryOptionTickers queries the tickers with the implied volatilities and the greeks of the options of the base coin, e.g. BTC
+func (e *Exchange) QueryOptionTickers(ctx context.Context, underlying string) (map[string]types.OptionTicker, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
+	}
+
+	allTickers, err := e.client.NewGetTickersRequest().
+		Category(bybitapi.CategoryOption).
+		BaseCoin(underlying).
+		DoWithResponseTime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call option tickers, err: %w", err)
+	}
+
+	tickers := make(map[string]types.OptionTicker, len(allTickers.List))
+	for _, s := range allTickers.List {
+		tickers[s.Symbol] = toGlobalOptionTicker(s, allTickers.ClosedTime.Time())
+	}
+
+	return tickers, nil
+}
+
+// QueryPremiumIndex queries the mark price and the funding rate of the linear perpetual contract,
+// the funding interval varies by symbol, so it's queried from the instrument info.
+func (e *Exchange) QueryPremiumIndex(ctx context.Context, symbol string) (*types.PremiumIndex, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
+	}
+
+	tickers, err := e.client.NewGetTickersRequest().
+		Category(bybitapi.CategoryLinear).
+		Symbol(symbol).
+		DoWithResponseTime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call linear ticker, symbol: %s, err: %w", symbol, err)
+	}
+
+	if len(tickers.List) != 1 {
+		return nil, fmt.Errorf("unexpected ticker length, exp:1, got:%d", len(tickers.List))
+	}
+
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	instruments, err := e.client.NewGetInstrumentsInfoRequest().
+		Category(bybitapi.CategoryLinear).
+		Symbol(symbol).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query linear instrument, symbol: %s, err: %w", symbol, err)
+	}
+
+	if len(instruments.List) != 1 {
+		return nil, fmt.Errorf("unexpected instrument length, exp:1, got:%d", len(instruments.List))
+	}
+
+	return toGlobalPremiumIndex(tickers.List[0], instruments.List[0], tickers.ClosedTime.Time()), nil
+}
+
+// QueryDepth queries the order book snapshot of the spot or the option market, the returned update ID is the update ID of the snapshot
+func (e *Exchange) QueryDepth(ctx context.Context, symbol string) (types.SliceOrderBook, int64, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return types.SliceOrderBook{}, 0, fmt.Errorf("depth rate limiter wait error: %w", err)
+	}
+
+	req := e.client.NewGetOrderBookRequest().Symbol(symbol)
+	if category := e.category(symbol); category == bybitapi.CategoryOption {
+		req.Category(category).Limit(optionDepthLimit)
+	} else {
+		req.Category(category).Limit(defaultDepthLimit)
+	}
+
+	book, err := req.Do(ctx)
+	if err != nil {
+		return types.SliceOrderBook{}, 0, fmt.Errorf("failed to query order book, symbol: %s, err: %w", symbol, err)
+	}
+
+	return types.SliceOrderBook{
+		Symbol: symbol,
+		Time:   book.Timestamp.Time(),
+		Bids:   book.Bids,
+		Asks:   book.Asks,
+	}, book.UpdateId, nil
+}
+
 func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
 	if err := sharedRateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("ticker order rate limiter wait error: %w", err)
 	}
 
-	s, err := e.client.NewGetTickersRequest().Symbol(symbol).DoWithResponseTime(ctx)
+	s, err := e.client.NewGetTickersRequest().Category(e.category(symbol)).Symbol(symbol).DoWithResponseTime(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call ticker, symbol: %s, err: %w", symbol, err)
 	}
@@ -150,7 +344,12 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 	if err := sharedRateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
 	}
-	allTickers, err := e.client.NewGetTickersRequest().DoWithResponseTime(ctx)
+	req := e.client.NewGetTickersRequest()
+	if e.IsFutures {
+		req.Category(bybitapi.CategoryLinear)
+	}
+
+	allTickers, err := req.DoWithResponseTime(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call ticker, err: %w", err)
 	}
@@ -173,7 +372,7 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
 	cursor := ""
 	// OpenOnlyOrder: UTA2.0, UTA1.0, classic account query open status orders (e.g., New, PartiallyFilled) only
-	req := e.client.NewGetOpenOrderRequest().Symbol(symbol).OpenOnly(bybitapi.OpenOnlyOrder).Limit(defaultQueryLimit)
+	req := e.client.NewGetOpenOrderRequest().Category(e.category(symbol)).Symbol(symbol).OpenOnly(bybitapi.OpenOnlyOrder).Limit(defaultQueryLimit)
 	for {
 		if len(cursor) != 0 {
 			// the default limit is 20.
@@ -212,7 +411,7 @@ func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.O
 		return nil, errors.New("only accept one parameter of OrderID/ClientOrderID")
 	}
 
-	req := e.client.NewGetOrderHistoriesRequest()
+	req := e.client.NewGetOrderHistoriesRequest().Category(e.category(q.Symbol))
 	if len(q.Symbol) != 0 {
 		req.Symbol(q.Symbol)
 	}
@@ -239,7 +438,7 @@ func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.O
 // QueryOrderTrades You can query by symbol, baseCoin, orderId and orderLinkId, and if you pass multiple params,
 // the system will process them according to this priority: orderId > orderLinkId > symbol > baseCoin.
 func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) (trades []types.Trade, err error) {
-	req := e.client.NewGetExecutionListRequest()
+	req := e.client.NewGetExecutionListRequest().Category(e.category(q.Symbol))
 	if len(q.ClientOrderID) != 0 {
 		req.OrderLinkId(q.ClientOrderID)
 	}
@@ -264,6 +463,10 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 	req := e.client.NewPlaceOrderRequest()
 	req.Symbol(order.Market.Symbol)
 
+	category := e.category(order.Market.Symbol)
+	isOption := category == bybitapi.CategoryOption
+	req.Category(category)
+
 	// set order type
 	orderType, err := toLocalOrderType(order.Type)
 	if err != nil {
@@ -283,6 +486,11 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 	case types.OrderTypeStopLimit, types.OrderTypeLimit, types.OrderTypeLimitMaker:
 		req.Price(order.Market.FormatPrice(order.Price))
 	case types.OrderTypeMarket:
+		// the quantity of the options and the linear futures is always in the base coin
+		if category != bybitapi.CategorySpot {
+			break
+		}
+
 		// Because our order.Quantity unit is base coin, so we indicate the target currency to Base.
 		if order.Side == types.SideTypeBuy {
 			req.MarketUnit(bybitapi.MarketUnitBase)
@@ -307,6 +515,10 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 	}
 	if len(order.ClientOrderID) > 0 {
 		req.OrderLinkId(order.ClientOrderID)
+	} else if isOption {
+		// the orderLinkId is required for the options
+		order.ClientOrderID = uuid.New().String()
+		req.OrderLinkId(order.ClientOrderID)
 	}
 
 	timeNow := time.Now()
@@ -319,7 +531,7 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 		return nil, fmt.Errorf("unexpected order id, resp: %#v, order: %#v", res, order)
 	}
 
-	intOrderId, err := strconv.ParseUint(res.OrderId, 10, 64)
+	intOrderId, err := toGlobalID(res.OrderId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse orderId: %s", res.OrderId)
 	}
@@ -364,7 +576,9 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (err
 			continue
 		}
 
-		req.Symbol(order.Market.Symbol)
+		// the orders queried from the exchange do not carry the market, use the order symbol instead
+		req.Symbol(order.Symbol)
+		req.Category(e.category(order.Symbol))
 
 		res, err := req.Do(ctx)
 		if err != nil {
@@ -415,6 +629,7 @@ func (e *Exchange) QueryClosedOrders(
 		since = newStartTime
 	}
 	req := e.client.NewGetOrderHistoriesRequest().
+		Category(e.category(symbol)).
 		Symbol(symbol).
 		Limit(defaultQueryLimit).
 		StartTime(since).
@@ -467,6 +682,14 @@ func (e *Exchange) queryTrades(ctx context.Context, req *bybitapi.GetExecutionLi
 				return nil, fmt.Errorf("failed to convert trade, err: %v", err)
 			}
 
+			// the fee currency is only available in the spot category, the fees of the linear contracts are charged in the quote coin
+			if e.IsFutures {
+				trade.IsFutures = true
+				if market, ok := e.marketsInfo[trade.Symbol]; ok && len(trade.FeeCurrency) == 0 {
+					trade.FeeCurrency = market.QuoteCurrency
+				}
+			}
+
 			trades = append(trades, *trade)
 		}
 
@@ -488,7 +711,7 @@ QueryTrades queries trades by time range.
 ** If both are passed, the rule is endTime - startTime <= 7 days **
 */
 func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) (trades []types.Trade, err error) {
-	req := e.client.NewGetExecutionListRequest()
+	req := e.client.NewGetExecutionListRequest().Category(e.category(symbol))
 	req.Symbol(symbol)
 
 	if options.StartTime != nil && options.EndTime != nil {
@@ -558,7 +781,8 @@ e.q. 15m interval k line can be represented as 00:00:00.000 ~ 00:14:59.999
 func (e *Exchange) QueryKLines(
 	ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions,
 ) ([]types.KLine, error) {
-	req := e.client.NewGetKLinesRequest().Symbol(symbol)
+	category := e.category(symbol)
+	req := e.client.NewGetKLinesRequest().Category(category).Symbol(symbol)
 	intervalStr, err := toLocalInterval(interval)
 	if err != nil {
 		return nil, err
@@ -589,7 +813,7 @@ func (e *Exchange) QueryKLines(
 		return nil, fmt.Errorf("failed to call k line, err: %w", err)
 	}
 
-	if resp.Category != bybitapi.CategorySpot {
+	if resp.Category != category {
 		return nil, fmt.Errorf("unexpected category: %s", resp.Category)
 	}
 
@@ -624,5 +848,7 @@ func (e *Exchange) GetAllFeeRates(ctx context.Context) (bybitapi.FeeRates, error
 }
 
 func (e *Exchange) NewStream() types.Stream {
-	return NewStream(e.key, e.secret, e)
+	stream := NewStream(e.key, e.secret, e)
+	stream.FuturesSettings = e.FuturesSettings
+	return stream
 }
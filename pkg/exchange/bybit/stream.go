@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -57,6 +58,7 @@ type StreamDataProvider interface {
 //go:generate callbackgen -type Stream
 type Stream struct {
 	types.StandardStream
+	types.FuturesSettings
 
 	key, secret        string
 	streamDataProvider StreamDataProvider
@@ -161,6 +163,9 @@ func (s *Stream) createEndpoint(_ context.Context) (string, error) {
 	var url string
 	if s.PublicOnly {
 		url = bybitapi.WsSpotPublicSpotUrl
+		if s.IsFutures {
+			url = bybitapi.WsLinearPublicUrl
+		}
 	} else {
 		url = bybitapi.WsSpotPrivateUrl
 	}
@@ -401,8 +406,10 @@ func (s *Stream) handleWalletEvent(events []bybitapi.WalletBalances) {
 
 func (s *Stream) handleOrderEvent(events []OrderEvent) {
 	for _, event := range events {
-		if event.Category != bybitapi.CategorySpot {
-			return
+		switch event.Category {
+		case bybitapi.CategorySpot, bybitapi.CategoryLinear, bybitapi.CategoryOption:
+		default:
+			continue
 		}
 
 		gOrder, err := toGlobalOrder(event.Order)
@@ -451,7 +458,8 @@ func getFeeRate(symbol string, poller FeeRatePoller, marketsInfo types.MarketMap
 			QuoteCoin: "",
 		}
 
-		if market, ok := marketsInfo[symbol]; ok {
+		// the linear dated futures (e.g. BTCUSDT-27DEC24) share the coins of the spot market
+		if market, ok := marketsInfo[strings.SplitN(symbol, "-", 2)[0]]; ok {
 			feeRate.BaseCoin = market.BaseCurrency
 			feeRate.QuoteCoin = market.QuoteCurrency
 		}
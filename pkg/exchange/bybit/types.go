@@ -291,7 +291,6 @@ type TradeEvent struct {
 	bybitapi.Trade
 	// linear and inverse order id format: 42f4f364-82e1-49d3-ad1d-cd8cf9aa308d (UUID format)
 	// spot: 1468264727470772736 (only numbers)
-	// we only use spot trading.
 	OrderLinkId string            `json:"orderLinkId"`
 	Category    bybitapi.Category `json:"category"`
 
@@ -329,7 +328,9 @@ type TradeEvent struct {
 }
 
 func (t *TradeEvent) toGlobalTrade(symbolFee SymbolFeeDetail) (*types.Trade, error) {
-	if t.Category != bybitapi.CategorySpot {
+	switch t.Category {
+	case bybitapi.CategorySpot, bybitapi.CategoryLinear, bybitapi.CategoryOption:
+	default:
 		return nil, fmt.Errorf("unexected category: %s", t.Category)
 	}
 
@@ -338,12 +339,12 @@ func (t *TradeEvent) toGlobalTrade(symbolFee SymbolFeeDetail) (*types.Trade, err
 		return nil, err
 	}
 
-	orderIdNum, err := strconv.ParseUint(t.OrderId, 10, 64)
+	orderIdNum, err := toGlobalID(t.OrderId)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected order id: %s, err: %w", t.OrderId, err)
 	}
 
-	execIdNum, err := strconv.ParseUint(t.ExecId, 10, 64)
+	execIdNum, err := toGlobalID(t.ExecId)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected exec id: %s, err: %w", t.ExecId, err)
 	}
@@ -364,6 +365,12 @@ func (t *TradeEvent) toGlobalTrade(symbolFee SymbolFeeDetail) (*types.Trade, err
 		FeeCurrency:   "",
 	}
 	trade.FeeCurrency, trade.Fee = calculateFee(t.Trade, symbolFee)
+
+	// the fees of the linear futures and the options are charged in the settlement coin
+	if t.Category != bybitapi.CategorySpot {
+		trade.FeeCurrency, trade.Fee = symbolFee.QuoteCoin, quoteCoinAsFee(t.Trade, symbolFee)
+		trade.IsFutures = t.Category == bybitapi.CategoryLinear
+	}
 	return trade, nil
 }
 
@@ -10,13 +10,25 @@ import (
 	"github.com/c9s/bbgo/pkg/types"
 )
 
+// toGlobalSymbol converts the spot instrument ID into the global symbol, e.g. BTC-USDT -> BTCUSDT,
+// the derivatives instrument IDs (e.g. BTC-USDT-250328, BTC-USD-241227-100000-C) are kept as they are,
+// so that they can be converted back by toLocalSymbol.
 func toGlobalSymbol(symbol string) string {
+	if strings.Count(symbol, "-") > 1 {
+		return symbol
+	}
+
 	return strings.ReplaceAll(symbol, "-", "")
 }
 
 //go:generate sh -c "echo \"package okex\nvar spotSymbolMap = map[string]string{\n\" $(curl -s -L 'https://www.okx.com/api/v5/public/instruments?instType=SPOT' | jq -r '.data[] | \"\\(.instId | sub(\"-\" ; \"\") | tojson ): \\( .instId | tojson),\n\"') \"\n}\" > symbols.go"
 //go:generate go run gensymbols.go
 func toLocalSymbol(symbol string) string {
+	// the futures and the option symbols are the instrument IDs, e.g. BTC-USDT-250328, BTC-USD-241227-100000-C
+	if strings.Contains(symbol, "-") {
+		return symbol
+	}
+
 	if s, ok := spotSymbolMap[symbol]; ok {
 		return s
 	}
@@ -25,6 +37,29 @@ func toLocalSymbol(symbol string) string {
 	return symbol
 }
 
+// toLocalPerpetualSymbol converts the global symbol into the instrument ID of the perpetual swap, e.g. BTCUSDT -> BTC-USDT-SWAP
+func toLocalPerpetualSymbol(symbol string) string {
+	if strings.HasSuffix(symbol, "-SWAP") {
+		return symbol
+	}
+
+	return toLocalSymbol(symbol) + "-SWAP"
+}
+
+// toLocalInstrumentType returns the instrument type of the global symbol by the instrument ID format
+func toLocalInstrumentType(symbol string) okexapi.InstrumentType {
+	switch n := strings.Count(symbol, "-"); {
+	case n <= 1:
+		return okexapi.InstrumentTypeSpot
+	case strings.HasSuffix(symbol, "-SWAP"):
+		return okexapi.InstrumentTypeSwap
+	case n == 2:
+		return okexapi.InstrumentTypeFutures
+	default:
+		return okexapi.InstrumentTypeOption
+	}
+}
+
 func toGlobalTicker(marketTicker okexapi.MarketTicker) *types.Ticker {
 	return &types.Ticker{
 		Time:   marketTicker.Timestamp.Time(),
@@ -38,6 +73,175 @@ func toGlobalTicker(marketTicker okexapi.MarketTicker) *types.Ticker {
 	}
 }
 
+func toGlobalContractType(alias string) types.ContractType {
+	switch alias {
+	case "this_week":
+		return types.ContractTypeCurrentWeek
+	case "next_week":
+		return types.ContractTypeNextWeek
+	case "this_month":
+		return types.ContractTypeCurrentMonth
+	case "next_month":
+		return types.ContractTypeNextMonth
+	case "quarter":
+		return types.ContractTypeCurrentQuarter
+	case "next_quarter":
+		return types.ContractTypeNextQuarter
+	default:
+		return types.ContractTypeDelivery
+	}
+}
+
+// toGlobalContractValue returns the base quantity of one contract, i.e. ctVal * ctMult
+func toGlobalContractValue(instrument okexapi.InstrumentInfo) (fixedpoint.Value, error) {
+	contractValue, err := fixedpoint.NewFromString(instrument.ContractValue)
+	if err != nil {
+		return fixedpoint.Zero, fmt.Errorf("unexpected contract value %q of instrument %s: %w", instrument.ContractValue, instrument.InstrumentID, err)
+	}
+
+	if len(instrument.ContractMultiplier) > 0 {
+		contractMultiplier, err := fixedpoint.NewFromString(instrument.ContractMultiplier)
+		if err != nil {
+			return fixedpoint.Zero, fmt.Errorf("unexpected contract multiplier %q of instrument %s: %w", instrument.ContractMultiplier, instrument.InstrumentID, err)
+		}
+		contractValue = contractValue.Mul(contractMultiplier)
+	}
+
+	return contractValue, nil
+}
+
+// toGlobalDeliveryFuturesMarket converts the FUTURES instrument into the market,
+// the base and the quote currencies are taken from the underlying index since OKX leaves them empty for FUTURES.
+// the symbol of the market is the instrument ID, e.g. BTC-USDT-250328, and the quantity is in contracts.
+func toGlobalDeliveryFuturesMarket(instrument okexapi.InstrumentInfo) (types.Market, error) {
+	currencies := strings.Split(instrument.Underlying, "-")
+	if len(currencies) != 2 {
+		return types.Market{}, fmt.Errorf("unexpected underlying %q of instrument %s", instrument.Underlying, instrument.InstrumentID)
+	}
+
+	contractValue, err := toGlobalContractValue(instrument)
+	if err != nil {
+		return types.Market{}, err
+	}
+
+	return types.Market{
+		Exchange:    types.ExchangeOKEx,
+		Symbol:      toGlobalSymbol(instrument.InstrumentID),
+		LocalSymbol: instrument.InstrumentID,
+
+		BaseCurrency:  currencies[0],
+		QuoteCurrency: currencies[1],
+
+		PricePrecision:  instrument.TickSize.NumFractionalDigits(),
+		VolumePrecision: instrument.LotSize.NumFractionalDigits(),
+		TickSize:        instrument.TickSize,
+
+		// the quantity of FUTURES instruments is in contracts
+		StepSize:    instrument.LotSize,
+		MinQuantity: instrument.MinSize,
+
+		// there is no min notional filter for the FUTURES instruments, the min size is the only limit
+		MinNotional: fixedpoint.Zero,
+		MinAmount:   fixedpoint.Zero,
+
+		ContractType:  toGlobalContractType(instrument.Alias),
+		DeliveryTime:  instrument.ExpiryTime.Time(),
+		ContractValue: contractValue,
+	}, nil
+}
+
+func toGlobalOptionType(optType string) (types.OptionType, error) {
+	switch optType {
+	case "C":
+		return types.OptionTypeCall, nil
+	case "P":
+		return types.OptionTypePut, nil
+	}
+
+	return "", fmt.Errorf("unexpected option type: %s", optType)
+}
+
+// toGlobalOptionMarket converts the OPTION instrument into the market,
+// the symbol of the option market is the instrument ID since there is no compact form for the options.
+func toGlobalOptionMarket(instrument okexapi.InstrumentInfo) (types.Market, error) {
+	optionType, err := toGlobalOptionType(instrument.OptionType)
+	if err != nil {
+		return types.Market{}, err
+	}
+
+	currencies := strings.Split(instrument.Underlying, "-")
+	if len(currencies) != 2 {
+		return types.Market{}, fmt.Errorf("unexpected underlying %q of instrument %s", instrument.Underlying, instrument.InstrumentID)
+	}
+
+	contractValue, err := toGlobalContractValue(instrument)
+	if err != nil {
+		return types.Market{}, err
+	}
+
+	return types.Market{
+		Exchange:    types.ExchangeOKEx,
+		Symbol:      instrument.InstrumentID,
+		LocalSymbol: instrument.InstrumentID,
+
+		// the options are priced in the settlement currency, e.g. BTC for BTC-USD options
+		BaseCurrency:  currencies[0],
+		QuoteCurrency: instrument.SettleCurrency,
+
+		PricePrecision:  instrument.TickSize.NumFractionalDigits(),
+		VolumePrecision: instrument.LotSize.NumFractionalDigits(),
+		TickSize:        instrument.TickSize,
+
+		// the quantity of OPTION instruments is in contracts
+		StepSize:    instrument.LotSize,
+		MinQuantity: instrument.MinSize,
+
+		MinNotional: fixedpoint.Zero,
+		MinAmount:   fixedpoint.Zero,
+
+		DeliveryTime:  instrument.ExpiryTime.Time(),
+		ContractValue: contractValue,
+		OptionType:    optionType,
+		StrikePrice:   instrument.StrikePrice,
+	}, nil
+}
+
+func toGlobalOptionTicker(marketTicker okexapi.MarketTicker, summary okexapi.OptionSummary, markPrice fixedpoint.Value) types.OptionTicker {
+	return types.OptionTicker{
+		Ticker:          *toGlobalTicker(marketTicker),
+		Symbol:          marketTicker.InstrumentID,
+		MarkPrice:       markPrice,
+		UnderlyingPrice: summary.ForwardPrice,
+		MarkIV:          summary.MarkVolatility,
+		BidIV:           summary.BidVolatility,
+		AskIV:           summary.AskVolatility,
+		Greeks: types.OptionGreeks{
+			Delta: summary.DeltaBS,
+			Gamma: summary.GammaBS,
+			Vega:  summary.VegaBS,
+			Theta: summary.ThetaBS,
+		},
+	}
+}
+
+// toGlobalPremiumIndex converts the funding rate and the mark price of the perpetual swap into the premium index,
+// the funding interval is the period between the current funding time and the next funding time.
+func toGlobalPremiumIndex(symbol string, fundingRate okexapi.FundingRate, markPrice okexapi.MarkPrice) *types.PremiumIndex {
+	index := &types.PremiumIndex{
+		Symbol:          symbol,
+		MarkPrice:       markPrice.MarkPrice,
+		LastFundingRate: fundingRate.FundingRate,
+		NextFundingTime: fundingRate.FundingTime.Time(),
+		Time:            markPrice.Timestamp.Time(),
+	}
+
+	if fundingTime, nextFundingTime := fundingRate.FundingTime.Time(), fundingRate.NextFundingTime.Time(); nextFundingTime.After(fundingTime) {
+		index.FundingInterval = nextFundingTime.Sub(fundingTime)
+	}
+
+	return index
+}
+
 func toGlobalBalance(account *okexapi.Account) types.BalanceMap {
 	var balanceMap = types.BalanceMap{}
 	for _, balanceDetail := range account.Details {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -243,3 +244,103 @@ func Test_processMarketBuyQuantity(t *testing.T) {
 		assert.ErrorContains(err, "unexpected")
 	})
 }
+
+func Test_toGlobalDeliveryFuturesMarket(t *testing.T) {
+	data := `{"alias":"quarter","baseCcy":"","category":"1","ctMult":"1","ctType":"linear","ctVal":"0.01","ctValCcy":"BTC","expTime":"1743148800000","instFamily":"BTC-USDT","instId":"BTC-USDT-250328","instType":"FUTURES","lever":"100","listTime":"1727424000000","lotSz":"1","maxIcebergSz":"1000000.0000000000000000","maxLmtAmt":"20000000","maxLmtSz":"1000000","maxMktAmt":"","maxMktSz":"3000","maxStopSz":"3000","maxTriggerSz":"1000000.0000000000000000","maxTwapSz":"1000000.0000000000000000","minSz":"1","optType":"","quoteCcy":"","settleCcy":"USDT","state":"live","stk":"","tickSz":"0.1","uly":"BTC-USDT"}`
+
+	var instrument okexapi.InstrumentInfo
+	err := json.Unmarshal([]byte(data), &instrument)
+	assert.NoError(t, err)
+
+	market, err := toGlobalDeliveryFuturesMarket(instrument)
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC-USDT-250328", market.Symbol)
+	assert.Equal(t, "BTC-USDT-250328", market.LocalSymbol)
+	assert.Equal(t, instrument.InstrumentID, toLocalSymbol(market.Symbol))
+	assert.Equal(t, okexapi.InstrumentTypeFutures, toLocalInstrumentType(market.Symbol))
+	assert.Equal(t, "BTC", market.BaseCurrency)
+	assert.Equal(t, "USDT", market.QuoteCurrency)
+	assert.Equal(t, types.ContractTypeCurrentQuarter, market.ContractType)
+	assert.True(t, market.IsDeliveryContract())
+	assert.Equal(t, int64(1743148800000), market.DeliveryTime.UnixMilli())
+
+	// one contract is 0.01 BTC, so 0.05 BTC is 5 contracts
+	assert.Equal(t, "0.01", market.ContractValue.String())
+	assert.Equal(t, "5", market.ToContractQuantity(fixedpoint.NewFromFloat(0.05)).String())
+	assert.Equal(t, "0.05", market.ToBaseQuantity(fixedpoint.NewFromInt(5)).String())
+	assert.Equal(t, "1", market.TruncateQuantity(market.ToContractQuantity(fixedpoint.NewFromFloat(0.019))).String())
+
+	instrument.Underlying = ""
+	_, err = toGlobalDeliveryFuturesMarket(instrument)
+	assert.Error(t, err)
+}
+
+func Test_toGlobalOptionMarket(t *testing.T) {
+	data := `{"alias":"","baseCcy":"","category":"1","ctMult":"0.01","ctType":"","ctVal":"1","ctValCcy":"BTC","expTime":"1735286400000","instFamily":"BTC-USD","instId":"BTC-USD-241227-100000-C","instType":"OPTION","lever":"","listTime":"1719561600000","lotSz":"1","minSz":"1","optType":"C","quoteCcy":"","settleCcy":"BTC","state":"live","stk":"100000","tickSz":"0.0005","uly":"BTC-USD"}`
+
+	var instrument okexapi.InstrumentInfo
+	err := json.Unmarshal([]byte(data), &instrument)
+	assert.NoError(t, err)
+
+	market, err := toGlobalOptionMarket(instrument)
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC-USD-241227-100000-C", market.Symbol)
+	assert.Equal(t, "BTC-USD-241227-100000-C", market.LocalSymbol)
+	assert.Equal(t, "BTC", market.BaseCurrency)
+	assert.Equal(t, "BTC", market.QuoteCurrency)
+	assert.True(t, market.IsOption())
+	assert.Equal(t, types.OptionTypeCall, market.OptionType)
+	assert.Equal(t, fixedpoint.NewFromInt(100000), market.StrikePrice)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.01"), market.ContractValue)
+	assert.Equal(t, int64(1735286400000), market.DeliveryTime.UnixMilli())
+
+	instrument.OptionType = ""
+	_, err = toGlobalOptionMarket(instrument)
+	assert.Error(t, err)
+}
+
+func Test_toGlobalOptionTicker(t *testing.T) {
+	summaryData := `{"askVol":"0.5542","bidVol":"0.5031","delta":"0.0412","deltaBS":"0.4521","fwdPx":"95123.5","gamma":"1.7453","gammaBS":"0.00002","instId":"BTC-USD-241227-100000-C","instType":"OPTION","lever":"31.2","markVol":"0.5286","realVol":"","volLv":"0.5012","theta":"-0.0005","thetaBS":"-61.23","ts":"1733203200000","uly":"BTC-USD","vega":"0.0008","vegaBS":"80.12"}`
+	tickerData := `{"instType":"OPTION","instId":"BTC-USD-241227-100000-C","last":"0.032","lastSz":"1","askPx":"0.0325","askSz":"10","bidPx":"0.031","bidSz":"12","open24h":"0.03","high24h":"0.034","low24h":"0.029","volCcy24h":"1.2","vol24h":"120","ts":"1733203200000"}`
+
+	var summary okexapi.OptionSummary
+	assert.NoError(t, json.Unmarshal([]byte(summaryData), &summary))
+
+	var marketTicker okexapi.MarketTicker
+	assert.NoError(t, json.Unmarshal([]byte(tickerData), &marketTicker))
+
+	ticker := toGlobalOptionTicker(marketTicker, summary, fixedpoint.MustNewFromString("0.0318"))
+	assert.Equal(t, "BTC-USD-241227-100000-C", ticker.Symbol)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.0318"), ticker.MarkPrice)
+	assert.Equal(t, fixedpoint.MustNewFromString("95123.5"), ticker.UnderlyingPrice)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.5286"), ticker.MarkIV)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.5031"), ticker.BidIV)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.5542"), ticker.AskIV)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.4521"), ticker.Greeks.Delta)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.031"), ticker.Buy)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.0325"), ticker.Sell)
+}
+
+func Test_toGlobalPremiumIndex(t *testing.T) {
+	assert.Equal(t, "BTC-USDT-SWAP", toLocalPerpetualSymbol("BTCUSDT"))
+	assert.Equal(t, "BTC-USDT-SWAP", toLocalPerpetualSymbol("BTC-USDT-SWAP"))
+
+	var fundingRate okexapi.FundingRate
+	err := json.Unmarshal([]byte(`{"fundingRate":"0.0001","fundingTime":"1703059200000","instId":"BTC-USDT-SWAP","instType":"SWAP","nextFundingRate":"","nextFundingTime":"1703073600000"}`), &fundingRate)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var markPrice okexapi.MarkPrice
+	err = json.Unmarshal([]byte(`{"instId":"BTC-USDT-SWAP","instType":"SWAP","markPx":"42310.6","ts":"1703055600000"}`), &markPrice)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	index := toGlobalPremiumIndex("BTCUSDT", fundingRate, markPrice)
+	assert.Equal(t, "BTCUSDT", index.Symbol)
+	assert.Equal(t, "42310.6", index.MarkPrice.String())
+	assert.Equal(t, "0.0001", index.LastFundingRate.String())
+	assert.Equal(t, 4*time.Hour, index.FundingInterval)
+	assert.Equal(t, "0.0002", index.NormalizedFundingRate(types.DefaultFundingInterval).String())
+}
@@ -44,6 +44,14 @@ var (
 	queryTradeLimiter = rate.NewLimiter(rate.Every(200*time.Millisecond), 1)
 	// Rate Limit: 40 requests per 2 seconds, Rate limit rule: IP
 	queryKLineLimiter = rate.NewLimiter(rate.Every(50*time.Millisecond), 1)
+	// Rate Limit: 40 requests per 2 seconds, Rate limit rule: IP
+	queryDepthLimiter = rate.NewLimiter(rate.Every(50*time.Millisecond), 1)
+	// Rate Limit: 20 requests per 2 seconds, Rate limit rule: IP
+	queryOptionSummaryLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
+	// Rate Limit: 10 requests per 2 seconds, Rate limit rule: IP
+	queryMarkPriceLimiter = rate.NewLimiter(rate.Every(200*time.Millisecond), 1)
+	// Rate Limit: 20 requests per 2 seconds, Rate limit rule: IP + instrumentID
+	queryFundingRateLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
 )
 
 const (
@@ -54,6 +62,8 @@ const (
 
 	defaultQueryLimit = 100
 
+	defaultDepthLimit = 400
+
 	maxHistoricalDataQueryPeriod = 90 * 24 * time.Hour
 	threeDaysHistoricalPeriod    = 3 * 24 * time.Hour
 )
@@ -149,6 +159,186 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 	return markets, nil
 }
 
+// QueryDeliveryFuturesMarkets queries the FUTURES instruments, the local symbol of the markets is the instrument ID, e.g. BTC-USDT-250328
+func (e *Exchange) QueryDeliveryFuturesMarkets(ctx context.Context) (types.MarketMap, error) {
+	if err := queryMarketLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	instruments, err := e.client.NewGetInstrumentsInfoRequest().InstType(okexapi.InstrumentTypeFutures).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	markets := types.MarketMap{}
+	for _, instrument := range instruments {
+		market, err := toGlobalDeliveryFuturesMarket(instrument)
+		if err != nil {
+			log.WithError(err).Warnf("skip futures instrument %s", instrument.InstrumentID)
+			continue
+		}
+
+		markets[market.Symbol] = market
+	}
+
+	return markets, nil
+}
+
+// toOptionUnderlying converts the underlying currency into the underlying index of the options, e.g. BTC -> BTC-USD
+func toOptionUnderlying(underlying string) string {
+	return underlying + "-USD"
+}
+
+// QueryOptionMarkets queries the OPTION instruments of the underlying currency, e.g. BTC,
+// the symbol of the markets is the instrument ID, e.g. BTC-USD-241227-100000-C
+func (e *Exchange) QueryOptionMarkets(ctx context.Context, underlying string) (types.MarketMap, error) {
+	if err := queryMarketLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	instruments, err := e.client.NewGetInstrumentsInfoRequest().
+		InstType(okexapi.InstrumentTypeOption).
+		Underlying(toOptionUnderlying(underlying)).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	markets := types.MarketMap{}
+	for _, instrument := range instruments {
+		market, err := toGlobalOptionMarket(instrument)
+		if err != nil {
+			log.WithError(err).Warnf("skip option instrument %s", instrument.InstrumentID)
+			continue
+		}
+
+		markets[market.Symbol] = market
+	}
+
+	return markets, nil
+}
+
+// QueryOptionTickers queries the tickers of the options of the underlying currency, e.g. BTC,
+// the tickers are merged with the option summary (implied volatilities and greeks) and the mark prices.
+func (e *Exchange) QueryOptionTickers(ctx context.Context, underlying string) (map[string]types.OptionTicker, error) {
+	uly := toOptionUnderlying(underlying)
+
+	if err := queryTickersLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
+	}
+
+	marketTickers, err := e.client.NewGetTickersRequest().
+		InstType(okexapi.InstrumentTypeOption).
+		Underlying(uly).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := queryOptionSummaryLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("option summary rate limiter wait error: %w", err)
+	}
+
+	summaries, err := e.client.NewGetOptionSummaryRequest().Underlying(uly).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := queryMarkPriceLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("mark price rate limiter wait error: %w", err)
+	}
+
+	markPrices, err := e.client.NewGetMarkPriceRequest().
+		InstType(okexapi.InstrumentTypeOption).
+		Underlying(uly).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryMap := make(map[string]okexapi.OptionSummary, len(summaries))
+	for _, summary := range summaries {
+		summaryMap[summary.InstrumentID] = summary
+	}
+
+	markPriceMap := make(map[string]fixedpoint.Value, len(markPrices))
+	for _, markPrice := range markPrices {
+		markPriceMap[markPrice.InstrumentID] = markPrice.MarkPrice
+	}
+
+	tickers := make(map[string]types.OptionTicker, len(marketTickers))
+	for _, marketTicker := range marketTickers {
+		summary, ok := summaryMap[marketTicker.InstrumentID]
+		if !ok {
+			log.Warnf("option summary of %s not found, skip", marketTicker.InstrumentID)
+			continue
+		}
+
+		tickers[marketTicker.InstrumentID] = toGlobalOptionTicker(marketTicker, summary, markPriceMap[marketTicker.InstrumentID])
+	}
+
+	return tickers, nil
+}
+
+// QueryPremiumIndex queries the funding rate and the mark price of the perpetual swap of the symbol, e.g. BTCUSDT -> BTC-USDT-SWAP
+func (e *Exchange) QueryPremiumIndex(ctx context.Context, symbol string) (*types.PremiumIndex, error) {
+	instId := toLocalPerpetualSymbol(symbol)
+
+	if err := queryFundingRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("funding rate rate limiter wait error: %w", err)
+	}
+
+	fundingRate, err := e.client.NewGetFundingRate().InstrumentID(instId).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := queryMarkPriceLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("mark price rate limiter wait error: %w", err)
+	}
+
+	markPrices, err := e.client.NewGetMarkPriceRequest().
+		InstType(okexapi.InstrumentTypeSwap).
+		InstId(instId).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(markPrices) != 1 {
+		return nil, fmt.Errorf("unexpected mark price length of %s, exp: 1, got: %d", instId, len(markPrices))
+	}
+
+	return toGlobalPremiumIndex(symbol, *fundingRate, markPrices[0]), nil
+}
+
+// QueryDepth queries the order book snapshot, the returned update ID is the timestamp of the snapshot in milliseconds
+func (e *Exchange) QueryDepth(ctx context.Context, symbol string) (types.SliceOrderBook, int64, error) {
+	if err := queryDepthLimiter.Wait(ctx); err != nil {
+		return types.SliceOrderBook{}, 0, fmt.Errorf("depth rate limiter wait error: %w", err)
+	}
+
+	instrumentID := toLocalSymbol(symbol)
+	books, err := e.client.NewGetOrderBookRequest().
+		InstId(instrumentID).
+		Size(defaultDepthLimit).
+		Do(ctx)
+	if err != nil {
+		return types.SliceOrderBook{}, 0, err
+	}
+
+	if len(books) != 1 {
+		return types.SliceOrderBook{}, 0, fmt.Errorf("unexpected length of %s order book, got: %v", instrumentID, books)
+	}
+
+	return types.SliceOrderBook{
+		Symbol: symbol,
+		Time:   books[0].Timestamp.Time(),
+		Bids:   books[0].Bids,
+		Asks:   books[0].Asks,
+	}, books[0].Timestamp.Time().UnixMilli(), nil
+}
+
 func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
 	if err := queryTickerLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("ticker rate limiter wait error: %w", err)
@@ -237,11 +427,27 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 	orderReq.Side(toLocalSideType(order.Side))
 	orderReq.Size(order.Market.FormatQuantity(order.Quantity))
 
+	// the options and the dated futures can only be traded in the margin mode, and the quantity is in contracts
+	isOption := order.Market.IsOption()
+	isDerivative := isOption || order.Market.IsDeliveryContract()
+	if isDerivative {
+		orderReq.TradeMode(okexapi.TradeModeCross)
+	}
+
 	// set price field for limit orders
 	switch order.Type {
 	case types.OrderTypeStopLimit, types.OrderTypeLimit, types.OrderTypeLimitMaker:
 		orderReq.Price(order.Market.FormatPrice(order.Price))
 	case types.OrderTypeMarket:
+		if isOption {
+			return nil, fmt.Errorf("market order is not supported for the option %s, use an IOC limit order instead", order.Symbol)
+		}
+
+		// the target currency is only applicable to the spot market orders
+		if isDerivative {
+			break
+		}
+
 		// Because our order.Quantity unit is base coin, so we indicate the target currency to Base.
 		if order.Side == types.SideTypeBuy {
 			orderReq.Size(order.Market.FormatQuantity(order.Quantity))
@@ -351,6 +557,7 @@ func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders [
 		}
 
 		req := e.client.NewGetOpenOrdersRequest().
+			InstrumentType(toLocalInstrumentType(symbol)).
 			InstrumentID(instrumentID).
 			After(strconv.FormatInt(nextCursor, 10))
 		openOrders, err := req.Do(ctx)
@@ -28,15 +28,33 @@ type InstrumentInfo struct {
 
 	// instrument status
 	State string `json:"state"`
+
+	// ContractType is the contract type of the SWAP and FUTURES instruments: linear, inverse
+	ContractType string `json:"ctType"`
+
+	// Underlying is the underlying index of the derivatives instruments, e.g. BTC-USDT
+	Underlying string `json:"uly"`
+
+	// Alias is the alias of the FUTURES instruments: this_week, next_week, this_month, next_month, quarter, next_quarter
+	Alias string `json:"alias"`
+
+	// OptionType is the option type of the OPTION instruments: C (call), P (put)
+	OptionType string `json:"optType"`
+
+	// StrikePrice is the strike price of the OPTION instruments
+	StrikePrice fixedpoint.Value `json:"stk"`
 }
 
 //go:generate GetRequest -url "/api/v5/public/instruments" -type GetInstrumentsInfoRequest -responseDataType []InstrumentInfo
 type GetInstrumentsInfoRequest struct {
 	client requestgen.APIClient
 
-	instType InstrumentType `param:"instType,query" validValues:"SPOT"`
+	instType InstrumentType `param:"instType,query" validValues:"SPOT,FUTURES,OPTION"`
 
 	instId *string `param:"instId,query"`
+
+	// underlying is required for the OPTION instruments, e.g. BTC-USD
+	underlying *string `param:"uly,query"`
 }
 
 func (c *RestClient) NewGetInstrumentsInfoRequest() *GetInstrumentsInfoRequest {
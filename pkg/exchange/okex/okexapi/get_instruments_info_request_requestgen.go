@@ -21,6 +21,11 @@ func (g *GetInstrumentsInfoRequest) InstId(instId string) *GetInstrumentsInfoReq
 	return g
 }
 
+func (g *GetInstrumentsInfoRequest) Underlying(underlying string) *GetInstrumentsInfoRequest {
+	g.underlying = &underlying
+	return g
+}
+
 // GetQueryParameters builds and checks the query parameters and returns url.Values
 func (g *GetInstrumentsInfoRequest) GetQueryParameters() (url.Values, error) {
 	var params = map[string]interface{}{}
@@ -29,7 +34,7 @@ func (g *GetInstrumentsInfoRequest) GetQueryParameters() (url.Values, error) {
 
 	// TEMPLATE check-valid-values
 	switch instType {
-	case "SPOT":
+	case "SPOT", "FUTURES", "OPTION":
 		params["instType"] = instType
 
 	default:
@@ -48,6 +53,14 @@ func (g *GetInstrumentsInfoRequest) GetQueryParameters() (url.Values, error) {
 		params["instId"] = instId
 	} else {
 	}
+	// check underlying field -> json key uly
+	if g.underlying != nil {
+		underlying := *g.underlying
+
+		// assign parameter of underlying
+		params["uly"] = underlying
+	} else {
+	}
 
 	query := url.Values{}
 	for _k, _v := range params {
@@ -0,0 +1,36 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type MarkPrice struct {
+	InstrumentType string                     `json:"instType"`
+	InstrumentID   string                     `json:"instId"`
+	MarkPrice      fixedpoint.Value           `json:"markPx"`
+	Timestamp      types.MillisecondTimestamp `json:"ts"`
+}
+
+//go:generate GetRequest -url "/api/v5/public/mark-price" -type GetMarkPriceRequest -responseDataType []MarkPrice
+type GetMarkPriceRequest struct {
+	client requestgen.APIClient
+
+	instType InstrumentType `param:"instType,query" validValues:"MARGIN,SWAP,FUTURES,OPTION"`
+
+	underlying *string `param:"uly,query"`
+
+	instId *string `param:"instId,query"`
+}
+
+func (c *RestClient) NewGetMarkPriceRequest() *GetMarkPriceRequest {
+	return &GetMarkPriceRequest{
+		client:   c,
+		instType: InstrumentTypeOption,
+	}
+}
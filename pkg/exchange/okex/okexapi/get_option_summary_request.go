@@ -0,0 +1,55 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type OptionSummary struct {
+	InstrumentType string `json:"instType"`
+	InstrumentID   string `json:"instId"`
+	Underlying     string `json:"uly"`
+
+	// Greeks in the coin unit
+	Delta fixedpoint.Value `json:"delta"`
+	Gamma fixedpoint.Value `json:"gamma"`
+	Vega  fixedpoint.Value `json:"vega"`
+	Theta fixedpoint.Value `json:"theta"`
+
+	// Greeks in the Black-Scholes model (dollar unit)
+	DeltaBS fixedpoint.Value `json:"deltaBS"`
+	GammaBS fixedpoint.Value `json:"gammaBS"`
+	VegaBS  fixedpoint.Value `json:"vegaBS"`
+	ThetaBS fixedpoint.Value `json:"thetaBS"`
+
+	Leverage fixedpoint.Value `json:"lever"`
+
+	// MarkVolatility is the implied volatility of the mark price
+	MarkVolatility fixedpoint.Value `json:"markVol"`
+	BidVolatility  fixedpoint.Value `json:"bidVol"`
+	AskVolatility  fixedpoint.Value `json:"askVol"`
+
+	// ForwardPrice is the forward price of the underlying
+	ForwardPrice fixedpoint.Value `json:"fwdPx"`
+
+	Timestamp types.MillisecondTimestamp `json:"ts"`
+}
+
+//go:generate GetRequest -url "/api/v5/public/opt-summary" -type GetOptionSummaryRequest -responseDataType []OptionSummary
+type GetOptionSummaryRequest struct {
+	client requestgen.APIClient
+
+	// underlying is the underlying index of the options, e.g. BTC-USD
+	underlying string `param:"uly,query"`
+}
+
+func (c *RestClient) NewGetOptionSummaryRequest() *GetOptionSummaryRequest {
+	return &GetOptionSummaryRequest{
+		client: c,
+	}
+}
@@ -0,0 +1,33 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type OrderBook struct {
+	// Asks and Bids are in the format of [price, size, deprecated, number of orders]
+	Asks      types.PriceVolumeSlice     `json:"asks"`
+	Bids      types.PriceVolumeSlice     `json:"bids"`
+	Timestamp types.MillisecondTimestamp `json:"ts"`
+}
+
+//go:generate GetRequest -url "/api/v5/market/books" -type GetOrderBookRequest -responseDataType []OrderBook
+type GetOrderBookRequest struct {
+	client requestgen.APIClient
+
+	instId string `param:"instId,query"`
+
+	// size is the order book depth per side, maximum 400
+	size *int `param:"sz,query"`
+}
+
+func (c *RestClient) NewGetOrderBookRequest() *GetOrderBookRequest {
+	return &GetOrderBookRequest{
+		client: c,
+	}
+}
@@ -40,7 +40,10 @@ type MarketTicker struct {
 type GetTickersRequest struct {
 	client requestgen.APIClient
 
-	instType InstrumentType `param:"instType,query" validValues:"SPOT"`
+	instType InstrumentType `param:"instType,query" validValues:"SPOT,OPTION"`
+
+	// underlying is required for the OPTION instruments, e.g. BTC-USD
+	underlying *string `param:"uly,query"`
 }
 
 func (c *RestClient) NewGetTickersRequest() *GetTickersRequest {
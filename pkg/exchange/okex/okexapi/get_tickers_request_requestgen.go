@@ -16,6 +16,11 @@ func (g *GetTickersRequest) InstType(instType InstrumentType) *GetTickersRequest
 	return g
 }
 
+func (g *GetTickersRequest) Underlying(underlying string) *GetTickersRequest {
+	g.underlying = &underlying
+	return g
+}
+
 // GetQueryParameters builds and checks the query parameters and returns url.Values
 func (g *GetTickersRequest) GetQueryParameters() (url.Values, error) {
 	var params = map[string]interface{}{}
@@ -24,7 +29,7 @@ func (g *GetTickersRequest) GetQueryParameters() (url.Values, error) {
 
 	// TEMPLATE check-valid-values
 	switch instType {
-	case "SPOT":
+	case "SPOT", "OPTION":
 		params["instType"] = instType
 
 	default:
@@ -35,6 +40,14 @@ func (g *GetTickersRequest) GetQueryParameters() (url.Values, error) {
 
 	// assign parameter of instType
 	params["instType"] = instType
+	// check underlying field -> json key uly
+	if g.underlying != nil {
+		underlying := *g.underlying
+
+		// assign parameter of underlying
+		params["uly"] = underlying
+	} else {
+	}
 
 	query := url.Values{}
 	for _k, _v := range params {
@@ -22,6 +22,7 @@ type FundingRate struct {
 	FundingRate     fixedpoint.Value           `json:"fundingRate"`
 	NextFundingRate fixedpoint.Value           `json:"nextFundingRate"`
 	FundingTime     types.MillisecondTimestamp `json:"fundingTime"`
+	NextFundingTime types.MillisecondTimestamp `json:"nextFundingTime"`
 }
 
 type GetFundingRateRequest struct {
@@ -194,6 +194,8 @@ func (s *Stream) subscribePrivateChannels(next func()) func() {
 		var subs = []WebsocketSubscription{
 			{Channel: ChannelAccount},
 			{Channel: "orders", InstrumentType: string(okexapi.InstrumentTypeSpot)},
+			{Channel: "orders", InstrumentType: string(okexapi.InstrumentTypeFutures)},
+			{Channel: "orders", InstrumentType: string(okexapi.InstrumentTypeOption)},
 		}
 
 		// https://www.okx.com/docs-v5/zh/#overview-websocket-connect
@@ -39,11 +39,13 @@ func (inc *OBV) Update(price, volume float64) {
 		return
 	}
 
-	if volume < inc.PrePrice {
+	if price < inc.PrePrice {
 		inc.Values.Push(inc.Last(0) - volume)
 	} else {
 		inc.Values.Push(inc.Last(0) + volume)
 	}
+
+	inc.PrePrice = price
 }
 
 func (inc *OBV) Last(i int) float64 {
@@ -54,6 +56,10 @@ func (inc *OBV) Index(i int) float64 {
 	return inc.Last(i)
 }
 
+func (inc *OBV) Length() int {
+	return len(inc.Values)
+}
+
 var _ types.SeriesExtend = &OBV{}
 
 func (inc *OBV) PushK(k types.KLine) {
@@ -0,0 +1,36 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ADStream is the accumulation/distribution indicator
+// - https://www.investopedia.com/terms/a/accumulationdistribution.asp
+type ADStream struct {
+	*types.Float64Series
+}
+
+func AD(source KLineSubscription) *ADStream {
+	s := &ADStream{
+		Float64Series: types.NewFloat64Series(),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.PushAndEmit(s.calculate(k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64()))
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *ADStream) calculate(high, low, cls, volume float64) float64 {
+	var moneyFlowVolume float64
+	if high != low {
+		moneyFlowVolume = ((2*cls - high - low) / (high - low)) * volume
+	}
+
+	return s.Slice.Last(0) + moneyFlowVolume
+}
+
+func (s *ADStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,65 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ALMAStream is the Arnaud Legoux Moving Average
+// - https://capital.com/arnaud-legoux-moving-average
+//
+// offset is the gaussian offset applied to the weights, 1 -> ema, 0 -> sma, recommend to be 0.85
+// sigma is the standard deviation applied to the weights, which makes the line sharper, recommend to be 6
+type ALMAStream struct {
+	*types.Float64Series
+
+	window  int
+	weights []float64
+	sum     float64
+	input   []float64
+}
+
+func ALMA(source types.Float64Source, window int, offset float64, sigma int) *ALMAStream {
+	checkWindow(window)
+
+	s := &ALMAStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		weights:       make([]float64, window),
+	}
+
+	m := offset * (float64(window) - 1.)
+	d := float64(window) / float64(sigma)
+	for i := 0; i < window; i++ {
+		diff := float64(i) - m
+		wt := math.Exp(-diff * diff / 2. / d / d)
+		s.sum += wt
+		s.weights[i] = wt
+	}
+
+	s.Subscribe(source, func(v float64) {
+		s.input = append(s.input, v)
+		if len(s.input) < s.window {
+			return
+		}
+
+		s.input = s.input[len(s.input)-s.window:]
+		s.PushAndEmit(s.calculate())
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *ALMAStream) calculate() float64 {
+	weightedSum := 0.0
+	for i := 0; i < s.window; i++ {
+		weightedSum += s.weights[s.window-i-1] * s.input[i]
+	}
+
+	return weightedSum / s.sum
+}
+
+func (s *ALMAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,38 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// BarStream is a KLine stream of the alternative bars (tick, volume, dollar, range and renko bars),
+// the v2 indicators can be built on top of it just like a time-based KLine stream.
+type BarStream struct {
+	*KLineStream
+
+	Builder *types.BarBuilder
+}
+
+// Bars creates a bar stream that builds the bars from the market trades of the source stream,
+// or from the closed klines of the configured interval if it's set.
+func Bars(source types.Stream, symbol string, config types.BarConfig) *BarStream {
+	s := &BarStream{
+		KLineStream: &KLineStream{},
+		Builder:     types.NewBarBuilder(symbol, config),
+	}
+
+	s.Builder.OnKLineClosed(s.Push)
+	s.Builder.BindStream(source)
+	return s
+}
+
+// BackFillTrades builds the bars from the historical trades
+func (s *BarStream) BackFillTrades(trades []types.Trade) {
+	for _, trade := range trades {
+		s.Builder.HandleTrade(trade)
+	}
+}
+
+// BackFillKLines builds the bars from the historical klines
+func (s *BarStream) BackFillKLines(kLines []types.KLine) {
+	for _, k := range kLines {
+		s.Builder.HandleKLine(k)
+	}
+}
@@ -0,0 +1,47 @@
+package indicatorv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestBars(t *testing.T) {
+	stream := &types.StandardStream{}
+	bars := Bars(stream, "BTCUSDT", types.BarConfig{Type: types.BarTypeTick, Threshold: fixedpoint.NewFromInt(2)})
+	sma := SMA(ClosePrices(bars), 2)
+
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []float64{10, 11, 12, 13, 14, 15, 16} {
+		stream.EmitMarketTrade(types.Trade{
+			ID:       uint64(i),
+			Symbol:   "BTCUSDT",
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.One,
+			Time:     types.Time(start.Add(time.Duration(i) * time.Second)),
+		})
+	}
+
+	// the closes of the tick bars are 11, 13 and 15
+	assert.Equal(t, 3, bars.Length())
+	assert.Equal(t, 15.0, bars.Last(0).Close.Float64())
+	assert.Equal(t, []float64{11, 12, 14}, []float64(sma.Slice))
+
+	// the bars built from the klines of the back-fill
+	kLineBars := Bars(stream, "BTCUSDT", types.BarConfig{Type: types.BarTypeRange, Threshold: fixedpoint.NewFromInt(5), Interval: types.Interval1m})
+	kLineBars.BackFillKLines([]types.KLine{{
+		Symbol:    "BTCUSDT",
+		Interval:  types.Interval1m,
+		StartTime: types.Time(start),
+		EndTime:   types.Time(start.Add(time.Minute - time.Millisecond)),
+		Open:      fixedpoint.NewFromFloat(100),
+		High:      fixedpoint.NewFromFloat(112),
+		Low:       fixedpoint.NewFromFloat(99),
+		Close:     fixedpoint.NewFromFloat(110),
+	}})
+	assert.Equal(t, 2, kLineBars.Length())
+}
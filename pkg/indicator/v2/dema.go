@@ -0,0 +1,36 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DEMAStream is the Double Exponential Moving Average
+// - https://investopedia.com/terms/d/double-exponential-moving-average.asp
+//
+// DEMA = 2 * EMA(price) - EMA(EMA(price))
+type DEMAStream struct {
+	*types.Float64Series
+
+	A1, A2 *EWMAStream
+}
+
+func DEMA(source types.Float64Source, window int) *DEMAStream {
+	a1 := EWMA2(source, window)
+	a2 := EWMA2(a1, window)
+
+	s := &DEMAStream{
+		Float64Series: types.NewFloat64Series(),
+		A1:            a1,
+		A2:            a2,
+	}
+	s.Bind(a2, s)
+	return s
+}
+
+func (s *DEMAStream) Calculate(a2 float64) float64 {
+	return 2*s.A1.Last(0) - a2
+}
+
+func (s *DEMAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
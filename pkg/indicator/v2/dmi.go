@@ -0,0 +1,82 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DMIStream is the Directional Movement Index, the embedded RMAStream is the ADX smoothed by the adxSmoothing window
+// - https://www.investopedia.com/terms/d/dmi.asp
+//
+// Unlike ADXStream, the values are pushed after the ATR has the full window of the true ranges.
+type DMIStream struct {
+	*RMAStream
+
+	DIPlus, DIMinus *types.Float64Series
+
+	window            int
+	prevHigh, prevLow float64
+	started           bool
+}
+
+func DMI(source KLineSubscription, window, adxSmoothing int) *DMIStream {
+	checkWindow(window)
+
+	var (
+		atr  = ATR2(source, window)
+		dmp  = types.NewFloat64Series()
+		dmn  = types.NewFloat64Series()
+		dx   = types.NewFloat64Series()
+		sdmp = RMA2(dmp, window, true)
+		sdmn = RMA2(dmn, window, true)
+		s    = &DMIStream{
+			RMAStream: RMA2(dx, adxSmoothing, true),
+			DIPlus:    types.NewFloat64Series(),
+			DIMinus:   types.NewFloat64Series(),
+			window:    window,
+		}
+	)
+
+	source.AddSubscriber(func(k types.KLine) {
+		high, low := k.High.Float64(), k.Low.Float64()
+		if !s.started {
+			s.started = true
+			s.prevHigh, s.prevLow = high, low
+			return
+		}
+
+		up := high - s.prevHigh
+		dn := s.prevLow - low
+		s.prevHigh, s.prevLow = high, low
+
+		pos := 0.0
+		if up > dn && up > 0. {
+			pos = up
+		}
+
+		neg := 0.0
+		if dn > up && dn > 0. {
+			neg = dn
+		}
+
+		dmp.PushAndEmit(pos)
+		dmn.PushAndEmit(neg)
+		dmp.Slice = generalTruncate(dmp.Slice)
+		dmn.Slice = generalTruncate(dmn.Slice)
+		if atr.Length() < s.window {
+			return
+		}
+
+		m := 100. / atr.Last(0)
+		p, n := sdmp.Last(0), sdmn.Last(0)
+		s.DIPlus.PushAndEmit(m * p)
+		s.DIMinus.PushAndEmit(m * n)
+		s.DIPlus.Slice = generalTruncate(s.DIPlus.Slice)
+		s.DIMinus.Slice = generalTruncate(s.DIMinus.Slice)
+
+		dx.PushAndEmit(100. * math.Abs(p-n) / (p + n))
+		dx.Slice = generalTruncate(dx.Slice)
+	})
+	return s
+}
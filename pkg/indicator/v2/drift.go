@@ -0,0 +1,58 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DriftStream is the drift of the log returns in the Geometric Brownian Motion model
+// - https://tradingview.com/script/aDymGrFx-Drift-Study-Inspired-by-Monte-Carlo-Simulations-with-BM-KL/
+//
+// Drift = Mean(log returns) - Variance(log returns) / 2
+type DriftStream struct {
+	*types.Float64Series
+
+	window    int
+	chng      *types.Queue
+	lastValue float64
+	started   bool
+}
+
+func Drift(source types.Float64Source, window int) *DriftStream {
+	checkWindow(window)
+
+	s := &DriftStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		chng:          types.NewQueue(window),
+	}
+
+	s.Subscribe(source, func(v float64) {
+		if !s.started {
+			s.started = true
+			s.lastValue = v
+			return
+		}
+
+		var chng float64
+		if v != 0 {
+			chng = math.Log(v / s.lastValue)
+			s.lastValue = v
+		}
+
+		s.chng.Update(chng)
+		if s.chng.Length() < s.window {
+			return
+		}
+
+		stdev := types.Stdev(s.chng, s.window)
+		s.PushAndEmit(types.Mean(s.chng) - stdev*stdev*0.5)
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *DriftStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,64 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultEMVScale float64 = 100000000.
+
+// EMVStream is the Ease of Movement, the simple moving average of the price moves relative to the volume
+// - https://www.investopedia.com/terms/e/easeofmovement.asp
+type EMVStream struct {
+	*types.Float64Series
+
+	window       int
+	scale        float64
+	prevH, prevL float64
+	values       *types.Queue
+}
+
+// EMV creates the ease of movement stream, the volume scale is 1e8 if it's zero
+func EMV(source KLineSubscription, window int, scale float64) *EMVStream {
+	checkWindow(window)
+
+	if scale == 0 {
+		scale = defaultEMVScale
+	}
+
+	s := &EMVStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		scale:         scale,
+		values:        types.NewQueue(window),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.calculateAndPush(k.High.Float64(), k.Low.Float64(), k.Volume.Float64())
+	})
+	return s
+}
+
+func (s *EMVStream) calculateAndPush(high, low, volume float64) {
+	if s.prevH == 0 {
+		s.prevH = high
+		s.prevL = low
+		return
+	}
+
+	distanceMoved := (high+low)/2. - (s.prevH+s.prevL)/2.
+	boxRatio := volume / s.scale / (high - low)
+	s.prevH = high
+	s.prevL = low
+
+	s.values.Update(distanceMoved / boxRatio)
+	if s.values.Length() < s.window {
+		return
+	}
+
+	s.PushAndEmit(types.Mean(s.values))
+	s.Truncate()
+}
+
+func (s *EMVStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
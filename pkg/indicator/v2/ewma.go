@@ -28,3 +28,26 @@ func (s *EWMAStream) Calculate(v float64) float64 {
 	m := s.multiplier
 	return (1.0-m)*last + m*v
 }
+
+// ewma is the exponential moving average state seeded by the first value,
+// it's used by the composite indicators whose inputs can be zero
+type ewma struct {
+	multiplier float64
+	value      float64
+	count      int
+}
+
+func newEWMA(window int) *ewma {
+	return &ewma{multiplier: 2.0 / float64(1+window)}
+}
+
+func (e *ewma) update(v float64) float64 {
+	if e.count == 0 {
+		e.value = v
+	} else {
+		e.value = (1-e.multiplier)*e.value + e.multiplier*v
+	}
+
+	e.count++
+	return e.value
+}
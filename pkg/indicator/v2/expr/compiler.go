@@ -0,0 +1,278 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Stream is the compiled expression stream, it pushes one value for each closed kline.
+// The comparison and the logical operations push 1 for true and 0 for false,
+// NaN is pushed when the upstream indicators are not ready yet.
+type Stream struct {
+	*types.Float64Series
+
+	Expression string
+	Node       Node
+}
+
+// Truthy returns true if the last value is non-zero
+func (s *Stream) Truthy() bool {
+	return truthy(s.Last(0))
+}
+
+// Triggered returns true if the last value is non-zero and the previous value is zero or NaN,
+// which means the condition just becomes true on the last kline.
+func (s *Stream) Triggered() bool {
+	return s.Length() > 0 && truthy(s.Last(0)) && !truthy(s.Last(1))
+}
+
+// value is the compiled node, which is either a constant or a series
+type value struct {
+	series   types.Float64Source
+	constant float64
+}
+
+func (v value) isConstant() bool {
+	return v.series == nil
+}
+
+// current returns the current value of the series, NaN if the series has no value yet
+func (v value) current() float64 {
+	if v.series == nil {
+		return v.constant
+	}
+
+	if v.series.Length() == 0 {
+		return math.NaN()
+	}
+
+	return v.series.Last(0)
+}
+
+type compiler struct {
+	// kLines is the kline stream that drives the whole graph, the operator streams are subscribed after
+	// their operands, so they are always calculated after the operands are updated for the same kline.
+	kLines *indicatorv2.KLineStream
+
+	prices map[string]*indicatorv2.PriceStream
+}
+
+// Compile parses the expression and compiles it into the indicator stream graph bound to the kline source,
+// the historical klines of the source are replayed through the graph.
+func Compile(expression string, source indicatorv2.KLineSubscription) (*Stream, error) {
+	s, c, err := compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	source.AddSubscriber(c.kLines.Push)
+	return s, nil
+}
+
+// Check verifies the syntax, the function names and the argument types of the expression without binding it
+func Check(expression string) error {
+	_, _, err := compile(expression)
+	return err
+}
+
+func compile(expression string) (*Stream, *compiler, error) {
+	node, err := Parse(expression)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := &compiler{
+		kLines: &indicatorv2.KLineStream{},
+		prices: make(map[string]*indicatorv2.PriceStream),
+	}
+
+	root, err := c.compile(node)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the root is always an operator stream, so that the stream pushes exactly one value for each kline
+	return &Stream{
+		Float64Series: c.operator([]value{root}, func(args []float64) float64 {
+			return args[0]
+		}),
+		Expression: expression,
+		Node:       node,
+	}, c, nil
+}
+
+func (c *compiler) compile(node Node) (value, error) {
+	switch n := node.(type) {
+	case *NumberNode:
+		return value{constant: n.Value}, nil
+
+	case *IdentNode:
+		return c.compileIdent(n)
+
+	case *CallNode:
+		return c.compileCall(n)
+
+	case *UnaryNode:
+		operand, err := c.compile(n.Operand)
+		if err != nil {
+			return value{}, err
+		}
+
+		f := unaryOperators[n.Operator]
+		return c.apply([]value{operand}, func(args []float64) float64 {
+			return f(args[0])
+		}), nil
+
+	case *BinaryNode:
+		left, err := c.compile(n.Left)
+		if err != nil {
+			return value{}, err
+		}
+
+		right, err := c.compile(n.Right)
+		if err != nil {
+			return value{}, err
+		}
+
+		f := binaryOperators[n.Operator]
+		return c.apply([]value{left, right}, func(args []float64) float64 {
+			return f(args[0], args[1])
+		}), nil
+	}
+
+	return value{}, fmt.Errorf("unsupported node %s", node)
+}
+
+func (c *compiler) compileIdent(n *IdentNode) (value, error) {
+	switch n.Name {
+	case "true":
+		return value{constant: 1}, nil
+	case "false":
+		return value{constant: 0}, nil
+	}
+
+	if s, ok := c.prices[n.Name]; ok {
+		return value{series: s}, nil
+	}
+
+	var s *indicatorv2.PriceStream
+	switch n.Name {
+	case "open":
+		s = indicatorv2.OpenPrices(c.kLines)
+	case "high":
+		s = indicatorv2.HighPrices(c.kLines)
+	case "low":
+		s = indicatorv2.LowPrices(c.kLines)
+	case "close":
+		s = indicatorv2.ClosePrices(c.kLines)
+	case "volume":
+		s = indicatorv2.Volumes(c.kLines)
+	case "hlc3":
+		s = indicatorv2.HLC3(c.kLines)
+	default:
+		return value{}, fmt.Errorf("undefined series %q", n.Name)
+	}
+
+	c.prices[n.Name] = s
+	return value{series: s}, nil
+}
+
+func (c *compiler) compileCall(n *CallNode) (value, error) {
+	f, ok := functions[n.Name]
+	if !ok {
+		return value{}, fmt.Errorf("undefined function %q", n.Name)
+	}
+
+	if len(n.Args) < f.minArgs || len(n.Args) > f.maxArgs {
+		if f.minArgs == f.maxArgs {
+			return value{}, fmt.Errorf("%s: expected %d arguments, got %d", n.Name, f.minArgs, len(n.Args))
+		}
+
+		return value{}, fmt.Errorf("%s: expected %d to %d arguments, got %d", n.Name, f.minArgs, f.maxArgs, len(n.Args))
+	}
+
+	var args []value
+	for _, argNode := range n.Args {
+		arg, err := c.compile(argNode)
+		if err != nil {
+			return value{}, err
+		}
+
+		args = append(args, arg)
+	}
+
+	return f.build(c, &arguments{name: n.Name, values: args})
+}
+
+// apply folds the constant operands, or creates the operator stream if any of the operands is a series
+func (c *compiler) apply(operands []value, f func(args []float64) float64) value {
+	constant := true
+	args := make([]float64, len(operands))
+	for i, operand := range operands {
+		if !operand.isConstant() {
+			constant = false
+			break
+		}
+
+		args[i] = operand.constant
+	}
+
+	if constant {
+		return value{constant: f(args)}
+	}
+
+	return value{series: c.operator(operands, f)}
+}
+
+// operator creates the stream that calculates the value from the current values of the operands for each kline
+func (c *compiler) operator(operands []value, f func(args []float64) float64) *types.Float64Series {
+	s := types.NewFloat64Series()
+	args := make([]float64, len(operands))
+	c.kLines.AddSubscriber(func(k types.KLine) {
+		for i, operand := range operands {
+			args[i] = operand.current()
+		}
+
+		s.PushAndEmit(f(args))
+		if len(s.Slice) > indicatorv2.MaxSliceSize {
+			s.Slice = s.Slice[indicatorv2.TruncateSize-1:]
+		}
+	})
+	return s
+}
+
+func truthy(v float64) bool {
+	return v != 0 && !math.IsNaN(v)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+var unaryOperators = map[string]func(a float64) float64{
+	"-": func(a float64) float64 { return -a },
+	"!": func(a float64) float64 { return boolValue(!truthy(a)) },
+}
+
+// the comparisons with NaN are always false
+var binaryOperators = map[string]func(a, b float64) float64{
+	"+":  func(a, b float64) float64 { return a + b },
+	"-":  func(a, b float64) float64 { return a - b },
+	"*":  func(a, b float64) float64 { return a * b },
+	"/":  func(a, b float64) float64 { return a / b },
+	"<":  func(a, b float64) float64 { return boolValue(a < b) },
+	"<=": func(a, b float64) float64 { return boolValue(a <= b) },
+	">":  func(a, b float64) float64 { return boolValue(a > b) },
+	">=": func(a, b float64) float64 { return boolValue(a >= b) },
+	"==": func(a, b float64) float64 { return boolValue(a == b) },
+	"!=": func(a, b float64) float64 { return boolValue(a != b && !math.IsNaN(a) && !math.IsNaN(b)) },
+	"&&": func(a, b float64) float64 { return boolValue(truthy(a) && truthy(b)) },
+	"||": func(a, b float64) float64 { return boolValue(truthy(a) || truthy(b)) },
+}
@@ -0,0 +1,189 @@
+package expr
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func buildKLines(prices ...float64) (kLines []types.KLine) {
+	open := prices[0]
+	for _, p := range prices {
+		kLines = append(kLines, types.KLine{
+			Open:   fixedpoint.NewFromFloat(open),
+			High:   fixedpoint.NewFromFloat(math.Max(open, p) + 1),
+			Low:    fixedpoint.NewFromFloat(math.Min(open, p) - 1),
+			Close:  fixedpoint.NewFromFloat(p),
+			Volume: fixedpoint.NewFromFloat(10),
+		})
+		open = p
+	}
+	return kLines
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"close", "close"},
+		{"1 + 2 * 3", "(1 + (2 * 3))"},
+		{"(1 + 2) * 3", "((1 + 2) * 3)"},
+		{"a - b - c", "((a - b) - c)"},
+		{"-close + 1", "(-close + 1)"},
+		{"close > open and rsi(close, 14) < 70", "((close > open) && (rsi(close, 14) < 70))"},
+		{"a or b && c", "(a || (b && c))"},
+		{"NOT a", "!a"},
+		{"cross_over(ema(close,12), ema(close,26))", "cross_over(ema(close, 12), ema(close, 26))"},
+		{"obv()", "obv()"},
+		{"close >= .5", "(close >= 0.5)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := Parse(tt.input)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, node.String())
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"close >",
+		"ema(close, 12",
+		"ema(close 12)",
+		"close $ open",
+		"(close",
+		"close open",
+	} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, Check("cross_over(ema(close,12), ema(close,26)) and rsi(close,14) < 70"))
+	assert.NoError(t, Check("supertrend(10) < close and vwap() > 0"))
+
+	for _, input := range []string{
+		"foo(close, 12)",
+		"price > 0",
+		"ema(close)",
+		"ema(12, close)",
+		"ema(close, 1.5)",
+		"ema(close, 0)",
+		"sma(close, sma(close, 3))",
+		"cross_over(1, 2)",
+	} {
+		assert.Error(t, Check(input), input)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	kLines := buildKLines(1, 2, 3, 2, 1, 2, 3, 4)
+
+	tests := []struct {
+		expression string
+		want       []float64
+	}{
+		{"close", []float64{1, 2, 3, 2, 1, 2, 3, 4}},
+		{"close > open", []float64{0, 1, 1, 0, 0, 1, 1, 1}},
+		{"close - open + 1 * 2", []float64{2, 3, 3, 1, 1, 3, 3, 3}},
+		{"1 + 2", []float64{3, 3, 3, 3, 3, 3, 3, 3}},
+		{"-close", []float64{-1, -2, -3, -2, -1, -2, -3, -4}},
+		{"!(close > 2)", []float64{1, 1, 0, 1, 1, 1, 0, 0}},
+		{"close > 1 and close < 3", []float64{0, 1, 0, 1, 0, 1, 0, 0}},
+		{"close < 2 or close > 3", []float64{1, 0, 0, 0, 1, 0, 0, 1}},
+		{"prev(close)", []float64{math.NaN(), 1, 2, 3, 2, 1, 2, 3}},
+		{"prev(close, 2) < close", []float64{0, 0, 1, 0, 0, 0, 1, 1}},
+		{"abs(close - 2)", []float64{1, 0, 1, 0, 1, 0, 1, 2}},
+		{"max(close, 2)", []float64{2, 2, 3, 2, 2, 2, 3, 4}},
+		{"cross_over(close, 2.5)", []float64{0, 0, 1, 0, 0, 0, 1, 0}},
+		{"cross_under(close, 1.5)", []float64{0, 0, 0, 0, 1, 0, 0, 0}},
+		{"cross_over(close, sma(close, 2))", []float64{0, 1, 0, 0, 0, 1, 0, 0}},
+		{"atr(3) > 0", []float64{0, 1, 1, 1, 1, 1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			stream := &types.StandardStream{}
+			s, err := Compile(tt.expression, indicatorv2.KLines(stream, "", ""))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, k := range kLines {
+				stream.EmitKLineClosed(k)
+			}
+
+			assertValues(t, tt.want, s.Slice)
+		})
+	}
+}
+
+func TestCompile_BackFill(t *testing.T) {
+	kLines := buildKLines(1, 2, 3, 2, 1, 2, 3, 4, 5, 4, 3)
+	expression := "cross_over(close, sma(close, 3)) or rsi(close, 3) > 80"
+
+	// live klines
+	stream := &types.StandardStream{}
+	live, err := Compile(expression, indicatorv2.KLines(stream, "", ""))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, k := range kLines {
+		stream.EmitKLineClosed(k)
+	}
+
+	// the graph compiled after the klines are loaded should have the same values
+	source := &indicatorv2.KLineStream{}
+	source.BackFill(kLines)
+
+	backFilled, err := Compile(expression, source)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assertValues(t, live.Slice, backFilled.Slice)
+	assert.Equal(t, live.Truthy(), backFilled.Truthy())
+}
+
+func assertValues(t *testing.T, want, got []float64) {
+	if !assert.Equal(t, len(want), len(got)) {
+		return
+	}
+
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			assert.True(t, math.IsNaN(got[i]), "index %d: want NaN, got %f", i, got[i])
+			continue
+		}
+
+		assert.InDelta(t, want[i], got[i], 1e-9, "index %d", i)
+	}
+}
+
+func TestStream_Triggered(t *testing.T) {
+	stream := &types.StandardStream{}
+	s, err := Compile("close > 2", indicatorv2.KLines(stream, "", ""))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var triggered []bool
+	for _, k := range buildKLines(1, 3, 4, 1, 3) {
+		stream.EmitKLineClosed(k)
+		triggered = append(triggered, s.Triggered())
+	}
+
+	assert.Equal(t, []bool{false, true, false, false, true}, triggered)
+}
@@ -0,0 +1,442 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type function struct {
+	minArgs, maxArgs int
+	build            func(c *compiler, args *arguments) (value, error)
+}
+
+type arguments struct {
+	name   string
+	values []value
+}
+
+func (a *arguments) series(i int) (types.Float64Source, error) {
+	v := a.values[i]
+	if v.isConstant() {
+		return nil, fmt.Errorf("%s: argument %d must be a series, got constant %v", a.name, i+1, v.constant)
+	}
+
+	return v.series, nil
+}
+
+func (a *arguments) float(i int, defaultValue float64) (float64, error) {
+	if i >= len(a.values) {
+		return defaultValue, nil
+	}
+
+	v := a.values[i]
+	if !v.isConstant() {
+		return 0, fmt.Errorf("%s: argument %d must be a constant", a.name, i+1)
+	}
+
+	return v.constant, nil
+}
+
+func (a *arguments) int(i int, defaultValue int) (int, error) {
+	f, err := a.float(i, float64(defaultValue))
+	if err != nil {
+		return 0, err
+	}
+
+	if f != math.Trunc(f) || f < 0 {
+		return 0, fmt.Errorf("%s: argument %d must be a non-negative integer, got %v", a.name, i+1, f)
+	}
+
+	return int(f), nil
+}
+
+func (a *arguments) window(i int) (int, error) {
+	w, err := a.int(i, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if w == 0 {
+		return 0, fmt.Errorf("%s: window can not be zero", a.name)
+	}
+
+	return w, nil
+}
+
+func series(s types.Float64Source) (value, error) {
+	return value{series: s}, nil
+}
+
+// windowFunction creates the function with the signature name(source, window)
+func windowFunction(f func(source types.Float64Source, window int) types.Float64Source) function {
+	return function{
+		minArgs: 2,
+		maxArgs: 2,
+		build: func(c *compiler, args *arguments) (value, error) {
+			source, err := args.series(0)
+			if err != nil {
+				return value{}, err
+			}
+
+			window, err := args.window(1)
+			if err != nil {
+				return value{}, err
+			}
+
+			return series(f(source, window))
+		},
+	}
+}
+
+// kLineWindowFunction creates the function with the signature name(window), which is calculated from the klines
+func kLineWindowFunction(f func(source indicatorv2.KLineSubscription, window int) types.Float64Source) function {
+	return function{
+		minArgs: 1,
+		maxArgs: 1,
+		build: func(c *compiler, args *arguments) (value, error) {
+			window, err := args.window(0)
+			if err != nil {
+				return value{}, err
+			}
+
+			return series(f(c.kLines, window))
+		},
+	}
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		// moving averages and the other indicators calculated from a series
+		"sma": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.SMA(source, window)
+		}),
+		"ema": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.EWMA2(source, window)
+		}),
+		"rma": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.RMA2(source, window, true)
+		}),
+		"smma": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.SMMA2(source, window)
+		}),
+		"dema": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.DEMA(source, window)
+		}),
+		"tema": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.TEMA(source, window)
+		}),
+		"tma": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.TMA(source, window)
+		}),
+		"zlema": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.ZLEMA(source, window)
+		}),
+		"hull": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.HULL(source, window)
+		}),
+		"wwma": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.WWMA(source, window)
+		}),
+		"vidya": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.VIDYA(source, window)
+		}),
+		"gma": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.GMA(source, window)
+		}),
+		"rsi": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.RSI2(source, window)
+		}),
+		"cci": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.CCI(source, window)
+		}),
+		"stddev": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.StdDev(source, window)
+		}),
+		"linreg": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.LinReg(source, window)
+		}),
+		"drift": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.Drift(source, window)
+		}),
+		"fisher": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.FisherTransform(source, window)
+		}),
+		"volatility": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.Volatility(source, window)
+		}),
+		"ghfilter": windowFunction(func(source types.Float64Source, window int) types.Float64Source {
+			return indicatorv2.GHFilter(source, window)
+		}),
+
+		// the indicators calculated from the klines
+		"atr": kLineWindowFunction(func(source indicatorv2.KLineSubscription, window int) types.Float64Source {
+			return indicatorv2.ATR2(source, window)
+		}),
+		"atrp": kLineWindowFunction(func(source indicatorv2.KLineSubscription, window int) types.Float64Source {
+			return indicatorv2.ATRP2(source, window)
+		}),
+		"adx": kLineWindowFunction(func(source indicatorv2.KLineSubscription, window int) types.Float64Source {
+			return indicatorv2.ADX(source, window)
+		}),
+		"vwma": kLineWindowFunction(func(source indicatorv2.KLineSubscription, window int) types.Float64Source {
+			return indicatorv2.VWMA(source, window)
+		}),
+		"emv": kLineWindowFunction(func(source indicatorv2.KLineSubscription, window int) types.Float64Source {
+			return indicatorv2.EMV(source, window, 0)
+		}),
+		"psar": kLineWindowFunction(func(source indicatorv2.KLineSubscription, window int) types.Float64Source {
+			return indicatorv2.PSAR(source, window)
+		}),
+		"obv": {
+			build: func(c *compiler, args *arguments) (value, error) {
+				return series(indicatorv2.OBV(c.kLines))
+			},
+		},
+		"ad": {
+			build: func(c *compiler, args *arguments) (value, error) {
+				return series(indicatorv2.AD(c.kLines))
+			},
+		},
+		"vwap": {
+			minArgs: 0,
+			maxArgs: 1,
+			build: func(c *compiler, args *arguments) (value, error) {
+				window, err := args.int(0, 0)
+				if err != nil {
+					return value{}, err
+				}
+
+				return series(indicatorv2.VWAP(c.kLines, window))
+			},
+		},
+		"supertrend": {
+			minArgs: 1,
+			maxArgs: 2,
+			build: func(c *compiler, args *arguments) (value, error) {
+				window, err := args.window(0)
+				if err != nil {
+					return value{}, err
+				}
+
+				multiplier, err := args.float(1, 3.0)
+				if err != nil {
+					return value{}, err
+				}
+
+				return series(indicatorv2.Supertrend(c.kLines, window, multiplier))
+			},
+		},
+
+		// the indicators with the optional parameters
+		"alma": {
+			minArgs: 2,
+			maxArgs: 4,
+			build: func(c *compiler, args *arguments) (value, error) {
+				source, err := args.series(0)
+				if err != nil {
+					return value{}, err
+				}
+
+				window, err := args.window(1)
+				if err != nil {
+					return value{}, err
+				}
+
+				offset, err := args.float(2, 0.85)
+				if err != nil {
+					return value{}, err
+				}
+
+				sigma, err := args.int(3, 6)
+				if err != nil {
+					return value{}, err
+				}
+
+				return series(indicatorv2.ALMA(source, window, offset, sigma))
+			},
+		},
+		"ssf": {
+			minArgs: 2,
+			maxArgs: 3,
+			build: func(c *compiler, args *arguments) (value, error) {
+				source, err := args.series(0)
+				if err != nil {
+					return value{}, err
+				}
+
+				window, err := args.window(1)
+				if err != nil {
+					return value{}, err
+				}
+
+				poles, err := args.int(2, 2)
+				if err != nil {
+					return value{}, err
+				}
+
+				return series(indicatorv2.SSF(source, window, poles))
+			},
+		},
+		"till": {
+			minArgs: 2,
+			maxArgs: 3,
+			build: func(c *compiler, args *arguments) (value, error) {
+				source, err := args.series(0)
+				if err != nil {
+					return value{}, err
+				}
+
+				window, err := args.window(1)
+				if err != nil {
+					return value{}, err
+				}
+
+				volumeFactor, err := args.float(2, 0)
+				if err != nil {
+					return value{}, err
+				}
+
+				return series(indicatorv2.TILL(source, window, volumeFactor))
+			},
+		},
+		"tsi": {
+			minArgs: 1,
+			maxArgs: 3,
+			build: func(c *compiler, args *arguments) (value, error) {
+				source, err := args.series(0)
+				if err != nil {
+					return value{}, err
+				}
+
+				fast, err := args.int(1, 0)
+				if err != nil {
+					return value{}, err
+				}
+
+				slow, err := args.int(2, 0)
+				if err != nil {
+					return value{}, err
+				}
+
+				return series(indicatorv2.TSI(source, fast, slow))
+			},
+		},
+		"kalman": {
+			minArgs: 2,
+			maxArgs: 3,
+			build: func(c *compiler, args *arguments) (value, error) {
+				source, err := args.series(0)
+				if err != nil {
+					return value{}, err
+				}
+
+				window, err := args.window(1)
+				if err != nil {
+					return value{}, err
+				}
+
+				smooth, err := args.int(2, 0)
+				if err != nil {
+					return value{}, err
+				}
+
+				return series(indicatorv2.KalmanFilter(source, window, smooth))
+			},
+		},
+
+		// math functions
+		"abs": {
+			minArgs: 1,
+			maxArgs: 1,
+			build: func(c *compiler, args *arguments) (value, error) {
+				return c.apply(args.values, func(v []float64) float64 {
+					return math.Abs(v[0])
+				}), nil
+			},
+		},
+		"min": {
+			minArgs: 2,
+			maxArgs: 2,
+			build: func(c *compiler, args *arguments) (value, error) {
+				return c.apply(args.values, func(v []float64) float64 {
+					return math.Min(v[0], v[1])
+				}), nil
+			},
+		},
+		"max": {
+			minArgs: 2,
+			maxArgs: 2,
+			build: func(c *compiler, args *arguments) (value, error) {
+				return c.apply(args.values, func(v []float64) float64 {
+					return math.Max(v[0], v[1])
+				}), nil
+			},
+		},
+
+		// prev(x, n) is the value of x n klines ago
+		"prev": {
+			minArgs: 1,
+			maxArgs: 2,
+			build: func(c *compiler, args *arguments) (value, error) {
+				if _, err := args.series(0); err != nil {
+					return value{}, err
+				}
+
+				n, err := args.int(1, 1)
+				if err != nil {
+					return value{}, err
+				}
+
+				history := types.NewQueue(n + 1)
+				return value{series: c.operator(args.values[:1], func(v []float64) float64 {
+					history.Update(v[0])
+					if history.Length() <= n {
+						return math.NaN()
+					}
+
+					return history.Last(n)
+				})}, nil
+			},
+		},
+
+		// cross signals
+		"cross_over": crossFunction(func(previous, current float64) bool {
+			return previous <= 0 && current > 0
+		}),
+		"cross_under": crossFunction(func(previous, current float64) bool {
+			return previous >= 0 && current < 0
+		}),
+	}
+}
+
+// crossFunction creates the function name(a, b) which is 1 when the difference a - b crosses zero on the current kline
+func crossFunction(crossed func(previous, current float64) bool) function {
+	return function{
+		minArgs: 2,
+		maxArgs: 2,
+		build: func(c *compiler, args *arguments) (value, error) {
+			if args.values[0].isConstant() && args.values[1].isConstant() {
+				return value{}, fmt.Errorf("%s: at least one of the arguments must be a series", args.name)
+			}
+
+			previous := math.NaN()
+			return value{series: c.operator(args.values, func(v []float64) float64 {
+				current := v[0] - v[1]
+				defer func() {
+					previous = current
+				}()
+
+				if math.IsNaN(previous) || math.IsNaN(current) {
+					return 0
+				}
+
+				return boolValue(crossed(previous, current))
+			})}, nil
+		},
+	}
+}
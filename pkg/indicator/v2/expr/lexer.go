@@ -0,0 +1,123 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	typ   tokenType
+	text  string
+	value float64
+	pos   int
+}
+
+func (t token) String() string {
+	if t.typ == tokenEOF {
+		return "end of expression"
+	}
+
+	return strconv.Quote(t.text)
+}
+
+// operator aliases, the keywords and the symbols are normalized into the same operator
+var operatorAliases = map[string]string{
+	"and": "&&",
+	"or":  "||",
+	"not": "!",
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{typ: tokenLeftParen, text: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{typ: tokenRightParen, text: ")", pos: i})
+			i++
+
+		case c == ',':
+			tokens = append(tokens, token{typ: tokenComma, text: ",", pos: i})
+			i++
+
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+
+			text := string(runes[start:i])
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", text, start)
+			}
+
+			tokens = append(tokens, token{typ: tokenNumber, text: text, value: value, pos: start})
+
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+
+			text := strings.ToLower(string(runes[start:i]))
+			if op, ok := operatorAliases[text]; ok {
+				tokens = append(tokens, token{typ: tokenOperator, text: op, pos: start})
+			} else {
+				tokens = append(tokens, token{typ: tokenIdent, text: text, pos: start})
+			}
+
+		default:
+			start := i
+			op, ok := matchOperator(runes[i:])
+			if !ok {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, start)
+			}
+
+			i += len(op)
+			tokens = append(tokens, token{typ: tokenOperator, text: op, pos: start})
+		}
+	}
+
+	tokens = append(tokens, token{typ: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+func matchOperator(runes []rune) (string, bool) {
+	if len(runes) >= 2 {
+		switch op := string(runes[:2]); op {
+		case "<=", ">=", "==", "!=", "&&", "||":
+			return op, true
+		}
+	}
+
+	switch op := string(runes[:1]); op {
+	case "+", "-", "*", "/", "<", ">", "!":
+		return op, true
+	}
+
+	return "", false
+}
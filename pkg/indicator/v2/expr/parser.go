@@ -0,0 +1,220 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is the node of the expression syntax tree
+type Node interface {
+	String() string
+}
+
+// NumberNode is a numeric literal
+type NumberNode struct {
+	Value float64
+}
+
+func (n *NumberNode) String() string {
+	return strconv.FormatFloat(n.Value, 'f', -1, 64)
+}
+
+// IdentNode is a price series like close, open, high, low or volume
+type IdentNode struct {
+	Name string
+}
+
+func (n *IdentNode) String() string {
+	return n.Name
+}
+
+// CallNode is a function call like ema(close, 12)
+type CallNode struct {
+	Name string
+	Args []Node
+}
+
+func (n *CallNode) String() string {
+	var args []string
+	for _, arg := range n.Args {
+		args = append(args, arg.String())
+	}
+
+	return n.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// UnaryNode is the negation "-x" or the logical not "!x"
+type UnaryNode struct {
+	Operator string
+	Operand  Node
+}
+
+func (n *UnaryNode) String() string {
+	return n.Operator + n.Operand.String()
+}
+
+// BinaryNode is the arithmetic, comparison or logical operation
+type BinaryNode struct {
+	Operator    string
+	Left, Right Node
+}
+
+func (n *BinaryNode) String() string {
+	return "(" + n.Left.String() + " " + n.Operator + " " + n.Right.String() + ")"
+}
+
+// binary operator precedences, the higher binds tighter
+var precedences = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6,
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses the expression into the syntax tree
+func Parse(input string) (Node, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.typ != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+	}
+
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.typ != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) expect(typ tokenType, text string) error {
+	if tok := p.next(); tok.typ != typ {
+		return fmt.Errorf("expected %q, got %s at position %d", text, tok, tok.pos)
+	}
+
+	return nil
+}
+
+// parseBinary parses the binary operations with the precedence climbing method
+func (p *parser) parseBinary(minPrecedence int) (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.typ != tokenOperator {
+			return left, nil
+		}
+
+		precedence, ok := precedences[tok.text]
+		if !ok || precedence < minPrecedence {
+			return left, nil
+		}
+
+		p.next()
+
+		// all the binary operators are left associative
+		right, err := p.parseBinary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &BinaryNode{Operator: tok.text, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.peek()
+	if tok.typ == tokenOperator && (tok.text == "-" || tok.text == "!") {
+		p.next()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &UnaryNode{Operator: tok.text, Operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.typ {
+	case tokenNumber:
+		return &NumberNode{Value: tok.value}, nil
+
+	case tokenLeftParen:
+		node, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(tokenRightParen, ")"); err != nil {
+			return nil, err
+		}
+
+		return node, nil
+
+	case tokenIdent:
+		if p.peek().typ != tokenLeftParen {
+			return &IdentNode{Name: tok.text}, nil
+		}
+
+		p.next()
+
+		call := &CallNode{Name: tok.text}
+		if p.peek().typ == tokenRightParen {
+			p.next()
+			return call, nil
+		}
+
+		for {
+			arg, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+
+			call.Args = append(call.Args, arg)
+
+			sep := p.next()
+			if sep.typ == tokenRightParen {
+				return call, nil
+			}
+
+			if sep.typ != tokenComma {
+				return nil, fmt.Errorf("expected \",\" or \")\", got %s at position %d", sep, sep.pos)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+}
@@ -0,0 +1,50 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// FisherTransformStream converts the prices into a Gaussian normal distribution
+// - https://www.investopedia.com/terms/f/fisher-transform.asp
+type FisherTransformStream struct {
+	*types.Float64Series
+
+	window int
+	prices *types.Queue
+}
+
+func FisherTransform(source types.Float64Source, window int) *FisherTransformStream {
+	checkWindow(window)
+
+	s := &FisherTransformStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		prices:        types.NewQueue(window),
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *FisherTransformStream) Calculate(value float64) float64 {
+	s.prices.Update(value)
+	highest := s.prices.Highest(s.window)
+	lowest := s.prices.Lowest(s.window)
+	if highest == lowest {
+		return 0
+	}
+
+	x := 2*((value-lowest)/(highest-lowest)) - 1
+	if x == 1 {
+		x = 0.9999
+	} else if x == -1 {
+		x = -0.9999
+	}
+
+	return 0.5 * math.Log((1+x)/(1-x))
+}
+
+func (s *FisherTransformStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
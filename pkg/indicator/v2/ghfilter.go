@@ -0,0 +1,55 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// GHFilterStream is the Ehler's Optimal Tracking Filter, an alpha-beta filter, also called g-h filter
+// - https://jamesgoulding.com/Research_II/Ehlers/Ehlers%20(Optimal%20Tracking%20Filters).doc
+//
+// The measurement uncertainty is the absolute change of the input value.
+type GHFilterStream struct {
+	*types.Float64Series
+
+	window int
+
+	a               float64 // maneuverability uncertainty
+	b               float64 // measurement uncertainty
+	lastMeasurement float64
+}
+
+func GHFilter(source types.Float64Source, window int) *GHFilterStream {
+	checkWindow(window)
+
+	s := &GHFilterStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *GHFilterStream) Calculate(value float64) float64 {
+	uncertainty := math.Abs(value - s.lastMeasurement)
+	if s.Slice.Length() == 0 {
+		s.a = 0
+		s.b = uncertainty / 2
+		s.lastMeasurement = value
+		return value
+	}
+
+	multiplier := 2.0 / float64(1+s.window) // EMA multiplier
+	s.a = multiplier*(value-s.lastMeasurement) + (1-multiplier)*s.a
+	s.b = multiplier*uncertainty/2 + (1-multiplier)*s.b
+	lambda := s.a / s.b
+	lambda2 := lambda * lambda
+	alpha := (-lambda2 + math.Sqrt(lambda2*lambda2+16*lambda2)) / 8
+	s.lastMeasurement = value
+	return alpha*value + (1-alpha)*s.Slice.Last(0)
+}
+
+func (s *GHFilterStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,42 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// GMAStream is the Geometric Moving Average, the n-th root of the product of the last n values,
+// which is calculated by the exponential of the mean of the log values.
+// The value is pushed once the window is full.
+type GMAStream struct {
+	*types.Float64Series
+
+	window    int
+	rawValues *types.Queue
+}
+
+func GMA(source types.Float64Source, window int) *GMAStream {
+	checkWindow(window)
+
+	s := &GMAStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		rawValues:     types.NewQueue(window),
+	}
+
+	s.Subscribe(source, func(v float64) {
+		s.rawValues.Update(math.Log(v))
+		if s.rawValues.Length() < s.window {
+			return
+		}
+
+		s.PushAndEmit(math.Exp(types.Mean(s.rawValues)))
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *GMAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,34 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// HULLStream is the Hull Moving Average
+// - https://fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/hull-moving-average
+//
+// HULL = EMA(2 * EMA(price, window / 2) - EMA(price, window), sqrt(window))
+type HULLStream struct {
+	*EWMAStream
+
+	MA1, MA2 *EWMAStream
+}
+
+func HULL(source types.Float64Source, window int) *HULLStream {
+	ma1 := EWMA2(source, window/2)
+	ma2 := EWMA2(source, window)
+
+	diff := types.NewFloat64Series()
+	ma2.OnUpdate(func(v float64) {
+		diff.PushAndEmit(2*ma1.Last(0) - v)
+		diff.Slice = generalTruncate(diff.Slice)
+	})
+
+	return &HULLStream{
+		EWMAStream: EWMA2(diff, int(math.Sqrt(float64(window)))),
+		MA1:        ma1,
+		MA2:        ma2,
+	}
+}
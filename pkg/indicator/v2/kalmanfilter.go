@@ -0,0 +1,66 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// KalmanFilterStream is the one-dimensional Kalman filter
+// - https://www.kalmanfilter.net/kalman1d.html
+//
+// The measurement uncertainty is the root mean square of the value changes in the window,
+// additionalSmoothWindow enlarges the uncertainty to make the output smoother.
+type KalmanFilterStream struct {
+	*types.Float64Series
+
+	window                 int
+	additionalSmoothWindow int
+
+	amp2         *types.Queue // measurement uncertainty
+	k            float64      // Kalman gain
+	measurements *types.Queue
+}
+
+func KalmanFilter(source types.Float64Source, window, additionalSmoothWindow int) *KalmanFilterStream {
+	checkWindow(window)
+
+	s := &KalmanFilterStream{
+		Float64Series:          types.NewFloat64Series(),
+		window:                 window,
+		additionalSmoothWindow: additionalSmoothWindow,
+		amp2:                   types.NewQueue(window),
+		measurements:           types.NewQueue(window),
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *KalmanFilterStream) Calculate(value float64) float64 {
+	if s.Slice.Length() == 0 {
+		s.amp2.Update(value * value)
+		s.measurements.Update(value)
+		return value
+	}
+
+	amp := math.Abs(value - s.measurements.Last(0))
+
+	// measurement
+	s.measurements.Update(value)
+	s.amp2.Update(amp * amp)
+	q := math.Sqrt(types.Mean(s.amp2)) * float64(1+s.additionalSmoothWindow)
+
+	// update
+	lastPredict := s.Slice.Last(0)
+	curState := value + (value - lastPredict)
+	estimated := lastPredict + s.k*(curState-lastPredict)
+
+	// predict
+	p := math.Abs(curState - estimated)
+	s.k = p / (p + q)
+	return estimated
+}
+
+func (s *KalmanFilterStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
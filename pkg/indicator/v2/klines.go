@@ -49,16 +49,18 @@ func (s *KLineStream) BackFill(kLines []types.KLine) {
 func KLines(source types.Stream, symbol string, interval types.Interval) *KLineStream {
 	s := &KLineStream{}
 
-	source.OnKLineClosed(types.KLineWith(symbol, interval, func(k types.KLine) {
-		s.kLines = append(s.kLines, k)
-		s.EmitUpdate(k)
+	source.OnKLineClosed(types.KLineWith(symbol, interval, s.Push))
+	return s
+}
 
-		if len(s.kLines) > MaxNumOfKLines {
-			s.kLines = s.kLines[len(s.kLines)-1-MaxNumOfKLines:]
-		}
-	}))
+// Push appends the kline and pushes it to the subscribers
+func (s *KLineStream) Push(k types.KLine) {
+	s.kLines = append(s.kLines, k)
+	s.EmitUpdate(k)
 
-	return s
+	if len(s.kLines) > MaxNumOfKLines {
+		s.kLines = s.kLines[len(s.kLines)-1-MaxNumOfKLines:]
+	}
 }
 
 type KLineSubscription interface {
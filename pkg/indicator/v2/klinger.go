@@ -0,0 +1,56 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultKlingerFastWindow = 34
+	defaultKlingerSlowWindow = 55
+)
+
+// KlingerOscillatorStream is the difference between the fast and the slow EMAs of the volume force
+// - https://www.investopedia.com/terms/k/klingeroscillator.asp
+type KlingerOscillatorStream struct {
+	*types.Float64Series
+
+	vf         indicator.VolumeForce
+	fast, slow *ewma
+	started    bool
+}
+
+// KlingerOscillator creates the klinger oscillator stream, the fast and the slow windows are 34 and 55 if they are zero
+func KlingerOscillator(source KLineSubscription, fastWindow, slowWindow int) *KlingerOscillatorStream {
+	if fastWindow == 0 {
+		fastWindow = defaultKlingerFastWindow
+	}
+
+	if slowWindow == 0 {
+		slowWindow = defaultKlingerSlowWindow
+	}
+
+	s := &KlingerOscillatorStream{
+		Float64Series: types.NewFloat64Series(),
+		fast:          newEWMA(fastWindow),
+		slow:          newEWMA(slowWindow),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.vf.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64())
+
+		// the first volume force is the raw volume, which is not used
+		if !s.started {
+			s.started = true
+			return
+		}
+
+		s.PushAndEmit(s.fast.update(s.vf.Value) - s.slow.update(s.vf.Value))
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *KlingerOscillatorStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,464 @@
+package indicatorv2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// buildLegacyTestKLines generates a deterministic random walk of klines for comparing the outputs
+// with the legacy indicator package
+func buildLegacyTestKLines(n int) (kLines []types.KLine) {
+	r := rand.New(rand.NewSource(42))
+	price := 100.0
+	for i := 0; i < n; i++ {
+		open := price
+		price = math.Max(1.0, price+r.NormFloat64()*2.0)
+		high := math.Max(open, price) + r.Float64()*1.5
+		low := math.Min(open, price) - r.Float64()*1.5
+		kLines = append(kLines, types.KLine{
+			Open:   fixedpoint.NewFromFloat(open),
+			High:   fixedpoint.NewFromFloat(high),
+			Low:    fixedpoint.NewFromFloat(low),
+			Close:  fixedpoint.NewFromFloat(price),
+			Volume: fixedpoint.NewFromFloat(10.0 + r.Float64()*100.0),
+		})
+	}
+	return kLines
+}
+
+// assertLegacySeries asserts the values of the legacy series are identical to the values of the v2 series
+func assertLegacySeries(t *testing.T, legacy types.Series, values []float64) {
+	if !assert.Equal(t, legacy.Length(), len(values), "length") {
+		return
+	}
+
+	for i := 0; i < len(values); i++ {
+		want := legacy.Last(i)
+		got := values[len(values)-1-i]
+		if math.IsNaN(want) {
+			assert.True(t, math.IsNaN(got), "index %d: want NaN, got %f", i, got)
+			continue
+		}
+
+		assert.InDelta(t, want, got, 1e-9, "index %d", i)
+	}
+}
+
+func Test_LegacyFloat64Indicators(t *testing.T) {
+	kLines := buildLegacyTestKLines(300)
+
+	tests := []struct {
+		name   string
+		legacy types.UpdatableSeries
+		v2     func(source types.Float64Source) *types.Float64Series
+	}{
+		{
+			name:   "ALMA",
+			legacy: &indicator.ALMA{IntervalWindow: types.IntervalWindow{Window: 9}, Offset: 0.85, Sigma: 6},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return ALMA(source, 9, 0.85, 6).Float64Series
+			},
+		},
+		{
+			name:   "DEMA",
+			legacy: &indicator.DEMA{IntervalWindow: types.IntervalWindow{Window: 16}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return DEMA(source, 16).Float64Series
+			},
+		},
+		{
+			name:   "TEMA",
+			legacy: &indicator.TEMA{IntervalWindow: types.IntervalWindow{Window: 16}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return TEMA(source, 16).Float64Series
+			},
+		},
+		{
+			name:   "TMA",
+			legacy: &indicator.TMA{IntervalWindow: types.IntervalWindow{Window: 9}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return TMA(source, 9).Float64Series
+			},
+		},
+		{
+			name:   "ZLEMA",
+			legacy: &indicator.ZLEMA{IntervalWindow: types.IntervalWindow{Window: 16}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return ZLEMA(source, 16).Float64Series
+			},
+		},
+		{
+			name:   "HULL",
+			legacy: &indicator.HULL{IntervalWindow: types.IntervalWindow{Window: 16}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return HULL(source, 16).Float64Series
+			},
+		},
+		{
+			name:   "GMA",
+			legacy: &indicator.GMA{IntervalWindow: types.IntervalWindow{Window: 5}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return GMA(source, 5).Float64Series
+			},
+		},
+		{
+			name:   "GHFilter",
+			legacy: &indicator.GHFilter{IntervalWindow: types.IntervalWindow{Window: 50}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return GHFilter(source, 50).Float64Series
+			},
+		},
+		{
+			name:   "KalmanFilter",
+			legacy: &indicator.KalmanFilter{IntervalWindow: types.IntervalWindow{Window: 10}, AdditionalSmoothWindow: 2},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return KalmanFilter(source, 10, 2).Float64Series
+			},
+		},
+		{
+			name:   "SSF2",
+			legacy: &indicator.SSF{IntervalWindow: types.IntervalWindow{Window: 5}, Poles: 2},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return SSF(source, 5, 2).Float64Series
+			},
+		},
+		{
+			name:   "SSF3",
+			legacy: &indicator.SSF{IntervalWindow: types.IntervalWindow{Window: 5}, Poles: 3},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return SSF(source, 5, 3).Float64Series
+			},
+		},
+		{
+			name:   "VIDYA",
+			legacy: &indicator.VIDYA{IntervalWindow: types.IntervalWindow{Window: 16}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return VIDYA(source, 16).Float64Series
+			},
+		},
+		{
+			name:   "TSI",
+			legacy: &indicator.TSI{},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return TSI(source, 0, 0).Float64Series
+			},
+		},
+		{
+			name:   "TILL",
+			legacy: &indicator.TILL{IntervalWindow: types.IntervalWindow{Window: 16}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return TILL(source, 16, 0).Float64Series
+			},
+		},
+		{
+			name:   "FisherTransform",
+			legacy: &indicator.FisherTransform{IntervalWindow: types.IntervalWindow{Window: 10}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return FisherTransform(source, 10).Float64Series
+			},
+		},
+		{
+			name:   "Drift",
+			legacy: &indicator.Drift{IntervalWindow: types.IntervalWindow{Window: 3}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return Drift(source, 3).Float64Series
+			},
+		},
+		{
+			name:   "WWMA",
+			legacy: &indicator.WWMA{IntervalWindow: types.IntervalWindow{Window: 14}},
+			v2: func(source types.Float64Source) *types.Float64Series {
+				return WWMA(source, 14).Float64Series
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices := ClosePrices(nil)
+			s := tt.v2(prices)
+			for _, k := range kLines {
+				v := k.Close.Float64()
+				tt.legacy.Update(v)
+				prices.PushAndEmit(v)
+			}
+
+			assertLegacySeries(t, tt.legacy, s.Slice)
+		})
+	}
+}
+
+// legacyAD adds the missing PushK method to the legacy AD indicator
+type legacyAD struct {
+	*indicator.AD
+}
+
+func (inc *legacyAD) PushK(k types.KLine) {
+	inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64())
+}
+
+func Test_LegacyKLineIndicators(t *testing.T) {
+	kLines := buildLegacyTestKLines(300)
+
+	tests := []struct {
+		name   string
+		legacy interface {
+			types.Series
+			PushK(k types.KLine)
+		}
+		v2 func(source KLineSubscription) *types.Float64Series
+	}{
+		{
+			name:   "AD",
+			legacy: &legacyAD{AD: &indicator.AD{}},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return AD(source).Float64Series
+			},
+		},
+		{
+			name:   "OBV",
+			legacy: &indicator.OBV{},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return OBV(source).Float64Series
+			},
+		},
+		{
+			name:   "VWAP",
+			legacy: &indicator.VWAP{IntervalWindow: types.IntervalWindow{Window: 10}},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return VWAP(source, 10).Float64Series
+			},
+		},
+		{
+			name:   "CumulativeVWAP",
+			legacy: &indicator.VWAP{},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return VWAP(source, 0).Float64Series
+			},
+		},
+		{
+			name:   "WeightedDrift",
+			legacy: &indicator.WeightedDrift{IntervalWindow: types.IntervalWindow{Window: 10}},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return WeightedDrift(source, 10).Float64Series
+			},
+		},
+		{
+			name:   "EMV",
+			legacy: &indicator.EMV{IntervalWindow: types.IntervalWindow{Window: 14}},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return EMV(source, 14, 0).Float64Series
+			},
+		},
+		{
+			name:   "KlingerOscillator",
+			legacy: &indicator.KlingerOscillator{},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return KlingerOscillator(source, 0, 0).Float64Series
+			},
+		},
+		{
+			name:   "PSAR",
+			legacy: &indicator.PSAR{IntervalWindow: types.IntervalWindow{Window: 2}},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return PSAR(source, 2).Float64Series
+			},
+		},
+		{
+			name:   "LinReg",
+			legacy: &indicator.LinReg{IntervalWindow: types.IntervalWindow{Window: 20}},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return LinReg(ClosePrices(source), 20).Float64Series
+			},
+		},
+		{
+			name: "Supertrend",
+			legacy: &indicator.Supertrend{
+				IntervalWindow:   types.IntervalWindow{Window: 10},
+				ATRMultiplier:    3,
+				AverageTrueRange: &indicator.ATR{IntervalWindow: types.IntervalWindow{Window: 10}},
+			},
+			v2: func(source KLineSubscription) *types.Float64Series {
+				return Supertrend(source, 10, 3).Float64Series
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &types.StandardStream{}
+			s := tt.v2(KLines(stream, "", ""))
+			for _, k := range kLines {
+				tt.legacy.PushK(k)
+				stream.EmitKLineClosed(k)
+			}
+
+			assertLegacySeries(t, tt.legacy, s.Slice)
+		})
+	}
+}
+
+func Test_LegacyVWMA(t *testing.T) {
+	kLines := buildLegacyTestKLines(300)
+
+	legacy := &indicator.VWMA{IntervalWindow: types.IntervalWindow{Window: 10}}
+	stream := &types.StandardStream{}
+	vwma := VWMA(KLines(stream, "", ""), 10)
+	for _, k := range kLines {
+		legacy.PushK(k)
+		stream.EmitKLineClosed(k)
+	}
+
+	// the legacy VWMA pushes NaN before the window is full, the v2 VWMA pushes nothing
+	for i := 0; i < 9; i++ {
+		assert.True(t, math.IsNaN(legacy.Values[i]), "index %d", i)
+	}
+
+	assertLegacySeries(t, floats.Slice(legacy.Values[9:]), vwma.Slice)
+}
+
+func Test_LegacyVolatility(t *testing.T) {
+	kLines := buildLegacyTestKLines(300)
+
+	legacy := &indicator.Volatility{IntervalWindow: types.IntervalWindow{Window: 20}}
+	stream := &types.StandardStream{}
+	volatility := Volatility(ClosePrices(KLines(stream, "", "")), 20)
+	for i, k := range kLines {
+		legacy.CalculateAndUpdate(kLines[:i+1])
+		stream.EmitKLineClosed(k)
+	}
+
+	assertLegacySeries(t, legacy, volatility.Slice)
+}
+
+func Test_LegacyDMI(t *testing.T) {
+	kLines := buildLegacyTestKLines(300)
+
+	legacy := &indicator.DMI{IntervalWindow: types.IntervalWindow{Window: 14}, ADXSmoothing: 14}
+	stream := &types.StandardStream{}
+	dmi := DMI(KLines(stream, "", ""), 14, 14)
+	for _, k := range kLines {
+		legacy.PushK(k)
+		stream.EmitKLineClosed(k)
+	}
+
+	assertLegacySeries(t, legacy.GetDIPlus(), dmi.DIPlus.Slice)
+	assertLegacySeries(t, legacy.GetDIMinus(), dmi.DIMinus.Slice)
+	assertLegacySeries(t, legacy.GetADX(), dmi.Slice)
+}
+
+func Test_LegacyUTBotAlert(t *testing.T) {
+	kLines := buildLegacyTestKLines(300)
+
+	legacy := indicator.NewUtBotAlert(types.IntervalWindow{Window: 10}, 1)
+	stream := &types.StandardStream{}
+	alert := UTBotAlert(KLines(stream, "", ""), 10, 1)
+	for _, k := range kLines {
+		legacy.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+		stream.EmitKLineClosed(k)
+	}
+
+	if assert.Equal(t, legacy.Length(), alert.Length()) {
+		for i := 0; i < legacy.Length(); i++ {
+			assert.Equal(t, legacy.Index(i), types.Direction(alert.Last(i)), "index %d", i)
+		}
+	}
+}
+
+func Test_LegacyPivotSupertrend(t *testing.T) {
+	kLines := buildLegacyTestKLines(300)
+
+	iw := types.IntervalWindow{Window: 10}
+	legacy := &indicator.PivotSupertrend{
+		IntervalWindow:   iw,
+		ATRMultiplier:    3,
+		PivotWindow:      5,
+		AverageTrueRange: &indicator.ATR{IntervalWindow: iw},
+		PivotLow:         &indicator.PivotLow{IntervalWindow: types.IntervalWindow{Window: 5}},
+		PivotHigh:        &indicator.PivotHigh{IntervalWindow: types.IntervalWindow{Window: 5}},
+	}
+
+	stream := &types.StandardStream{}
+	st := PivotSupertrend(KLines(stream, "", ""), 10, 5, 3)
+	for _, k := range kLines {
+		legacy.PushK(k)
+		stream.EmitKLineClosed(k)
+	}
+
+	assertLegacySeries(t, legacy, st.Slice)
+	assert.Equal(t, legacy.Direction(), st.Direction())
+}
+
+func Test_LegacyLine(t *testing.T) {
+	kLines := buildLegacyTestKLines(30)
+
+	legacy := indicator.NewLine(20, 100, 5, 110, types.Interval1m)
+	stream := &types.StandardStream{}
+	line := Line(KLines(stream, "", ""), 20, 100, 5, 110)
+
+	for i := -5; i < 30; i++ {
+		assert.InDelta(t, legacy.Last(i), line.At(i), 1e-9, "index %d", i)
+	}
+
+	for _, k := range kLines {
+		stream.EmitKLineClosed(k)
+	}
+
+	// the legacy line is shifted by the kline window updates, move the points to the shifted indexes
+	n := len(kLines)
+	legacy.SetXY1(20+n, 100)
+	legacy.SetXY2(5+n, 110)
+
+	for i := -5; i < 60; i++ {
+		assert.InDelta(t, legacy.Last(i), line.At(i), 1e-9, "index %d", i)
+	}
+
+	if assert.Equal(t, n, line.Length()) {
+		for i := 0; i < n; i++ {
+			assert.InDelta(t, legacy.Last(i), line.Last(i), 1e-9, "index %d", i)
+		}
+	}
+}
+
+func Test_LegacyVolumeProfile(t *testing.T) {
+	kLines := buildLegacyTestKLines(300)
+
+	// the legacy volume profile returns the price levels instead of the prices and steps by the delta,
+	// the results are identical when the delta is 1
+	legacy := &indicator.VolumeProfile{IntervalWindow: types.IntervalWindow{Window: 20, Interval: types.Interval1m}, Delta: 1}
+	stream := &types.StandardStream{}
+	vp := VolumeProfile(KLines(stream, "", ""), 20, 1)
+
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, k := range kLines {
+		k.StartTime = types.Time(startTime.Add(time.Duration(i) * time.Minute))
+		k.EndTime = types.Time(k.StartTime.Time().Add(time.Minute - time.Millisecond))
+
+		price := types.KLineTypicalPriceMapper(k)
+		legacy.Update(price, k.Volume.Float64(), k.EndTime)
+		stream.EmitKLineClosed(k)
+
+		for _, p := range []float64{price - 3, price, price + 3} {
+			wantPrice, wantVolume := legacy.PointOfControlAboveEqual(p)
+			gotPrice, gotVolume := vp.PointOfControlAboveEqual(p)
+			assert.Equal(t, wantPrice, gotPrice, "kline %d above %f", i, p)
+			assert.InDelta(t, wantVolume, gotVolume, 1e-9, "kline %d above %f", i, p)
+
+			wantPrice, wantVolume = legacy.PointOfControlBelowEqual(p)
+			gotPrice, gotVolume = vp.PointOfControlBelowEqual(p)
+			assert.Equal(t, wantPrice, gotPrice, "kline %d below %f", i, p)
+			assert.InDelta(t, wantVolume, gotVolume, 1e-9, "kline %d below %f", i, p)
+		}
+	}
+
+	assert.Equal(t, len(kLines), vp.Length())
+}
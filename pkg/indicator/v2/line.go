@@ -0,0 +1,59 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// LineStream is a straight line through two points to simulate the trend, the support or the resistance.
+// The points are defined by the kline indexes, the index 0 is the latest kline. The indexes are shifted
+// when a new kline is closed, so the line stays at the same klines, and the value at the latest kline is pushed.
+type LineStream struct {
+	*types.Float64Series
+
+	start, end           float64
+	startIndex, endIndex int
+}
+
+func Line(source KLineSubscription, startIndex int, startValue float64, endIndex int, endValue float64) *LineStream {
+	if startIndex == endIndex {
+		panic("the start index and the end index of the line must be different")
+	}
+
+	s := &LineStream{
+		Float64Series: types.NewFloat64Series(),
+		start:         startValue,
+		end:           endValue,
+		startIndex:    startIndex,
+		endIndex:      endIndex,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.startIndex++
+		s.endIndex++
+
+		s.PushAndEmit(s.At(0))
+		s.Truncate()
+	})
+	return s
+}
+
+// At returns the value of the line at the kline index, the index 0 is the latest kline
+func (s *LineStream) At(i int) float64 {
+	return (s.end-s.start)/float64(s.startIndex-s.endIndex)*float64(s.endIndex-i) + s.end
+}
+
+// SetXY1 moves the start point of the line
+func (s *LineStream) SetXY1(index int, value float64) {
+	s.startIndex = index
+	s.start = value
+}
+
+// SetXY2 moves the end point of the line
+func (s *LineStream) SetXY2(index int, value float64) {
+	s.endIndex = index
+	s.end = value
+}
+
+func (s *LineStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,70 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// LinRegStream is the slope of the linear regression baseline of the prices in the window,
+// zero is pushed until the window is full.
+type LinRegStream struct {
+	*types.Float64Series
+
+	// ValueRatios are the ratios of the slope to the price
+	ValueRatios *types.Float64Series
+
+	window int
+	prices *types.Queue
+}
+
+func LinReg(source types.Float64Source, window int) *LinRegStream {
+	checkWindow(window)
+
+	s := &LinRegStream{
+		Float64Series: types.NewFloat64Series(),
+		ValueRatios:   types.NewFloat64Series(),
+		window:        window,
+		prices:        types.NewQueue(window),
+	}
+
+	s.Subscribe(source, func(v float64) {
+		slope := s.calculate(v)
+		ratio := 0.0
+		if s.prices.Length() >= s.window {
+			ratio = slope / v
+		}
+
+		s.ValueRatios.PushAndEmit(ratio)
+		s.ValueRatios.Slice = generalTruncate(s.ValueRatios.Slice)
+		s.PushAndEmit(slope)
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *LinRegStream) calculate(v float64) float64 {
+	s.prices.Update(v)
+	if s.prices.Length() < s.window {
+		return 0
+	}
+
+	var sumX, sumY, sumXSqr, sumXY float64
+	for i := 0; i < s.window; i++ {
+		val := s.prices.Last(i)
+		per := float64(i + 1)
+		sumX += per
+		sumY += val
+		sumXSqr += per * per
+		sumXY += val * per
+	}
+
+	length := float64(s.window)
+	slope := (length*sumXY - sumX*sumY) / (length*sumXSqr - sumX*sumX)
+	average := sumY / length
+	endPrice := average - slope*sumX/length + slope
+	startPrice := endPrice + slope*(length-1)
+	return (endPrice - startPrice) / (length - 1)
+}
+
+func (s *LinRegStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,46 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// OBVStream is the On-Balance Volume
+// - https://www.investopedia.com/terms/o/onbalancevolume.asp
+//
+// The volume is added when the close price goes up, and subtracted when the close price goes down.
+type OBVStream struct {
+	*types.Float64Series
+
+	prevPrice float64
+}
+
+func OBV(source KLineSubscription) *OBVStream {
+	s := &OBVStream{
+		Float64Series: types.NewFloat64Series(),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.calculateAndPush(k.Close.Float64(), k.Volume.Float64())
+	})
+	return s
+}
+
+func (s *OBVStream) calculateAndPush(price, volume float64) {
+	if s.Slice.Length() == 0 {
+		s.prevPrice = price
+		s.PushAndEmit(volume)
+		return
+	}
+
+	if price < s.prevPrice {
+		volume = -volume
+	}
+
+	s.prevPrice = price
+	s.PushAndEmit(s.Slice.Last(0) + volume)
+	s.Truncate()
+}
+
+func (s *OBVStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
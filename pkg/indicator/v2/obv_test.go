@@ -0,0 +1,58 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_OBV(t *testing.T) {
+	buildKLines := func(prices, volumes []float64) (kLines []types.KLine) {
+		for i, p := range prices {
+			price := fixedpoint.NewFromFloat(p)
+			kLines = append(kLines, types.KLine{High: price, Low: price, Close: price, Volume: fixedpoint.NewFromFloat(volumes[i])})
+		}
+		return kLines
+	}
+
+	tests := []struct {
+		name   string
+		kLines []types.KLine
+		want   []float64
+	}{
+		{
+			name:   "trivial_case",
+			kLines: buildKLines([]float64{0}, []float64{1}),
+			want:   []float64{1.0},
+		},
+		{
+			name:   "easy_case",
+			kLines: buildKLines([]float64{3, 2, 1, 4}, []float64{3, 2, 2, 6}),
+			want:   []float64{3, 1, -1, 5},
+		},
+		{
+			name:   "price_comparison",
+			kLines: buildKLines([]float64{10, 11, 11, 9}, []float64{5, 1, 2, 1}),
+			want:   []float64{5, 6, 8, 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &types.StandardStream{}
+			obv := OBV(KLines(stream, "", ""))
+			for _, k := range tt.kLines {
+				stream.EmitKLineClosed(k)
+			}
+
+			if assert.Equal(t, len(tt.want), obv.Length()) {
+				for i, v := range tt.want {
+					assert.InDelta(t, v, obv.Slice[i], 1e-9)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PivotSupertrendStream is the Supertrend whose center line is the weighted average of the pivot points
+// instead of the mid price of the kline.
+type PivotSupertrendStream struct {
+	*types.Float64Series
+
+	// SupportLine is the support line in an uptrend (green)
+	SupportLine *types.Float64Series
+
+	// ResistanceLine is the resistance line in a downtrend (red)
+	ResistanceLine *types.Float64Series
+
+	PivotLow  *PivotLowStream
+	PivotHigh *PivotHighStream
+
+	atr        *ATRStream
+	multiplier float64
+
+	closePrice     float64
+	uptrendPrice   float64
+	downtrendPrice float64
+
+	lastPp                              float64
+	src                                 float64 // center
+	previousPivotHigh, previousPivotLow float64
+
+	trend       types.Direction
+	tradeSignal types.Direction
+}
+
+func PivotSupertrend(source KLineSubscription, window, pivotWindow int, atrMultiplier float64) *PivotSupertrendStream {
+	s := &PivotSupertrendStream{
+		Float64Series:  types.NewFloat64Series(),
+		SupportLine:    types.NewFloat64Series(),
+		ResistanceLine: types.NewFloat64Series(),
+		PivotLow:       PivotLow(LowPrices(source), pivotWindow),
+		PivotHigh:      PivotHigh(HighPrices(source), pivotWindow),
+		atr:            ATR2(source, window),
+		multiplier:     atrMultiplier,
+		trend:          types.DirectionUp,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.calculateAndPush(k.Close.Float64())
+	})
+	return s
+}
+
+func (s *PivotSupertrendStream) calculateAndPush(closePrice float64) {
+	// the pivot streams are updated before this handler, so the previous pivots are saved at the end of the last call
+	pivotLow, pivotHigh := s.PivotLow.Last(0), s.PivotHigh.Last(0)
+	previousPivotLow, previousPivotHigh := s.previousPivotLow, s.previousPivotHigh
+	s.previousPivotLow, s.previousPivotHigh = pivotLow, pivotHigh
+
+	previousUptrendPrice := s.uptrendPrice
+	previousDowntrendPrice := s.downtrendPrice
+	previousClosePrice := s.closePrice
+	previousTrend := s.trend
+
+	s.closePrice = closePrice
+
+	// initialize lastPp as soon as pivots are made
+	if s.lastPp == 0 || math.IsNaN(s.lastPp) {
+		if s.PivotHigh.Length() > 0 {
+			s.lastPp = pivotHigh
+		} else if s.PivotLow.Length() > 0 {
+			s.lastPp = pivotLow
+		} else {
+			s.lastPp = math.NaN()
+			return
+		}
+	}
+
+	// set lastPp to the latest pivot point, it's only changed when a new pivot is found
+	if pivotHigh != previousPivotHigh {
+		s.lastPp = pivotHigh
+	} else if pivotLow != previousPivotLow {
+		s.lastPp = pivotLow
+	}
+
+	if s.src == 0 || math.IsNaN(s.src) {
+		s.src = s.lastPp
+	} else {
+		s.src = (s.src*2 + s.lastPp) / 3
+	}
+
+	atr := s.atr.Last(0)
+
+	s.uptrendPrice = s.src - atr*s.multiplier
+	if previousClosePrice > previousUptrendPrice {
+		s.uptrendPrice = math.Max(s.uptrendPrice, previousUptrendPrice)
+	}
+
+	s.downtrendPrice = s.src + atr*s.multiplier
+	if previousClosePrice < previousDowntrendPrice {
+		s.downtrendPrice = math.Min(s.downtrendPrice, previousDowntrendPrice)
+	}
+
+	if previousTrend == types.DirectionUp && closePrice < previousUptrendPrice {
+		s.trend = types.DirectionDown
+	} else if previousTrend == types.DirectionDown && closePrice > previousDowntrendPrice {
+		s.trend = types.DirectionUp
+	}
+
+	if atr <= 0 {
+		s.tradeSignal = types.DirectionNone
+	} else if s.trend == types.DirectionUp && previousTrend == types.DirectionDown {
+		s.tradeSignal = types.DirectionUp
+	} else if s.trend == types.DirectionDown && previousTrend == types.DirectionUp {
+		s.tradeSignal = types.DirectionDown
+	} else {
+		s.tradeSignal = types.DirectionNone
+	}
+
+	s.SupportLine.PushAndEmit(s.uptrendPrice)
+	s.ResistanceLine.PushAndEmit(s.downtrendPrice)
+	s.SupportLine.Slice = generalTruncate(s.SupportLine.Slice)
+	s.ResistanceLine.Slice = generalTruncate(s.ResistanceLine.Slice)
+
+	if s.trend == types.DirectionDown {
+		s.PushAndEmit(s.downtrendPrice)
+	} else {
+		s.PushAndEmit(s.uptrendPrice)
+	}
+	s.Truncate()
+}
+
+// Signal returns the trade signal of the last kline, it's DirectionNone if the trend is not reversed
+func (s *PivotSupertrendStream) Signal() types.Direction {
+	return s.tradeSignal
+}
+
+// Direction returns the current trend
+func (s *PivotSupertrendStream) Direction() types.Direction {
+	return s.trend
+}
+
+func (s *PivotSupertrendStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
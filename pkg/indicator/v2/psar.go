@@ -0,0 +1,112 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PSARStream is the Parabolic SAR (Stop and Reverse)
+// - https://www.investopedia.com/terms/p/parabolicindicator.asp
+//
+// The dots are below the price when the price is rising, and above the price when the price is falling.
+type PSARStream struct {
+	*types.Float64Series
+
+	AF      float64 // Acceleration Factor
+	EP      float64 // Extreme Point
+	Falling bool
+
+	window    int
+	high, low *types.Queue
+}
+
+func PSAR(source KLineSubscription, window int) *PSARStream {
+	checkWindow(window)
+
+	s := &PSARStream{
+		Float64Series: types.NewFloat64Series(),
+		AF:            0.02,
+		window:        window,
+		high:          types.NewQueue(window),
+		low:           types.NewQueue(window),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.calculateAndPush(k.High.Float64(), k.Low.Float64())
+	})
+	return s
+}
+
+func (s *PSARStream) falling() bool {
+	up := s.high.Last(0) - s.high.Last(1)
+	dn := s.low.Last(1) - s.low.Last(0)
+	return (dn > up) && (dn > 0)
+}
+
+func (s *PSARStream) calculateAndPush(high, low float64) {
+	if s.high.Length() == 0 {
+		s.high.Update(high)
+		s.low.Update(low)
+		return
+	}
+
+	isFirst := s.high.Length() < s.window
+	s.high.Update(high)
+	s.low.Update(low)
+
+	if isFirst {
+		s.Falling = s.falling()
+		if s.Falling {
+			s.PushAndEmit(s.high.Last(1))
+			s.EP = s.low.Last(1)
+		} else {
+			s.PushAndEmit(s.low.Last(1))
+			s.EP = s.high.Last(1)
+		}
+		return
+	}
+
+	var sar float64
+	ppsar := s.Slice.Last(0)
+	if s.Falling {
+		psar := ppsar - s.AF*(ppsar-s.EP)
+		sar = math.Max(psar, types.Highest(types.Shift(s.high, 1), 2))
+		if low < s.EP {
+			s.EP = low
+			if s.AF <= 0.18 {
+				s.AF += 0.02
+			}
+		}
+
+		if high > psar { // reverse
+			s.AF = 0.02
+			sar = s.EP
+			s.EP = high
+			s.Falling = false
+		}
+	} else {
+		psar := ppsar + s.AF*(s.EP-ppsar)
+		sar = math.Min(psar, types.Lowest(types.Shift(s.low, 1), 2))
+		if high > s.EP {
+			s.EP = high
+			if s.AF <= 0.18 {
+				s.AF += 0.02
+			}
+		}
+
+		if low < psar { // reverse
+			s.AF = 0.02
+			sar = s.EP
+			s.EP = low
+			s.Falling = true
+		}
+	}
+
+	s.PushAndEmit(sar)
+	s.Truncate()
+}
+
+func (s *PSARStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,110 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SupertrendStream is the trend line of the Supertrend indicator, the values are the support line in an uptrend
+// and the resistance line in a downtrend.
+// - https://www.investopedia.com/supertrend-indicator-7976167
+type SupertrendStream struct {
+	*types.Float64Series
+
+	// SupportLine is the support line in an uptrend (green)
+	SupportLine *types.Float64Series
+
+	// ResistanceLine is the resistance line in a downtrend (red)
+	ResistanceLine *types.Float64Series
+
+	atr        *ATRStream
+	multiplier float64
+
+	closePrice     float64
+	uptrendPrice   float64
+	downtrendPrice float64
+
+	trend       types.Direction
+	tradeSignal types.Direction
+}
+
+func Supertrend(source KLineSubscription, window int, atrMultiplier float64) *SupertrendStream {
+	s := &SupertrendStream{
+		Float64Series:  types.NewFloat64Series(),
+		SupportLine:    types.NewFloat64Series(),
+		ResistanceLine: types.NewFloat64Series(),
+		atr:            ATR2(source, window),
+		multiplier:     atrMultiplier,
+		trend:          types.DirectionUp,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.calculateAndPush(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+	})
+	return s
+}
+
+func (s *SupertrendStream) calculateAndPush(high, low, closePrice float64) {
+	previousUptrendPrice := s.uptrendPrice
+	previousDowntrendPrice := s.downtrendPrice
+	previousClosePrice := s.closePrice
+	previousTrend := s.trend
+
+	s.closePrice = closePrice
+
+	src := (high + low) / 2
+	atr := s.atr.Last(0)
+
+	s.uptrendPrice = src - atr*s.multiplier
+	if previousClosePrice > previousUptrendPrice {
+		s.uptrendPrice = math.Max(s.uptrendPrice, previousUptrendPrice)
+	}
+
+	s.downtrendPrice = src + atr*s.multiplier
+	if previousClosePrice < previousDowntrendPrice {
+		s.downtrendPrice = math.Min(s.downtrendPrice, previousDowntrendPrice)
+	}
+
+	if previousTrend == types.DirectionUp && closePrice < previousUptrendPrice {
+		s.trend = types.DirectionDown
+	} else if previousTrend == types.DirectionDown && closePrice > previousDowntrendPrice {
+		s.trend = types.DirectionUp
+	}
+
+	if atr <= 0 {
+		s.tradeSignal = types.DirectionNone
+	} else if s.trend == types.DirectionUp && previousTrend == types.DirectionDown {
+		s.tradeSignal = types.DirectionUp
+	} else if s.trend == types.DirectionDown && previousTrend == types.DirectionUp {
+		s.tradeSignal = types.DirectionDown
+	} else {
+		s.tradeSignal = types.DirectionNone
+	}
+
+	s.SupportLine.PushAndEmit(s.uptrendPrice)
+	s.ResistanceLine.PushAndEmit(s.downtrendPrice)
+	s.SupportLine.Slice = generalTruncate(s.SupportLine.Slice)
+	s.ResistanceLine.Slice = generalTruncate(s.ResistanceLine.Slice)
+
+	if s.trend == types.DirectionDown {
+		s.PushAndEmit(s.downtrendPrice)
+	} else {
+		s.PushAndEmit(s.uptrendPrice)
+	}
+	s.Truncate()
+}
+
+// Signal returns the trade signal of the last kline, it's DirectionNone if the trend is not reversed
+func (s *SupertrendStream) Signal() types.Direction {
+	return s.tradeSignal
+}
+
+// Direction returns the current trend
+func (s *SupertrendStream) Direction() types.Direction {
+	return s.trend
+}
+
+func (s *SupertrendStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,38 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// TEMAStream is the Triple Exponential Moving Average
+// - https://investopedia.com/terms/t/triple-exponential-moving-average.asp
+//
+// TEMA = 3 * EMA1 - 3 * EMA2 + EMA3, where EMA2 = EMA(EMA1) and EMA3 = EMA(EMA2)
+type TEMAStream struct {
+	*types.Float64Series
+
+	A1, A2, A3 *EWMAStream
+}
+
+func TEMA(source types.Float64Source, window int) *TEMAStream {
+	a1 := EWMA2(source, window)
+	a2 := EWMA2(a1, window)
+	a3 := EWMA2(a2, window)
+
+	s := &TEMAStream{
+		Float64Series: types.NewFloat64Series(),
+		A1:            a1,
+		A2:            a2,
+		A3:            a3,
+	}
+	s.Bind(a3, s)
+	return s
+}
+
+func (s *TEMAStream) Calculate(a3 float64) float64 {
+	return 3*s.A1.Last(0) - 3*s.A2.Last(0) + a3
+}
+
+func (s *TEMAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
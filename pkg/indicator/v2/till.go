@@ -0,0 +1,57 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultTILLVolumeFactor = 0.7
+
+// TILLStream is the Tillson T3 Moving Average
+// - https://www.tradingpedia.com/forex-trading-indicator/t3-moving-average-indicator/
+//
+// T3 is the weighted sum of the six times chained EMAs, the weights are derived from the volume factor
+type TILLStream struct {
+	*types.Float64Series
+
+	e1, e2, e3, e4, e5, e6 *ewma
+	c1, c2, c3, c4         float64
+}
+
+// TILL creates the Tillson T3 stream, the volume factor is 0.7 if it's zero
+func TILL(source types.Float64Source, window int, volumeFactor float64) *TILLStream {
+	checkWindow(window)
+
+	if volumeFactor == 0 {
+		volumeFactor = defaultTILLVolumeFactor
+	}
+
+	square := volumeFactor * volumeFactor
+	cube := volumeFactor * square
+	s := &TILLStream{
+		Float64Series: types.NewFloat64Series(),
+		e1:            newEWMA(window),
+		e2:            newEWMA(window),
+		e3:            newEWMA(window),
+		e4:            newEWMA(window),
+		e5:            newEWMA(window),
+		e6:            newEWMA(window),
+		c1:            -cube,
+		c2:            3.*square + 3.*cube,
+		c3:            -6.*square - 3*volumeFactor - 3*cube,
+		c4:            1. + 3.*volumeFactor + cube + 3.*square,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *TILLStream) Calculate(value float64) float64 {
+	e3 := s.e3.update(s.e2.update(s.e1.update(value)))
+	e4 := s.e4.update(e3)
+	e5 := s.e5.update(e4)
+	e6 := s.e6.update(e5)
+	return s.c1*e6 + s.c2*e5 + s.c3*e4 + s.c4*e3
+}
+
+func (s *TILLStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
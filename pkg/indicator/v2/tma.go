@@ -0,0 +1,51 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// TMAStream is the Triangular Moving Average, the simple moving average of the simple moving average
+// with the window (window + 1) / 2.
+//
+// The outer average takes zero as the inner average until the inner window is full,
+// which is the same as the legacy indicator.
+type TMAStream struct {
+	*types.Float64Series
+
+	s1, s2 *types.Queue
+	window int
+}
+
+func TMA(source types.Float64Source, window int) *TMAStream {
+	checkWindow(window)
+
+	w := (window + 1) / 2
+	s := &TMAStream{
+		Float64Series: types.NewFloat64Series(),
+		s1:            types.NewQueue(w),
+		s2:            types.NewQueue(w),
+		window:        w,
+	}
+
+	s.Subscribe(source, func(v float64) {
+		s.s1.Update(v)
+
+		inner := 0.0
+		if s.s1.Length() >= s.window {
+			inner = types.Mean(s.s1)
+		}
+
+		s.s2.Update(inner)
+		if s.s2.Length() < s.window {
+			return
+		}
+
+		s.PushAndEmit(types.Mean(s.s2))
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *TMAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,65 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultTSIFastWindow = 13
+	defaultTSISlowWindow = 25
+)
+
+// TSIStream is the True Strength Index
+// - https://www.investopedia.com/terms/t/tsi.asp
+//
+// TSI = EMA(EMA(pc, slow), fast) / EMA(EMA(|pc|, slow), fast) * 100, where pc is the price change
+type TSIStream struct {
+	*types.Float64Series
+
+	pcs, pcds, apcs, apcds *ewma
+
+	prevValue float64
+	started   bool
+}
+
+// TSI creates the true strength index stream, the fast and the slow windows are 13 and 25 if they are zero
+func TSI(source types.Float64Source, fastWindow, slowWindow int) *TSIStream {
+	if fastWindow == 0 {
+		fastWindow = defaultTSIFastWindow
+	}
+
+	if slowWindow == 0 {
+		slowWindow = defaultTSISlowWindow
+	}
+
+	s := &TSIStream{
+		Float64Series: types.NewFloat64Series(),
+		pcs:           newEWMA(slowWindow),
+		pcds:          newEWMA(fastWindow),
+		apcs:          newEWMA(slowWindow),
+		apcds:         newEWMA(fastWindow),
+	}
+
+	s.Subscribe(source, func(v float64) {
+		if !s.started {
+			s.started = true
+			s.prevValue = v
+			return
+		}
+
+		pc := v - s.prevValue
+		s.prevValue = v
+
+		pcd := s.pcds.update(s.pcs.update(pc))
+		apcd := s.apcds.update(s.apcs.update(math.Abs(pc)))
+		s.PushAndEmit(pcd / apcd * 100.)
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *TSIStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,76 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// UTBotAlertStream is based on "UT Bot Alerts by QuantNomad" from tradingview,
+// the values are the signal directions: 1 for buy, -1 for sell and 0 for none.
+type UTBotAlertStream struct {
+	*types.Float64Series
+
+	atr      *ATRStream
+	keyValue float64 // the ATR multiplier
+
+	xATRTrailingStop   floats.Slice
+	previousClosePrice float64
+}
+
+func UTBotAlert(source KLineSubscription, window int, keyValue float64) *UTBotAlertStream {
+	s := &UTBotAlertStream{
+		Float64Series: types.NewFloat64Series(),
+		atr:           ATR2(source, window),
+		keyValue:      keyValue,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.calculateAndPush(k.Close.Float64())
+	})
+	return s
+}
+
+func (s *UTBotAlertStream) calculateAndPush(closePrice float64) {
+	nLoss := s.atr.Last(0) * s.keyValue
+
+	// NOTE: Last(1) is compared before the new trailing stop is pushed, which is the same as the legacy indicator
+	stop := s.xATRTrailingStop.Last(1)
+	if s.xATRTrailingStop.Length() == 0 {
+		s.xATRTrailingStop.Push(0)
+	} else if closePrice > stop && s.previousClosePrice > stop {
+		s.xATRTrailingStop.Push(math.Max(stop, closePrice-nLoss))
+	} else if closePrice < stop && s.previousClosePrice < stop {
+		s.xATRTrailingStop.Push(math.Min(stop, closePrice+nLoss))
+	} else if closePrice > stop {
+		s.xATRTrailingStop.Push(closePrice - nLoss)
+	} else {
+		s.xATRTrailingStop.Push(closePrice + nLoss)
+	}
+	s.xATRTrailingStop = generalTruncate(s.xATRTrailingStop)
+
+	last, prev := s.xATRTrailingStop.Last(0), s.xATRTrailingStop.Last(1)
+	above := closePrice > last && s.previousClosePrice < prev
+	below := closePrice < last && s.previousClosePrice > prev
+
+	s.previousClosePrice = closePrice
+
+	if closePrice > last && above {
+		s.PushAndEmit(float64(types.DirectionUp))
+	} else if closePrice < last && below {
+		s.PushAndEmit(float64(types.DirectionDown))
+	} else {
+		s.PushAndEmit(float64(types.DirectionNone))
+	}
+	s.Truncate()
+}
+
+// Signal returns the last signal direction
+func (s *UTBotAlertStream) Signal() types.Direction {
+	return types.Direction(s.Slice.Last(0))
+}
+
+func (s *UTBotAlertStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
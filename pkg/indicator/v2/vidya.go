@@ -0,0 +1,46 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// VIDYAStream is the Variable Index Dynamic Average, an EMA whose smoothing factor is
+// adjusted by the Chande Momentum Oscillator of the input
+// - https://metatrader5.com/en/terminal/help/indicators/trend_indicators/vida
+type VIDYAStream struct {
+	*types.Float64Series
+
+	window int
+	input  floats.Slice
+}
+
+func VIDYA(source types.Float64Source, window int) *VIDYAStream {
+	checkWindow(window)
+
+	s := &VIDYAStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *VIDYAStream) Calculate(value float64) float64 {
+	s.input.Push(value)
+	s.input = generalTruncate(s.input)
+	if s.Slice.Length() == 0 {
+		return value
+	}
+
+	change := types.Change(&s.input)
+	cmo := math.Abs(types.Sum(change, s.window) / types.Sum(types.Abs(change), s.window))
+	alpha := 2. / float64(s.window+1)
+	return value*alpha*cmo + s.Slice.Last(0)*(1.-alpha*cmo)
+}
+
+func (s *VIDYAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
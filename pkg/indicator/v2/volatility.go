@@ -0,0 +1,47 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// VolatilityStream is the population standard deviation of the values in the window,
+// the value is pushed once the window is full.
+type VolatilityStream struct {
+	*types.Float64Series
+
+	window int
+	values *types.Queue
+}
+
+func Volatility(source types.Float64Source, window int) *VolatilityStream {
+	checkWindow(window)
+
+	s := &VolatilityStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		values:        types.NewQueue(window),
+	}
+
+	s.Subscribe(source, func(v float64) {
+		s.values.Update(v)
+		if s.values.Length() < s.window {
+			return
+		}
+
+		avg := types.Mean(s.values)
+		sv := 0.0 // sum of variance
+		for i := 0; i < s.window; i++ {
+			sv += math.Pow(s.values.Last(i)-avg, 2)
+		}
+
+		s.PushAndEmit(math.Sqrt(sv / float64(s.window)))
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *VolatilityStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,135 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type volumeProfileEntry struct {
+	level  float64
+	volume float64
+}
+
+// VolumeProfileStream is the distribution of the volume at the price levels of the klines in the window,
+// the typical price of each kline is rounded to the price level of the delta.
+// The point of control (POC), the price level with the most volume in the window, is pushed.
+type VolumeProfileStream struct {
+	*types.Float64Series
+
+	window  int
+	delta   float64
+	profile map[float64]float64
+	entries []volumeProfileEntry
+
+	minLevel, maxLevel float64
+}
+
+func VolumeProfile(source KLineSubscription, window int, delta float64) *VolumeProfileStream {
+	checkWindow(window)
+
+	if delta <= 0 {
+		panic("the price delta of the volume profile must be positive")
+	}
+
+	s := &VolumeProfileStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		delta:         delta,
+		profile:       make(map[float64]float64),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.Update(types.KLineTypicalPriceMapper(k), k.Volume.Float64())
+
+		price, _ := s.PointOfControl()
+		s.PushAndEmit(price)
+		s.Truncate()
+	})
+	return s
+}
+
+// Update adds the volume at the price, and removes the volume that slides out of the window
+func (s *VolumeProfileStream) Update(price, volume float64) {
+	level := math.Round(price / s.delta)
+	s.profile[level] += volume
+	s.entries = append(s.entries, volumeProfileEntry{level: level, volume: volume})
+
+	if n := len(s.entries) - s.window; n > 0 {
+		for _, entry := range s.entries[:n] {
+			s.profile[entry.level] -= entry.volume
+			if s.profile[entry.level] == 0 {
+				delete(s.profile, entry.level)
+			}
+		}
+
+		s.entries = s.entries[n:]
+	}
+
+	s.minLevel, s.maxLevel = math.Inf(1), math.Inf(-1)
+	for _, entry := range s.entries {
+		s.minLevel = math.Min(s.minLevel, entry.level)
+		s.maxLevel = math.Max(s.maxLevel, entry.level)
+	}
+}
+
+// PointOfControl returns the price level with the most volume in the window
+func (s *VolumeProfileStream) PointOfControl() (price, volume float64) {
+	if len(s.entries) == 0 {
+		return 0, 0
+	}
+
+	return s.PointOfControlAboveEqual(s.minLevel * s.delta)
+}
+
+// PointOfControlAboveEqual returns the price level with the most volume between the price and the limit,
+// which is the highest price level of the window by default. It can be used as the resistance level.
+func (s *VolumeProfileStream) PointOfControlAboveEqual(price float64, limit ...float64) (resultPrice, volume float64) {
+	to := s.maxLevel
+	if len(limit) > 0 {
+		to = math.Round(limit[0] / s.delta)
+	}
+
+	from := math.Round(price / s.delta)
+	if from > to {
+		return 0, 0
+	}
+
+	volume = math.Inf(-1)
+	for level := from; level <= to; level++ {
+		if v := math.Abs(s.profile[level]); v > volume {
+			volume = v
+			resultPrice = level * s.delta
+		}
+	}
+
+	return resultPrice, volume
+}
+
+// PointOfControlBelowEqual returns the price level with the most volume between the limit and the price,
+// the limit is the lowest price level of the window by default. It can be used as the support level.
+func (s *VolumeProfileStream) PointOfControlBelowEqual(price float64, limit ...float64) (resultPrice, volume float64) {
+	to := s.minLevel
+	if len(limit) > 0 {
+		to = math.Round(limit[0] / s.delta)
+	}
+
+	from := math.Round(price / s.delta)
+	if from < to {
+		return 0, 0
+	}
+
+	volume = math.Inf(-1)
+	for level := from; level >= to; level-- {
+		if v := math.Abs(s.profile[level]); v > volume {
+			volume = v
+			resultPrice = level * s.delta
+		}
+	}
+
+	return resultPrice, volume
+}
+
+func (s *VolumeProfileStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,50 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestVolumeProfile(t *testing.T) {
+	stream := &types.StandardStream{}
+	vp := VolumeProfile(KLines(stream, "", ""), 3, 0.5)
+
+	push := func(price, volume float64) {
+		stream.EmitKLineClosed(types.KLine{
+			High:   fixedpoint.NewFromFloat(price),
+			Low:    fixedpoint.NewFromFloat(price),
+			Close:  fixedpoint.NewFromFloat(price),
+			Volume: fixedpoint.NewFromFloat(volume),
+		})
+	}
+
+	push(10.1, 100)
+	push(10.9, 50)
+	push(11.0, 30)
+	assert.Equal(t, 10.0, vp.Last(0))
+
+	price, volume := vp.PointOfControlAboveEqual(10.6)
+	assert.Equal(t, 11.0, price)
+	assert.Equal(t, 80.0, volume)
+
+	price, volume = vp.PointOfControlBelowEqual(10.4)
+	assert.Equal(t, 10.0, price)
+	assert.Equal(t, 100.0, volume)
+
+	// the first kline slides out of the window
+	push(12.0, 10)
+	assert.Equal(t, 11.0, vp.Last(0))
+
+	// the price below the lowest price level of the window
+	price, volume = vp.PointOfControlBelowEqual(10.4)
+	assert.Equal(t, 0.0, price)
+	assert.Equal(t, 0.0, volume)
+
+	price, volume = vp.PointOfControlBelowEqual(11.9)
+	assert.Equal(t, 11.0, price)
+	assert.Equal(t, 80.0, volume)
+}
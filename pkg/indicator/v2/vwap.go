@@ -0,0 +1,59 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// VWAPStream is the Volume Weighted Average Price of the kline typical prices
+// - https://www.investopedia.com/terms/v/vwap.asp
+//
+// The window 0 means the cumulative VWAP since the first kline.
+type VWAPStream struct {
+	*types.Float64Series
+
+	window      int
+	prices      *types.Queue
+	volumes     *types.Queue
+	weightedSum float64
+	volumeSum   float64
+}
+
+func VWAP(source KLineSubscription, window int) *VWAPStream {
+	s := &VWAPStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+	}
+
+	if window > 0 {
+		s.prices = types.NewQueue(window + 1)
+		s.volumes = types.NewQueue(window + 1)
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.calculateAndPush(types.KLineTypicalPriceMapper(k), k.Volume.Float64())
+	})
+	return s
+}
+
+func (s *VWAPStream) calculateAndPush(price, volume float64) {
+	if s.window > 0 {
+		s.prices.Update(price)
+		s.volumes.Update(volume)
+
+		// remove the value that slides out of the window
+		if s.prices.Length() > s.window {
+			s.weightedSum -= s.prices.Last(s.window) * s.volumes.Last(s.window)
+			s.volumeSum -= s.volumes.Last(s.window)
+		}
+	}
+
+	s.weightedSum += price * volume
+	s.volumeSum += volume
+
+	s.PushAndEmit(s.weightedSum / s.volumeSum)
+	s.Truncate()
+}
+
+func (s *VWAPStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,45 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// VWMAStream is the Volume Weighted Moving Average
+// - https://www.motivewave.com/studies/volume_weighted_moving_average.htm
+//
+// VWMA = SMA(price * volume) / SMA(volume), the value is pushed once the window is full
+type VWMAStream struct {
+	*types.Float64Series
+
+	window       int
+	priceVolumes *types.Queue
+	volumes      *types.Queue
+}
+
+func VWMA(source KLineSubscription, window int) *VWMAStream {
+	checkWindow(window)
+
+	s := &VWMAStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		priceVolumes:  types.NewQueue(window),
+		volumes:       types.NewQueue(window),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		price, volume := k.Close.Float64(), k.Volume.Float64()
+		s.priceVolumes.Update(price * volume)
+		s.volumes.Update(volume)
+		if s.volumes.Length() < s.window {
+			return
+		}
+
+		s.PushAndEmit(types.Mean(s.priceVolumes) / types.Mean(s.volumes))
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *VWMAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
@@ -0,0 +1,74 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// WeightedDriftStream is the drift of the log returns weighted by the kline volumes,
+// the log return of a kline with a larger volume is counted multiple times.
+type WeightedDriftStream struct {
+	*types.Float64Series
+
+	window    int
+	chng      *types.Queue
+	weight    *types.Queue
+	lastValue float64
+	started   bool
+}
+
+func WeightedDrift(source KLineSubscription, window int) *WeightedDriftStream {
+	checkWindow(window)
+
+	s := &WeightedDriftStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		chng:          types.NewQueue(window),
+		weight:        types.NewQueue(window),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.calculateAndPush(k.Close.Float64(), k.Volume.Abs().Float64())
+	})
+	return s
+}
+
+func (s *WeightedDriftStream) calculateAndPush(value, weight float64) {
+	if weight == 0 {
+		s.lastValue = value
+		return
+	}
+
+	s.weight.Update(weight)
+	if !s.started {
+		s.started = true
+		s.lastValue = value
+		return
+	}
+
+	base := s.weight.Lowest(s.window)
+	multiplier := int(weight / base)
+
+	var chng float64
+	if value != 0 {
+		chng = math.Log(value/s.lastValue) / weight * base
+		s.lastValue = value
+	}
+
+	for i := 0; i < multiplier; i++ {
+		s.chng.Update(chng)
+	}
+
+	if s.chng.Length() < s.window {
+		return
+	}
+
+	stdev := types.Stdev(s.chng, s.window)
+	s.PushAndEmit(types.Mean(s.chng) - stdev*stdev*0.5)
+	s.Truncate()
+}
+
+func (s *WeightedDriftStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
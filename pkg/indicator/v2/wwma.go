@@ -0,0 +1,36 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// WWMAStream is the Welles Wilder's Moving Average, the EMA with the smoothing factor 1 / window
+type WWMAStream struct {
+	*types.Float64Series
+
+	window int
+}
+
+func WWMA(source types.Float64Source, window int) *WWMAStream {
+	checkWindow(window)
+
+	s := &WWMAStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *WWMAStream) Calculate(value float64) float64 {
+	if s.Slice.Length() == 0 {
+		return value
+	}
+
+	last := s.Slice.Last(0)
+	return last + (value-last)/float64(s.window)
+}
+
+func (s *WWMAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
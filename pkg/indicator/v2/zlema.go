@@ -0,0 +1,44 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ZLEMAStream is the Zero Lag Exponential Moving Average
+// - https://en.wikipedia.org/wiki/Zero_lag_exponential_moving_average
+//
+// ZLEMA = EMA(2 * price - price[lag]), where lag = (window - 1) / 2
+type ZLEMAStream struct {
+	*types.Float64Series
+
+	data floats.Slice
+	lag  int
+	ema  *ewma
+}
+
+func ZLEMA(source types.Float64Source, window int) *ZLEMAStream {
+	checkWindow(window)
+
+	s := &ZLEMAStream{
+		Float64Series: types.NewFloat64Series(),
+		lag:           int((float64(window)-1.)/2. + 0.5),
+		ema:           newEWMA(window),
+	}
+
+	s.Subscribe(source, func(v float64) {
+		s.data.Push(v)
+		s.data = generalTruncate(s.data)
+		if s.lag >= s.data.Length() {
+			return
+		}
+
+		s.PushAndEmit(s.ema.update(2.*v - s.data.Last(s.lag)))
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *ZLEMAStream) Truncate() {
+	s.Slice = generalTruncate(s.Slice)
+}
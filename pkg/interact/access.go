@@ -0,0 +1,347 @@
+package interact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+func (r Role) level() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleAdmin:
+		return 3
+	}
+
+	return 0
+}
+
+// Allows returns true if the role has the permission of the required role
+func (r Role) Allows(required Role) bool {
+	return r.level() > 0 && r.level() >= required.level()
+}
+
+func (r Role) Validate() error {
+	if r.level() == 0 {
+		return fmt.Errorf("invalid role %q, valid roles are viewer, operator and admin", r)
+	}
+
+	return nil
+}
+
+// DefaultCommandRoles are the required roles of the built-in private commands,
+// the private commands that are not defined here require the operator role.
+var DefaultCommandRoles = map[string]Role{
+	"/sessions":       RoleViewer,
+	"/balances":       RoleViewer,
+	"/position":       RoleViewer,
+	"/status":         RoleViewer,
+	"/orders":         RoleViewer,
+	"/closeposition":  RoleOperator,
+	"/suspend":        RoleOperator,
+	"/resume":         RoleOperator,
+	"/buy":            RoleOperator,
+	"/sell":           RoleOperator,
+	"/cancel":         RoleOperator,
+	"/resetposition":  RoleAdmin,
+	"/modifyposition": RoleAdmin,
+	"/modify":         RoleAdmin,
+	"/emergencystop":  RoleAdmin,
+}
+
+// Identity is the messenger user who sends the messages
+type Identity struct {
+	Messenger string `json:"messenger"`
+	UserID    string `json:"userId"`
+	Username  string `json:"username,omitempty"`
+}
+
+// IdentifiedSession is implemented by the sessions that know the messenger user
+type IdentifiedSession interface {
+	Identity() Identity
+}
+
+// User maps the messenger accounts to a role
+type User struct {
+	Name string `json:"name" yaml:"name"`
+	Role Role   `json:"role" yaml:"role"`
+
+	// Telegram, Slack and Discord are the user IDs or the usernames of the messenger accounts
+	Telegram string `json:"telegram,omitempty" yaml:"telegram,omitempty"`
+	Slack    string `json:"slack,omitempty" yaml:"slack,omitempty"`
+	Discord  string `json:"discord,omitempty" yaml:"discord,omitempty"`
+
+	// Strategies are the regular expressions of the strategy signatures that the user can access,
+	// if ignored, all strategies can be accessed
+	Strategies []string `json:"strategies,omitempty" yaml:"strategies,omitempty"`
+
+	strategyPatterns []*regexp.Regexp
+}
+
+func (u *User) account(messenger string) string {
+	switch messenger {
+	case "telegram":
+		return u.Telegram
+	case "slack":
+		return u.Slack
+	case "discord":
+		return u.Discord
+	}
+
+	return ""
+}
+
+func (u *User) matches(identity Identity) bool {
+	account := strings.TrimPrefix(u.account(identity.Messenger), "@")
+	if len(account) == 0 {
+		return false
+	}
+
+	return account == identity.UserID || (len(identity.Username) > 0 && account == identity.Username)
+}
+
+// CanAccessStrategy returns true if the strategy signature matches one of the strategy patterns of the user
+func (u *User) CanAccessStrategy(signature string) bool {
+	if len(u.strategyPatterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range u.strategyPatterns {
+		if pattern.MatchString(signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsScoped returns true if the user can only access the strategies that match the strategy patterns
+func (u *User) IsScoped() bool {
+	return len(u.strategyPatterns) > 0
+}
+
+// AuditRecord is the record of a command or a command response sent by a user
+type AuditRecord struct {
+	Time     time.Time `json:"time"`
+	Identity Identity  `json:"identity"`
+	User     string    `json:"user,omitempty"`
+	Role     Role      `json:"role,omitempty"`
+
+	// Command is the command name, State is the command step, and Text is the command arguments or the step response
+	Command string `json:"command,omitempty"`
+	State   State  `json:"state,omitempty"`
+	Text    string `json:"text,omitempty"`
+
+	// Allowed is false if the command is denied by the role, Error is the error returned by the command
+	Allowed bool   `json:"allowed"`
+	Error   string `json:"error,omitempty"`
+}
+
+type AuditLogger interface {
+	Log(record AuditRecord)
+}
+
+// LogAuditLogger writes the audit records to the logger
+type LogAuditLogger struct{}
+
+func (l *LogAuditLogger) Log(r AuditRecord) {
+	log.WithFields(log.Fields{
+		"messenger": r.Identity.Messenger,
+		"userId":    r.Identity.UserID,
+		"username":  r.Identity.Username,
+		"user":      r.User,
+		"role":      r.Role,
+		"command":   r.Command,
+		"state":     r.State,
+		"allowed":   r.Allowed,
+	}).Infof("[interact] audit: %q %s", r.Text, r.Error)
+}
+
+// FileAuditLogger appends the audit records to the file in the JSON lines format
+type FileAuditLogger struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileAuditLogger(filePath string) (*FileAuditLogger, error) {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileAuditLogger{file: f}, nil
+}
+
+func (l *FileAuditLogger) Log(r AuditRecord) {
+	out, err := json.Marshal(r)
+	if err != nil {
+		log.WithError(err).Errorf("[interact] audit record marshal error")
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(out, '\n')); err != nil {
+		log.WithError(err).Errorf("[interact] audit record write error")
+	}
+}
+
+// AccessControl authorizes the private commands by the roles of the users.
+// The users need to be authorized by the auth command first, and then the user role is checked.
+type AccessControl struct {
+	Users []User
+
+	// CommandRoles overrides the required roles of the commands
+	CommandRoles map[string]Role
+
+	AuditLoggers []AuditLogger
+}
+
+func NewAccessControl(users []User, commandRoles map[string]Role) (*AccessControl, error) {
+	ac := &AccessControl{
+		CommandRoles: make(map[string]Role),
+	}
+
+	for command, role := range commandRoles {
+		if err := role.Validate(); err != nil {
+			return nil, fmt.Errorf("command %s: %w", command, err)
+		}
+
+		if !strings.HasPrefix(command, "/") {
+			command = "/" + command
+		}
+
+		ac.CommandRoles[strings.ToLower(command)] = role
+	}
+
+	for _, user := range users {
+		if err := user.Role.Validate(); err != nil {
+			return nil, fmt.Errorf("user %s: %w", user.Name, err)
+		}
+
+		for _, pattern := range user.Strategies {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("user %s: invalid strategy pattern %q: %w", user.Name, pattern, err)
+			}
+
+			user.strategyPatterns = append(user.strategyPatterns, re)
+		}
+
+		ac.Users = append(ac.Users, user)
+	}
+
+	return ac, nil
+}
+
+// FindUser returns the user of the session, the session must implement IdentifiedSession
+func (ac *AccessControl) FindUser(session Session) (*User, bool) {
+	is, ok := session.(IdentifiedSession)
+	if !ok {
+		return nil, false
+	}
+
+	identity := is.Identity()
+	for i := range ac.Users {
+		if ac.Users[i].matches(identity) {
+			return &ac.Users[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// RequiredRole returns the required role of the command
+func (ac *AccessControl) RequiredRole(command string) Role {
+	command = strings.ToLower(command)
+	if role, ok := ac.CommandRoles[command]; ok {
+		return role
+	}
+
+	if role, ok := DefaultCommandRoles[command]; ok {
+		return role
+	}
+
+	return RoleOperator
+}
+
+// CheckCommand checks if the user of the session has the required role of the command
+func (ac *AccessControl) CheckCommand(session Session, command string) error {
+	user, ok := ac.FindUser(session)
+	if !ok {
+		return fmt.Errorf("permission denied, you are not assigned to any role")
+	}
+
+	required := ac.RequiredRole(command)
+	if !user.Role.Allows(required) {
+		return fmt.Errorf("permission denied, command %s requires the %s role", command, required)
+	}
+
+	return nil
+}
+
+// CanAccessStrategy checks if the user of the session can access the strategy
+func (ac *AccessControl) CanAccessStrategy(session Session, signature string) bool {
+	user, ok := ac.FindUser(session)
+	if !ok {
+		return false
+	}
+
+	return user.CanAccessStrategy(signature)
+}
+
+// IsStrategyScoped returns true if the user of the session can not access all the strategies,
+// the users that are not in the list are treated as scoped since they can not access any strategy
+func (ac *AccessControl) IsStrategyScoped(session Session) bool {
+	user, ok := ac.FindUser(session)
+	if !ok {
+		return true
+	}
+
+	return user.IsScoped()
+}
+
+func (ac *AccessControl) audit(session Session, command string, state State, text string, allowed bool, err error) {
+	record := AuditRecord{
+		Time:    time.Now(),
+		Command: command,
+		State:   state,
+		Text:    text,
+		Allowed: allowed,
+	}
+
+	if is, ok := session.(IdentifiedSession); ok {
+		record.Identity = is.Identity()
+	}
+
+	if user, ok := ac.FindUser(session); ok {
+		record.User = user.Name
+		record.Role = user.Role
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	for _, logger := range ac.AuditLoggers {
+		logger.Log(record)
+	}
+}
@@ -0,0 +1,159 @@
+package interact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+type memoryAuditLogger struct {
+	records []AuditRecord
+}
+
+func (l *memoryAuditLogger) Log(record AuditRecord) {
+	l.records = append(l.records, record)
+}
+
+func TestRole_Allows(t *testing.T) {
+	assert.True(t, RoleAdmin.Allows(RoleOperator))
+	assert.True(t, RoleOperator.Allows(RoleOperator))
+	assert.False(t, RoleViewer.Allows(RoleOperator))
+	assert.False(t, Role("guest").Allows(RoleViewer))
+	assert.Error(t, Role("guest").Validate())
+}
+
+func TestNewAccessControl(t *testing.T) {
+	ac, err := NewAccessControl([]User{
+		{Name: "alice", Role: RoleAdmin, Telegram: "@alice"},
+		{Name: "bob", Role: RoleOperator, Slack: "U0123", Strategies: []string{`^binance\.grid`}},
+	}, map[string]Role{
+		"suspend": RoleAdmin,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, RoleAdmin, ac.RequiredRole("/suspend"))
+	assert.Equal(t, RoleAdmin, ac.RequiredRole("/emergencystop"))
+	assert.Equal(t, RoleViewer, ac.RequiredRole("/balances"))
+	assert.Equal(t, RoleOperator, ac.RequiredRole("/custom"))
+
+	alice := &TelegramSession{User: &tb.User{ID: 1, Username: "alice"}, Chat: &tb.Chat{ID: 1}}
+	bob := &SlackSession{UserID: "U0123", ChannelID: "C1"}
+	unknown := &SlackSession{UserID: "U9999", ChannelID: "C1"}
+
+	assert.NoError(t, ac.CheckCommand(alice, "/emergencystop"))
+	assert.Error(t, ac.CheckCommand(bob, "/emergencystop"))
+	assert.Error(t, ac.CheckCommand(bob, "/suspend"))
+	assert.NoError(t, ac.CheckCommand(bob, "/closeposition"))
+	assert.Error(t, ac.CheckCommand(unknown, "/balances"))
+
+	assert.True(t, ac.CanAccessStrategy(alice, "max.xmaker:BTCUSDT"))
+	assert.True(t, ac.CanAccessStrategy(bob, "binance.grid:BTCUSDT"))
+	assert.False(t, ac.CanAccessStrategy(bob, "max.xmaker:BTCUSDT"))
+	assert.False(t, ac.CanAccessStrategy(unknown, "binance.grid:BTCUSDT"))
+
+	assert.False(t, ac.IsStrategyScoped(alice))
+	assert.True(t, ac.IsStrategyScoped(bob))
+	assert.True(t, ac.IsStrategyScoped(unknown))
+
+	_, err = NewAccessControl([]User{{Name: "carol", Role: "root"}}, nil)
+	assert.Error(t, err)
+
+	_, err = NewAccessControl([]User{{Name: "carol", Role: RoleViewer, Strategies: []string{"("}}}, nil)
+	assert.Error(t, err)
+}
+
+func TestAccessControl_runCommand(t *testing.T) {
+	b, err := tb.NewBot(tb.Settings{
+		Offline: true,
+	})
+	if !assert.NoError(t, err, "should have bot setup without error") {
+		return
+	}
+
+	it := New()
+	telegram := &Telegram{
+		Bot: b,
+	}
+	it.AddMessenger(telegram)
+
+	var stopped bool
+	it.PrivateCommand("/emergencystop", "", func(reply Reply) error {
+		return nil
+	}).Next(func(signature string) error {
+		stopped = true
+		return nil
+	})
+
+	assert.NoError(t, it.init())
+
+	ac, err := NewAccessControl([]User{
+		{Name: "alice", Role: RoleAdmin, Telegram: "1"},
+		{Name: "bob", Role: RoleOperator, Telegram: "2"},
+	}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	auditLogger := &memoryAuditLogger{}
+	ac.AuditLoggers = append(ac.AuditLoggers, auditLogger)
+	it.SetAccessControl(ac)
+
+	bob := telegram.loadSession(&tb.Message{Chat: &tb.Chat{ID: 22}, Sender: &tb.User{ID: 2}})
+	bob.SetAuthorized()
+	err = it.runCommand(bob, "/emergencystop", []string{}, telegram.newReply(bob))
+	assert.ErrorContains(t, err, "requires the admin role")
+
+	alice := telegram.loadSession(&tb.Message{Chat: &tb.Chat{ID: 22}, Sender: &tb.User{ID: 1}})
+	alice.SetAuthorized()
+	err = it.runCommand(alice, "/emergencystop", []string{}, telegram.newReply(alice))
+	assert.NoError(t, err)
+
+	// bob can not respond to the command of alice in the same group chat, bob has his own session
+	bob = telegram.loadSession(&tb.Message{Chat: &tb.Chat{ID: 22}, Sender: &tb.User{ID: 2}})
+	assert.Equal(t, StatePublic, bob.GetState())
+	_ = it.handleResponse(bob, "binance.grid", telegram.newReply(bob))
+	assert.False(t, stopped)
+
+	alice = telegram.loadSession(&tb.Message{Chat: &tb.Chat{ID: 22}, Sender: &tb.User{ID: 1}})
+	err = it.handleResponse(alice, "binance.grid", telegram.newReply(alice))
+	assert.NoError(t, err)
+	assert.True(t, stopped)
+
+	if assert.Len(t, auditLogger.records, 3) {
+		assert.Equal(t, "bob", auditLogger.records[0].User)
+		assert.False(t, auditLogger.records[0].Allowed)
+
+		assert.Equal(t, "alice", auditLogger.records[1].User)
+		assert.Equal(t, "/emergencystop", auditLogger.records[1].Command)
+		assert.True(t, auditLogger.records[1].Allowed)
+
+		assert.Equal(t, "/emergencystop", auditLogger.records[2].Command)
+		assert.Equal(t, "binance.grid", auditLogger.records[2].Text)
+		assert.Equal(t, "1", auditLogger.records[2].Identity.UserID)
+	}
+}
+
+func TestTelegram_loadSession(t *testing.T) {
+	tm := &Telegram{}
+	group := &tb.Chat{ID: -100}
+	alice := &tb.User{ID: 1, Username: "alice"}
+	mallory := &tb.User{ID: 2, Username: "mallory"}
+
+	aliceSession := tm.loadSession(&tb.Message{Chat: group, Sender: alice})
+	aliceSession.Authorized = true
+
+	// the other users in the same group chat must not share the authorized session
+	mallorySession := tm.loadSession(&tb.Message{Chat: group, Sender: mallory})
+	assert.NotSame(t, aliceSession, mallorySession)
+	assert.False(t, mallorySession.IsAuthorized())
+	assert.Equal(t, "alice", aliceSession.Identity().Username)
+	assert.Same(t, aliceSession, tm.loadSession(&tb.Message{Chat: group, Sender: alice}))
+
+	// the sessions stored by the chat ID are re-keyed when they are restored
+	restored := &Telegram{}
+	restored.RestoreSessions(TelegramSessionMap{"-100": &TelegramSession{User: mallory, Chat: group}})
+	assert.Equal(t, "mallory", restored.loadSession(&tb.Message{Chat: group, Sender: mallory}).User.Username)
+}
@@ -16,6 +16,10 @@ func AddCustomInteraction(custom CustomInteraction) {
 	defaultInteraction.AddCustomInteraction(custom)
 }
 
+func SetAccessControl(ac *AccessControl) {
+	defaultInteraction.SetAccessControl(ac)
+}
+
 func Start(ctx context.Context) error {
 	return defaultInteraction.Start(ctx)
 }
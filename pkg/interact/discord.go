@@ -0,0 +1,366 @@
+package interact
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/discord/discordapi"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+func init() {
+	// force interface type check
+	_ = Reply(&DiscordReply{})
+}
+
+const maxDiscordMessageSize int = 2000
+
+// maxDiscordButtonsPerRow is the max number of the buttons in an action row
+const maxDiscordButtonsPerRow = 5
+
+// maxDiscordActionRows is the max number of the action rows in a message
+const maxDiscordActionRows = 5
+
+type DiscordSessionMap map[string]*DiscordSession
+
+type DiscordSession struct {
+	BaseSession
+
+	discord *Discord
+
+	User      *discordapi.User `json:"user"`
+	ChannelID string           `json:"channelId"`
+}
+
+func NewDiscordSession(discord *Discord, user *discordapi.User, channelID string) *DiscordSession {
+	return &DiscordSession{
+		BaseSession: BaseSession{
+			OriginState:  StatePublic,
+			CurrentState: StatePublic,
+			Authorized:   false,
+			authorizing:  false,
+
+			StartedTime: time.Now(),
+		},
+		discord:   discord,
+		User:      user,
+		ChannelID: channelID,
+	}
+}
+
+func (s *DiscordSession) ID() string {
+	return fmt.Sprintf("discord-%s-%s", s.User.ID, s.ChannelID)
+}
+
+func (s *DiscordSession) Identity() Identity {
+	return Identity{Messenger: "discord", UserID: s.User.ID, Username: s.User.Username}
+}
+
+func (s *DiscordSession) SetAuthorized() {
+	s.BaseSession.SetAuthorized()
+	s.discord.EmitAuthorized(s)
+}
+
+type DiscordReply struct {
+	client  *discordapi.Client
+	session *DiscordSession
+
+	message string
+	buttons []Button
+	set     bool
+}
+
+func (r *DiscordReply) Send(message string) {
+	ctx := context.Background()
+	for _, split := range util.StringSplitByLength(message, maxDiscordMessageSize) {
+		if err := sendLimiter.Wait(ctx); err != nil {
+			log.WithError(err).Errorf("discord send limit exceeded")
+			return
+		}
+
+		if _, err := r.client.CreateMessage(ctx, r.session.ChannelID, discordapi.MessageCreate{Content: split}); err != nil {
+			log.WithError(err).Errorf("[discord] message send error")
+		}
+	}
+}
+
+func (r *DiscordReply) Message(message string) {
+	r.message = message
+	r.set = true
+}
+
+// RemoveKeyboard removes the buttons of the reply, discord buttons are attached to the message,
+// so we only need to skip them
+func (r *DiscordReply) RemoveKeyboard() {
+	r.buttons = nil
+	r.set = true
+}
+
+func (r *DiscordReply) AddButton(text string, name string, value string) {
+	r.buttons = append(r.buttons, Button{
+		Text:  text,
+		Name:  name,
+		Value: value,
+	})
+	r.set = true
+}
+
+func (r *DiscordReply) AddMultipleButtons(buttonsForm [][3]string) {
+	for _, buttonForm := range buttonsForm {
+		r.AddButton(buttonForm[0], buttonForm[1], buttonForm[2])
+	}
+}
+
+// build builds the reply messages, the buttons are attached to the last message.
+// The custom id of the button is the text that will be sent back to the responder when the button is clicked.
+func (r *DiscordReply) build() (messages []discordapi.MessageCreate) {
+	for _, split := range util.StringSplitByLength(r.message, maxDiscordMessageSize) {
+		messages = append(messages, discordapi.MessageCreate{Content: split})
+	}
+
+	if len(r.buttons) == 0 {
+		return messages
+	}
+
+	var rows []discordapi.Component
+	for i, btn := range r.buttons {
+		if i%maxDiscordButtonsPerRow == 0 {
+			if len(rows) == maxDiscordActionRows {
+				log.Warnf("[discord] too many buttons, only %d buttons are shown", maxDiscordActionRows*maxDiscordButtonsPerRow)
+				break
+			}
+
+			rows = append(rows, discordapi.Component{Type: discordapi.ComponentTypeActionRow})
+		}
+
+		value := btn.Value
+		if value == "" {
+			value = btn.Text
+		}
+
+		row := &rows[len(rows)-1]
+		row.Components = append(row.Components, discordapi.Component{
+			Type:     discordapi.ComponentTypeButton,
+			Style:    discordapi.ButtonStyleSecondary,
+			Label:    btn.Text,
+			CustomID: value,
+		})
+	}
+
+	if len(messages) == 0 {
+		// discord requires the content or the embeds for a message
+		messages = append(messages, discordapi.MessageCreate{Content: "Please choose:"})
+	}
+
+	messages[len(messages)-1].Components = rows
+	return messages
+}
+
+//go:generate callbackgen -type Discord
+type Discord struct {
+	Client  *discordapi.Client  `json:"-"`
+	Gateway *discordapi.Gateway `json:"-"`
+
+	// Private is used to protect the discord bot, users not authenticated can not see messages or sending commands
+	Private bool `json:"private,omitempty"`
+
+	sessions   DiscordSessionMap
+	sessionsMu sync.Mutex
+
+	// botUserID is the user id of the bot, messages sent by the bot itself are ignored
+	botUserID string
+
+	commandResponders map[string]Responder
+
+	// textMessageResponder is used for interact to register its message handler
+	textMessageResponder Responder
+
+	authorizedCallbacks []func(s *DiscordSession)
+}
+
+func NewDiscord(client *discordapi.Client, gateway *discordapi.Gateway) *Discord {
+	return &Discord{
+		Client:            client,
+		Gateway:           gateway,
+		Private:           true,
+		sessions:          make(DiscordSessionMap),
+		commandResponders: make(map[string]Responder),
+	}
+}
+
+func (dc *Discord) SetTextMessageResponder(responder Responder) {
+	dc.textMessageResponder = responder
+}
+
+func (dc *Discord) AddCommand(cmd *Command, responder Responder) {
+	name := strings.ToLower(cmd.Name)
+	if _, exists := dc.commandResponders[name]; exists {
+		panic(fmt.Errorf("command %s already exists, can not be re-defined", cmd.Name))
+	}
+
+	dc.commandResponders[name] = responder
+}
+
+func (dc *Discord) Start(ctx context.Context) {
+	dc.Gateway.OnReady(func(e discordapi.ReadyEvent) {
+		dc.botUserID = e.User.ID
+	})
+
+	dc.Gateway.OnMessageCreate(func(m discordapi.Message) {
+		if m.Author == nil || m.Author.Bot || m.Author.ID == dc.botUserID {
+			return
+		}
+
+		dc.handleText(ctx, m.Author, m.ChannelID, m.Content)
+	})
+
+	dc.Gateway.OnInteractionCreate(func(i discordapi.Interaction) {
+		if i.Type != discordapi.InteractionTypeMessageComponent {
+			return
+		}
+
+		// acknowledge the button click, or the user sees "interaction failed"
+		if err := dc.Client.CreateInteractionResponse(ctx, &i, discordapi.InteractionResponse{
+			Type: discordapi.InteractionCallbackDeferredUpdateMessage,
+		}); err != nil {
+			log.WithError(err).Errorf("[discord] interaction response error")
+		}
+
+		user := i.Sender()
+		if user == nil {
+			return
+		}
+
+		dc.handleText(ctx, user, i.ChannelID, i.Data.CustomID)
+	})
+
+	dc.Gateway.Run(ctx)
+}
+
+// handleText dispatches the text to the command responder if it's a command, otherwise the text message responder
+func (dc *Discord) handleText(ctx context.Context, user *discordapi.User, channelID, text string) {
+	log.Infof("[discord] onText: user=%s channel=%s text=%q", user.Username, channelID, text)
+
+	session := dc.loadSession(user, channelID)
+	reply := dc.newReply(session)
+
+	if name, payload, ok := parseDiscordCommand(text); ok {
+		responder, exists := dc.commandResponders[name]
+		if !exists {
+			log.Debugf("[discord] command %s not found, skipping message", name)
+			return
+		}
+
+		if err := responder(session, payload, reply); err != nil {
+			log.WithError(err).Errorf("[discord] responder error")
+			dc.send(ctx, channelID, discordapi.MessageCreate{Content: fmt.Sprintf("error: %v", err)})
+			return
+		}
+	} else {
+		if dc.Private {
+			if !session.authorizing && !session.Authorized {
+				log.Warn("[discord] discord is set to private mode, skipping message")
+				return
+			}
+		}
+
+		if dc.textMessageResponder != nil {
+			if err := dc.textMessageResponder(session, text, reply); err != nil {
+				log.WithError(err).Errorf("[discord] response handling error")
+			}
+		}
+	}
+
+	if reply.set {
+		for _, message := range reply.build() {
+			dc.send(ctx, channelID, message)
+		}
+	}
+}
+
+func (dc *Discord) send(ctx context.Context, channelID string, message discordapi.MessageCreate) {
+	if err := sendLimiter.Wait(ctx); err != nil {
+		log.WithError(err).Errorf("discord send limit exceeded")
+		return
+	}
+
+	if _, err := dc.Client.CreateMessage(ctx, channelID, message); err != nil {
+		log.WithError(err).Errorf("[discord] message send error")
+	}
+}
+
+// parseDiscordCommand parses the command name and the payload from the text like "/position BTCUSDT"
+func parseDiscordCommand(text string) (name, payload string, ok bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+
+	name = text
+	if idx := strings.IndexAny(text, " \t\n"); idx > 0 {
+		name = text[:idx]
+		payload = strings.TrimSpace(text[idx:])
+	}
+
+	return strings.ToLower(name), payload, true
+}
+
+func (dc *Discord) loadSession(user *discordapi.User, channelID string) *DiscordSession {
+	dc.sessionsMu.Lock()
+	defer dc.sessionsMu.Unlock()
+
+	if dc.sessions == nil {
+		dc.sessions = make(DiscordSessionMap)
+	}
+
+	key := user.ID + "-" + channelID
+	if session, ok := dc.sessions[key]; ok {
+		log.Infof("[discord] loaded existing session: %+v", session)
+		return session
+	}
+
+	session := NewDiscordSession(dc, user, channelID)
+	dc.sessions[key] = session
+
+	log.Infof("[discord] allocated a new session: %+v", session)
+	return session
+}
+
+func (dc *Discord) newReply(session *DiscordSession) *DiscordReply {
+	return &DiscordReply{
+		client:  dc.Client,
+		session: session,
+	}
+}
+
+func (dc *Discord) Sessions() DiscordSessionMap {
+	return dc.sessions
+}
+
+func (dc *Discord) RestoreSessions(sessions DiscordSessionMap) {
+	if len(sessions) == 0 {
+		return
+	}
+
+	log.Infof("[discord] restoring discord %d sessions", len(sessions))
+	dc.sessions = sessions
+	for _, session := range sessions {
+		if session.User == nil || session.ChannelID == "" {
+			continue
+		}
+
+		// update discord context reference
+		session.discord = dc
+
+		if session.IsAuthorized() {
+			dc.send(context.Background(), session.ChannelID, discordapi.MessageCreate{
+				Content: fmt.Sprintf("Hi %s, I'm back. Your discord session is restored.", session.User.Username),
+			})
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package interact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseDiscordCommand(t *testing.T) {
+	name, payload, ok := parseDiscordCommand(" /closePosition BTCUSDT 0.5 ")
+	assert.True(t, ok)
+	assert.Equal(t, "/closeposition", name)
+	assert.Equal(t, "BTCUSDT 0.5", payload)
+
+	name, payload, ok = parseDiscordCommand("/position")
+	assert.True(t, ok)
+	assert.Equal(t, "/position", name)
+	assert.Empty(t, payload)
+
+	_, _, ok = parseDiscordCommand("BTCUSDT")
+	assert.False(t, ok)
+}
+
+func TestDiscordReply_build(t *testing.T) {
+	reply := &DiscordReply{}
+	reply.Message("Choose your position")
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "DOGEUSDT", "SOLUSDT", "XRPUSDT"} {
+		reply.AddButton(symbol, "symbol", symbol)
+	}
+
+	messages := reply.build()
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, "Choose your position", messages[0].Content)
+		if assert.Len(t, messages[0].Components, 2) {
+			assert.Len(t, messages[0].Components[0].Components, 5)
+			assert.Equal(t, "XRPUSDT", messages[0].Components[1].Components[0].CustomID)
+		}
+	}
+
+	reply.RemoveKeyboard()
+	messages = reply.build()
+	if assert.Len(t, messages, 1) {
+		assert.Empty(t, messages[0].Components)
+	}
+}
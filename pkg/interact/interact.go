@@ -3,6 +3,7 @@ package interact
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -47,6 +48,12 @@ type Interact struct {
 	states     map[State]State
 	statesFunc map[State]interface{}
 
+	// privateStates maps the states of the private commands to the command names for the audit log
+	privateStates map[State]string
+
+	// accessControl is optional, all the authorized users can execute the private commands if it's nil
+	accessControl *AccessControl
+
 	customInteractions []CustomInteraction
 
 	messengers []Messenger
@@ -61,9 +68,45 @@ func New() *Interact {
 		privateCommands: make(map[string]*Command),
 		states:          make(map[State]State),
 		statesFunc:      make(map[State]interface{}),
+		privateStates:   make(map[State]string),
 	}
 }
 
+// SetAccessControl enables the role-based authorization of the private commands
+func (it *Interact) SetAccessControl(ac *AccessControl) {
+	it.mu.Lock()
+	it.accessControl = ac
+	it.mu.Unlock()
+}
+
+func (it *Interact) getAccessControl() *AccessControl {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return it.accessControl
+}
+
+// CanAccessStrategy returns true if the user of the session can access the strategy of the given signature,
+// it's always true if the access control is not set
+func (it *Interact) CanAccessStrategy(session Session, signature string) bool {
+	ac := it.getAccessControl()
+	if ac == nil {
+		return true
+	}
+
+	return ac.CanAccessStrategy(session, signature)
+}
+
+// IsStrategyScoped returns true if the user of the session is restricted to some strategies,
+// it's always false if the access control is not set
+func (it *Interact) IsStrategyScoped(session Session) bool {
+	ac := it.getAccessControl()
+	if ac == nil {
+		return false
+	}
+
+	return ac.IsStrategyScoped(session)
+}
+
 func (it *Interact) AddCustomInteraction(custom CustomInteraction) {
 	custom.Commands(it)
 
@@ -125,8 +168,26 @@ func (it *Interact) handleResponse(session Session, text string, ctxObjects ...i
 		return fmt.Errorf("state function of %s is not defined", state)
 	}
 
+	it.mu.Lock()
+	command, isPrivate := it.privateStates[state]
+	it.mu.Unlock()
+
+	ac := it.getAccessControl()
+	if ac != nil && isPrivate {
+		// the role of the user could be changed during the conversation, check it again on every response
+		if err := ac.CheckCommand(session, command); err != nil {
+			ac.audit(session, command, state, text, false, err)
+			return err
+		}
+	}
+
 	ctxObjects = append(ctxObjects, session)
 	_, err := ParseFuncArgsAndCall(f, args, ctxObjects...)
+
+	if ac != nil && isPrivate {
+		ac.audit(session, command, state, text, true, err)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -147,6 +208,12 @@ func (it *Interact) getCommand(session Session, command string) (*Command, error
 
 	if session.IsAuthorized() {
 		if cmd, ok := it.privateCommands[command]; ok {
+			if it.accessControl != nil {
+				if err := it.accessControl.CheckCommand(session, command); err != nil {
+					return nil, err
+				}
+			}
+
 			return cmd, nil
 		}
 	} else {
@@ -164,14 +231,30 @@ func (it *Interact) getCommand(session Session, command string) (*Command, error
 }
 
 func (it *Interact) runCommand(session Session, command string, args []string, ctxObjects ...interface{}) error {
+	it.mu.Lock()
+	_, isPrivate := it.privateCommands[command]
+	it.mu.Unlock()
+
+	ac := it.getAccessControl()
+	text := strings.Join(args, " ")
+
 	cmd, err := it.getCommand(session, command)
 	if err != nil {
+		if ac != nil && isPrivate {
+			ac.audit(session, command, session.GetState(), text, false, err)
+		}
 		return err
 	}
 
 	ctxObjects = append(ctxObjects, session)
 	session.SetState(cmd.initState)
-	if _, err := ParseFuncArgsAndCall(cmd.F, args, ctxObjects...); err != nil {
+	_, err = ParseFuncArgsAndCall(cmd.F, args, ctxObjects...)
+
+	if ac != nil && isPrivate {
+		ac.audit(session, command, cmd.initState, text, true, err)
+	}
+
+	if err != nil {
 		return err
 	}
 
@@ -220,6 +303,12 @@ func (it *Interact) init() error {
 		return err
 	}
 
+	for name, cmd := range it.privateCommands {
+		for state := range cmd.statesFunc {
+			it.privateStates[state] = name
+		}
+	}
+
 	return nil
 }
 
@@ -43,6 +43,17 @@ func Test_parseFuncArgsAndCall_InterfaceInjection(t *testing.T) {
 	assert.Equal(t, "123", buf.String())
 }
 
+func Test_parseFuncArgsAndCall_MissingArguments(t *testing.T) {
+	f := func(a string, b float64) error {
+		assert.Equal(t, "BTCUSDT", a)
+		assert.Equal(t, 0.0, b)
+		return nil
+	}
+
+	_, err := ParseFuncArgsAndCall(f, []string{"BTCUSDT"})
+	assert.NoError(t, err)
+}
+
 func Test_parseCommand(t *testing.T) {
 	args := parseCommand(`closePosition "BTC USDT" 3.1415926 market`)
 	t.Logf("args: %+v", args)
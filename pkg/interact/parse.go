@@ -19,6 +19,12 @@ func ParseFuncArgsAndCall(f interface{}, args []string, objects ...interface{})
 	for i := 0; i < ft.NumIn(); i++ {
 		at := ft.In(i)
 
+		// the missing arguments are passed as the zero values, so that the trailing arguments can be optional
+		if at.Kind() != reflect.Interface && argIndex >= len(args) {
+			rArgs = append(rArgs, reflect.Zero(at))
+			continue
+		}
+
 		// get the kind of argument
 		switch k := at.Kind(); k {
 
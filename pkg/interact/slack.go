@@ -147,6 +147,10 @@ func (s *SlackSession) ID() string {
 	return fmt.Sprintf("%s-%s", s.UserID, s.ChannelID)
 }
 
+func (s *SlackSession) Identity() Identity {
+	return Identity{Messenger: "slack", UserID: s.UserID}
+}
+
 func (s *SlackSession) SetAuthorized() {
 	s.BaseSession.SetAuthorized()
 	s.slack.EmitAuthorized(s)
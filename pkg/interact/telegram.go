@@ -3,6 +3,7 @@ package interact
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,7 +22,18 @@ var sendLimiter = rate.NewLimiter(10, 2)
 
 const maxMessageSize int = 3000
 
-type TelegramSessionMap map[int64]*TelegramSession
+// TelegramSessionMap is keyed by the user ID and the chat ID, see telegramSessionKey,
+// the users in the same group chat have their own sessions like the slack and the discord sessions
+type TelegramSessionMap map[string]*TelegramSession
+
+func telegramSessionKey(chat *telebot.Chat, user *telebot.User) string {
+	var userID int64
+	if user != nil {
+		userID = user.ID
+	}
+
+	return fmt.Sprintf("%d-%d", userID, chat.ID)
+}
 
 type TelegramSession struct {
 	BaseSession
@@ -36,6 +48,10 @@ func (s *TelegramSession) ID() string {
 	return fmt.Sprintf("telegram-%d-%d", s.User.ID, s.Chat.ID)
 }
 
+func (s *TelegramSession) Identity() Identity {
+	return Identity{Messenger: "telegram", UserID: strconv.FormatInt(s.User.ID, 10), Username: s.User.Username}
+}
+
 func (s *TelegramSession) SetAuthorized() {
 	s.BaseSession.SetAuthorized()
 	s.telegram.EmitAuthorized(s)
@@ -134,7 +150,7 @@ func NewTelegram(bot *telebot.Bot) *Telegram {
 	return &Telegram{
 		Bot:      bot,
 		Private:  true,
-		sessions: make(map[int64]*TelegramSession),
+		sessions: make(TelegramSessionMap),
 	}
 }
 
@@ -215,17 +231,18 @@ func checkSendErr(m *telebot.Message, err error) {
 
 func (tm *Telegram) loadSession(m *telebot.Message) *TelegramSession {
 	if tm.sessions == nil {
-		tm.sessions = make(map[int64]*TelegramSession)
+		tm.sessions = make(TelegramSessionMap)
 	}
 
-	session, ok := tm.sessions[m.Chat.ID]
+	key := telegramSessionKey(m.Chat, m.Sender)
+	session, ok := tm.sessions[key]
 	if ok {
 		log.Infof("[telegram] loaded existing session: %+v", session)
 		return session
 	}
 
 	session = NewTelegramSession(tm, m)
-	tm.sessions[m.Chat.ID] = session
+	tm.sessions[key] = session
 
 	log.Infof("[telegram] allocated a new session: %+v", session)
 	return session
@@ -268,7 +285,7 @@ func (tm *Telegram) RestoreSessions(sessions TelegramSessionMap) {
 	}
 
 	log.Infof("[telegram] restoring telegram %d sessions", len(sessions))
-	tm.sessions = sessions
+	tm.sessions = make(TelegramSessionMap, len(sessions))
 	for _, session := range sessions {
 		if session.Chat == nil || session.User == nil {
 			continue
@@ -277,6 +294,9 @@ func (tm *Telegram) RestoreSessions(sessions TelegramSessionMap) {
 		// update telegram context reference
 		session.telegram = tm
 
+		// the sessions stored by the previous versions are keyed by the chat ID only, re-key them by the chat and the user
+		tm.sessions[telegramSessionKey(session.Chat, session.User)] = session
+
 		if session.IsAuthorized() {
 			if _, err := tm.Bot.Send(session.Chat, fmt.Sprintf("Hi %s, I'm back. Your telegram session is restored.", session.User.Username)); err != nil {
 				log.WithError(err).Error("[telegram] can not send telegram message")
@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper/v2"
+)
+
+func init() {
+	AddMigration("main", up_main_addEquitySnapshots, down_main_addEquitySnapshots)
+}
+
+func up_main_addEquitySnapshots(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `equity_snapshots`\n(\n    `gid`             BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n    `session`         VARCHAR(30)     NOT NULL,\n    `exchange`        VARCHAR(20)     NOT NULL,\n    `time`            DATETIME(3)     NOT NULL,\n    `quote_currency`  VARCHAR(10)     NOT NULL,\n    -- net_value is the account net value in the quote currency\n    `net_value`       DECIMAL(32, 8)  NOT NULL,\n    `high_water_mark` DECIMAL(32, 8)  NOT NULL,\n    -- drawdown ratios from the high-water mark\n    `drawdown`        DECIMAL(32, 8)  NOT NULL,\n    `max_drawdown`    DECIMAL(32, 8)  NOT NULL,\n    PRIMARY KEY (`gid`),\n    INDEX `session_time` (`session`, `time`)\n);")
+	if err != nil {
+		return err
+	}
+	return err
+}
+
+func down_main_addEquitySnapshots(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `equity_snapshots`;")
+	if err != nil {
+		return err
+	}
+	return err
+}
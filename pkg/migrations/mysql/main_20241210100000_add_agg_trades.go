@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper/v2"
+)
+
+func init() {
+	AddMigration("main", up_main_addAggTrades, down_main_addAggTrades)
+}
+
+func up_main_addAggTrades(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `agg_trades`\n(\n    `gid`            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n    `exchange`       VARCHAR(20)     NOT NULL,\n    `symbol`         VARCHAR(32)     NOT NULL,\n    `agg_trade_id`   BIGINT UNSIGNED NOT NULL,\n    `price`          DECIMAL(32, 8)  NOT NULL,\n    `quantity`       DECIMAL(32, 8)  NOT NULL,\n    `first_trade_id` BIGINT UNSIGNED NOT NULL,\n    `last_trade_id`  BIGINT UNSIGNED NOT NULL,\n    `is_buyer_maker` BOOLEAN         NOT NULL DEFAULT FALSE,\n    `traded_at`      DATETIME(3)     NOT NULL,\n    PRIMARY KEY (`gid`),\n    UNIQUE KEY `agg_trade_id` (`exchange`, `symbol`, `agg_trade_id`),\n    INDEX `symbol_traded_at` (`exchange`, `symbol`, `traded_at`)\n);")
+	if err != nil {
+		return err
+	}
+	return err
+}
+
+func down_main_addAggTrades(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `agg_trades`;")
+	if err != nil {
+		return err
+	}
+	return err
+}
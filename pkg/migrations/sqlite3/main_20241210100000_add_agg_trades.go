@@ -0,0 +1,45 @@
+package sqlite3
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper/v2"
+)
+
+func init() {
+	AddMigration("main", up_main_addAggTrades, down_main_addAggTrades)
+}
+
+func up_main_addAggTrades(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `agg_trades`\n(\n    `gid`            INTEGER PRIMARY KEY AUTOINCREMENT,\n    `exchange`       VARCHAR(20)    NOT NULL,\n    `symbol`         VARCHAR(32)    NOT NULL,\n    `agg_trade_id`   INTEGER        NOT NULL,\n    `price`          DECIMAL(32, 8) NOT NULL,\n    `quantity`       DECIMAL(32, 8) NOT NULL,\n    `first_trade_id` INTEGER        NOT NULL,\n    `last_trade_id`  INTEGER        NOT NULL,\n    `is_buyer_maker` BOOLEAN        NOT NULL DEFAULT FALSE,\n    `traded_at`      DATETIME(3)    NOT NULL\n);")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "CREATE UNIQUE INDEX agg_trades_agg_trade_id ON agg_trades (exchange, symbol, agg_trade_id);")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "CREATE INDEX agg_trades_symbol_traded_at ON agg_trades (exchange, symbol, traded_at);")
+	if err != nil {
+		return err
+	}
+	return err
+}
+
+func down_main_addAggTrades(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+	_, err = tx.ExecContext(ctx, "DROP INDEX IF EXISTS agg_trades_symbol_traded_at;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DROP INDEX IF EXISTS agg_trades_agg_trade_id;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `agg_trades`;")
+	if err != nil {
+		return err
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package sqlite3
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper/v2"
+)
+
+func init() {
+	AddMigration("main", up_main_addAccountStatements, down_main_addAccountStatements)
+}
+
+func up_main_addAccountStatements(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `balance_snapshots`\n(\n    `gid`       INTEGER PRIMARY KEY AUTOINCREMENT,\n    `session`   VARCHAR(30)    NOT NULL,\n    `exchange`  VARCHAR(20)    NOT NULL,\n    `time`      DATETIME(3)    NOT NULL,\n    `asset`     VARCHAR(24)    NOT NULL,\n    `available` DECIMAL(32, 8) NOT NULL,\n    `locked`    DECIMAL(32, 8) NOT NULL,\n    `borrowed`  DECIMAL(32, 8) NOT NULL,\n    `interest`  DECIMAL(32, 8) NOT NULL,\n    -- net_asset = available + locked - borrowed - interest\n    `net_asset` DECIMAL(32, 8) NOT NULL\n);")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "CREATE INDEX balance_snapshots_session_time ON balance_snapshots (session, time);")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `account_statements`\n(\n    `gid`           INTEGER PRIMARY KEY AUTOINCREMENT,\n    `session`       VARCHAR(30)    NOT NULL,\n    `exchange`      VARCHAR(20)    NOT NULL,\n    `asset`         VARCHAR(24)    NOT NULL,\n    `start_time`    DATETIME(3)    NOT NULL,\n    `end_time`      DATETIME(3)    NOT NULL,\n    -- the net assets of the balance snapshots\n    `start_balance` DECIMAL(32, 8) NOT NULL,\n    `end_balance`   DECIMAL(32, 8) NOT NULL,\n    -- the flows from the synced records between the snapshots\n    `trade`         DECIMAL(32, 8) NOT NULL,\n    `fee`           DECIMAL(32, 8) NOT NULL,\n    `deposit`       DECIMAL(32, 8) NOT NULL,\n    `withdraw`      DECIMAL(32, 8) NOT NULL,\n    `reward`        DECIMAL(32, 8) NOT NULL,\n    `interest`      DECIMAL(32, 8) NOT NULL,\n    -- difference = end_balance - (start_balance + flows)\n    `difference`    DECIMAL(32, 8) NOT NULL,\n    `reconciled`    BOOLEAN        NOT NULL DEFAULT TRUE\n);")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "CREATE INDEX account_statements_session_end_time ON account_statements (session, end_time);")
+	if err != nil {
+		return err
+	}
+	return err
+}
+
+func down_main_addAccountStatements(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+	_, err = tx.ExecContext(ctx, "DROP INDEX IF EXISTS account_statements_session_end_time;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `account_statements`;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DROP INDEX IF EXISTS balance_snapshots_session_time;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `balance_snapshots`;")
+	if err != nil {
+		return err
+	}
+	return err
+}
@@ -0,0 +1,298 @@
+package discordnotifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/discord/discordapi"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// discord allows 5 messages per 5 seconds for a channel
+var limiter = rate.NewLimiter(rate.Every(time.Second), 5)
+
+const defaultQueueSize = 500
+
+type notifyTask struct {
+	channel string
+
+	message discordapi.MessageCreate
+
+	// photo is the image attachment of the message
+	photo []byte
+}
+
+// ChannelRouter routes the notification object to the discord channel id
+type ChannelRouter func(obj interface{}) (channel string, ok bool)
+
+// Notifier is a discord notifier
+//
+// To use this notifier, you need to invite the bot to your server with the permissions:
+// - View Channels
+// - Send Messages
+// - Embed Links
+// - Attach Files
+type Notifier struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	client  *discordapi.Client
+	channel string
+
+	router ChannelRouter
+
+	taskC chan notifyTask
+}
+
+type NotifyOption func(notifier *Notifier)
+
+func OptionContext(baseCtx context.Context) NotifyOption {
+	return func(notifier *Notifier) {
+		ctx, cancel := context.WithCancel(baseCtx)
+		notifier.ctx = ctx
+		notifier.cancel = cancel
+	}
+}
+
+func OptionQueueSize(size int) NotifyOption {
+	return func(notifier *Notifier) {
+		notifier.taskC = make(chan notifyTask, size)
+	}
+}
+
+// OptionChannelRouter sets the router for the notifications that are not sent to a specific channel
+func OptionChannelRouter(router ChannelRouter) NotifyOption {
+	return func(notifier *Notifier) {
+		notifier.router = router
+	}
+}
+
+// New returns a discord notifier instance, channel is the id of the default channel
+func New(client *discordapi.Client, channel string, options ...NotifyOption) *Notifier {
+	notifier := &Notifier{
+		ctx:     context.Background(),
+		cancel:  func() {},
+		channel: channel,
+		client:  client,
+		taskC:   make(chan notifyTask, defaultQueueSize),
+	}
+
+	for _, o := range options {
+		o(notifier)
+	}
+
+	go notifier.worker(notifier.ctx)
+
+	return notifier
+}
+
+func (n *Notifier) worker(ctx context.Context) {
+	defer n.cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case task := <-n.taskC:
+			if err := n.executeTask(ctx, task); err != nil {
+				log.WithError(err).
+					WithField("channel", task.channel).
+					Errorf("discord api error: %s", err.Error())
+			}
+		}
+	}
+}
+
+func (n *Notifier) executeTask(ctx context.Context, task notifyTask) error {
+	// ignore the wait error
+	if err := limiter.Wait(ctx); err != nil {
+		log.WithError(err).Warnf("discord rate limiter error")
+	}
+
+	if task.photo != nil {
+		_, err := n.client.UploadFile(ctx, task.channel, task.message, "image.png", task.photo)
+		return err
+	}
+
+	_, err := n.client.CreateMessage(ctx, task.channel, task.message)
+	return err
+}
+
+func (n *Notifier) Notify(obj interface{}, args ...interface{}) {
+	n.NotifyTo("", obj, args...)
+}
+
+// NotifyTo sends the notification to the channel,
+// the channel names of the other messengers are ignored since discord uses the numeric channel id.
+func (n *Notifier) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	channel = n.resolveChannel(channel, obj)
+	if channel == "" {
+		log.Warnf("discord channel is not configured, notification dropped: %T", obj)
+		return
+	}
+
+	embeds, pureArgs := filterEmbeds(args)
+
+	var content string
+	switch a := obj.(type) {
+	case string:
+		content = fmt.Sprintf(a, pureArgs...)
+
+	case slack.Attachment:
+		embeds = append([]discordapi.Embed{EmbedFromSlackAttachment(a)}, embeds...)
+
+	case *slack.Attachment:
+		embeds = append([]discordapi.Embed{EmbedFromSlackAttachment(*a)}, embeds...)
+
+	case types.SlackAttachmentCreator:
+		embeds = append([]discordapi.Embed{EmbedFromSlackAttachment(a.SlackAttachment())}, embeds...)
+
+	case types.PlainText:
+		content = a.PlainText()
+
+	case types.Stringer:
+		content = a.String()
+
+	default:
+		log.Errorf("discord message conversion error, unsupported object: %T %+v", a, a)
+		return
+	}
+
+	for _, message := range buildMessages(content, embeds) {
+		n.queueTask(context.Background(), notifyTask{
+			channel: channel,
+			message: message,
+		}, 100*time.Millisecond)
+	}
+}
+
+func (n *Notifier) resolveChannel(channel string, obj interface{}) string {
+	if isChannelID(channel) {
+		return channel
+	}
+
+	if n.router != nil {
+		if routed, ok := n.router(obj); ok && isChannelID(routed) {
+			return routed
+		}
+	}
+
+	return n.channel
+}
+
+func (n *Notifier) queueTask(ctx context.Context, task notifyTask, timeout time.Duration) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(timeout):
+		log.Warnf("discord notify task is dropped due to timeout %s", timeout)
+		return
+	case n.taskC <- task:
+	}
+}
+
+func (n *Notifier) SendPhoto(buffer *bytes.Buffer) {
+	n.SendPhotoTo("", buffer)
+}
+
+func (n *Notifier) SendPhotoTo(channel string, buffer *bytes.Buffer) {
+	if !isChannelID(channel) {
+		channel = n.channel
+	}
+
+	n.queueTask(context.Background(), notifyTask{
+		channel: channel,
+		photo:   append([]byte(nil), buffer.Bytes()...),
+	}, 100*time.Millisecond)
+}
+
+// buildMessages splits the content and the embeds into messages that fit the discord limits,
+// the embeds are attached to the last message
+func buildMessages(content string, embeds []discordapi.Embed) (messages []discordapi.MessageCreate) {
+	if content != "" {
+		for _, split := range util.StringSplitByLength(content, maxContentLength) {
+			messages = append(messages, discordapi.MessageCreate{Content: split})
+		}
+	}
+
+	for len(embeds) > 0 {
+		size := len(embeds)
+		if size > maxEmbedsPerMessage {
+			size = maxEmbedsPerMessage
+		}
+
+		if len(messages) > 0 && messages[len(messages)-1].Embeds == nil {
+			messages[len(messages)-1].Embeds = embeds[:size]
+		} else {
+			messages = append(messages, discordapi.MessageCreate{Embeds: embeds[:size]})
+		}
+
+		embeds = embeds[size:]
+	}
+
+	return messages
+}
+
+func filterEmbeds(args []interface{}) (embeds []discordapi.Embed, pureArgs []interface{}) {
+	var firstEmbedOffset = -1
+	for idx, arg := range args {
+		switch a := arg.(type) {
+
+		// concrete type assert first
+		case discordapi.Embed:
+			embeds = append(embeds, a)
+
+		case slack.Attachment:
+			embeds = append(embeds, EmbedFromSlackAttachment(a))
+
+		case *slack.Attachment:
+			embeds = append(embeds, EmbedFromSlackAttachment(*a))
+
+		case types.SlackAttachmentCreator:
+			embeds = append(embeds, EmbedFromSlackAttachment(a.SlackAttachment()))
+
+		case types.PlainText:
+			// fallback to PlainText if it's not supported
+			embeds = append(embeds, discordapi.Embed{
+				Title: truncate(a.PlainText(), maxEmbedTitle),
+			})
+
+		default:
+			continue
+		}
+
+		if firstEmbedOffset == -1 {
+			firstEmbedOffset = idx
+		}
+	}
+
+	pureArgs = args
+	if firstEmbedOffset > -1 {
+		pureArgs = args[:firstEmbedOffset]
+	}
+
+	return embeds, pureArgs
+}
+
+// isChannelID checks if the channel is a discord snowflake id
+func isChannelID(channel string) bool {
+	if channel == "" {
+		return false
+	}
+
+	for _, c := range channel {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
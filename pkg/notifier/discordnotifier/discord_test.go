@@ -0,0 +1,69 @@
+package discordnotifier
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/discord/discordapi"
+)
+
+func TestEmbedFromSlackAttachment(t *testing.T) {
+	embed := EmbedFromSlackAttachment(slack.Attachment{
+		Title:   "BTCUSDT Position",
+		Color:   "#228B22",
+		Pretext: "*Position* updated",
+		Fields: []slack.AttachmentField{
+			{Title: "Base", Value: "`1.5`", Short: true},
+			{Title: "Note", Value: ""},
+		},
+		Footer: "strategy: grid",
+		Ts:     json.Number("1672531200"),
+	})
+
+	assert.Equal(t, "BTCUSDT Position", embed.Title)
+	assert.Equal(t, 0x228B22, embed.Color)
+	assert.Equal(t, "**Position** updated", embed.Description)
+	if assert.Len(t, embed.Fields, 2) {
+		assert.Equal(t, "`1.5`", embed.Fields[0].Value)
+		assert.True(t, embed.Fields[0].Inline)
+		assert.Equal(t, "-", embed.Fields[1].Value)
+	}
+
+	assert.Equal(t, "strategy: grid", embed.Footer.Text)
+	assert.Equal(t, int64(1672531200), embed.Timestamp.Unix())
+
+	assert.Equal(t, 0xA30200, parseColor("danger"))
+	assert.Equal(t, 0, parseColor("unknown"))
+}
+
+func TestBuildMessages(t *testing.T) {
+	embeds := make([]discordapi.Embed, 12)
+	messages := buildMessages(strings.Repeat("a", 2500), embeds)
+	if assert.Len(t, messages, 3) {
+		assert.Len(t, messages[0].Content, 2000)
+		assert.Empty(t, messages[0].Embeds)
+		assert.Len(t, messages[1].Content, 500)
+		assert.Len(t, messages[1].Embeds, 10)
+		assert.Len(t, messages[2].Embeds, 2)
+	}
+}
+
+func TestNotifier_resolveChannel(t *testing.T) {
+	n := &Notifier{
+		channel: "100",
+		router: func(obj interface{}) (string, bool) {
+			if obj == "routed" {
+				return "200", true
+			}
+			return "", false
+		},
+	}
+
+	assert.Equal(t, "300", n.resolveChannel("300", "routed"))
+	assert.Equal(t, "200", n.resolveChannel("dev-bbgo", "routed"))
+	assert.Equal(t, "100", n.resolveChannel("", "other"))
+}
@@ -0,0 +1,129 @@
+package discordnotifier
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/c9s/bbgo/pkg/discord/discordapi"
+)
+
+// the discord limits, see https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+const (
+	maxContentLength     = 2000
+	maxEmbedsPerMessage  = 10
+	maxEmbedTitle        = 256
+	maxEmbedDescription  = 4096
+	maxEmbedFields       = 25
+	maxEmbedFieldName    = 256
+	maxEmbedFieldValue   = 1024
+	maxEmbedFooterLength = 2048
+)
+
+var namedColors = map[string]int{
+	"good":    0x2EB886,
+	"warning": 0xDAA038,
+	"danger":  0xA30200,
+}
+
+// EmbedFromSlackAttachment converts the slack attachment to the discord embed,
+// so that the objects implement types.SlackAttachmentCreator can be rendered in discord.
+func EmbedFromSlackAttachment(a slack.Attachment) discordapi.Embed {
+	embed := discordapi.Embed{
+		Title: truncate(a.Title, maxEmbedTitle),
+		URL:   a.TitleLink,
+		Color: parseColor(a.Color),
+	}
+
+	var description []string
+	if a.Pretext != "" {
+		description = append(description, a.Pretext)
+	}
+
+	if a.Text != "" {
+		description = append(description, a.Text)
+	}
+
+	embed.Description = truncate(translateMarkdown(strings.Join(description, "\n")), maxEmbedDescription)
+
+	// fallback is used when the attachment has no title and text
+	if embed.Title == "" && embed.Description == "" {
+		embed.Description = truncate(a.Fallback, maxEmbedDescription)
+	}
+
+	for i, field := range a.Fields {
+		if i >= maxEmbedFields {
+			break
+		}
+
+		value := translateMarkdown(field.Value)
+		if value == "" {
+			// discord rejects the embed field with an empty value
+			value = "-"
+		}
+
+		embed.Fields = append(embed.Fields, discordapi.EmbedField{
+			Name:   truncate(field.Title, maxEmbedFieldName),
+			Value:  truncate(value, maxEmbedFieldValue),
+			Inline: field.Short,
+		})
+	}
+
+	if a.Footer != "" {
+		embed.Footer = &discordapi.EmbedFooter{Text: truncate(translateMarkdown(a.Footer), maxEmbedFooterLength)}
+	}
+
+	if a.Ts != "" {
+		if ts, err := a.Ts.Float64(); err == nil && ts > 0 {
+			t := time.Unix(0, int64(ts*float64(time.Second))).UTC()
+			embed.Timestamp = &t
+		}
+	}
+
+	return embed
+}
+
+// parseColor parses the slack color, which is either a hex color like "#228B22" or a named color like "good"
+func parseColor(color string) int {
+	if c, ok := namedColors[color]; ok {
+		return c
+	}
+
+	c, err := strconv.ParseInt(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil {
+		return 0
+	}
+
+	return int(c)
+}
+
+// translateMarkdown translates the slack markdown to the discord markdown,
+// slack uses *text* for bold and _text_ for italic while discord uses **text** and *text*
+func translateMarkdown(text string) string {
+	var sb strings.Builder
+	var inCode bool
+	for _, r := range text {
+		switch {
+		case r == '`':
+			inCode = !inCode
+			sb.WriteRune(r)
+		case r == '*' && !inCode:
+			sb.WriteString("**")
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func truncate(s string, size int) string {
+	runes := []rune(s)
+	if len(runes) <= size {
+		return s
+	}
+
+	return string(runes[:size-3]) + "..."
+}
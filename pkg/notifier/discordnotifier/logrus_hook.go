@@ -0,0 +1,48 @@
+package discordnotifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+var logLimiter = rate.NewLimiter(rate.Every(time.Minute), 3)
+
+// LogHook sends the error logs to the discord error channel
+type LogHook struct {
+	notifier *Notifier
+	channel  string
+}
+
+func NewLogHook(notifier *Notifier, channel string) *LogHook {
+	return &LogHook{
+		notifier: notifier,
+		channel:  channel,
+	}
+}
+
+func (t *LogHook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.ErrorLevel,
+		logrus.FatalLevel,
+		logrus.PanicLevel,
+	}
+}
+
+func (t *LogHook) Fire(e *logrus.Entry) error {
+	if !logLimiter.Allow() {
+		return nil
+	}
+
+	var message = fmt.Sprintf("[%s] %s", e.Level.String(), e.Message)
+	if errData, ok := e.Data[logrus.ErrorKey]; ok && errData != nil {
+		if err, isErr := errData.(error); isErr {
+			message += " Error: " + err.Error()
+		}
+	}
+
+	t.notifier.NotifyTo(t.channel, "%s", message)
+	return nil
+}
@@ -0,0 +1,296 @@
+package emailnotifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+var log = logrus.WithField("service", "email")
+
+const defaultQueueSize = 500
+
+const maxSubjectLength = 78
+
+// SendMailFunc sends the mail, it's smtp.SendMail by default
+type SendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+type entry struct {
+	time    time.Time
+	subject string
+	text    string
+	photo   []byte
+}
+
+// Notifier sends the notifications by email through the SMTP server.
+//
+// In the digest mode, the notifications are collected and sent in one email per digest interval,
+// otherwise each notification is sent as an email.
+type Notifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+
+	subjectPrefix string
+
+	digestInterval time.Duration
+	digest         []entry
+
+	sendMail SendMailFunc
+
+	entryC chan entry
+}
+
+type Option func(notifier *Notifier)
+
+// OptionAuth sets the PLAIN authentication of the SMTP server
+func OptionAuth(username, password string) Option {
+	return func(notifier *Notifier) {
+		host, _, _ := net.SplitHostPort(notifier.addr)
+		notifier.auth = smtp.PlainAuth("", username, password, host)
+	}
+}
+
+func OptionSubjectPrefix(prefix string) Option {
+	return func(notifier *Notifier) {
+		notifier.subjectPrefix = prefix
+	}
+}
+
+// OptionDigest enables the digest mode, the notifications are sent in one email every interval
+func OptionDigest(interval time.Duration) Option {
+	return func(notifier *Notifier) {
+		notifier.digestInterval = interval
+	}
+}
+
+func OptionQueueSize(size int) Option {
+	return func(notifier *Notifier) {
+		notifier.entryC = make(chan entry, size)
+	}
+}
+
+func OptionSendMailFunc(f SendMailFunc) Option {
+	return func(notifier *Notifier) {
+		notifier.sendMail = f
+	}
+}
+
+// New returns an email notifier, addr is the address of the SMTP server, e.g., smtp.gmail.com:587
+func New(ctx context.Context, addr, from string, to []string, options ...Option) *Notifier {
+	notifier := &Notifier{
+		addr:          addr,
+		from:          from,
+		to:            to,
+		subjectPrefix: "[bbgo]",
+		sendMail:      smtp.SendMail,
+		entryC:        make(chan entry, defaultQueueSize),
+	}
+
+	for _, o := range options {
+		o(notifier)
+	}
+
+	go notifier.worker(ctx)
+	return notifier
+}
+
+func (n *Notifier) worker(ctx context.Context) {
+	var tickerC <-chan time.Time
+	if n.digestInterval > 0 {
+		ticker := time.NewTicker(n.digestInterval)
+		defer ticker.Stop()
+		tickerC = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			n.flushDigest()
+			return
+
+		case e := <-n.entryC:
+			if n.digestInterval > 0 {
+				n.digest = append(n.digest, e)
+				continue
+			}
+
+			if err := n.send(n.subject(e.subject), []entry{e}); err != nil {
+				log.WithError(err).Errorf("unable to send the email notification")
+			}
+
+		case <-tickerC:
+			n.flushDigest()
+		}
+	}
+}
+
+// flushDigest sends the collected notifications in one email
+func (n *Notifier) flushDigest() {
+	entries := n.digest
+	n.digest = nil
+
+	if len(entries) == 0 {
+		return
+	}
+
+	subject := n.subject(fmt.Sprintf("digest: %d notifications since %s", len(entries), entries[0].time.Format(time.RFC3339)))
+	if err := n.send(subject, entries); err != nil {
+		log.WithError(err).Errorf("unable to send the email digest, %d notifications are dropped", len(entries))
+	}
+}
+
+func (n *Notifier) subject(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+
+	text = strings.TrimSpace(text)
+
+	if n.subjectPrefix != "" {
+		text = n.subjectPrefix + " " + text
+	}
+
+	runes := []rune(text)
+	if len(runes) > maxSubjectLength {
+		text = string(runes[:maxSubjectLength-3]) + "..."
+	}
+
+	return text
+}
+
+func (n *Notifier) send(subject string, entries []entry) error {
+	msg, err := n.buildMessage(subject, entries)
+	if err != nil {
+		return err
+	}
+
+	return n.sendMail(n.addr, n.auth, n.from, n.to, msg)
+}
+
+// buildMessage builds the MIME message, the photos are attached as the png files
+func (n *Notifier) buildMessage(subject string, entries []entry) ([]byte, error) {
+	var text strings.Builder
+	var photos [][]byte
+	for i, e := range entries {
+		if i > 0 {
+			text.WriteString("\r\n----\r\n\r\n")
+		}
+
+		if len(entries) > 1 {
+			text.WriteString(e.time.Format(time.RFC3339) + "\r\n")
+		}
+
+		if e.text != "" {
+			text.WriteString(strings.ReplaceAll(e.text, "\n", "\r\n") + "\r\n")
+		}
+
+		if e.photo != nil {
+			photos = append(photos, e.photo)
+			text.WriteString(fmt.Sprintf("(attachment: image-%d.png)\r\n", len(photos)))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("From: " + n.from + "\r\n")
+	buf.WriteString("To: " + strings.Join(n.to, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(photos) == 0 {
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		buf.WriteString(text.String())
+		return buf.Bytes(), nil
+	}
+
+	boundary := "bbgo-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	buf.WriteString("Content-Type: multipart/mixed; boundary=" + boundary + "\r\n\r\n")
+	buf.WriteString("--" + boundary + "\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	buf.WriteString(text.String() + "\r\n")
+
+	for i, photo := range photos {
+		buf.WriteString("--" + boundary + "\r\n")
+		buf.WriteString("Content-Type: image/png\r\n")
+		buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+		buf.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=\"image-%d.png\"\r\n\r\n", i+1))
+
+		encoded := base64.StdEncoding.EncodeToString(photo)
+		for _, line := range util.StringSplitByLength(encoded, 76) {
+			buf.WriteString(line + "\r\n")
+		}
+	}
+
+	buf.WriteString("--" + boundary + "--\r\n")
+	return buf.Bytes(), nil
+}
+
+func (n *Notifier) Notify(obj interface{}, args ...interface{}) {
+	n.NotifyTo("", obj, args...)
+}
+
+// NotifyTo sends the notification by email, the channel is ignored
+func (n *Notifier) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	e := entry{time: time.Now()}
+
+	switch a := obj.(type) {
+	case string:
+		e.text = fmt.Sprintf(a, util.FilterSimpleArgs(args)...)
+		e.subject = e.text
+
+	case types.PlainText:
+		e.text = a.PlainText()
+		e.subject = e.text
+
+	case types.Stringer:
+		e.text = a.String()
+		e.subject = e.text
+
+	default:
+		out, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			log.WithError(err).Errorf("unsupported email notification object: %T", obj)
+			return
+		}
+
+		e.text = string(out)
+		e.subject = fmt.Sprintf("%T", obj)
+	}
+
+	n.queue(e)
+}
+
+func (n *Notifier) SendPhoto(buffer *bytes.Buffer) {
+	n.SendPhotoTo("", buffer)
+}
+
+func (n *Notifier) SendPhotoTo(channel string, buffer *bytes.Buffer) {
+	n.queue(entry{
+		time:    time.Now(),
+		subject: "photo",
+		photo:   append([]byte(nil), buffer.Bytes()...),
+	})
+}
+
+func (n *Notifier) queue(e entry) {
+	select {
+	case n.entryC <- e:
+	default:
+		log.Errorf("email notification queue is full, notification is dropped")
+	}
+}
@@ -0,0 +1,72 @@
+package emailnotifier
+
+import (
+	"bytes"
+	"context"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mailRecorder struct {
+	mu    sync.Mutex
+	mails []string
+}
+
+func (r *mailRecorder) send(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mails = append(r.mails, string(msg))
+	return nil
+}
+
+func (r *mailRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.mails...)
+}
+
+func TestNotifier(t *testing.T) {
+	recorder := &mailRecorder{}
+	notifier := New(context.Background(), "localhost:25", "bbgo@example.com", []string{"ops@example.com"},
+		OptionSendMailFunc(recorder.send))
+
+	notifier.Notify("position closed: %s", "BTCUSDT")
+
+	assert.Eventually(t, func() bool { return len(recorder.get()) == 1 }, time.Second, 10*time.Millisecond)
+
+	mail := recorder.get()[0]
+	assert.Contains(t, mail, "To: ops@example.com\r\n")
+	assert.Contains(t, mail, "Subject: [bbgo] position closed: BTCUSDT\r\n")
+	assert.True(t, strings.HasSuffix(mail, "position closed: BTCUSDT\r\n"))
+}
+
+func TestNotifier_Digest(t *testing.T) {
+	recorder := &mailRecorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	notifier := New(ctx, "localhost:25", "bbgo@example.com", []string{"ops@example.com"},
+		OptionSendMailFunc(recorder.send),
+		OptionDigest(time.Hour))
+
+	notifier.Notify("first")
+	notifier.Notify(map[string]string{"symbol": "BTCUSDT"})
+	notifier.SendPhoto(bytes.NewBufferString("png"))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, recorder.get())
+
+	// the digest is flushed when the notifier is stopped
+	cancel()
+	assert.Eventually(t, func() bool { return len(recorder.get()) == 1 }, time.Second, 10*time.Millisecond)
+
+	mail := recorder.get()[0]
+	assert.Contains(t, mail, "digest: 3 notifications")
+	assert.Contains(t, mail, "first\r\n")
+	assert.Contains(t, mail, `"symbol": "BTCUSDT"`)
+	assert.Contains(t, mail, "Content-Type: multipart/mixed")
+	assert.Contains(t, mail, "cG5n\r\n")
+}
@@ -0,0 +1,308 @@
+package telegramnotifier
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/tucnak/telebot.v2"
+
+	"github.com/c9s/bbgo/pkg/dynamic"
+	"github.com/c9s/bbgo/pkg/livenote"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var typeNamePrefixRE = regexp.MustCompile(`^\*?([a-zA-Z0-9_]+\.)?`)
+
+// liveNoteMessages is the posted messages of a live note
+type liveNoteMessages struct {
+	note     *livenote.LiveNote
+	messages []*telebot.Message
+	pinned   bool
+}
+
+// PostLiveNote posts the object as a telegram message to the chats once,
+// and then edits the same message when the object is posted again.
+//
+// The comments and the mentions are sent as the replies of the live note message,
+// so that they are shown as a thread of the message.
+// The channel option is the chat id, when it's not given, the live note is posted to
+// the subscribers (broadcast mode) or the authorized chats.
+func (n *Notifier) PostLiveNote(obj livenote.Object, opts ...livenote.Option) error {
+	var firstTimeHandles []string
+	var commentHandles []string
+	var comments []string
+	var shouldCompare bool
+	var shouldPin bool
+	var ttl time.Duration = 0
+	var channel string
+
+	for _, opt := range opts {
+		switch val := opt.(type) {
+		case *livenote.OptionOneTimeMention:
+			firstTimeHandles = append(firstTimeHandles, val.Users...)
+		case *livenote.OptionComment:
+			comments = append(comments, val.Text)
+			commentHandles = append(commentHandles, val.Users...)
+		case *livenote.OptionCompare:
+			shouldCompare = val.Value
+		case *livenote.OptionPin:
+			shouldPin = val.Value
+		case *livenote.OptionTimeToLive:
+			ttl = val.Duration
+		case *livenote.OptionChannel:
+			channel = val.Channel
+		}
+	}
+
+	var curObj, prevObj any
+	if shouldCompare {
+		if prevNote := n.liveNotePool.Get(obj); prevNote != nil {
+			prevObj = prevNote.Object
+		}
+	}
+
+	note := n.liveNotePool.Update(obj)
+	curObj = note.Object
+
+	if ttl > 0 {
+		note.SetTimeToLive(ttl)
+	}
+
+	if shouldCompare && prevObj != nil {
+		diffs, err := dynamic.Compare(curObj, prevObj)
+		if err != nil {
+			log.WithError(err).Warnf("unable to compare objects: %T and %T", curObj, prevObj)
+		} else if comment := diffsToComment(curObj, diffs); len(comment) > 0 {
+			comments = append(comments, comment)
+		}
+	}
+
+	var text string
+	switch a := note.Object.(type) {
+	case types.PlainText:
+		text = a.PlainText()
+	case types.Stringer:
+		text = a.String()
+	default:
+		return fmt.Errorf("livenote object does not support types.PlainText or types.Stringer interface")
+	}
+
+	ctx := context.Background()
+
+	n.liveNoteMu.Lock()
+	defer n.liveNoteMu.Unlock()
+
+	n.removeExpiredLiveNotes(ctx, time.Now())
+
+	posted, ok := n.liveNoteMessages[note.ObjectID()]
+	if ok && posted.note == note {
+		for _, msg := range posted.messages {
+			if err := apiLimiter.Wait(ctx); err != nil {
+				return err
+			}
+
+			if _, err := n.bot.Edit(msg, text); err != nil && !isMessageNotModified(err) {
+				return err
+			}
+		}
+
+		if len(comments) > 0 {
+			n.replyLiveNote(ctx, posted.messages, joinComments(commentHandles, comments))
+		}
+
+		return nil
+	}
+
+	// the note is replaced by a new one, the old messages are not updated anymore
+	if ok {
+		n.removeLiveNote(ctx, note.ObjectID(), posted)
+	}
+
+	chats, err := n.liveNoteChats(channel)
+	if err != nil {
+		return err
+	}
+
+	if len(chats) == 0 {
+		return fmt.Errorf("no telegram chat to post the live note")
+	}
+
+	var messages []*telebot.Message
+	for _, chat := range chats {
+		if err := apiLimiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		msg, err := n.bot.Send(chat, text)
+		if err != nil {
+			log.WithError(err).
+				WithField("chat", chat.ID).
+				Errorf("telegram api error: %s", err.Error())
+			return err
+		}
+
+		messages = append(messages, msg)
+	}
+
+	posted = &liveNoteMessages{note: note, messages: messages}
+	n.liveNoteMessages[note.ObjectID()] = posted
+
+	note.SetChannelID(strconv.FormatInt(messages[0].Chat.ID, 10))
+	note.SetMessageID(strconv.Itoa(messages[0].ID))
+	note.SetPostedTime(time.Now())
+
+	if shouldPin {
+		note.SetPin(true)
+		posted.pinned = true
+
+		for _, msg := range messages {
+			if err := apiLimiter.Wait(ctx); err != nil {
+				return err
+			}
+
+			if err := n.bot.Pin(msg, telebot.Silent); err != nil {
+				log.WithError(err).Warnf("unable to pin the telegram message: %d", msg.ID)
+			}
+		}
+	}
+
+	if len(firstTimeHandles) > 0 {
+		n.replyLiveNote(ctx, messages, joinHandles(firstTimeHandles))
+	}
+
+	if len(comments) > 0 {
+		n.replyLiveNote(ctx, messages, joinComments(commentHandles, comments))
+	}
+
+	return nil
+}
+
+// removeExpiredLiveNotes removes the messages of the expired live notes
+func (n *Notifier) removeExpiredLiveNotes(ctx context.Context, now time.Time) {
+	for id, posted := range n.liveNoteMessages {
+		if posted.note.IsExpired(now) {
+			n.removeLiveNote(ctx, id, posted)
+		}
+	}
+}
+
+// removeLiveNote unpins the messages of the live note and stops tracking them
+func (n *Notifier) removeLiveNote(ctx context.Context, id string, posted *liveNoteMessages) {
+	delete(n.liveNoteMessages, id)
+
+	if !posted.pinned {
+		return
+	}
+
+	for _, msg := range posted.messages {
+		if err := apiLimiter.Wait(ctx); err != nil {
+			return
+		}
+
+		if err := n.bot.Unpin(msg.Chat, msg.ID); err != nil {
+			log.WithError(err).Warnf("unable to unpin the telegram message: %d", msg.ID)
+		}
+	}
+}
+
+// replyLiveNote sends the text as the reply of the live note messages
+func (n *Notifier) replyLiveNote(ctx context.Context, messages []*telebot.Message, text string) {
+	for _, msg := range messages {
+		if err := apiLimiter.Wait(ctx); err != nil {
+			return
+		}
+
+		if _, err := n.bot.Reply(msg, text); err != nil {
+			log.WithError(err).Errorf("unable to reply the telegram message: %d", msg.ID)
+		}
+	}
+}
+
+// liveNoteChats returns the chats to post the live note
+func (n *Notifier) liveNoteChats(channel string) (chats []*telebot.Chat, err error) {
+	if channel != "" {
+		chat, err := n.bot.ChatByID(channel)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*telebot.Chat{chat}, nil
+	}
+
+	if n.broadcast {
+		for chatID := range n.Subscribers {
+			chat, err := n.bot.ChatByID(strconv.FormatInt(chatID, 10))
+			if err != nil {
+				log.WithError(err).Error("can not get chat by ID")
+				continue
+			}
+
+			chats = append(chats, chat)
+		}
+
+		return chats, nil
+	}
+
+	for _, chat := range n.Chats {
+		chats = append(chats, chat)
+	}
+
+	return chats, nil
+}
+
+func isMessageNotModified(err error) bool {
+	return err == telebot.ErrMessageNotModified || err == telebot.ErrSameMessageContent
+}
+
+func joinHandles(handles []string) string {
+	var tags []string
+	for _, handle := range handles {
+		if !strings.HasPrefix(handle, "@") {
+			handle = "@" + handle
+		}
+
+		tags = append(tags, handle)
+	}
+
+	return strings.Join(tags, " ")
+}
+
+func joinComments(handles, comments []string) (text string) {
+	if len(handles) > 0 {
+		text = joinHandles(handles) + " "
+	}
+
+	return text + strings.Join(comments, "\n")
+}
+
+func diffsToComment(obj any, diffs []dynamic.Diff) (text string) {
+	if len(diffs) == 0 {
+		return text
+	}
+
+	text += fmt.Sprintf("%s updated\n", objectName(obj))
+
+	for _, diff := range diffs {
+		text += fmt.Sprintf("- %s: %s transited to %s\n", diff.Field, diff.Before, diff.After)
+	}
+
+	return text
+}
+
+func objectName(obj any) string {
+	type labelInf interface {
+		Label() string
+	}
+
+	if ll, ok := obj.(labelInf); ok {
+		return ll.Label()
+	}
+
+	typeName := fmt.Sprintf("%T", obj)
+	typeName = typeNamePrefixRE.ReplaceAllString(typeName, "")
+	return typeName
+}
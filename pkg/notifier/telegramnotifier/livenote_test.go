@@ -0,0 +1,150 @@
+package telegramnotifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/tucnak/telebot.v2"
+
+	"github.com/c9s/bbgo/pkg/livenote"
+)
+
+type testObject struct {
+	ID    string
+	Price float64
+}
+
+func (o *testObject) ObjectID() string {
+	return o.ID
+}
+
+func (o *testObject) PlainText() string {
+	return fmt.Sprintf("price: %v", o.Price)
+}
+
+type apiCall struct {
+	method string
+	params map[string]string
+}
+
+func newTestBot(t *testing.T) (*telebot.Bot, func() []apiCall) {
+	var mu sync.Mutex
+	var calls []apiCall
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&params)
+
+		mu.Lock()
+		calls = append(calls, apiCall{method: path.Base(r.URL.Path), params: params})
+		messageID := len(calls)
+		mu.Unlock()
+
+		var result any = true
+		switch path.Base(r.URL.Path) {
+		case "sendMessage", "editMessageText":
+			result = map[string]any{
+				"message_id": messageID,
+				"date":       time.Now().Unix(),
+				"chat":       map[string]any{"id": 1234, "type": "private"},
+				"text":       params["text"],
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	bot, err := telebot.NewBot(telebot.Settings{URL: server.URL, Token: "token", Offline: true})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return bot, func() []apiCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]apiCall(nil), calls...)
+	}
+}
+
+func TestNotifier_PostLiveNote(t *testing.T) {
+	bot, getCalls := newTestBot(t)
+
+	notifier := New(bot)
+	notifier.AddChat(&telebot.Chat{ID: 1234, Type: telebot.ChatPrivate})
+
+	obj := &testObject{ID: "grid-1", Price: 10}
+	err := notifier.PostLiveNote(obj, livenote.Pin(true), livenote.OneTimeMention("alice"))
+	assert.NoError(t, err)
+
+	calls := getCalls()
+	if assert.Len(t, calls, 3) {
+		assert.Equal(t, "sendMessage", calls[0].method)
+		assert.Equal(t, "pinChatMessage", calls[1].method)
+		assert.Equal(t, "1", calls[1].params["message_id"])
+		assert.Equal(t, "sendMessage", calls[2].method)
+		assert.Equal(t, "@alice", calls[2].params["text"])
+		assert.Equal(t, "1", calls[2].params["reply_to_message_id"])
+	}
+
+	note := notifier.liveNotePool.Get(obj)
+	if assert.NotNil(t, note) {
+		assert.Equal(t, "1", note.MessageID)
+		assert.Equal(t, "1234", note.ChannelID)
+		assert.True(t, note.Pin)
+	}
+
+	// the second post edits the message and replies the comment
+	err = notifier.PostLiveNote(&testObject{ID: "grid-1", Price: 20}, livenote.Comment("price changed", "bob"))
+	assert.NoError(t, err)
+
+	calls = getCalls()[3:]
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, "editMessageText", calls[0].method)
+		assert.Equal(t, "1", calls[0].params["message_id"])
+		assert.Equal(t, "price: 20", calls[0].params["text"])
+		assert.Equal(t, "sendMessage", calls[1].method)
+		assert.Equal(t, "@bob price changed", calls[1].params["text"])
+		assert.Equal(t, "1", calls[1].params["reply_to_message_id"])
+	}
+
+	// the expired note is unpinned and posted as a new message
+	note.SetTimeToLive(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	err = notifier.PostLiveNote(&testObject{ID: "grid-1", Price: 30})
+	assert.NoError(t, err)
+
+	calls = getCalls()[5:]
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, "unpinChatMessage", calls[0].method)
+		assert.Equal(t, "1", calls[0].params["message_id"])
+		assert.Equal(t, "sendMessage", calls[1].method)
+	}
+
+	if posted, ok := notifier.liveNoteMessages["grid-1"]; assert.True(t, ok) && assert.Len(t, posted.messages, 1) {
+		assert.Equal(t, 7, posted.messages[0].ID)
+		assert.False(t, posted.pinned)
+	}
+
+	// the other expired notes are removed when a live note is posted
+	notifier.liveNoteMessages["grid-1"].note.SetTimeToLive(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	err = notifier.PostLiveNote(&testObject{ID: "grid-2", Price: 10})
+	assert.NoError(t, err)
+	assert.Len(t, notifier.liveNoteMessages, 1)
+	assert.Contains(t, notifier.liveNoteMessages, "grid-2")
+}
+
+func TestJoinComments(t *testing.T) {
+	assert.Equal(t, "@alice @bob hello\nworld", joinComments([]string{"alice", "@bob"}, []string{"hello", "world"}))
+	assert.Equal(t, "hello", joinComments(nil, []string{"hello"}))
+}
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
 	"gopkg.in/tucnak/telebot.v2"
 
+	"github.com/c9s/bbgo/pkg/livenote"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -37,6 +39,13 @@ type Notifier struct {
 	broadcast bool
 
 	taskC chan notifyTask
+
+	liveNotePool *livenote.Pool
+
+	// liveNoteMessages stores the posted messages of the live notes by the object id,
+	// one live note could be posted to multiple chats
+	liveNoteMessages map[string]*liveNoteMessages
+	liveNoteMu       sync.Mutex
 }
 
 type Option func(notifier *Notifier)
@@ -54,6 +63,9 @@ func New(bot *telebot.Bot, options ...Option) *Notifier {
 		Chats:       make(map[int64]*telebot.Chat),
 		Subscribers: make(map[int64]time.Time),
 		taskC:       make(chan notifyTask, 100),
+
+		liveNotePool:     livenote.NewPool(100),
+		liveNoteMessages: make(map[string]*liveNoteMessages),
 	}
 
 	for _, o := range options {
@@ -0,0 +1,547 @@
+package webhooknotifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+var log = logrus.WithField("service", "webhook")
+
+const (
+	// SignatureHeader is the header of the hex encoded HMAC-SHA256 signature of "{timestamp}.{body}"
+	SignatureHeader = "X-BBGO-Signature"
+
+	// TimestampHeader is the header of the unix timestamp (in seconds) that is used in the signature
+	TimestampHeader = "X-BBGO-Timestamp"
+)
+
+const (
+	defaultQueueSize     = 1000
+	defaultBatchSize     = 1
+	defaultFlushInterval = 3 * time.Second
+	defaultMaxRetries    = 5
+	defaultRetryInterval = time.Second
+	defaultTimeout       = 10 * time.Second
+
+	// maxPendingBatches is the max number of the batches waiting for the delivery, the oldest batch is dropped when it's full
+	maxPendingBatches = 100
+
+	// maxRetryBatches is the max number of the failed batches in the retry queue, the oldest batch is dropped when it's full
+	maxRetryBatches = 100
+)
+
+// Event is the json object of a notification
+type Event struct {
+	// Type is the object type, e.g., message, trade, order, submitOrder, position and profit
+	Type string `json:"type"`
+
+	Channel string    `json:"channel,omitempty"`
+	Time    time.Time `json:"time"`
+
+	// Message is the text of the notification, it's set when the notification is a text message or
+	// the object can be rendered as plain text
+	Message string `json:"message,omitempty"`
+
+	// Object is the notified object, it's serialized when the notification is sent,
+	// so that the later changes of the object won't affect the event
+	Object json.RawMessage `json:"object,omitempty"`
+
+	// Attachments are the objects passed with the text message
+	Attachments []json.RawMessage `json:"attachments,omitempty"`
+
+	// Photo is the base64 encoded image
+	Photo string `json:"photo,omitempty"`
+}
+
+// Payload is the request body of the webhook
+type Payload struct {
+	Events []Event `json:"events"`
+}
+
+// retryBatch is a failed batch that is waiting in the retry queue
+type retryBatch struct {
+	events   []Event
+	retries  int
+	interval time.Duration
+	nextTime time.Time
+}
+
+// Notifier posts the notifications to the webhook endpoint as JSON.
+//
+// The events are batched by the batch size and the flush interval in the batcher goroutine,
+// and the batches are posted by the sender goroutine, so that receiving the events never waits for the endpoint.
+// The failed batches are moved to the retry queue and retried with the exponential back-off until the max retries is reached,
+// so that the new events are not blocked by the unavailable endpoint.
+type Notifier struct {
+	url    string
+	secret string
+
+	headers map[string]string
+
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryInterval time.Duration
+
+	client *http.Client
+
+	eventC chan Event
+
+	// batchC passes the batches from the batcher to the sender
+	batchC chan []Event
+
+	// finalC passes the remaining batches from the batcher to the sender on shutdown
+	finalC chan [][]Event
+
+	// retries is only accessed by the sender
+	retries []*retryBatch
+
+	closeC    chan struct{}
+	closeOnce sync.Once
+	doneC     chan struct{}
+}
+
+type Option func(notifier *Notifier)
+
+// OptionSecret sets the secret of the HMAC signature
+func OptionSecret(secret string) Option {
+	return func(notifier *Notifier) {
+		notifier.secret = secret
+	}
+}
+
+func OptionHeaders(headers map[string]string) Option {
+	return func(notifier *Notifier) {
+		notifier.headers = headers
+	}
+}
+
+// OptionBatch sets the max number of events in a request and the interval to flush the incomplete batch
+func OptionBatch(size int, flushInterval time.Duration) Option {
+	return func(notifier *Notifier) {
+		if size > 0 {
+			notifier.batchSize = size
+		}
+
+		if flushInterval > 0 {
+			notifier.flushInterval = flushInterval
+		}
+	}
+}
+
+// OptionRetry sets the max retries and the initial retry interval
+func OptionRetry(maxRetries int, interval time.Duration) Option {
+	return func(notifier *Notifier) {
+		notifier.maxRetries = maxRetries
+		if interval > 0 {
+			notifier.retryInterval = interval
+		}
+	}
+}
+
+func OptionQueueSize(size int) Option {
+	return func(notifier *Notifier) {
+		notifier.eventC = make(chan Event, size)
+	}
+}
+
+func OptionTimeout(timeout time.Duration) Option {
+	return func(notifier *Notifier) {
+		notifier.client.Timeout = timeout
+	}
+}
+
+// New returns a webhook notifier that posts the events to the url
+func New(ctx context.Context, url string, options ...Option) *Notifier {
+	notifier := &Notifier{
+		url:           url,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		maxRetries:    defaultMaxRetries,
+		retryInterval: defaultRetryInterval,
+		client:        &http.Client{Timeout: defaultTimeout},
+		eventC:        make(chan Event, defaultQueueSize),
+		batchC:        make(chan []Event),
+		finalC:        make(chan [][]Event, 1),
+		closeC:        make(chan struct{}),
+		doneC:         make(chan struct{}),
+	}
+
+	for _, o := range options {
+		o(notifier)
+	}
+
+	go notifier.batcher(ctx)
+	go notifier.sender(ctx)
+	return notifier
+}
+
+// Close stops the goroutines and waits until the queued events are flushed, or the context is done
+func (n *Notifier) Close(ctx context.Context) error {
+	n.closeOnce.Do(func() {
+		close(n.closeC)
+	})
+
+	select {
+	case <-n.doneC:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// batcher collects the events into batches and passes them to the sender,
+// the batches are kept in the pending queue while the sender is busy, so that the event queue is always drained.
+func (n *Notifier) batcher(ctx context.Context) {
+	ticker := time.NewTicker(n.flushInterval)
+	defer ticker.Stop()
+
+	var batch []Event
+	var pending [][]Event
+	for {
+		// the send case is disabled by the nil channel when there is no pending batch
+		var batchC chan []Event
+		var next []Event
+		if len(pending) > 0 {
+			batchC = n.batchC
+			next = pending[0]
+		}
+
+		select {
+		case <-ctx.Done():
+			n.finish(batch, pending)
+			return
+
+		case <-n.closeC:
+			n.finish(batch, pending)
+			return
+
+		case event := <-n.eventC:
+			batch = append(batch, event)
+			if len(batch) >= n.batchSize {
+				pending = appendPending(pending, batch)
+				batch = nil
+			}
+
+		case <-ticker.C:
+			if len(batch) > 0 {
+				pending = appendPending(pending, batch)
+				batch = nil
+			}
+
+		case batchC <- next:
+			pending = pending[1:]
+		}
+	}
+}
+
+func appendPending(pending [][]Event, batch []Event) [][]Event {
+	if len(pending) >= maxPendingBatches {
+		log.Errorf("webhook pending queue is full, %d events are dropped", len(pending[0]))
+		pending = pending[1:]
+	}
+
+	return append(pending, batch)
+}
+
+// finish drains the queued events and passes all the remaining batches to the sender
+func (n *Notifier) finish(batch []Event, pending [][]Event) {
+drain:
+	for {
+		select {
+		case event := <-n.eventC:
+			batch = append(batch, event)
+		default:
+			break drain
+		}
+	}
+
+	for len(batch) > 0 {
+		size := n.batchSize
+		if size > len(batch) {
+			size = len(batch)
+		}
+
+		pending = append(pending, batch[:size])
+		batch = batch[size:]
+	}
+
+	n.finalC <- pending
+}
+
+// sender posts the batches and retries the failed batches, it's the only goroutine that waits for the endpoint
+func (n *Notifier) sender(ctx context.Context) {
+	defer close(n.doneC)
+
+	retryTicker := time.NewTicker(n.retryInterval)
+	defer retryTicker.Stop()
+
+	for {
+		select {
+		case batch := <-n.batchC:
+			n.flush(ctx, batch)
+
+		case now := <-retryTicker.C:
+			n.retry(ctx, now)
+
+		case batches := <-n.finalC:
+			n.shutdown(batches)
+			return
+		}
+	}
+}
+
+// shutdown delivers the failed batches and the remaining batches once,
+// each batch is delivered with its own deadline, since the notifier context is usually canceled on shutdown.
+func (n *Notifier) shutdown(batches [][]Event) {
+	for _, rb := range n.retries {
+		if err := n.deliverWithTimeout(rb.events); err != nil {
+			log.WithError(err).Errorf("webhook retry failed on shutdown, %d events are dropped", len(rb.events))
+		}
+	}
+
+	n.retries = nil
+
+	for _, batch := range batches {
+		if err := n.deliverWithTimeout(batch); err != nil {
+			log.WithError(err).Errorf("webhook delivery failed on shutdown, %d events are dropped", len(batch))
+		}
+	}
+}
+
+func (n *Notifier) deliverWithTimeout(events []Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.client.Timeout)
+	defer cancel()
+
+	_, err := n.deliver(ctx, events)
+	return err
+}
+
+// flush delivers the batch once, the batch is moved to the retry queue if the request is retryable
+func (n *Notifier) flush(ctx context.Context, batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+
+	retryable, err := n.deliver(ctx, batch)
+	if err == nil {
+		return
+	}
+
+	if !retryable || n.maxRetries <= 0 {
+		log.WithError(err).Errorf("webhook delivery failed, %d events are dropped", len(batch))
+		return
+	}
+
+	if len(n.retries) >= maxRetryBatches {
+		log.Errorf("webhook retry queue is full, %d events are dropped", len(n.retries[0].events))
+		n.retries = n.retries[1:]
+	}
+
+	log.WithError(err).Warnf("webhook request failed, retrying in %s", n.retryInterval)
+	n.retries = append(n.retries, &retryBatch{
+		events:   batch,
+		interval: n.retryInterval,
+		nextTime: time.Now().Add(n.retryInterval),
+	})
+}
+
+// retry delivers the failed batches that are due, the retry interval is doubled after each failure
+func (n *Notifier) retry(ctx context.Context, now time.Time) {
+	var pending []*retryBatch
+	for _, rb := range n.retries {
+		if now.Before(rb.nextTime) {
+			pending = append(pending, rb)
+			continue
+		}
+
+		retryable, err := n.deliver(ctx, rb.events)
+		if err == nil {
+			continue
+		}
+
+		rb.retries++
+		if !retryable || rb.retries >= n.maxRetries {
+			log.WithError(err).Errorf("webhook delivery failed after %d retries, %d events are dropped", rb.retries, len(rb.events))
+			continue
+		}
+
+		rb.interval *= 2
+		rb.nextTime = now.Add(rb.interval)
+		log.WithError(err).Warnf("webhook request failed, retrying in %s (%d/%d)", rb.interval, rb.retries+1, n.maxRetries)
+		pending = append(pending, rb)
+	}
+
+	n.retries = pending
+}
+
+// deliver posts the events once, the error is retryable if the request can be retried later
+func (n *Notifier) deliver(ctx context.Context, events []Event) (retryable bool, err error) {
+	body, err := json.Marshal(Payload{Events: events})
+	if err != nil {
+		return false, err
+	}
+
+	return n.post(ctx, body)
+}
+
+func (n *Notifier) post(ctx context.Context, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.headers {
+		req.Header.Set(k, v)
+	}
+
+	if n.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(n.secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		retryable = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("webhook response status: %s", resp.Status)
+	}
+
+	return false, nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of "{timestamp}.{body}",
+// the receiver should verify the signature with the same secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *Notifier) Notify(obj interface{}, args ...interface{}) {
+	n.NotifyTo("", obj, args...)
+}
+
+func (n *Notifier) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	event := Event{
+		Type:    "message",
+		Channel: channel,
+		Time:    time.Now(),
+	}
+
+	switch a := obj.(type) {
+	case string:
+		event.Message = fmt.Sprintf(a, util.FilterSimpleArgs(args)...)
+		for _, o := range filterObjects(args) {
+			data, err := json.Marshal(o)
+			if err != nil {
+				log.WithError(err).Errorf("unable to serialize the webhook attachment: %T", o)
+				continue
+			}
+
+			event.Attachments = append(event.Attachments, data)
+		}
+
+	default:
+		data, err := json.Marshal(obj)
+		if err != nil {
+			log.WithError(err).Errorf("unable to serialize the webhook object: %T", obj)
+			return
+		}
+
+		event.Type = ObjectType(obj)
+		event.Object = data
+
+		switch o := obj.(type) {
+		case types.PlainText:
+			event.Message = o.PlainText()
+		case types.Stringer:
+			event.Message = o.String()
+		}
+	}
+
+	n.queue(event)
+}
+
+func (n *Notifier) SendPhoto(buffer *bytes.Buffer) {
+	n.SendPhotoTo("", buffer)
+}
+
+func (n *Notifier) SendPhotoTo(channel string, buffer *bytes.Buffer) {
+	n.queue(Event{
+		Type:    "photo",
+		Channel: channel,
+		Time:    time.Now(),
+		Photo:   base64.StdEncoding.EncodeToString(buffer.Bytes()),
+	})
+}
+
+func (n *Notifier) queue(event Event) {
+	select {
+	case n.eventC <- event:
+	default:
+		log.Errorf("webhook event queue is full, %s event is dropped", event.Type)
+	}
+}
+
+// filterObjects returns the non-simple args, which are the objects passed with the text message
+func filterObjects(args []interface{}) (objects []interface{}) {
+	for _, arg := range args {
+		if arg == nil {
+			continue
+		}
+
+		if len(util.FilterSimpleArgs([]interface{}{arg})) == 0 {
+			objects = append(objects, arg)
+		}
+	}
+
+	return objects
+}
+
+// ObjectType returns the type name of the object in lower camel case, e.g., types.SubmitOrder -> submitOrder
+func ObjectType(obj interface{}) string {
+	rt := reflect.TypeOf(obj)
+	if rt == nil {
+		return "nil"
+	}
+
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	name := rt.Name()
+	if name == "" {
+		return rt.Kind().String()
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
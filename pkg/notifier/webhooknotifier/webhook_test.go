@@ -0,0 +1,254 @@
+package webhooknotifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestNotifier(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	var payloads []Payload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests++
+
+		// the first request fails and should be retried
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		assert.Equal(t, Sign("secret", r.Header.Get(TimestampHeader), body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "bbgo", r.Header.Get("X-Source"))
+
+		var payload Payload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notifier := New(ctx, server.URL,
+		OptionSecret("secret"),
+		OptionHeaders(map[string]string{"X-Source": "bbgo"}),
+		OptionBatch(3, time.Hour),
+		OptionRetry(2, time.Millisecond))
+
+	trade := types.Trade{ID: 1, Symbol: "BTCUSDT", Price: fixedpoint.NewFromInt(20000)}
+	notifier.Notify("hello %s", "world", &trade)
+	notifier.Notify(trade)
+	notifier.NotifyTo("#trades", &types.SubmitOrder{Symbol: "BTCUSDT"})
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(payloads) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, 2, requests)
+	events := payloads[0].Events
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, "message", events[0].Type)
+		assert.Equal(t, "hello world", events[0].Message)
+		assert.Len(t, events[0].Attachments, 1)
+
+		assert.Equal(t, "trade", events[1].Type)
+		var decoded map[string]interface{}
+		assert.NoError(t, json.Unmarshal(events[1].Object, &decoded))
+		assert.Equal(t, "BTCUSDT", decoded["symbol"])
+		assert.Equal(t, 20000.0, decoded["price"])
+
+		assert.Equal(t, "submitOrder", events[2].Type)
+		assert.Equal(t, "#trades", events[2].Channel)
+	}
+}
+
+func TestNotifier_NonRetryableError(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := New(context.Background(), server.URL, OptionRetry(3, time.Millisecond))
+	retryable, err := notifier.deliver(context.Background(), []Event{{Type: "message", Message: "hello"}})
+	assert.Error(t, err)
+	assert.False(t, retryable)
+	assert.Equal(t, 1, requests)
+}
+
+func TestNotifier_RetryQueue(t *testing.T) {
+	var mu sync.Mutex
+	var messages []string
+	var failing = true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload Payload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if failing && payload.Events[0].Message == "first" {
+			failing = false
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		for _, event := range payload.Events {
+			messages = append(messages, event.Message)
+		}
+	}))
+	defer server.Close()
+
+	notifier := New(context.Background(), server.URL, OptionRetry(3, 50*time.Millisecond))
+	defer notifier.Close(context.Background())
+
+	// the failed batch is moved to the retry queue, so the second event is delivered before the retry
+	notifier.Notify("first")
+	notifier.Notify("second")
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(messages) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"second", "first"}, messages)
+}
+
+func TestNotifier_Close(t *testing.T) {
+	var mu sync.Mutex
+	var events int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload Payload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		events += len(payload.Events)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	notifier := New(ctx, server.URL, OptionBatch(100, time.Hour))
+	for i := 0; i < 5; i++ {
+		notifier.Notify("hello")
+	}
+
+	// the queued events are flushed with a fresh context even if the worker context is canceled
+	cancel()
+	assert.NoError(t, notifier.Close(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 5, events)
+}
+
+func TestNotifier_SlowEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	var events int
+
+	releaseC := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-releaseC
+
+		var payload Payload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		events += len(payload.Events)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	notifier := New(context.Background(), server.URL, OptionQueueSize(2))
+	defer notifier.Close(context.Background())
+
+	// the event queue is drained while the first request is blocked
+	for i := 0; i < 20; i++ {
+		notifier.Notify("hello")
+		assert.Eventually(t, func() bool {
+			return len(notifier.eventC) == 0
+		}, time.Second, time.Millisecond)
+	}
+
+	close(releaseC)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return events == 20
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNotifier_CloseTimeout(t *testing.T) {
+	var mu sync.Mutex
+	var messages []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload Payload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		// the request of the first event always times out
+		if payload.Events[0].Message == "first" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+
+		mu.Lock()
+		messages = append(messages, payload.Events[0].Message)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	notifier := New(context.Background(), server.URL, OptionBatch(1, time.Hour), OptionTimeout(100*time.Millisecond))
+	notifier.Notify("first")
+	notifier.Notify("second")
+	notifier.Notify("third")
+
+	// each batch has its own deadline, so the timed out batch doesn't fail the others
+	assert.NoError(t, notifier.Close(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"second", "third"}, messages)
+}
+
+func TestObjectType(t *testing.T) {
+	assert.Equal(t, "submitOrder", ObjectType(&types.SubmitOrder{}))
+	assert.Equal(t, "position", ObjectType(types.Position{}))
+	assert.Equal(t, "map", ObjectType(map[string]string{}))
+}
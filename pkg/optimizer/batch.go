@@ -0,0 +1,464 @@
+package optimizer
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/c9s/bbgo/pkg/backtest"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+var batchTaskLabels = []string{"config", "symbol", "startTime", "endTime"}
+
+type BatchDateRange struct {
+	StartTime types.LooseFormatTime  `json:"startTime" yaml:"startTime"`
+	EndTime   *types.LooseFormatTime `json:"endTime,omitempty" yaml:"endTime,omitempty"`
+}
+
+// BatchConfig is the manifest of the backtest batch, the backtests of configs × symbols × date ranges are executed.
+// The symbols and the date ranges are optional, the values of the config files are used if they are not given.
+type BatchConfig struct {
+	Executor   *ExecutorConfig  `json:"executor" yaml:"executor"`
+	Configs    []string         `json:"configs" yaml:"configs"`
+	Symbols    []string         `json:"symbols,omitempty" yaml:"symbols,omitempty"`
+	DateRanges []BatchDateRange `json:"dateRanges,omitempty" yaml:"dateRanges,omitempty"`
+}
+
+func LoadBatchConfig(yamlConfigFileName string) (*BatchConfig, error) {
+	configYaml, err := os.ReadFile(yamlConfigFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchConfig BatchConfig
+	if err := yaml.Unmarshal(configYaml, &batchConfig); err != nil {
+		return nil, err
+	}
+
+	if len(batchConfig.Configs) == 0 {
+		return nil, fmt.Errorf("no backtest configs are defined in %s", yamlConfigFileName)
+	}
+
+	if batchConfig.Executor == nil {
+		batchConfig.Executor = defaultExecutorConfig
+	}
+
+	if batchConfig.Executor.Type == "" {
+		batchConfig.Executor.Type = "local"
+	}
+
+	// the backtest batch only runs the backtests in the local processes
+	if batchConfig.Executor.Type != "local" {
+		return nil, fmt.Errorf("executor type %q is not supported by the backtest batch, only the local executor is supported", batchConfig.Executor.Type)
+	}
+
+	if batchConfig.Executor.LocalExecutorConfig == nil {
+		batchConfig.Executor.LocalExecutorConfig = defaultLocalExecutorConfig
+	}
+
+	return &batchConfig, nil
+}
+
+// BuildTasks builds the backtest tasks of the matrix, the task params are the config file, the symbol,
+// the start time and the end time.
+func (c *BatchConfig) BuildTasks() (tasks []BacktestTask, err error) {
+	symbols := c.Symbols
+	if len(symbols) == 0 {
+		symbols = []string{""}
+	}
+
+	dateRanges := c.DateRanges
+	if len(dateRanges) == 0 {
+		dateRanges = []BatchDateRange{{}}
+	}
+
+	for _, configFile := range c.Configs {
+		configJson, err := loadBatchConfigJson(configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, symbol := range symbols {
+			for _, dateRange := range dateRanges {
+				patched, err := patchBatchConfig(configJson, symbol, dateRange)
+				if err != nil {
+					return nil, fmt.Errorf("unable to patch config %s: %w", configFile, err)
+				}
+
+				tasks = append(tasks, BacktestTask{
+					ConfigJson: patched,
+					Labels:     batchTaskLabels,
+					Params:     []interface{}{configFile, symbol, formatBatchTime(dateRange.StartTime.Time()), formatBatchEndTime(dateRange.EndTime)},
+				})
+			}
+		}
+	}
+
+	return tasks, nil
+}
+
+// BuildSyncConfigs builds the configs for syncing the backtest data, the configs of the same file and symbol
+// are merged into one with the widest date range, so that the kline data are only synced once.
+func (c *BatchConfig) BuildSyncConfigs() (configs [][]byte, err error) {
+	symbols := c.Symbols
+	if len(symbols) == 0 {
+		symbols = []string{""}
+	}
+
+	var dateRange BatchDateRange
+	var openEnded bool
+	for i, r := range c.DateRanges {
+		if i == 0 || r.StartTime.Time().Before(dateRange.StartTime.Time()) {
+			dateRange.StartTime = r.StartTime
+		}
+
+		if r.EndTime == nil {
+			openEnded = true
+		} else if dateRange.EndTime == nil || r.EndTime.Time().After(dateRange.EndTime.Time()) {
+			endTime := *r.EndTime
+			dateRange.EndTime = &endTime
+		}
+	}
+
+	// the end time of the config (or now) is used if any date range has no end time
+	if openEnded {
+		dateRange.EndTime = nil
+	}
+
+	for _, configFile := range c.Configs {
+		configJson, err := loadBatchConfigJson(configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, symbol := range symbols {
+			patched, err := patchBatchConfig(configJson, symbol, dateRange)
+			if err != nil {
+				return nil, fmt.Errorf("unable to patch config %s: %w", configFile, err)
+			}
+
+			configs = append(configs, patched)
+		}
+	}
+
+	return configs, nil
+}
+
+func formatBatchTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func formatBatchEndTime(t *types.LooseFormatTime) string {
+	if t == nil {
+		return ""
+	}
+
+	return formatBatchTime(t.Time())
+}
+
+// loadBatchConfigJson loads the yaml config file as json, the notifications and the sync configs are removed
+func loadBatchConfigJson(configFile string) ([]byte, error) {
+	yamlBody, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(yamlBody, &obj); err != nil {
+		return nil, err
+	}
+
+	delete(obj, "notifications")
+	delete(obj, "sync")
+
+	if _, ok := obj["backtest"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("backtest config is not defined in %s", configFile)
+	}
+
+	return json.Marshal(obj)
+}
+
+// patchBatchConfig replaces the backtest symbols and the date range of the config,
+// the symbol fields of the strategy configs are also replaced if the symbol is given.
+func patchBatchConfig(configJson []byte, symbol string, dateRange BatchDateRange) ([]byte, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(configJson, &obj); err != nil {
+		return nil, err
+	}
+
+	backtestConfig, ok := obj["backtest"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("backtest config is not defined")
+	}
+
+	if t := dateRange.StartTime.Time(); !t.IsZero() {
+		backtestConfig["startTime"] = t.Format(time.RFC3339)
+	}
+
+	if dateRange.EndTime != nil {
+		backtestConfig["endTime"] = dateRange.EndTime.Time().Format(time.RFC3339)
+	}
+
+	if len(symbol) > 0 {
+		backtestConfig["symbols"] = []string{symbol}
+
+		for _, key := range []string{"exchangeStrategies", "crossExchangeStrategies"} {
+			strategies, _ := obj[key].([]interface{})
+			for _, entry := range strategies {
+				entryMap, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				for _, strategyConfig := range entryMap {
+					if strategyMap, ok := strategyConfig.(map[string]interface{}); ok {
+						if _, ok := strategyMap["symbol"]; ok {
+							strategyMap["symbol"] = symbol
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return json.Marshal(obj)
+}
+
+// BatchResult is a backtest result of the batch, the report is nil if the backtest failed
+type BatchResult struct {
+	Config    string                  `json:"config"`
+	Symbol    string                  `json:"symbol,omitempty"`
+	StartTime string                  `json:"startTime,omitempty"`
+	EndTime   string                  `json:"endTime,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+	Report    *backtest.SummaryReport `json:"report,omitempty"`
+}
+
+type BatchRunner struct {
+	Executor Executor
+}
+
+// Run executes the tasks with the executor and returns the results in the order of the config, the symbol and the date range
+func (r *BatchRunner) Run(ctx context.Context, tasks []BacktestTask) ([]BatchResult, error) {
+	var taskC = make(chan BacktestTask, len(tasks))
+	for _, task := range tasks {
+		taskC <- task
+	}
+	close(taskC)
+
+	var bar = pb.Full.New(len(tasks))
+	bar.SetTemplateString(`{{ string . "log" | green}} | {{counters . }} {{bar . }} {{percent . }} {{etime . }} {{rtime . "ETA %s"}}`)
+
+	resultsC, err := r.Executor.Run(ctx, taskC, bar)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []BatchResult
+	for task := range resultsC {
+		bar.Increment()
+
+		result := BatchResult{
+			Config:    fmt.Sprint(task.Params[0]),
+			Symbol:    fmt.Sprint(task.Params[1]),
+			StartTime: fmt.Sprint(task.Params[2]),
+			EndTime:   fmt.Sprint(task.Params[3]),
+			Report:    task.Report,
+		}
+
+		if task.Error != nil {
+			result.Error = task.Error.Error()
+		} else if task.Report == nil {
+			result.Error = "no summary report found"
+		}
+
+		bar.Set("log", fmt.Sprintf("finished %s %s %s", result.Config, result.Symbol, result.StartTime))
+		results = append(results, result)
+	}
+	bar.Finish()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Config != b.Config {
+			return a.Config < b.Config
+		}
+
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+
+		return a.StartTime < b.StartTime
+	})
+
+	return results, nil
+}
+
+type batchReportColumn struct {
+	Name  string
+	Value func(report *backtest.SummaryReport) string
+}
+
+// batchReportColumns are the summary report fields in the comparison table
+var batchReportColumns = []batchReportColumn{
+	{"initialEquityValue", func(r *backtest.SummaryReport) string { return r.InitialEquityValue.String() }},
+	{"finalEquityValue", func(r *backtest.SummaryReport) string { return r.FinalEquityValue.String() }},
+	{"totalProfit", func(r *backtest.SummaryReport) string { return r.TotalProfit.String() }},
+	{"totalUnrealizedProfit", func(r *backtest.SummaryReport) string { return r.TotalUnrealizedProfit.String() }},
+	{"totalGrossProfit", func(r *backtest.SummaryReport) string { return r.TotalGrossProfit.String() }},
+	{"totalGrossLoss", func(r *backtest.SummaryReport) string { return r.TotalGrossLoss.String() }},
+	{"numOfTrades", func(r *backtest.SummaryReport) string {
+		n := 0
+		for _, symbolReport := range r.SymbolReports {
+			if symbolReport.PnL != nil {
+				n += symbolReport.PnL.NumTrades
+			}
+		}
+		return fmt.Sprint(n)
+	}},
+	{"maxDrawdown", func(r *backtest.SummaryReport) string { return r.MaxDrawdown.String() }},
+	{"maxDrawdownDuration", func(r *backtest.SummaryReport) string { return r.MaxDrawdownDuration.String() }},
+	{"calmarRatio", func(r *backtest.SummaryReport) string { return r.CalmarRatio.String() }},
+	{"exposureTime", func(r *backtest.SummaryReport) string { return r.ExposureTime.String() }},
+	{"averageTradeDuration", func(r *backtest.SummaryReport) string { return r.AverageTradeDuration.String() }},
+	{"alpha", func(r *backtest.SummaryReport) string {
+		if len(r.Benchmarks) == 0 {
+			return ""
+		}
+		return r.Benchmarks[0].Alpha.String()
+	}},
+	{"informationRatio", func(r *backtest.SummaryReport) string {
+		if len(r.Benchmarks) == 0 {
+			return ""
+		}
+		return r.Benchmarks[0].InformationRatio.String()
+	}},
+}
+
+// BatchReportTable returns the comparison table of the results
+func BatchReportTable(results []BatchResult) (headers []string, rows [][]string) {
+	headers = append(headers, batchTaskLabels...)
+	for _, column := range batchReportColumns {
+		headers = append(headers, column.Name)
+	}
+	headers = append(headers, "error")
+
+	for _, result := range results {
+		row := []string{result.Config, result.Symbol, result.StartTime, result.EndTime}
+		for _, column := range batchReportColumns {
+			if result.Report == nil {
+				row = append(row, "")
+			} else {
+				row = append(row, column.Value(result.Report))
+			}
+		}
+
+		rows = append(rows, append(row, result.Error))
+	}
+
+	return headers, rows
+}
+
+func FormatBatchReportTsv(writer io.Writer, results []BatchResult) error {
+	headers, rows := BatchReportTable(results)
+
+	// the writer is not closed, so that the report can be written to os.Stdout
+	w := csv.NewWriter(writer)
+	w.Comma = '\t'
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+var batchReportHtmlTemplate = template.Must(template.New("batch").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Backtest Batch Report</title>
+<style>
+body { font-family: sans-serif; font-size: 13px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; white-space: nowrap; }
+th { background: #f0f0f0; }
+td.error { color: #c00; text-align: left; }
+</style>
+</head>
+<body>
+<h2>Backtest Batch Report</h2>
+<p>Generated at {{ .Time }}</p>
+<table>
+<thead><tr>{{ range .Headers }}<th>{{ . }}</th>{{ end }}</tr></thead>
+<tbody>
+{{ range .Rows }}<tr>{{ range $i, $cell := . }}<td{{ if eq $i $.ErrorColumn }} class="error"{{ end }}>{{ $cell }}</td>{{ end }}</tr>
+{{ end }}</tbody>
+</table>
+</body>
+</html>
+`))
+
+func FormatBatchReportHtml(writer io.Writer, results []BatchResult) error {
+	headers, rows := BatchReportTable(results)
+	return batchReportHtmlTemplate.Execute(writer, map[string]interface{}{
+		"Time":        time.Now().Format(time.RFC1123),
+		"Headers":     headers,
+		"Rows":        rows,
+		"ErrorColumn": len(headers) - 1,
+	})
+}
+
+// WriteBatchReport writes the comparison table in json, tsv and html formats to the output directory
+func WriteBatchReport(outputDir string, results []BatchResult) error {
+	if err := util.SafeMkdirAll(outputDir); err != nil {
+		return err
+	}
+
+	if err := util.WriteJsonFile(filepath.Join(outputDir, "batch_report.json"), results); err != nil {
+		return err
+	}
+
+	tsvFile, err := os.Create(filepath.Join(outputDir, "batch_report.tsv"))
+	if err != nil {
+		return err
+	}
+
+	if err := FormatBatchReportTsv(tsvFile, results); err != nil {
+		_ = tsvFile.Close()
+		return err
+	}
+
+	if err := tsvFile.Close(); err != nil {
+		return err
+	}
+
+	htmlFile, err := os.Create(filepath.Join(outputDir, "batch_report.html"))
+	if err != nil {
+		return err
+	}
+
+	defer htmlFile.Close()
+	return FormatBatchReportHtml(htmlFile, results)
+}
@@ -0,0 +1,177 @@
+package optimizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/backtest"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+const testBatchStrategyConfig = `---
+notifications:
+  slack:
+    defaultChannel: "dev-bbgo"
+backtest:
+  startTime: "2022-01-01"
+  endTime: "2022-02-01"
+  symbols:
+  - BTCUSDT
+  sessions: [binance]
+exchangeStrategies:
+- on: binance
+  grid:
+    symbol: BTCUSDT
+    gridNumber: 10
+`
+
+func writeTestBatchFiles(t *testing.T) string {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "grid.yaml"), []byte(testBatchStrategyConfig), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "batch.yaml"), []byte(`---
+configs:
+- `+filepath.Join(dir, "grid.yaml")+`
+symbols: [BTCUSDT, ETHUSDT]
+dateRanges:
+- startTime: "2022-03-01"
+  endTime: "2022-04-01"
+- startTime: "2022-01-01"
+  endTime: "2022-02-01"
+`), 0644))
+	return dir
+}
+
+func TestBatchConfig_BuildTasks(t *testing.T) {
+	dir := writeTestBatchFiles(t)
+
+	batchConfig, err := LoadBatchConfig(filepath.Join(dir, "batch.yaml"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "local", batchConfig.Executor.Type)
+	assert.NotNil(t, batchConfig.Executor.LocalExecutorConfig)
+
+	remoteConfig := filepath.Join(dir, "remote.yaml")
+	assert.NoError(t, os.WriteFile(remoteConfig, []byte("executor:\n  type: remote\nconfigs:\n- grid.yaml\n"), 0644))
+	_, err = LoadBatchConfig(remoteConfig)
+	assert.ErrorContains(t, err, `executor type "remote" is not supported`)
+
+	tasks, err := batchConfig.BuildTasks()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, tasks, 4)
+	assert.Equal(t, batchTaskLabels, tasks[0].Labels)
+	assert.Equal(t, "ETHUSDT", tasks[2].Params[1])
+
+	var obj map[string]interface{}
+	assert.NoError(t, json.Unmarshal(tasks[2].ConfigJson, &obj))
+	assert.NotContains(t, obj, "notifications")
+
+	backtestConfig := obj["backtest"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"ETHUSDT"}, backtestConfig["symbols"])
+	assert.True(t, strings.HasPrefix(backtestConfig["startTime"].(string), "2022-03-01"))
+
+	grid := obj["exchangeStrategies"].([]interface{})[0].(map[string]interface{})["grid"].(map[string]interface{})
+	assert.Equal(t, "ETHUSDT", grid["symbol"])
+	assert.Equal(t, 10.0, grid["gridNumber"])
+
+	syncConfigs, err := batchConfig.BuildSyncConfigs()
+	if assert.NoError(t, err) {
+		assert.Len(t, syncConfigs, 2)
+
+		assert.NoError(t, json.Unmarshal(syncConfigs[0], &obj))
+		backtestConfig := obj["backtest"].(map[string]interface{})
+		assert.True(t, strings.HasPrefix(backtestConfig["startTime"].(string), "2022-01-01"))
+		assert.True(t, strings.HasPrefix(backtestConfig["endTime"].(string), "2022-04-01"))
+	}
+}
+
+type testBatchExecutor struct{}
+
+func (e *testBatchExecutor) Execute(configJson []byte) (*backtest.SummaryReport, error) {
+	return &backtest.SummaryReport{TotalProfit: fixedpoint.NewFromInt(int64(len(configJson)))}, nil
+}
+
+func (e *testBatchExecutor) Run(ctx context.Context, taskC chan BacktestTask, bar *pb.ProgressBar) (chan BacktestTask, error) {
+	resultsC := make(chan BacktestTask)
+	go func() {
+		defer close(resultsC)
+		for task := range taskC {
+			if task.Params[1] == "ETHUSDT" {
+				task.Error = assert.AnError
+			} else {
+				task.Report, task.Error = e.Execute(task.ConfigJson)
+			}
+
+			resultsC <- task
+		}
+	}()
+
+	return resultsC, nil
+}
+
+func TestBatchRunner_Run(t *testing.T) {
+	dir := writeTestBatchFiles(t)
+
+	batchConfig, err := LoadBatchConfig(filepath.Join(dir, "batch.yaml"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tasks, err := batchConfig.BuildTasks()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	runner := &BatchRunner{Executor: &testBatchExecutor{}}
+	results, err := runner.Run(context.Background(), tasks)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if assert.Len(t, results, 4) {
+		assert.Equal(t, "BTCUSDT", results[0].Symbol)
+		assert.True(t, strings.HasPrefix(results[0].StartTime, "2022-01-01"))
+		assert.NotNil(t, results[0].Report)
+		assert.Equal(t, "ETHUSDT", results[3].Symbol)
+		assert.NotEmpty(t, results[3].Error)
+		assert.Nil(t, results[3].Report)
+	}
+
+	headers, rows := BatchReportTable(results)
+	assert.Equal(t, "config", headers[0])
+	assert.Equal(t, "error", headers[len(headers)-1])
+	assert.Len(t, rows, 4)
+	for _, row := range rows {
+		assert.Len(t, row, len(headers))
+	}
+
+	var buf bytes.Buffer
+	if assert.NoError(t, FormatBatchReportTsv(&buf, results)) {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 5)
+		assert.True(t, strings.HasPrefix(lines[0], "config\t"))
+	}
+
+	outputDir := filepath.Join(dir, "output")
+	if assert.NoError(t, WriteBatchReport(outputDir, results)) {
+		for _, filename := range []string{"batch_report.json", "batch_report.tsv", "batch_report.html"} {
+			assert.FileExists(t, filepath.Join(outputDir, filename))
+		}
+
+		html, err := os.ReadFile(filepath.Join(outputDir, "batch_report.html"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(html), "totalProfit")
+	}
+}
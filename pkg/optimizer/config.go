@@ -79,7 +79,8 @@ func LoadConfig(yamlConfigFileName string) (*Config, error) {
 	switch objective := strings.ToLower(optConfig.Objective); objective {
 	case "", "default":
 		optConfig.Objective = HpOptimizerObjectiveEquity
-	case HpOptimizerObjectiveEquity, HpOptimizerObjectiveProfit, HpOptimizerObjectiveVolume, HpOptimizerObjectiveProfitFactor:
+	case HpOptimizerObjectiveEquity, HpOptimizerObjectiveProfit, HpOptimizerObjectiveVolume, HpOptimizerObjectiveProfitFactor,
+		HpOptimizerObjectiveCalmar, HpOptimizerObjectiveDrawdown, HpOptimizerObjectiveAlpha, HpOptimizerObjectiveInformationRatio:
 		optConfig.Objective = objective
 	default:
 		return nil, fmt.Errorf(`unknown objective "%s"`, optConfig.Objective)
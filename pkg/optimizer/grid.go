@@ -53,6 +53,31 @@ var ProfitFactorMetricValueFunc = func(summaryReport *backtest.SummaryReport) fl
 	return pf*0.9 + win*0.1
 }
 
+var CalmarRatioMetricValueFunc = func(summaryReport *backtest.SummaryReport) float64 {
+	return summaryReport.CalmarRatio.Float64()
+}
+
+// MaxDrawdownMetricValueFunc returns the negative max drawdown, so that the smaller drawdown is ranked higher
+var MaxDrawdownMetricValueFunc = func(summaryReport *backtest.SummaryReport) float64 {
+	return -summaryReport.MaxDrawdown.Float64()
+}
+
+// AlphaMetricValueFunc returns the alpha against the first benchmark of the back-test config
+var AlphaMetricValueFunc = func(summaryReport *backtest.SummaryReport) float64 {
+	if len(summaryReport.Benchmarks) == 0 {
+		return 0
+	}
+	return summaryReport.Benchmarks[0].Alpha.Float64()
+}
+
+// InformationRatioMetricValueFunc returns the information ratio against the first benchmark of the back-test config
+var InformationRatioMetricValueFunc = func(summaryReport *backtest.SummaryReport) float64 {
+	if len(summaryReport.Benchmarks) == 0 {
+		return 0
+	}
+	return summaryReport.Benchmarks[0].InformationRatio.Float64()
+}
+
 type Metric struct {
 	// Labels is the labels of the given parameters
 	Labels []string `json:"labels,omitempty"`
@@ -209,10 +234,14 @@ func (o *GridOptimizer) Run(executor Executor, configJson []byte) (map[string][]
 	o.CurrentParams = make([]interface{}, len(o.Config.Matrix))
 
 	var valueFunctions = map[string]MetricValueFunc{
-		"totalProfit":     TotalProfitMetricValueFunc,
-		"totalVolume":     TotalVolume,
-		"totalEquityDiff": TotalEquityDiff,
-		"profitFactor":    ProfitFactorMetricValueFunc,
+		"totalProfit":      TotalProfitMetricValueFunc,
+		"totalVolume":      TotalVolume,
+		"totalEquityDiff":  TotalEquityDiff,
+		"profitFactor":     ProfitFactorMetricValueFunc,
+		"calmarRatio":      CalmarRatioMetricValueFunc,
+		"maxDrawdown":      MaxDrawdownMetricValueFunc,
+		"alpha":            AlphaMetricValueFunc,
+		"informationRatio": InformationRatioMetricValueFunc,
 	}
 	var metrics = map[string][]Metric{}
 
@@ -26,6 +26,14 @@ const (
 	HpOptimizerObjectiveVolume = "volume"
 	// HpOptimizerObjectiveProfitFactor optimize the parameters to maximize profit factor
 	HpOptimizerObjectiveProfitFactor = "profitfactor"
+	// HpOptimizerObjectiveCalmar optimize the parameters to maximize the calmar ratio
+	HpOptimizerObjectiveCalmar = "calmar"
+	// HpOptimizerObjectiveDrawdown optimize the parameters to minimize the max drawdown
+	HpOptimizerObjectiveDrawdown = "drawdown"
+	// HpOptimizerObjectiveAlpha optimize the parameters to maximize the alpha against the first benchmark
+	HpOptimizerObjectiveAlpha = "alpha"
+	// HpOptimizerObjectiveInformationRatio optimize the parameters to maximize the information ratio against the first benchmark
+	HpOptimizerObjectiveInformationRatio = "informationratio"
 )
 
 const (
@@ -204,6 +212,14 @@ func (o *HyperparameterOptimizer) buildObjective(executor Executor, configJson [
 		metricValueFunc = TotalEquityDiff
 	case HpOptimizerObjectiveProfitFactor:
 		metricValueFunc = ProfitFactorMetricValueFunc
+	case HpOptimizerObjectiveCalmar:
+		metricValueFunc = CalmarRatioMetricValueFunc
+	case HpOptimizerObjectiveDrawdown:
+		metricValueFunc = MaxDrawdownMetricValueFunc
+	case HpOptimizerObjectiveAlpha:
+		metricValueFunc = AlphaMetricValueFunc
+	case HpOptimizerObjectiveInformationRatio:
+		metricValueFunc = InformationRatioMetricValueFunc
 	}
 
 	return func(trial goptuna.Trial) (float64, error) {
@@ -0,0 +1,451 @@
+package pricesolver
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DefaultMaxHops is the default max number of the conversions in a price path
+const DefaultMaxHops = 4
+
+// Quote is the latest price of a market from a price source, the source is usually the session name or the exchange name
+type Quote struct {
+	Source string
+	Symbol string
+	Market types.Market
+	Price  fixedpoint.Value
+
+	// Liquidity is the quote volume of the market, it's used to choose the path among the paths of the same length
+	Liquidity fixedpoint.Value
+
+	UpdatedAt time.Time
+}
+
+// PriceHop is a conversion of a price path
+type PriceHop struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Source string `json:"source,omitempty"`
+	Symbol string `json:"symbol"`
+
+	// Rate is the amount of the To currency for one From currency
+	Rate fixedpoint.Value `json:"rate"`
+
+	// Inverted is true when the conversion is from the quote currency to the base currency of the market
+	Inverted bool `json:"inverted"`
+
+	// Liquidity is the liquidity of the market quote, measured in the quote currency of the market
+	Liquidity fixedpoint.Value `json:"liquidity"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PricePath is the conversion path used to resolve the price of an asset
+type PricePath struct {
+	Asset    string           `json:"asset"`
+	Currency string           `json:"currency"`
+	Price    fixedpoint.Value `json:"price"`
+
+	// Liquidity is the smallest liquidity of the hops in the resolved currency
+	Liquidity fixedpoint.Value `json:"liquidity"`
+
+	Hops []PriceHop `json:"hops"`
+}
+
+func (p *PricePath) String() string {
+	var sb strings.Builder
+	sb.WriteString(p.Asset)
+	for _, hop := range p.Hops {
+		sb.WriteString(" -> ")
+		sb.WriteString(hop.To)
+		sb.WriteString(" (")
+		if hop.Source != "" {
+			sb.WriteString(hop.Source + ":")
+		}
+		sb.WriteString(hop.Symbol + ")")
+	}
+
+	sb.WriteString(" = " + p.Price.String() + " " + p.Currency)
+	return sb.String()
+}
+
+// GraphPriceSolver resolves the asset prices by searching the conversion paths in the graph of the markets,
+// the markets could come from different sessions. The path with the fewest conversions is used,
+// and the paths of the same length are ranked by their smallest liquidity.
+//
+// The quotes older than the TTL are dropped from the graph. The age of a quote is measured from the latest quote update,
+// so that a stale market is detected while the other markets are updated, and it works with the back-test time as well.
+type GraphPriceSolver struct {
+	// TTL is the max age of the quotes, zero TTL disables the staleness check
+	TTL time.Duration
+
+	// MaxHops is the max number of the conversions in a price path
+	MaxHops int
+
+	markets types.MarketMap
+
+	// quotes stores the quotes by source and symbol
+	quotes map[string]map[string]*Quote
+
+	// edges stores the quotes by the base and quote currencies of their markets
+	edges map[string][]*Quote
+
+	lastUpdateTime time.Time
+
+	mu sync.Mutex
+}
+
+func NewGraphPriceSolver(markets types.MarketMap, ttl time.Duration) *GraphPriceSolver {
+	s := &GraphPriceSolver{
+		TTL:     ttl,
+		MaxHops: DefaultMaxHops,
+		markets: make(types.MarketMap),
+		quotes:  make(map[string]map[string]*Quote),
+		edges:   make(map[string][]*Quote),
+	}
+
+	s.AddMarkets(markets)
+	return s
+}
+
+// AddMarkets adds the markets of another session to the solver
+func (s *GraphPriceSolver) AddMarkets(markets types.MarketMap) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for symbol, market := range markets {
+		s.markets[symbol] = market
+	}
+}
+
+func (s *GraphPriceSolver) Update(symbol string, price fixedpoint.Value) {
+	s.UpdateQuote("", symbol, price, fixedpoint.Zero, time.Time{})
+}
+
+// UpdateQuote updates the price of the symbol from the given source,
+// the liquidity of the previous quote is kept if the given liquidity is zero,
+// and the latest update time is used if the given update time is zero.
+func (s *GraphPriceSolver) UpdateQuote(
+	source, symbol string, price, liquidity fixedpoint.Value, updatedAt time.Time,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	market, ok := s.markets[symbol]
+	if !ok {
+		log.Warnf("market info %s not found, unable to update price (%s)", symbol, price.String())
+		return
+	}
+
+	if updatedAt.IsZero() {
+		updatedAt = s.lastUpdateTime
+		if updatedAt.IsZero() {
+			updatedAt = time.Now()
+		}
+	}
+
+	if updatedAt.After(s.lastUpdateTime) {
+		s.lastUpdateTime = updatedAt
+	}
+
+	sourceQuotes, ok := s.quotes[source]
+	if !ok {
+		sourceQuotes = make(map[string]*Quote)
+		s.quotes[source] = sourceQuotes
+	}
+
+	quote, ok := sourceQuotes[symbol]
+	if !ok {
+		quote = &Quote{Source: source, Symbol: symbol, Market: market}
+		sourceQuotes[symbol] = quote
+		s.edges[market.BaseCurrency] = append(s.edges[market.BaseCurrency], quote)
+		s.edges[market.QuoteCurrency] = append(s.edges[market.QuoteCurrency], quote)
+	}
+
+	quote.Price = price
+	quote.UpdatedAt = updatedAt
+	if !liquidity.IsZero() {
+		quote.Liquidity = liquidity
+	}
+}
+
+func (s *GraphPriceSolver) UpdateFromTrade(trade types.Trade) {
+	s.UpdateQuote(trade.Exchange.String(), trade.Symbol, trade.Price, fixedpoint.Zero, trade.Time.Time())
+}
+
+func (s *GraphPriceSolver) BindStream(stream types.Stream) {
+	stream.OnKLineClosed(func(k types.KLine) {
+		s.UpdateQuote(k.Exchange.String(), k.Symbol, k.Close, k.QuoteVolume, k.EndTime.Time())
+	})
+}
+
+// UpdateFromTickers updates the prices from the tickers of the given symbols
+func (s *GraphPriceSolver) UpdateFromTickers(ctx context.Context, ex types.Exchange, symbols ...string) error {
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	tickers, err := ex.QueryTickers(ctx, symbols...)
+	if err != nil {
+		return err
+	}
+
+	s.updateFromTickers(ex, tickers)
+	return nil
+}
+
+// UpdateFromAllTickers updates the prices from the tickers of all the markets of the exchange
+func (s *GraphPriceSolver) UpdateFromAllTickers(ctx context.Context, ex types.Exchange) error {
+	tickers, err := ex.QueryTickers(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.updateFromTickers(ex, tickers)
+	return nil
+}
+
+func (s *GraphPriceSolver) updateFromTickers(ex types.Exchange, tickers map[string]types.Ticker) {
+
+	source := ex.Name().String()
+	for symbol, ticker := range tickers {
+		s.mu.Lock()
+		_, ok := s.markets[symbol]
+		s.mu.Unlock()
+
+		// only update the ticker for the symbol that is in the market map
+		if !ok {
+			continue
+		}
+
+		price := ticker.GetValidPrice()
+		if price.IsZero() {
+			continue
+		}
+
+		s.UpdateQuote(source, symbol, price, ticker.Volume.Mul(price), ticker.Time)
+	}
+}
+
+func (s *GraphPriceSolver) ResolvePrice(asset string, preferredFiats ...string) (fixedpoint.Value, bool) {
+	path, ok := s.ResolvePath(asset, preferredFiats...)
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	return path.Price, true
+}
+
+// ResolvePath resolves the price of the asset in the first reachable currency of the preferred fiats
+// and returns the conversion path used.
+func (s *GraphPriceSolver) ResolvePath(asset string, preferredFiats ...string) (*PricePath, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, fiat := range preferredFiats {
+		if asset == fiat {
+			return &PricePath{Asset: asset, Currency: fiat, Price: fixedpoint.One}, true
+		}
+
+		if path, ok := s.findPath(asset, fiat); ok {
+			return path, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *GraphPriceSolver) isAvailable(quote *Quote) bool {
+	if quote.Price.Sign() <= 0 {
+		return false
+	}
+
+	return s.TTL <= 0 || s.lastUpdateTime.Sub(quote.UpdatedAt) <= s.TTL
+}
+
+// findPath finds the paths of the fewest conversions from the asset to the target currency,
+// and returns the one with the largest liquidity.
+func (s *GraphPriceSolver) findPath(asset, target string) (*PricePath, bool) {
+	maxHops := s.MaxHops
+	if maxHops <= 0 {
+		maxHops = DefaultMaxHops
+	}
+
+	// the number of conversions from each currency to the target currency
+	distances := map[string]int{target: 0}
+	queue := []string{target}
+	for len(queue) > 0 {
+		currency := queue[0]
+		queue = queue[1:]
+
+		if distances[currency] >= maxHops {
+			continue
+		}
+
+		for _, quote := range s.edges[currency] {
+			if !s.isAvailable(quote) {
+				continue
+			}
+
+			next := quote.Market.BaseCurrency
+			if next == currency {
+				next = quote.Market.QuoteCurrency
+			}
+
+			if _, visited := distances[next]; !visited {
+				distances[next] = distances[currency] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if _, ok := distances[asset]; !ok {
+		return nil, false
+	}
+
+	var best *PricePath
+	var hops []PriceHop
+	var rates []float64
+
+	var walk func(currency string)
+	walk = func(currency string) {
+		if currency == target {
+			path := newPricePath(asset, target, hops, rates)
+			if best == nil || path.Liquidity.Compare(best.Liquidity) > 0 {
+				best = path
+			}
+			return
+		}
+
+		for _, quote := range s.edges[currency] {
+			if !s.isAvailable(quote) {
+				continue
+			}
+
+			hop := PriceHop{
+				From:      currency,
+				To:        quote.Market.QuoteCurrency,
+				Source:    quote.Source,
+				Symbol:    quote.Symbol,
+				Liquidity: quote.Liquidity,
+				UpdatedAt: quote.UpdatedAt,
+			}
+
+			rate := quote.Price.Float64()
+			if quote.Market.QuoteCurrency == currency {
+				hop.To = quote.Market.BaseCurrency
+				hop.Inverted = true
+				rate = 1.0 / rate
+			}
+
+			// only walk along the shortest paths
+			if d, ok := distances[hop.To]; !ok || d != distances[currency]-1 {
+				continue
+			}
+
+			hop.Rate = fixedpoint.NewFromFloat(rate)
+			hops = append(hops, hop)
+			rates = append(rates, rate)
+			walk(hop.To)
+			hops = hops[:len(hops)-1]
+			rates = rates[:len(rates)-1]
+		}
+	}
+
+	walk(asset)
+	return best, best != nil
+}
+
+// newPricePath calculates the price and the liquidity of the path from the conversion rates
+func newPricePath(asset, currency string, hops []PriceHop, rates []float64) *PricePath {
+	// values[i] is the value of one hops[i].From currency in the target currency
+	values := make([]float64, len(rates)+1)
+	values[len(rates)] = 1.0
+	for i := len(rates) - 1; i >= 0; i-- {
+		values[i] = values[i+1] * rates[i]
+	}
+
+	liquidity := -1.0
+	for i, hop := range hops {
+		// the liquidity is measured in the quote currency of the market
+		quoteValue := values[i+1]
+		if hop.Inverted {
+			quoteValue = values[i]
+		}
+
+		l := hop.Liquidity.Float64() * quoteValue
+		if liquidity < 0 || l < liquidity {
+			liquidity = l
+		}
+	}
+
+	if liquidity < 0 {
+		liquidity = 0
+	}
+
+	return &PricePath{
+		Asset:     asset,
+		Currency:  currency,
+		Price:     fixedpoint.NewFromFloat(values[0]),
+		Liquidity: fixedpoint.NewFromFloat(liquidity),
+		Hops:      append([]PriceHop(nil), hops...),
+	}
+}
+
+// ConversionSymbols returns the symbols of the markets needed to convert the currency into the quote currency.
+// The quote market is returned if it exists, otherwise the markets of the currency and the quote markets of their counter currencies
+// are returned, so that the exotic assets could be converted via one more hop.
+func ConversionSymbols(markets types.MarketMap, currency, quoteCurrency string) (symbols []string) {
+	if symbol, ok := findQuoteMarket(markets, currency, quoteCurrency); ok {
+		return []string{symbol}
+	}
+
+	exists := make(map[string]struct{})
+	add := func(symbol string) {
+		if _, ok := exists[symbol]; !ok {
+			exists[symbol] = struct{}{}
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	for symbol, market := range markets {
+		var counter string
+		switch currency {
+		case market.BaseCurrency:
+			counter = market.QuoteCurrency
+		case market.QuoteCurrency:
+			counter = market.BaseCurrency
+		default:
+			continue
+		}
+
+		if counterSymbol, ok := findQuoteMarket(markets, counter, quoteCurrency); ok {
+			add(symbol)
+			add(counterSymbol)
+		}
+	}
+
+	return symbols
+}
+
+// findQuoteMarket returns the symbol of the market that trades the currency against the quote currency
+func findQuoteMarket(markets types.MarketMap, currency, quoteCurrency string) (string, bool) {
+	symbol := currency + quoteCurrency
+	if _, ok := markets[symbol]; ok {
+		return symbol, true
+	}
+
+	reversedSymbol := quoteCurrency + currency
+	if _, ok := markets[reversedSymbol]; ok {
+		return reversedSymbol, true
+	}
+
+	return "", false
+}
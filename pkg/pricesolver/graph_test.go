@@ -0,0 +1,149 @@
+package pricesolver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/c9s/bbgo/pkg/testing/testhelper"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestGraphPriceSolver(t *testing.T) {
+	markets := types.MarketMap{
+		"BTCUSDT":  types.Market{BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"ETHBTC":   types.Market{BaseCurrency: "ETH", QuoteCurrency: "BTC"},
+		"ETHBNB":   types.Market{BaseCurrency: "ETH", QuoteCurrency: "BNB"},
+		"BNBUSDT":  types.Market{BaseCurrency: "BNB", QuoteCurrency: "USDT"},
+		"DOGEETH":  types.Market{BaseCurrency: "DOGE", QuoteCurrency: "ETH"},
+		"USDTTWD":  types.Market{BaseCurrency: "USDT", QuoteCurrency: "TWD"},
+		"MAXTWD":   types.Market{BaseCurrency: "MAX", QuoteCurrency: "TWD"},
+		"ALTCOINX": types.Market{BaseCurrency: "ALT", QuoteCurrency: "COINX"},
+	}
+
+	now := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	newSolver := func() *GraphPriceSolver {
+		solver := NewGraphPriceSolver(markets, time.Minute)
+		solver.UpdateQuote("binance", "BTCUSDT", Number(20000), Number(1_000_000), now)
+		solver.UpdateQuote("binance", "ETHBTC", Number(0.05), Number(100), now)
+		solver.UpdateQuote("binance", "ETHBNB", Number(4), Number(1000), now)
+		solver.UpdateQuote("binance", "BNBUSDT", Number(250), Number(10_000), now)
+		solver.UpdateQuote("binance", "DOGEETH", Number(0.0001), Number(10), now)
+		solver.UpdateQuote("max", "USDTTWD", Number(30), Number(3_000_000), now)
+		solver.UpdateQuote("max", "MAXTWD", Number(9), Number(90_000), now)
+		return solver
+	}
+
+	t.Run("direct", func(t *testing.T) {
+		solver := newSolver()
+		path, ok := solver.ResolvePath("BTC", "USDT")
+		if assert.True(t, ok) {
+			assert.Equal(t, "20000", path.Price.String())
+			assert.Len(t, path.Hops, 1)
+		}
+
+		price, ok := solver.ResolvePrice("USDT", "USDT")
+		assert.True(t, ok)
+		assert.Equal(t, "1", price.String())
+	})
+
+	t.Run("liquidity", func(t *testing.T) {
+		solver := newSolver()
+
+		// the path via BTC has 1M USDT liquidity at least, the path via BNB has 10K USDT liquidity at least
+		path, ok := solver.ResolvePath("ETH", "USDT")
+		if assert.True(t, ok) {
+			assert.Equal(t, "1000", path.Price.String())
+			assert.Equal(t, "ETHBTC", path.Hops[0].Symbol)
+			assert.Equal(t, "1000000", path.Liquidity.String())
+		}
+
+		solver.UpdateQuote("binance", "ETHBNB", Number(4), Number(100_000), now)
+		solver.UpdateQuote("binance", "BNBUSDT", Number(250), Number(50_000_000), now)
+		path, ok = solver.ResolvePath("ETH", "USDT")
+		if assert.True(t, ok) {
+			assert.Equal(t, "ETHBNB", path.Hops[0].Symbol)
+			assert.Equal(t, "ETH -> BNB (binance:ETHBNB) -> USDT (binance:BNBUSDT) = 1000 USDT", path.String())
+		}
+	})
+
+	t.Run("multi-hop across sessions", func(t *testing.T) {
+		solver := newSolver()
+		path, ok := solver.ResolvePath("DOGE", "TWD")
+		if assert.True(t, ok) {
+			assert.InDelta(t, 0.0001*1000*30, path.Price.Float64(), 1e-8)
+			assert.Len(t, path.Hops, 4)
+			assert.Equal(t, "max", path.Hops[3].Source)
+		}
+
+		// reversed pair, TWD -> USDT -> BTC
+		price, ok := solver.ResolvePrice("MAX", "BTC")
+		if assert.True(t, ok) {
+			assert.InDelta(t, 9.0/30.0/20000.0, price.Float64(), 1e-8)
+		}
+
+		// exceeds the max hops
+		solver.MaxHops = 3
+		_, ok = solver.ResolvePrice("DOGE", "TWD")
+		assert.False(t, ok)
+
+		// no path
+		_, ok = solver.ResolvePrice("ALT", "USDT")
+		assert.False(t, ok)
+	})
+
+	t.Run("preferred fiats", func(t *testing.T) {
+		solver := newSolver()
+		path, ok := solver.ResolvePath("MAX", "USD", "TWD", "USDT")
+		if assert.True(t, ok) {
+			assert.Equal(t, "TWD", path.Currency)
+			assert.Equal(t, "9", path.Price.String())
+		}
+	})
+
+	t.Run("stale quotes", func(t *testing.T) {
+		solver := newSolver()
+		solver.UpdateQuote("binance", "BTCUSDT", Number(21000), Number(1_000_000), now.Add(2*time.Minute))
+		solver.UpdateQuote("binance", "BNBUSDT", Number(260), Number(10_000), now.Add(2*time.Minute))
+		solver.UpdateQuote("binance", "ETHBNB", Number(4), Number(1000), now.Add(2*time.Minute))
+
+		// ETH/BTC is stale, so the path via BNB is used
+		path, ok := solver.ResolvePath("ETH", "USDT")
+		if assert.True(t, ok) {
+			assert.Equal(t, "ETHBNB", path.Hops[0].Symbol)
+			assert.Equal(t, "1040", path.Price.String())
+		}
+
+		_, ok = solver.ResolvePrice("DOGE", "USDT")
+		assert.False(t, ok)
+	})
+
+	t.Run("simple solver compatible", func(t *testing.T) {
+		var solver PriceSolver = NewGraphPriceSolver(markets, 0)
+		solver.UpdateFromTrade(types.Trade{Symbol: "BTCUSDT", Price: Number(48000.0)})
+		solver.Update("USDTTWD", Number(32.0))
+
+		price, ok := solver.ResolvePrice("BTC", "TWD")
+		if assert.True(t, ok) {
+			assert.Equal(t, "1536000", price.String())
+		}
+	})
+}
+
+func TestConversionSymbols(t *testing.T) {
+	markets := types.MarketMap{
+		"BTCUSDT": types.Market{BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"ETHBTC":  types.Market{BaseCurrency: "ETH", QuoteCurrency: "BTC"},
+		"ETHUSDT": types.Market{BaseCurrency: "ETH", QuoteCurrency: "USDT"},
+		"DOGEETH": types.Market{BaseCurrency: "DOGE", QuoteCurrency: "ETH"},
+		"DOGEXYZ": types.Market{BaseCurrency: "DOGE", QuoteCurrency: "XYZ"},
+		"USDTTWD": types.Market{BaseCurrency: "USDT", QuoteCurrency: "TWD"},
+	}
+
+	assert.Equal(t, []string{"BTCUSDT"}, ConversionSymbols(markets, "BTC", "USDT"))
+	assert.Equal(t, []string{"USDTTWD"}, ConversionSymbols(markets, "TWD", "USDT"))
+	assert.Equal(t, []string{"DOGEETH", "ETHUSDT"}, ConversionSymbols(markets, "DOGE", "USDT"))
+	assert.Empty(t, ConversionSymbols(markets, "XYZ", "USDT"))
+}
@@ -0,0 +1,20 @@
+package pricesolver
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PriceSolver resolves the price of an asset in the preferred fiat currencies from the market prices
+type PriceSolver interface {
+	Update(symbol string, price fixedpoint.Value)
+	UpdateFromTrade(trade types.Trade)
+	UpdateFromTickers(ctx context.Context, ex types.Exchange, symbols ...string) error
+	BindStream(stream types.Stream)
+	ResolvePrice(asset string, preferredFiats ...string) (fixedpoint.Value, bool)
+}
+
+var _ PriceSolver = &SimplePriceSolver{}
+var _ PriceSolver = &GraphPriceSolver{}
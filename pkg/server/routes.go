@@ -228,6 +228,7 @@ func (s *Server) newEngine(ctx context.Context) *gin.Engine {
 	r.GET("/api/sessions/:session/open-orders", s.listSessionOpenOrders)
 	r.GET("/api/sessions/:session/account", s.getSessionAccount)
 	r.GET("/api/sessions/:session/account/balances", s.getSessionAccountBalance)
+	r.GET("/api/sessions/:session/equity", s.getSessionEquity)
 	r.GET("/api/sessions/:session/symbols", s.listSessionSymbols)
 
 	r.GET("/api/sessions/:session/pnl", func(c *gin.Context) {
@@ -386,6 +387,50 @@ func (s *Server) getSessionAccount(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"account": session.GetAccount()})
 }
 
+func (s *Server) getSessionEquity(c *gin.Context) {
+	sessionName := c.Param("session")
+	if _, ok := s.Environ.Session(sessionName); !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("session %s not found", sessionName)})
+		return
+	}
+
+	tracker := s.Environ.EquityTracker()
+	if tracker == nil || !tracker.IsTracking(sessionName) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("equity tracking of session %s is not enabled", sessionName)})
+		return
+	}
+
+	// period is the lookback period of the snapshots, defaults to 24h
+	period, err := time.ParseDuration(c.DefaultQuery("period", "24h"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	since := time.Now().Add(-period)
+
+	var snapshots []types.EquitySnapshot
+	if s.Environ.EquityService != nil {
+		snapshots, err = s.Environ.EquityService.Query(c, sessionName, since, 0)
+		if err != nil {
+			logrus.WithError(err).Error("equity snapshot query error")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		snapshots = tracker.Snapshots(sessionName, since)
+	}
+
+	stats, _ := tracker.Stats(sessionName)
+	c.JSON(http.StatusOK, gin.H{
+		"quoteCurrency": tracker.Config.QuoteCurrency,
+		"highWaterMark": stats.HighWaterMark,
+		"drawdown":      stats.Drawdown,
+		"maxDrawdown":   stats.MaxDrawdown,
+		"snapshots":     snapshots,
+	})
+}
+
 func (s *Server) getSessionAccountBalance(c *gin.Context) {
 	sessionName := c.Param("session")
 	session, ok := s.Environ.Session(sessionName)
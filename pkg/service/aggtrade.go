@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type AggTradeService struct {
+	DB *sqlx.DB
+}
+
+func NewAggTradeService(db *sqlx.DB) *AggTradeService {
+	return &AggTradeService{DB: db}
+}
+
+// BatchInsert inserts the aggregated trades in a transaction, the existing trades are ignored
+func (s *AggTradeService) BatchInsert(trades []types.AggTrade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	insert := "INSERT OR IGNORE"
+	if s.DB.DriverName() == "mysql" {
+		insert = "INSERT IGNORE"
+	}
+
+	sql := insert + ` INTO agg_trades (exchange, symbol, agg_trade_id, price, quantity, first_trade_id, last_trade_id, is_buyer_maker, traded_at)
+		VALUES (:exchange, :symbol, :agg_trade_id, :price, :quantity, :first_trade_id, :last_trade_id, :is_buyer_maker, :traded_at)`
+
+	tx, err := s.DB.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.NamedExec(sql, trades); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Query queries the aggregated trades in the time range [since, until) in the ascending time order
+func (s *AggTradeService) Query(ctx context.Context, exchange types.ExchangeName, symbol string, since, until time.Time) ([]types.AggTrade, error) {
+	sql, args, err := SelectAggTrades(exchange, symbol, since, until).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var trades []types.AggTrade
+	for rows.Next() {
+		var trade types.AggTrade
+		if err := rows.StructScan(&trade); err != nil {
+			return trades, err
+		}
+
+		trades = append(trades, trade)
+	}
+
+	return trades, rows.Err()
+}
+
+// QueryCh queries the aggregated trades in the time range [since, until) in the ascending time order,
+// the trades are sent to the returned channel while the rows are scanned, so that a long time range can be replayed in back-test
+func (s *AggTradeService) QueryCh(
+	ctx context.Context, exchange types.ExchangeName, symbol string, since, until time.Time,
+) (chan types.AggTrade, chan error) {
+	ch := make(chan types.AggTrade, 500)
+	errC := make(chan error, 1)
+
+	sql, args, err := SelectAggTrades(exchange, symbol, since, until).ToSql()
+	if err != nil {
+		close(ch)
+		errC <- err
+		close(errC)
+		return ch, errC
+	}
+
+	rows, err := s.DB.QueryxContext(ctx, sql, args...)
+	if err != nil {
+		close(ch)
+		errC <- err
+		close(errC)
+		return ch, errC
+	}
+
+	go func() {
+		defer close(errC)
+		defer close(ch)
+		defer rows.Close()
+
+		for rows.Next() {
+			var trade types.AggTrade
+			if err := rows.StructScan(&trade); err != nil {
+				errC <- err
+				return
+			}
+
+			select {
+			case ch <- trade:
+			case <-ctx.Done():
+				errC <- ctx.Err()
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			errC <- err
+		}
+	}()
+
+	return ch, errC
+}
+
+func SelectAggTrades(exchange types.ExchangeName, symbol string, since, until time.Time) sq.SelectBuilder {
+	return sq.Select("*").
+		From("agg_trades").
+		Where(sq.And{
+			sq.Eq{"exchange": exchange.String()},
+			sq.Eq{"symbol": symbol},
+			sq.GtOrEq{"traded_at": since},
+			sq.Lt{"traded_at": until},
+		}).
+		OrderBy("traded_at ASC", "agg_trade_id ASC")
+}
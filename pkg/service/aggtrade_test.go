@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestAggTradeService(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		err := db.Close()
+		assert.NoError(t, err)
+	}()
+
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	service := NewAggTradeService(xdb)
+
+	now := time.Now().Truncate(time.Second)
+	var trades []types.AggTrade
+	for i := 0; i < 3; i++ {
+		trades = append(trades, types.AggTrade{
+			Exchange:     types.ExchangeBinance,
+			Symbol:       "BTCUSDT",
+			ID:           uint64(100 + i),
+			Price:        fixedpoint.NewFromFloat(20000.0 + float64(i)),
+			Quantity:     fixedpoint.NewFromFloat(0.1),
+			FirstTradeID: uint64(1000 + i*2),
+			LastTradeID:  uint64(1001 + i*2),
+			IsBuyerMaker: i%2 == 0,
+			Time:         types.Time(now.Add(time.Duration(i) * time.Second)),
+		})
+	}
+
+	assert.NoError(t, service.BatchInsert(trades))
+
+	// the duplicated trades are ignored
+	assert.NoError(t, service.BatchInsert(trades[1:]))
+
+	result, err := service.Query(context.Background(), types.ExchangeBinance, "BTCUSDT", now, now.Add(time.Minute))
+	if assert.NoError(t, err) && assert.Len(t, result, 3) {
+		assert.Equal(t, uint64(100), result[0].ID)
+		assert.Equal(t, "20002", result[2].Price.String())
+		assert.True(t, result[0].IsBuyerMaker)
+		assert.False(t, result[1].IsBuyerMaker)
+	}
+
+	ch, errC := service.QueryCh(context.Background(), types.ExchangeBinance, "BTCUSDT", now.Add(time.Second), now.Add(time.Minute))
+	var ids []uint64
+	for trade := range ch {
+		ids = append(ids, trade.ID)
+	}
+
+	assert.NoError(t, <-errC)
+	assert.Equal(t, []uint64{101, 102}, ids)
+}
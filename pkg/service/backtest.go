@@ -364,6 +364,66 @@ func (s *BacktestService) BatchInsert(kline []types.KLine, ex types.Exchange) er
 	return tx.Commit()
 }
 
+// ImportKLines inserts the klines that do not exist in the database, it returns the number of the inserted klines.
+// All klines should be the same exchange, symbol and interval.
+func (s *BacktestService) ImportKLines(ctx context.Context, ex types.Exchange, klines []types.KLine) (int, error) {
+	if len(klines) == 0 {
+		return 0, nil
+	}
+
+	first := klines[0]
+	since, until := first.StartTime.Time(), first.StartTime.Time()
+	for _, k := range klines {
+		if k.StartTime.Before(since) {
+			since = k.StartTime.Time()
+		}
+
+		if k.StartTime.After(until) {
+			until = k.StartTime.Time()
+		}
+	}
+
+	sql, args, err := s.SelectKLineTimePoints(ex, first.Symbol, first.Interval, since, until).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := s.DB.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	existing := make(map[int64]struct{})
+	for rows.Next() {
+		var tt types.Time
+		if err := rows.Scan(&tt); err != nil {
+			return 0, err
+		}
+
+		existing[tt.Time().Unix()] = struct{}{}
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var newKLines []types.KLine
+	for _, k := range klines {
+		key := k.StartTime.Time().Unix()
+		if _, ok := existing[key]; ok {
+			continue
+		}
+
+		// the klines in the archive could be duplicated as well
+		existing[key] = struct{}{}
+		newKLines = append(newKLines, k)
+	}
+
+	return len(newKLines), s.BatchInsert(newKLines, ex)
+}
+
 type TimeRange struct {
 	Start time.Time
 	End   time.Time
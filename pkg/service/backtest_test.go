@@ -185,3 +185,45 @@ func TestBacktestService_FindMissingTimeRanges(t *testing.T) {
 		assert.Empty(t, timeRanges, "after partial sync, missing time ranges should be back-filled")
 	}
 }
+
+func TestBacktestService_ImportKLines(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	dbx := sqlx.NewDb(db.DB, "sqlite3")
+
+	ex, err := exchange.NewPublic(types.ExchangeBinance)
+	assert.NoError(t, err)
+
+	service := &BacktestService{DB: dbx}
+
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Local()
+	newKLine := func(i int) types.KLine {
+		return types.KLine{
+			Exchange:  types.ExchangeBinance,
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1h,
+			StartTime: types.Time(startTime.Add(time.Duration(i) * time.Hour)),
+			EndTime:   types.Time(startTime.Add(time.Duration(i+1)*time.Hour - time.Millisecond)),
+			Closed:    true,
+		}
+	}
+
+	n, err := service.ImportKLines(ctx, ex, []types.KLine{newKLine(0), newKLine(1), newKLine(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// the existing klines are skipped
+	n, err = service.ImportKLines(ctx, ex, []types.KLine{newKLine(1), newKLine(2), newKLine(4)})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	timeRanges, err := service.FindMissingTimeRanges(ctx, ex, "BTCUSDT", types.Interval1h, startTime, startTime.Add(4*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, timeRanges, 1)
+}
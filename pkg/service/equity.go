@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type EquityService struct {
+	DB *sqlx.DB
+}
+
+func NewEquityService(db *sqlx.DB) *EquityService {
+	return &EquityService{DB: db}
+}
+
+func (s *EquityService) Insert(snapshot types.EquitySnapshot) error {
+	_, err := s.DB.NamedExec(`
+		INSERT INTO equity_snapshots (
+			session,
+			exchange,
+			time,
+			quote_currency,
+			net_value,
+			high_water_mark,
+			drawdown,
+			max_drawdown
+		) VALUES (
+			:session,
+			:exchange,
+			:time,
+			:quote_currency,
+			:net_value,
+			:high_water_mark,
+			:drawdown,
+			:max_drawdown
+		)`, snapshot)
+	return err
+}
+
+// Query queries the equity snapshots of the given session since the given time in the ascending time order,
+// zero limit means no limit
+func (s *EquityService) Query(ctx context.Context, session string, since time.Time, limit uint64) ([]types.EquitySnapshot, error) {
+	query := SelectEquitySnapshots(session, since, limit)
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return s.scanRows(rows)
+}
+
+// QueryLast queries the latest equity snapshot of the given session, returns nil if there is no snapshot
+func (s *EquityService) QueryLast(ctx context.Context, session string) (*types.EquitySnapshot, error) {
+	sql, args, err := sq.Select("*").
+		From("equity_snapshots").
+		Where(sq.Eq{"session": session}).
+		OrderBy("time DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	snapshots, err := s.scanRows(rows)
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+
+	return &snapshots[0], nil
+}
+
+func (s *EquityService) scanRows(rows *sqlx.Rows) (snapshots []types.EquitySnapshot, err error) {
+	for rows.Next() {
+		var snapshot types.EquitySnapshot
+		if err := rows.StructScan(&snapshot); err != nil {
+			return snapshots, err
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
+
+func SelectEquitySnapshots(session string, since time.Time, limit uint64) sq.SelectBuilder {
+	query := sq.Select("*").
+		From("equity_snapshots").
+		Where(sq.And{
+			sq.Eq{"session": session},
+			sq.GtOrEq{"time": since},
+		}).
+		OrderBy("time ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	return query
+}
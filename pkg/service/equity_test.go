@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestEquityService(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		err := db.Close()
+		assert.NoError(t, err)
+	}()
+
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	service := &EquityService{DB: xdb}
+
+	now := time.Now().Truncate(time.Second)
+	values := []float64{1000.0, 1200.0, 900.0}
+
+	var tracker types.DrawdownTracker
+	for i, v := range values {
+		value := fixedpoint.NewFromFloat(v)
+		tracker.Update(value)
+		err = service.Insert(types.EquitySnapshot{
+			Session:       "binance",
+			Exchange:      types.ExchangeBinance,
+			Time:          types.Time(now.Add(time.Duration(i) * time.Minute)),
+			QuoteCurrency: "USDT",
+			NetValue:      value,
+			HighWaterMark: tracker.HighWaterMark,
+			Drawdown:      tracker.Drawdown,
+			MaxDrawdown:   tracker.MaxDrawdown,
+		})
+		assert.NoError(t, err)
+	}
+
+	snapshots, err := service.Query(context.Background(), "binance", now.Add(time.Minute), 0)
+	if assert.NoError(t, err) && assert.Len(t, snapshots, 2) {
+		assert.Equal(t, "1200", snapshots[0].NetValue.String())
+		assert.Equal(t, "900", snapshots[1].NetValue.String())
+		assert.Equal(t, "1200", snapshots[1].HighWaterMark.String())
+		assert.Equal(t, "0.25", snapshots[1].MaxDrawdown.String())
+	}
+
+	last, err := service.QueryLast(context.Background(), "binance")
+	if assert.NoError(t, err) && assert.NotNil(t, last) {
+		assert.Equal(t, "900", last.NetValue.String())
+	}
+
+	last, err = service.QueryLast(context.Background(), "max")
+	assert.NoError(t, err)
+	assert.Nil(t, last)
+
+	snapshots, err = service.Query(context.Background(), "max", now, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
@@ -106,3 +107,36 @@ func (s *PositionService) Insert(
 		})
 	return err
 }
+
+// TradeStrategy is the strategy instance of a trade, it's recorded with the position of the strategy
+type TradeStrategy struct {
+	Exchange           types.ExchangeName `json:"exchange" db:"exchange"`
+	TradeID            uint64             `json:"tradeID" db:"trade_id"`
+	Side               types.SideType     `json:"side" db:"side"`
+	Strategy           string             `json:"strategy" db:"strategy"`
+	StrategyInstanceID string             `json:"strategyInstanceID" db:"strategy_instance_id"`
+}
+
+// QueryTradeStrategies returns the strategy instances of the trades that are recorded since the given time
+func (s *PositionService) QueryTradeStrategies(ctx context.Context, since time.Time) (map[types.TradeKey]TradeStrategy, error) {
+	rows, err := s.DB.NamedQueryContext(ctx, "SELECT exchange, trade_id, side, strategy, strategy_instance_id FROM positions WHERE traded_at >= :since", map[string]interface{}{
+		"since": since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	strategies := make(map[types.TradeKey]TradeStrategy)
+	for rows.Next() {
+		var ts TradeStrategy
+		if err := rows.StructScan(&ts); err != nil {
+			return nil, err
+		}
+
+		strategies[types.TradeKey{Exchange: ts.Exchange, ID: ts.TradeID, Side: ts.Side}] = ts
+	}
+
+	return strategies, rows.Err()
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -58,4 +59,15 @@ func TestPositionService(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("query trade strategies", func(t *testing.T) {
+		strategies, err := service.QueryTradeStrategies(context.Background(), time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+
+		ts, ok := strategies[types.TradeKey{Exchange: types.ExchangeBinance, ID: 9, Side: types.SideTypeSell}]
+		if assert.True(t, ok) {
+			assert.Equal(t, "bollmaker", ts.Strategy)
+			assert.Equal(t, "bollmaker-BTCUSDT-1m", ts.StrategyInstanceID)
+		}
+	})
+
 }
@@ -134,6 +134,20 @@ func (s *RewardService) QueryUnspent(ctx context.Context, ex types.ExchangeName,
 	return s.scanRows(rows)
 }
 
+// Query returns all the rewards of the exchange, including the spent rewards and the airdrops
+func (s *RewardService) Query(ctx context.Context, ex types.ExchangeName) ([]types.Reward, error) {
+	sql := "SELECT * FROM rewards WHERE exchange = :exchange ORDER BY created_at ASC"
+	rows, err := s.DB.NamedQueryContext(ctx, sql, map[string]interface{}{
+		"exchange": ex,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	return s.scanRows(rows)
+}
+
 func (s *RewardService) MarkCurrencyAsSpent(ctx context.Context, currency string) error {
 	result, err := s.DB.NamedExecContext(ctx, "UPDATE `rewards` SET `spent` = TRUE WHERE `currency` = :currency AND `spent` IS FALSE", map[string]interface{}{
 		"currency": currency,
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var tradeColumns = []string{
+	"gid", "id", "order_id", "exchange", "symbol", "price", "quantity", "quote_quantity", "side",
+	"is_buyer", "is_maker", "traded_at", "fee", "fee_currency",
+	"is_margin", "is_futures", "is_isolated", "strategy", "pnl",
+}
+
+// AccountRecords are the synced records of an exchange in a time range, they are used for the account reconciliation
+type AccountRecords struct {
+	Trades          []types.Trade
+	Deposits        []types.Deposit
+	Withdraws       []types.Withdraw
+	Rewards         []types.Reward
+	MarginInterests []types.MarginInterest
+}
+
+// StatementService stores the balance snapshots and the daily account statements
+type StatementService struct {
+	DB *sqlx.DB
+}
+
+func NewStatementService(db *sqlx.DB) *StatementService {
+	return &StatementService{DB: db}
+}
+
+func (s *StatementService) InsertSnapshots(snapshots []types.AssetBalanceSnapshot) error {
+	for _, snapshot := range snapshots {
+		_, err := s.DB.NamedExec(`
+			INSERT INTO balance_snapshots (
+				session,
+				exchange,
+				time,
+				asset,
+				available,
+				locked,
+				borrowed,
+				interest,
+				net_asset
+			) VALUES (
+				:session,
+				:exchange,
+				:time,
+				:asset,
+				:available,
+				:locked,
+				:borrowed,
+				:interest,
+				:net_asset
+			)`, snapshot)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QueryLastSnapshots queries the balance snapshots of the latest snapshot time of the given session
+func (s *StatementService) QueryLastSnapshots(ctx context.Context, session string) ([]types.AssetBalanceSnapshot, error) {
+	sql, args, err := sq.Select("*").
+		From("balance_snapshots").
+		Where(sq.And{
+			sq.Eq{"session": session},
+			sq.Expr("time = (SELECT MAX(time) FROM balance_snapshots WHERE session = ?)", session),
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []types.AssetBalanceSnapshot
+	err = s.DB.SelectContext(ctx, &snapshots, sql, args...)
+	return snapshots, err
+}
+
+func (s *StatementService) Insert(statement types.AccountStatement) error {
+	_, err := s.DB.NamedExec(`
+		INSERT INTO account_statements (
+			session,
+			exchange,
+			asset,
+			start_time,
+			end_time,
+			start_balance,
+			end_balance,
+			trade,
+			fee,
+			deposit,
+			withdraw,
+			reward,
+			interest,
+			difference,
+			reconciled
+		) VALUES (
+			:session,
+			:exchange,
+			:asset,
+			:start_time,
+			:end_time,
+			:start_balance,
+			:end_balance,
+			:trade,
+			:fee,
+			:deposit,
+			:withdraw,
+			:reward,
+			:interest,
+			:difference,
+			:reconciled
+		)`, statement)
+	return err
+}
+
+// Query queries the account statements of the given session that end after the given time in the ascending time order
+func (s *StatementService) Query(ctx context.Context, session string, since time.Time) ([]types.AccountStatement, error) {
+	sql, args, err := sq.Select("*").
+		From("account_statements").
+		Where(sq.And{
+			sq.Eq{"session": session},
+			sq.GtOrEq{"end_time": since},
+		}).
+		OrderBy("end_time ASC", "asset ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []types.AccountStatement
+	err = s.DB.SelectContext(ctx, &statements, sql, args...)
+	return statements, err
+}
+
+// QueryAccountRecords queries the synced trades, deposits, withdrawals, rewards and margin interests
+// of the exchange in the time range [startTime, endTime)
+func (s *StatementService) QueryAccountRecords(
+	ctx context.Context, exchange types.ExchangeName, startTime, endTime time.Time,
+) (*AccountRecords, error) {
+	records := &AccountRecords{}
+
+	queries := []struct {
+		table, timeColumn string
+		columns           []string
+		dest              interface{}
+	}{
+		// inserted_at is skipped since the sqlite trigger stores it in a format that can not be scanned into types.Time
+		{"trades", "traded_at", tradeColumns, &records.Trades},
+		{"deposits", "time", []string{"*"}, &records.Deposits},
+		{"withdraws", "time", []string{"*"}, &records.Withdraws},
+		{"rewards", "created_at", []string{"*"}, &records.Rewards},
+		{"margin_interests", "time", []string{"*"}, &records.MarginInterests},
+	}
+
+	for _, q := range queries {
+		sql, args, err := sq.Select(q.columns...).
+			From(q.table).
+			Where(sq.And{
+				sq.Eq{"exchange": exchange},
+				sq.GtOrEq{q.timeColumn: startTime},
+				sq.Lt{q.timeColumn: endTime},
+			}).
+			OrderBy(q.timeColumn + " ASC").
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.DB.SelectContext(ctx, q.dest, sql, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStatementService(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		err := db.Close()
+		assert.NoError(t, err)
+	}()
+
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	service := &StatementService{DB: xdb}
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+
+	balances := types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(1.0), Locked: fixedpoint.NewFromFloat(0.5)},
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(1000.0)},
+	}
+
+	assert.NoError(t, service.InsertSnapshots(types.NewAssetBalanceSnapshots("binance", types.ExchangeBinance, types.Time(now.Add(-time.Hour)), balances)))
+	assert.NoError(t, service.InsertSnapshots(types.NewAssetBalanceSnapshots("binance", types.ExchangeBinance, types.Time(now), balances)))
+
+	snapshots, err := service.QueryLastSnapshots(ctx, "binance")
+	if assert.NoError(t, err) && assert.Len(t, snapshots, 2) {
+		for _, snapshot := range snapshots {
+			assert.True(t, snapshot.Time.Time().Equal(now))
+			if snapshot.Asset == "BTC" {
+				assert.Equal(t, "1.5", snapshot.NetAsset.String())
+			}
+		}
+	}
+
+	statement := types.AccountStatement{
+		Session:      "binance",
+		Exchange:     types.ExchangeBinance,
+		Asset:        "BTC",
+		StartTime:    types.Time(now.Add(-time.Hour)),
+		EndTime:      types.Time(now),
+		StartBalance: fixedpoint.NewFromFloat(1.0),
+		EndBalance:   fixedpoint.NewFromFloat(1.5),
+		AccountFlow: types.AccountFlow{
+			Trade: fixedpoint.NewFromFloat(0.6),
+			Fee:   fixedpoint.NewFromFloat(0.1),
+		},
+	}
+	assert.True(t, statement.Reconcile(fixedpoint.Zero))
+	assert.NoError(t, service.Insert(statement))
+
+	statements, err := service.Query(ctx, "binance", now.Add(-time.Minute))
+	if assert.NoError(t, err) && assert.Len(t, statements, 1) {
+		assert.Equal(t, "0.6", statements[0].Trade.String())
+		assert.True(t, statements[0].Reconciled)
+	}
+
+	tradeService := &TradeService{DB: xdb}
+	assert.NoError(t, tradeService.Insert(types.Trade{
+		ID:            1,
+		OrderID:       1,
+		Exchange:      types.ExchangeBinance,
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		IsBuyer:       true,
+		Price:         fixedpoint.NewFromFloat(20000),
+		Quantity:      fixedpoint.NewFromFloat(0.1),
+		QuoteQuantity: fixedpoint.NewFromFloat(2000),
+		Time:          types.Time(now.Add(-30 * time.Minute)),
+	}))
+
+	withdrawService := &WithdrawService{DB: xdb}
+	assert.NoError(t, withdrawService.Insert(types.Withdraw{
+		Exchange:      types.ExchangeBinance,
+		Asset:         "USDT",
+		Amount:        fixedpoint.NewFromFloat(100),
+		TransactionID: "tx1",
+		ApplyTime:     types.Time(now.Add(-2 * time.Hour)),
+	}))
+
+	records, err := service.QueryAccountRecords(ctx, types.ExchangeBinance, now.Add(-time.Hour), now)
+	if assert.NoError(t, err) {
+		assert.Len(t, records.Trades, 1)
+		assert.Empty(t, records.Withdraws, "the withdrawal is out of the time range")
+		assert.Empty(t, records.Deposits)
+		assert.Empty(t, records.Rewards)
+		assert.Empty(t, records.MarginInterests)
+	}
+}
@@ -95,6 +95,15 @@ func (s *Strategy) Initialize(
 	}
 }
 
+// ActiveOrders returns the active maker orders of the order executor, it implements bbgo.ActiveOrdersReader
+func (s *Strategy) ActiveOrders() *bbgo.ActiveOrderBook {
+	if s.OrderExecutor == nil {
+		return nil
+	}
+
+	return s.OrderExecutor.ActiveMakerOrders()
+}
+
 func (s *Strategy) IsHalted(t time.Time) bool {
 	if s.circuitBreakRiskControl == nil {
 		return false
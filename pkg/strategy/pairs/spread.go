@@ -0,0 +1,93 @@
+package pairs
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type HedgeRatioMethod string
+
+const (
+	// HedgeRatioMethodOLS uses the rolling ordinary least squares regression of the log prices
+	HedgeRatioMethodOLS HedgeRatioMethod = "ols"
+
+	// HedgeRatioMethodKalman smooths the rolling regression slope with the kalman filter
+	HedgeRatioMethodKalman HedgeRatioMethod = "kalman"
+)
+
+// SpreadModel regresses the log price of leg A on the log price of leg B over a rolling window:
+//
+//	log(A) = intercept + hedgeRatio * log(B) + spread
+//
+// and measures the latest spread in the standard deviations of the spreads in the window (z-score).
+type SpreadModel struct {
+	Window int
+	Method HedgeRatioMethod
+
+	HedgeRatio float64
+	Intercept  float64
+	ZScore     float64
+
+	logPricesA floats.Slice
+	logPricesB floats.Slice
+	kalman     *indicator.KalmanFilter
+}
+
+func NewSpreadModel(interval types.Interval, window int, method HedgeRatioMethod) *SpreadModel {
+	m := &SpreadModel{
+		Window: window,
+		Method: method,
+	}
+
+	if method == HedgeRatioMethodKalman {
+		m.kalman = &indicator.KalmanFilter{
+			IntervalWindow: types.IntervalWindow{Interval: interval, Window: window},
+		}
+	}
+
+	return m
+}
+
+// Update pushes the prices of both legs at the same time and returns true when the window is filled
+func (m *SpreadModel) Update(priceA, priceB float64) bool {
+	if priceA <= 0 || priceB <= 0 {
+		return false
+	}
+
+	m.logPricesA.Push(math.Log(priceA))
+	m.logPricesB.Push(math.Log(priceB))
+	m.logPricesA = m.logPricesA.Tail(m.Window)
+	m.logPricesB = m.logPricesB.Tail(m.Window)
+
+	if m.logPricesA.Length() < m.Window {
+		return false
+	}
+
+	intercept, hedgeRatio := stat.LinearRegression(m.logPricesB, m.logPricesA, nil, false)
+
+	if m.kalman != nil {
+		m.kalman.Update(hedgeRatio)
+		hedgeRatio = m.kalman.Last(0)
+		intercept = m.logPricesA.Mean() - hedgeRatio*m.logPricesB.Mean()
+	}
+
+	spreads := make(floats.Slice, m.Window)
+	for i := range spreads {
+		spreads[i] = m.logPricesA[i] - intercept - hedgeRatio*m.logPricesB[i]
+	}
+
+	mean, std := stat.MeanStdDev(spreads, nil)
+	if std == 0 || math.IsNaN(std) {
+		return false
+	}
+
+	m.HedgeRatio = hedgeRatio
+	m.Intercept = intercept
+	m.ZScore = (spreads.Last(0) - mean) / std
+	return true
+}
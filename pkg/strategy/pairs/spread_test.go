@@ -0,0 +1,63 @@
+package pairs
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// cointegratedPrices generates the price of A as exp(0.5 + 1.5 * log(B) + noise)
+func cointegratedPrices(n int) (pricesA, pricesB []float64) {
+	r := rand.New(rand.NewSource(42))
+	logB := math.Log(100.0)
+	for i := 0; i < n; i++ {
+		logB += r.NormFloat64() * 0.01
+		logA := 0.5 + 1.5*logB + r.NormFloat64()*0.001
+		pricesA = append(pricesA, math.Exp(logA))
+		pricesB = append(pricesB, math.Exp(logB))
+	}
+	return pricesA, pricesB
+}
+
+func TestSpreadModel_OLS(t *testing.T) {
+	pricesA, pricesB := cointegratedPrices(200)
+
+	model := NewSpreadModel(types.Interval1h, 100, HedgeRatioMethodOLS)
+	for i := 0; i < 99; i++ {
+		assert.False(t, model.Update(pricesA[i], pricesB[i]))
+	}
+
+	for i := 99; i < len(pricesA); i++ {
+		assert.True(t, model.Update(pricesA[i], pricesB[i]))
+	}
+
+	assert.InDelta(t, 1.5, model.HedgeRatio, 0.05)
+	assert.Less(t, math.Abs(model.ZScore), 4.0)
+
+	// leg A jumps away from the equilibrium, the spread goes up
+	last := len(pricesA) - 1
+	assert.True(t, model.Update(pricesA[last]*1.02, pricesB[last]))
+	assert.Greater(t, model.ZScore, 3.0)
+
+	// invalid prices are ignored
+	assert.False(t, model.Update(0, pricesB[last]))
+}
+
+func TestSpreadModel_Kalman(t *testing.T) {
+	pricesA, pricesB := cointegratedPrices(300)
+
+	model := NewSpreadModel(types.Interval1h, 100, HedgeRatioMethodKalman)
+	for i := range pricesA {
+		model.Update(pricesA[i], pricesB[i])
+	}
+
+	assert.InDelta(t, 1.5, model.HedgeRatio, 0.1)
+
+	last := len(pricesA) - 1
+	assert.True(t, model.Update(pricesA[last]*0.98, pricesB[last]))
+	assert.Less(t, model.ZScore, -3.0)
+}
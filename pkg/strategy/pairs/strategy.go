@@ -0,0 +1,496 @@
+package pairs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const ID = "pairs"
+
+const (
+	legA = "A"
+	legB = "B"
+)
+
+var log = logrus.WithField("strategy", ID)
+
+func init() {
+	bbgo.RegisterStrategy(ID, &Strategy{})
+}
+
+// SpreadDirection is the direction of the spread position
+type SpreadDirection int
+
+const (
+	SpreadFlat SpreadDirection = 0
+
+	// SpreadLong buys leg A and sells leg B, the spread is expected to go up
+	SpreadLong SpreadDirection = 1
+
+	// SpreadShort sells leg A and buys leg B, the spread is expected to go down
+	SpreadShort SpreadDirection = -1
+)
+
+func (d SpreadDirection) String() string {
+	switch d {
+	case SpreadLong:
+		return "long"
+	case SpreadShort:
+		return "short"
+	}
+
+	return "flat"
+}
+
+type Leg struct {
+	Session string `json:"session"`
+	Symbol  string `json:"symbol"`
+}
+
+type State struct {
+	Direction SpreadDirection `json:"direction"`
+
+	// HedgeRatio is the hedge ratio when the spread position was opened
+	HedgeRatio fixedpoint.Value `json:"hedgeRatio"`
+
+	// EntryZScore is the z-score when the spread position was opened
+	EntryZScore fixedpoint.Value `json:"entryZScore"`
+
+	PositionStartTime time.Time `json:"positionStartTime"`
+}
+
+// Strategy trades the spread of two cointegrated symbols, possibly on different sessions.
+// The hedge ratio is the slope of the rolling regression of the log prices, the spread position is opened
+// when the z-score of the spread exceeds the entry threshold and closed when it reverts below the exit threshold.
+type Strategy struct {
+	Environment *bbgo.Environment
+
+	LegA Leg `json:"legA"`
+	LegB Leg `json:"legB"`
+
+	Interval types.Interval `json:"interval"`
+
+	// Window is the rolling window of the hedge ratio regression and the spread z-score
+	Window int `json:"window"`
+
+	// HedgeRatioMethod is the hedge ratio estimation method: ols or kalman
+	HedgeRatioMethod HedgeRatioMethod `json:"hedgeRatioMethod"`
+
+	// QuoteInvestment is the notional value of leg A, the notional value of leg B is QuoteInvestment * hedge ratio
+	QuoteInvestment fixedpoint.Value `json:"quoteInvestment"`
+
+	// EntryZScore opens the spread position when the absolute z-score is greater than this value
+	EntryZScore fixedpoint.Value `json:"entryZScore"`
+
+	// ExitZScore closes the spread position when the absolute z-score reverts below this value
+	ExitZScore fixedpoint.Value `json:"exitZScore"`
+
+	// StopZScore closes the spread position when the absolute z-score diverges above this value, 0 disables it
+	StopZScore fixedpoint.Value `json:"stopZScore"`
+
+	// Reset your position info
+	Reset bool `json:"reset"`
+
+	ProfitStats *types.ProfitStats `persistence:"profit_stats"`
+
+	// Positions stores the position of each leg
+	Positions map[string]*types.Position `persistence:"positions"`
+
+	State *State `persistence:"state"`
+
+	mu sync.Mutex
+
+	model *SpreadModel
+
+	legs           map[string]Leg
+	markets        map[string]types.Market
+	orderExecutors map[string]*bbgo.GeneralOrderExecutor
+	lastKLines     map[string]types.KLine
+	lastBarTime    time.Time
+}
+
+func (s *Strategy) ID() string {
+	return ID
+}
+
+func (s *Strategy) InstanceID() string {
+	return fmt.Sprintf("%s-%s-%s-%s", ID, s.LegA.Symbol, s.LegB.Symbol, s.Interval)
+}
+
+func (s *Strategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {
+	for _, leg := range []Leg{s.LegA, s.LegB} {
+		session, ok := sessions[leg.Session]
+		if !ok {
+			panic(fmt.Errorf("session %s is not defined", leg.Session))
+		}
+
+		session.Subscribe(types.KLineChannel, leg.Symbol, types.SubscribeOptions{Interval: s.Interval})
+	}
+}
+
+func (s *Strategy) Defaults() error {
+	if s.Interval == "" {
+		s.Interval = types.Interval1h
+	}
+
+	if s.Window == 0 {
+		s.Window = 120
+	}
+
+	if s.HedgeRatioMethod == "" {
+		s.HedgeRatioMethod = HedgeRatioMethodOLS
+	}
+
+	if s.EntryZScore.IsZero() {
+		s.EntryZScore = fixedpoint.NewFromInt(2)
+	}
+
+	if s.ExitZScore.IsZero() {
+		s.ExitZScore = fixedpoint.NewFromFloat(0.5)
+	}
+
+	return nil
+}
+
+func (s *Strategy) Validate() error {
+	if len(s.LegA.Session) == 0 || len(s.LegA.Symbol) == 0 {
+		return errors.New("legA session and symbol are required")
+	}
+
+	if len(s.LegB.Session) == 0 || len(s.LegB.Symbol) == 0 {
+		return errors.New("legB session and symbol are required")
+	}
+
+	if s.LegA == s.LegB {
+		return errors.New("legA and legB must be different")
+	}
+
+	switch s.HedgeRatioMethod {
+	case HedgeRatioMethodOLS, HedgeRatioMethodKalman:
+	default:
+		return fmt.Errorf("unsupported hedgeRatioMethod %q", s.HedgeRatioMethod)
+	}
+
+	if s.Window < 2 {
+		return errors.New("window must be greater than 1")
+	}
+
+	if s.QuoteInvestment.Sign() <= 0 {
+		return errors.New("quoteInvestment must be greater than zero")
+	}
+
+	if s.ExitZScore.Compare(s.EntryZScore) >= 0 {
+		return errors.New("exitZScore must be less than entryZScore")
+	}
+
+	if s.StopZScore.Sign() > 0 && s.StopZScore.Compare(s.EntryZScore) <= 0 {
+		return errors.New("stopZScore must be greater than entryZScore")
+	}
+
+	return nil
+}
+
+func (s *Strategy) CrossRun(
+	ctx context.Context, _ bbgo.OrderExecutionRouter, sessions map[string]*bbgo.ExchangeSession,
+) error {
+	instanceID := s.InstanceID()
+
+	s.legs = map[string]Leg{legA: s.LegA, legB: s.LegB}
+	s.markets = make(map[string]types.Market)
+	s.orderExecutors = make(map[string]*bbgo.GeneralOrderExecutor)
+	s.lastKLines = make(map[string]types.KLine)
+	s.model = NewSpreadModel(s.Interval, s.Window, s.HedgeRatioMethod)
+
+	if s.ProfitStats == nil || s.Reset {
+		s.ProfitStats = types.NewProfitStats(types.Market{Symbol: s.LegA.Symbol + "/" + s.LegB.Symbol})
+	}
+
+	if s.Positions == nil || s.Reset {
+		s.Positions = make(map[string]*types.Position)
+	}
+
+	if s.State == nil || s.Reset {
+		s.State = &State{}
+	}
+
+	for name, leg := range s.legs {
+		session := sessions[leg.Session]
+
+		market, ok := session.Market(leg.Symbol)
+		if !ok {
+			return fmt.Errorf("market %s is not found in session %s", leg.Symbol, leg.Session)
+		}
+
+		position, ok := s.Positions[name]
+		if !ok {
+			position = types.NewPositionFromMarket(market)
+			s.Positions[name] = position
+		}
+
+		position.Strategy = ID
+		position.StrategyInstanceID = instanceID
+
+		s.markets[name] = market
+		s.orderExecutors[name] = s.allocateOrderExecutor(ctx, session, leg.Symbol, instanceID, position)
+	}
+
+	log.Infof("state: %+v", s.State)
+	for name, position := range s.Positions {
+		log.Infof("loaded leg %s position: %s", name, position.String())
+	}
+
+	s.preloadKLines(sessions)
+
+	for name, leg := range s.legs {
+		name := name
+		session := sessions[leg.Session]
+		session.MarketDataStream.OnKLineClosed(types.KLineWith(leg.Symbol, s.Interval, func(k types.KLine) {
+			s.handleKLineClosed(ctx, name, k)
+		}))
+	}
+
+	bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		for _, orderExecutor := range s.orderExecutors {
+			_ = orderExecutor.GracefulCancel(ctx)
+			orderExecutor.TradeCollector().Process()
+		}
+
+		bbgo.Sync(ctx, s)
+	})
+
+	return nil
+}
+
+// preloadKLines feeds the loaded klines of both legs into the spread model, the klines are aligned by the start time
+func (s *Strategy) preloadKLines(sessions map[string]*bbgo.ExchangeSession) {
+	klinesA, ok := s.loadedKLines(sessions[s.LegA.Session], s.LegA.Symbol)
+	if !ok {
+		return
+	}
+
+	klinesB, ok := s.loadedKLines(sessions[s.LegB.Session], s.LegB.Symbol)
+	if !ok {
+		return
+	}
+
+	closesB := make(map[int64]fixedpoint.Value, len(klinesB))
+	for _, k := range klinesB {
+		closesB[k.StartTime.Unix()] = k.Close
+	}
+
+	for _, k := range klinesA {
+		closeB, ok := closesB[k.StartTime.Unix()]
+		if !ok {
+			continue
+		}
+
+		s.model.Update(k.Close.Float64(), closeB.Float64())
+		s.lastBarTime = k.StartTime.Time()
+	}
+}
+
+func (s *Strategy) loadedKLines(session *bbgo.ExchangeSession, symbol string) (types.KLineWindow, bool) {
+	store, ok := session.MarketDataStore(symbol)
+	if !ok {
+		return nil, false
+	}
+
+	klines, ok := store.KLinesOfInterval(s.Interval)
+	if !ok {
+		return nil, false
+	}
+
+	return *klines, true
+}
+
+// handleKLineClosed updates the spread model when the closed klines of both legs have the same start time
+func (s *Strategy) handleKLineClosed(ctx context.Context, name string, k types.KLine) {
+	s.mu.Lock()
+	s.lastKLines[name] = k
+
+	kA, okA := s.lastKLines[legA]
+	kB, okB := s.lastKLines[legB]
+	if !okA || !okB || !kA.StartTime.Equal(kB.StartTime.Time()) || !kA.StartTime.After(s.lastBarTime) {
+		s.mu.Unlock()
+		return
+	}
+
+	s.lastBarTime = kA.StartTime.Time()
+	ready := s.model.Update(kA.Close.Float64(), kB.Close.Float64())
+	zScore := fixedpoint.NewFromFloat(s.model.ZScore)
+	hedgeRatio := fixedpoint.NewFromFloat(s.model.HedgeRatio)
+	s.mu.Unlock()
+
+	if !ready {
+		return
+	}
+
+	log.Infof("%s/%s spread z-score: %f, hedge ratio: %f", s.LegA.Symbol, s.LegB.Symbol, zScore.Float64(), hedgeRatio.Float64())
+
+	s.tick(ctx, kA.EndTime.Time(), zScore, hedgeRatio, kA.Close, kB.Close)
+}
+
+func (s *Strategy) tick(ctx context.Context, now time.Time, zScore, hedgeRatio, priceA, priceB fixedpoint.Value) {
+	direction := s.getDirection()
+	if direction == SpreadFlat {
+		switch {
+		case zScore.Compare(s.EntryZScore) >= 0:
+			direction = SpreadShort
+		case zScore.Compare(s.EntryZScore.Neg()) <= 0:
+			direction = SpreadLong
+		default:
+			return
+		}
+
+		if err := s.openPosition(ctx, now, direction, zScore, hedgeRatio, priceA, priceB); err != nil {
+			log.WithError(err).Errorf("unable to open %s spread position", direction.String())
+		}
+		return
+	}
+
+	// normalize the z-score to the direction of the position, so that the position profits when the normalized z-score goes up
+	normalized := zScore.Mul(fixedpoint.NewFromInt(int64(direction)))
+
+	if s.StopZScore.Sign() > 0 && normalized.Compare(s.StopZScore.Neg()) <= 0 {
+		s.closePosition(ctx, fmt.Sprintf("z-score %f diverged beyond the stop z-score %f", zScore.Float64(), s.StopZScore.Float64()))
+		return
+	}
+
+	if normalized.Compare(s.ExitZScore.Neg()) >= 0 {
+		s.closePosition(ctx, fmt.Sprintf("z-score %f reverted within the exit z-score %f", zScore.Float64(), s.ExitZScore.Float64()))
+	}
+}
+
+// openPosition submits the orders of both legs at the same time
+func (s *Strategy) openPosition(
+	ctx context.Context, now time.Time, direction SpreadDirection, zScore, hedgeRatio, priceA, priceB fixedpoint.Value,
+) error {
+	if hedgeRatio.Sign() <= 0 {
+		return fmt.Errorf("hedge ratio %f is not positive, the legs are not moving together", hedgeRatio.Float64())
+	}
+
+	marketA := s.markets[legA]
+	marketB := s.markets[legB]
+
+	quantityA := marketA.TruncateQuantity(s.QuoteInvestment.Div(priceA))
+	quantityB := marketB.TruncateQuantity(s.QuoteInvestment.Mul(hedgeRatio).Div(priceB))
+	if marketA.IsDustQuantity(quantityA, priceA) || marketB.IsDustQuantity(quantityB, priceB) {
+		return fmt.Errorf("dust quantity %s %s / %s %s, please increase quoteInvestment",
+			quantityA.String(), s.LegA.Symbol, quantityB.String(), s.LegB.Symbol)
+	}
+
+	sideA, sideB := types.SideTypeBuy, types.SideTypeSell
+	if direction == SpreadShort {
+		sideA, sideB = types.SideTypeSell, types.SideTypeBuy
+	}
+
+	s.mu.Lock()
+	s.State.Direction = direction
+	s.State.HedgeRatio = hedgeRatio
+	s.State.EntryZScore = zScore
+	s.State.PositionStartTime = now
+	s.mu.Unlock()
+
+	bbgo.Notify("%s opening %s spread position at z-score %f: %s %s %s, %s %s %s",
+		s.InstanceID(), direction.String(), zScore.Float64(),
+		sideA, quantityA.String(), s.LegA.Symbol,
+		sideB, quantityB.String(), s.LegB.Symbol)
+
+	var g errgroup.Group
+	g.Go(func() error {
+		_, err := s.orderExecutors[legA].SubmitOrders(ctx, types.SubmitOrder{
+			Symbol:   s.LegA.Symbol,
+			Side:     sideA,
+			Type:     types.OrderTypeMarket,
+			Quantity: quantityA,
+			Market:   marketA,
+		})
+		return err
+	})
+	g.Go(func() error {
+		_, err := s.orderExecutors[legB].SubmitOrders(ctx, types.SubmitOrder{
+			Symbol:   s.LegB.Symbol,
+			Side:     sideB,
+			Type:     types.OrderTypeMarket,
+			Quantity: quantityB,
+			Market:   marketB,
+		})
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		// one of the legs might be filled, close the opened leg to avoid the naked exposure
+		s.closePosition(ctx, "failed to open the spread position: "+err.Error())
+		return err
+	}
+
+	bbgo.Sync(ctx, s)
+	return nil
+}
+
+// closePosition closes both legs at the same time
+func (s *Strategy) closePosition(ctx context.Context, reason string) {
+	log.Infof("closing spread position: %s", reason)
+	bbgo.Notify("%s closing spread position: %s", s.InstanceID(), reason)
+
+	var g errgroup.Group
+	for name, orderExecutor := range s.orderExecutors {
+		name, orderExecutor := name, orderExecutor
+		if orderExecutor.Position().GetBase().IsZero() {
+			continue
+		}
+
+		g.Go(func() error {
+			if err := orderExecutor.GracefulCancel(ctx); err != nil {
+				log.WithError(err).Errorf("unable to cancel leg %s orders", name)
+			}
+
+			return orderExecutor.ClosePosition(ctx, fixedpoint.One)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		log.WithError(err).Errorf("unable to close the spread position, will retry on the next bar")
+		bbgo.Sync(ctx, s)
+		return
+	}
+
+	s.mu.Lock()
+	s.State.Direction = SpreadFlat
+	s.mu.Unlock()
+
+	bbgo.Notify("%s spread position closed", s.InstanceID(), s.ProfitStats)
+	bbgo.Sync(ctx, s)
+}
+
+func (s *Strategy) getDirection() SpreadDirection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.State.Direction
+}
+
+func (s *Strategy) allocateOrderExecutor(
+	ctx context.Context, session *bbgo.ExchangeSession, symbol, instanceID string, position *types.Position,
+) *bbgo.GeneralOrderExecutor {
+	orderExecutor := bbgo.NewGeneralOrderExecutor(session, symbol, ID, instanceID, position)
+	orderExecutor.SetMaxRetries(0)
+	orderExecutor.BindEnvironment(s.Environment)
+	orderExecutor.BindProfitStats(s.ProfitStats)
+	orderExecutor.Bind()
+	orderExecutor.TradeCollector().OnPositionUpdate(func(position *types.Position) {
+		bbgo.Sync(ctx, s)
+	})
+	return orderExecutor
+}
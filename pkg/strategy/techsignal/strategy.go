@@ -3,6 +3,7 @@ package techsignal
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/c9s/bbgo/pkg/exchange/binance"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator/v2/expr"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/types"
@@ -57,6 +59,19 @@ type Strategy struct {
 
 		MinQuoteVolume fixedpoint.Value `json:"minQuoteVolume"`
 	} `json:"supportDetection"`
+
+	// Signals are the indicator expressions, the notification is sent when the expression becomes true, for example:
+	//
+	//  signal: "cross_over(ema(close, 12), ema(close, 26)) and rsi(close, 14) < 70"
+	Signals []SignalConfig `json:"signals"`
+}
+
+type SignalConfig struct {
+	Interval types.Interval `json:"interval"`
+	Signal   string         `json:"signal"`
+
+	// Message is the notification message, the signal expression is used if it's empty
+	Message string `json:"message"`
 }
 
 func (s *Strategy) ID() string {
@@ -74,6 +89,12 @@ func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
 			Interval: detection.MovingAverageInterval,
 		})
 	}
+
+	for _, signal := range s.Signals {
+		session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{
+			Interval: signal.Interval,
+		})
+	}
 }
 
 func (s *Strategy) Validate() error {
@@ -81,6 +102,16 @@ func (s *Strategy) Validate() error {
 		return errors.New("symbol is required")
 	}
 
+	for _, signal := range s.Signals {
+		if len(signal.Interval) == 0 {
+			return fmt.Errorf("signal %q: interval is required", signal.Signal)
+		}
+
+		if err := expr.Check(signal.Signal); err != nil {
+			return fmt.Errorf("signal %q: %w", signal.Signal, err)
+		}
+	}
+
 	return nil
 }
 
@@ -154,6 +185,12 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 		}
 	}
 
+	for _, signal := range s.Signals {
+		if err := s.bindSignal(session, signal); err != nil {
+			return err
+		}
+	}
+
 	session.MarketDataStream.OnKLineClosed(func(kline types.KLine) {
 		// skip k-lines from other symbols
 		if kline.Symbol != s.Symbol {
@@ -219,3 +256,26 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 	})
 	return nil
 }
+
+func (s *Strategy) bindSignal(session *bbgo.ExchangeSession, signal SignalConfig) error {
+	stream, err := session.Indicators(s.Symbol).Expr(signal.Interval, signal.Signal)
+	if err != nil {
+		return fmt.Errorf("signal %q: %w", signal.Signal, err)
+	}
+
+	message := signal.Message
+	if len(message) == 0 {
+		message = signal.Signal
+	}
+
+	// the historical klines are replayed in the compile step, so only the new klines trigger the notification
+	stream.OnUpdate(func(v float64) {
+		if !stream.Triggered() {
+			return
+		}
+
+		log.Infof("%s %s signal triggered: %s", s.Symbol, signal.Interval, signal.Signal)
+		bbgo.Notify("%s %s signal: %s", s.Symbol, signal.Interval, message)
+	})
+	return nil
+}
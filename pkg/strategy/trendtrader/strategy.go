@@ -66,6 +66,14 @@ func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
 	s.ExitMethods.SetAndSubscribe(session, s)
 }
 
+func (s *Strategy) Validate() error {
+	if s.TrendLine != nil {
+		return s.TrendLine.Validate()
+	}
+
+	return nil
+}
+
 func (s *Strategy) ID() string {
 	return ID
 }
@@ -122,7 +130,9 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 	}
 
 	if s.TrendLine != nil {
-		s.TrendLine.Bind(session, s.orderExecutor)
+		if err := s.TrendLine.Bind(session, s.orderExecutor); err != nil {
+			return err
+		}
 	}
 
 	bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
@@ -2,10 +2,12 @@ package trendtrader
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/indicator/v2/expr"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -21,6 +23,11 @@ type TrendLine struct {
 
 	Quantity fixedpoint.Value `json:"quantity"`
 
+	// LongFilter and ShortFilter are the optional indicator expressions that must be true to open a new position
+	// on the breakout, for example: "rsi(close, 14) < 70 and close > ema(close, 99)"
+	LongFilter  string `json:"longFilter"`
+	ShortFilter string `json:"shortFilter"`
+
 	orderExecutor *bbgo.GeneralOrderExecutor
 	session       *bbgo.ExchangeSession
 	activeOrders  *bbgo.ActiveOrderBook
@@ -28,6 +35,8 @@ type TrendLine struct {
 	pivotHigh *indicator.PivotHigh
 	pivotLow  *indicator.PivotLow
 
+	longFilter, shortFilter *expr.Stream
+
 	bbgo.QuantityOrAmount
 }
 
@@ -40,7 +49,42 @@ func (s *TrendLine) Subscribe(session *bbgo.ExchangeSession) {
 	// }
 }
 
-func (s *TrendLine) Bind(session *bbgo.ExchangeSession, orderExecutor *bbgo.GeneralOrderExecutor) {
+// Validate checks the syntax of the filter expressions
+func (s *TrendLine) Validate() error {
+	for _, filter := range []string{s.LongFilter, s.ShortFilter} {
+		if len(filter) == 0 {
+			continue
+		}
+
+		if err := expr.Check(filter); err != nil {
+			return fmt.Errorf("trendLine filter %q: %w", filter, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *TrendLine) compileFilter(session *bbgo.ExchangeSession, filter string) (*expr.Stream, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+
+	stream, err := session.Indicators(s.Symbol).Expr(s.Interval, filter)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile the trendLine filter %q: %w", filter, err)
+	}
+
+	return stream, nil
+}
+
+// allowed returns true if the filter is not set or the filter expression is true on the last kline
+func allowed(filter *expr.Stream) bool {
+	return filter == nil || filter.Truthy()
+}
+
+// Bind binds the trend line to the kline stream of the session,
+// an error is returned if the filter expressions can not be compiled with the indicators of the session.
+func (s *TrendLine) Bind(session *bbgo.ExchangeSession, orderExecutor *bbgo.GeneralOrderExecutor) error {
 	s.session = session
 	s.orderExecutor = orderExecutor
 
@@ -70,6 +114,16 @@ func (s *TrendLine) Bind(session *bbgo.ExchangeSession, orderExecutor *bbgo.Gene
 	supportSlope1 := 0.
 	supportSlope2 := 0.
 
+	// the filters must be compiled before the kline callback is registered, so that they are updated first
+	var err error
+	if s.longFilter, err = s.compileFilter(session, s.LongFilter); err != nil {
+		return err
+	}
+
+	if s.shortFilter, err = s.compileFilter(session, s.ShortFilter); err != nil {
+		return err
+	}
+
 	session.MarketDataStream.OnKLineClosed(types.KLineWith(s.Symbol, s.Interval, func(kline types.KLine) {
 		if s.pivotHigh.Last(0) != resistancePrices.Last(0) {
 			resistancePrices.Update(s.pivotHigh.Last(0))
@@ -109,7 +163,7 @@ func (s *TrendLine) Bind(session *bbgo.ExchangeSession, orderExecutor *bbgo.Gene
 				if position.IsShort() {
 					s.orderExecutor.ClosePosition(context.Background(), one)
 				}
-				if position.IsDust(kline.Close) || position.IsClosed() {
+				if (position.IsDust(kline.Close) || position.IsClosed()) && allowed(s.longFilter) {
 					s.placeOrder(context.Background(), types.SideTypeBuy, s.Quantity, symbol) // OrAmount.CalculateQuantity(kline.Close)
 				}
 
@@ -117,7 +171,7 @@ func (s *TrendLine) Bind(session *bbgo.ExchangeSession, orderExecutor *bbgo.Gene
 				if position.IsLong() {
 					s.orderExecutor.ClosePosition(context.Background(), one)
 				}
-				if position.IsDust(kline.Close) || position.IsClosed() {
+				if (position.IsDust(kline.Close) || position.IsClosed()) && allowed(s.shortFilter) {
 					s.placeOrder(context.Background(), types.SideTypeSell, s.Quantity, symbol) // OrAmount.CalculateQuantity(kline.Close)
 				}
 			}
@@ -128,6 +182,8 @@ func (s *TrendLine) Bind(session *bbgo.ExchangeSession, orderExecutor *bbgo.Gene
 		session.MarketDataStream.OnMarketTrade(func(trade types.Trade) {
 		})
 	}
+
+	return nil
 }
 
 func (s *TrendLine) placeOrder(
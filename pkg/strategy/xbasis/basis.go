@@ -0,0 +1,73 @@
+package xbasis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const yearDuration = 365 * 24 * time.Hour
+
+// calculateBasis returns the basis ratio of the futures price to the spot price
+func calculateBasis(spotPrice, futuresPrice fixedpoint.Value) fixedpoint.Value {
+	if spotPrice.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	return futuresPrice.Sub(spotPrice).Div(spotPrice)
+}
+
+// annualize converts the return ratio over the given duration into the annualized return ratio
+func annualize(ratio fixedpoint.Value, duration time.Duration) fixedpoint.Value {
+	if duration <= 0 {
+		return fixedpoint.Zero
+	}
+
+	return ratio.Mul(fixedpoint.NewFromFloat(float64(yearDuration) / float64(duration)))
+}
+
+// findDeliveryContracts finds the dated futures contracts of the given base and quote currency,
+// the contracts that will be delivered before the given deadline are excluded.
+// the returned contracts are sorted by the delivery time.
+func findDeliveryContracts(markets types.MarketMap, baseCurrency, quoteCurrency string, deadline time.Time) []types.Market {
+	var contracts []types.Market
+	for _, market := range markets {
+		if !market.IsDeliveryContract() {
+			continue
+		}
+
+		if market.BaseCurrency != baseCurrency || market.QuoteCurrency != quoteCurrency {
+			continue
+		}
+
+		if !market.DeliveryTime.After(deadline) {
+			continue
+		}
+
+		contracts = append(contracts, market)
+	}
+
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].DeliveryTime.Before(contracts[j].DeliveryTime)
+	})
+
+	return contracts
+}
+
+// contractQuantity converts the base quantity into the order quantity of the contract, e.g. the number of contracts on OKX,
+// the quantity is truncated by the step size of the contract.
+func contractQuantity(contract types.Market, base fixedpoint.Value) fixedpoint.Value {
+	return contract.TruncateQuantity(contract.ToContractQuantity(base))
+}
+
+// isDustContractQuantity checks the contract quantity against the min quantity and the min notional of the contract,
+// unlike Market.IsDustQuantity, the min quantity is tradable, e.g. 1 contract is tradable when the min size is 1.
+func isDustContractQuantity(contract types.Market, quantity, price fixedpoint.Value) bool {
+	if quantity.Sign() <= 0 || quantity.Compare(contract.MinQuantity) < 0 {
+		return true
+	}
+
+	return contract.ToBaseQuantity(quantity).Mul(price).Compare(contract.MinNotional) < 0
+}
@@ -0,0 +1,166 @@
+package xbasis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_calculateBasis(t *testing.T) {
+	basis := calculateBasis(fixedpoint.NewFromInt(50000), fixedpoint.NewFromInt(51000))
+	assert.Equal(t, "0.02", basis.String())
+
+	assert.True(t, calculateBasis(fixedpoint.Zero, fixedpoint.NewFromInt(51000)).IsZero())
+}
+
+func Test_annualize(t *testing.T) {
+	annualized := annualize(fixedpoint.NewFromFloat(0.02), yearDuration/4)
+	assert.InDelta(t, 0.08, annualized.Float64(), 1e-8)
+
+	assert.True(t, annualize(fixedpoint.NewFromFloat(0.02), 0).IsZero())
+}
+
+func Test_findDeliveryContracts(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	markets := types.MarketMap{
+		"BTCUSDT": {
+			Symbol:        "BTCUSDT",
+			BaseCurrency:  "BTC",
+			QuoteCurrency: "USDT",
+			ContractType:  types.ContractTypePerpetual,
+		},
+		"BTCUSDT_250627": {
+			Symbol:        "BTCUSDT_250627",
+			BaseCurrency:  "BTC",
+			QuoteCurrency: "USDT",
+			ContractType:  types.ContractTypeNextQuarter,
+			DeliveryTime:  time.Date(2025, 6, 27, 8, 0, 0, 0, time.UTC),
+		},
+		"BTCUSDT_250328": {
+			Symbol:        "BTCUSDT_250328",
+			BaseCurrency:  "BTC",
+			QuoteCurrency: "USDT",
+			ContractType:  types.ContractTypeCurrentQuarter,
+			DeliveryTime:  time.Date(2025, 3, 28, 8, 0, 0, 0, time.UTC),
+		},
+		"ETHUSDT_250328": {
+			Symbol:        "ETHUSDT_250328",
+			BaseCurrency:  "ETH",
+			QuoteCurrency: "USDT",
+			ContractType:  types.ContractTypeCurrentQuarter,
+			DeliveryTime:  time.Date(2025, 3, 28, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	contracts := findDeliveryContracts(markets, "BTC", "USDT", now)
+	if assert.Len(t, contracts, 2) {
+		assert.Equal(t, "BTCUSDT_250328", contracts[0].Symbol)
+		assert.Equal(t, "BTCUSDT_250627", contracts[1].Symbol)
+	}
+
+	contracts = findDeliveryContracts(markets, "BTC", "USDT", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	if assert.Len(t, contracts, 1) {
+		assert.Equal(t, "BTCUSDT_250627", contracts[0].Symbol)
+	}
+}
+
+func TestProfitStats_AnnualizedCarry(t *testing.T) {
+	openedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stats := &ProfitStats{}
+	stats.AddCarry(CarryRecord{
+		FuturesSymbol: "BTCUSDT_250328",
+		Notional:      fixedpoint.NewFromInt(10000),
+		Carry:         fixedpoint.NewFromInt(100),
+		OpenedAt:      openedAt,
+		ClosedAt:      openedAt.Add(yearDuration / 4),
+	})
+
+	assert.Equal(t, "100", stats.TotalCarry.String())
+	assert.InDelta(t, 0.04, stats.CarryRecords[0].AnnualizedCarry().Float64(), 1e-8)
+	assert.InDelta(t, 0.04, stats.AnnualizedCarry().Float64(), 1e-8)
+}
+
+func Test_contractQuantity(t *testing.T) {
+	// OKX BTC-USDT-250328, one contract is 0.01 BTC
+	contract := types.Market{
+		Symbol:        "BTC-USDT-250328",
+		StepSize:      fixedpoint.One,
+		MinQuantity:   fixedpoint.One,
+		ContractValue: fixedpoint.NewFromFloat(0.01),
+	}
+
+	contracts := contractQuantity(contract, fixedpoint.NewFromFloat(0.0567))
+	assert.Equal(t, "5", contracts.String())
+	assert.Equal(t, "0.05", contract.ToBaseQuantity(contracts).String())
+	assert.False(t, isDustContractQuantity(contract, fixedpoint.One, fixedpoint.NewFromInt(50000)))
+	assert.True(t, isDustContractQuantity(contract, contractQuantity(contract, fixedpoint.NewFromFloat(0.009)), fixedpoint.NewFromInt(50000)))
+
+	// the linear futures of Bybit are traded in the base quantity
+	linear := types.Market{
+		Symbol:      "BTCUSDT-27DEC24",
+		StepSize:    fixedpoint.NewFromFloat(0.001),
+		MinQuantity: fixedpoint.NewFromFloat(0.001),
+		MinNotional: fixedpoint.NewFromInt(5),
+	}
+	assert.Equal(t, "0.056", contractQuantity(linear, fixedpoint.NewFromFloat(0.0567)).String())
+	assert.True(t, isDustContractQuantity(linear, fixedpoint.NewFromFloat(0.001), fixedpoint.NewFromInt(1000)))
+}
+
+func TestStrategy_expireContract(t *testing.T) {
+	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	spotMarket := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+
+	s := &Strategy{
+		Symbol:          "BTCUSDT",
+		FuturesSession:  "binance_futures",
+		spotMarket:      spotMarket,
+		SpotPosition:    types.NewPositionFromMarket(spotMarket),
+		FuturesPosition: types.NewPosition("BTCUSDT_250627", "BTC", "USDT"),
+		ProfitStats: &ProfitStats{
+			ProfitStats: types.NewProfitStats(spotMarket),
+			TotalCarry:  fixedpoint.Zero,
+		},
+		State: &State{
+			PositionState:     PositionReady,
+			FuturesSymbol:     "BTCUSDT_250627",
+			DeliveryTime:      time.Date(2025, 6, 27, 8, 0, 0, 0, time.UTC),
+			Quantity:          fixedpoint.NewFromFloat(0.1),
+			EntrySpotPrice:    fixedpoint.NewFromInt(100000),
+			EntryFuturesPrice: fixedpoint.NewFromInt(102000),
+			PositionStartTime: time.Date(2025, 3, 27, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	s.SpotPosition.Base = fixedpoint.NewFromFloat(0.1)
+	s.FuturesPosition.Base = fixedpoint.NewFromFloat(-0.1)
+
+	s.expireContract(now)
+
+	assert.Equal(t, PositionReady, s.getPositionState())
+	assert.True(t, s.FuturesPosition.GetBase().IsZero())
+	assert.Equal(t, "0.1", s.SpotPosition.GetBase().String())
+	assert.Nil(t, s.futuresOrderExecutor)
+
+	// the delivered short leg is closed without the order executor, the carry is the entry basis
+	assert.NoError(t, s.closeFuturesLeg(context.Background(), fixedpoint.NewFromInt(101000), now))
+	if assert.Len(t, s.ProfitStats.CarryRecords, 1) {
+		assert.Equal(t, "BTCUSDT_250627", s.ProfitStats.CarryRecords[0].FuturesSymbol)
+		assert.Equal(t, "200", s.ProfitStats.TotalCarry.String())
+	}
+
+	t.Run("not delivered yet", func(t *testing.T) {
+		s.State.PositionState = PositionOpening
+		s.State.DeliveryTime = now.Add(24 * time.Hour)
+		s.FuturesPosition = nil
+
+		s.expireContract(now)
+
+		assert.Equal(t, PositionReady, s.getPositionState())
+		assert.Equal(t, now, s.State.DeliveryTime)
+		assert.True(t, s.FuturesPosition.GetBase().IsZero())
+	})
+}
@@ -0,0 +1,101 @@
+package xbasis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/style"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// CarryRecord is the captured basis of one closed (or rolled) cash-and-carry position
+type CarryRecord struct {
+	FuturesSymbol string           `json:"futuresSymbol"`
+	Quantity      fixedpoint.Value `json:"quantity"`
+	Notional      fixedpoint.Value `json:"notional"`
+	Carry         fixedpoint.Value `json:"carry"`
+	OpenedAt      time.Time        `json:"openedAt"`
+	ClosedAt      time.Time        `json:"closedAt"`
+}
+
+// AnnualizedCarry returns the annualized return of the carry over the notional
+func (r *CarryRecord) AnnualizedCarry() fixedpoint.Value {
+	if r.Notional.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	return annualize(r.Carry.Div(r.Notional), r.ClosedAt.Sub(r.OpenedAt))
+}
+
+func (r *CarryRecord) SlackAttachment() slack.Attachment {
+	return slack.Attachment{
+		Title: fmt.Sprintf("Carry %s %s", r.FuturesSymbol, style.PnLSignString(r.Carry)),
+		Color: style.PnLColor(r.Carry),
+		Fields: []slack.AttachmentField{
+			{Title: "Quantity", Value: r.Quantity.String(), Short: true},
+			{Title: "Notional", Value: r.Notional.String(), Short: true},
+			{Title: "Annualized Carry", Value: r.AnnualizedCarry().Percentage(), Short: true},
+		},
+		Footer: fmt.Sprintf("Opened At %s Closed At %s", r.OpenedAt.Format(time.RFC822), r.ClosedAt.Format(time.RFC822)),
+	}
+}
+
+type ProfitStats struct {
+	*types.ProfitStats
+
+	// TotalCarry is the total basis captured by the closed positions, in the quote currency
+	TotalCarry   fixedpoint.Value `json:"totalCarry"`
+	CarryRecords []CarryRecord    `json:"carryRecords"`
+}
+
+func (s *ProfitStats) AddCarry(record CarryRecord) {
+	s.CarryRecords = append(s.CarryRecords, record)
+	s.TotalCarry = s.TotalCarry.Add(record.Carry)
+}
+
+// AnnualizedCarry returns the time and notional weighted annualized carry of all the carry records
+func (s *ProfitStats) AnnualizedCarry() fixedpoint.Value {
+	var totalCarry, totalExposure float64
+	for _, record := range s.CarryRecords {
+		duration := record.ClosedAt.Sub(record.OpenedAt)
+		if duration <= 0 {
+			continue
+		}
+
+		totalCarry += record.Carry.Float64()
+		totalExposure += record.Notional.Float64() * float64(duration) / float64(yearDuration)
+	}
+
+	if totalExposure == 0 {
+		return fixedpoint.Zero
+	}
+
+	return fixedpoint.NewFromFloat(totalCarry / totalExposure)
+}
+
+func (s *ProfitStats) SlackAttachment() slack.Attachment {
+	var fields []slack.AttachmentField
+	if s.ProfitStats != nil {
+		fields = append(fields, slack.AttachmentField{
+			Title: "Accumulated Net Profit",
+			Value: style.PnLSignString(s.AccumulatedNetProfit),
+			Short: true,
+		})
+	}
+
+	fields = append(fields, slack.AttachmentField{
+		Title: "Annualized Carry",
+		Value: s.AnnualizedCarry().Percentage(),
+		Short: true,
+	})
+
+	return slack.Attachment{
+		Title:  fmt.Sprintf("Total Carry: %s", style.PnLSignString(s.TotalCarry)),
+		Color:  style.PnLColor(s.TotalCarry),
+		Fields: fields,
+		Footer: fmt.Sprintf("%d carry records", len(s.CarryRecords)),
+	}
+}
@@ -0,0 +1,673 @@
+package xbasis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const ID = "xbasis"
+
+// Position State Transitions:
+// Closed -> Opening -> Ready -> Closing -> Closed
+//
+//go:generate stringer -type=PositionState
+type PositionState int
+
+const (
+	PositionClosed PositionState = iota
+	PositionOpening
+	PositionReady
+	PositionClosing
+)
+
+var log = logrus.WithField("strategy", ID)
+
+func init() {
+	bbgo.RegisterStrategy(ID, &Strategy{})
+}
+
+type State struct {
+	PositionState PositionState `json:"positionState"`
+
+	// FuturesSymbol is the dated futures contract of the current position
+	FuturesSymbol string    `json:"futuresSymbol"`
+	DeliveryTime  time.Time `json:"deliveryTime"`
+
+	Quantity          fixedpoint.Value `json:"quantity"`
+	EntrySpotPrice    fixedpoint.Value `json:"entrySpotPrice"`
+	EntryFuturesPrice fixedpoint.Value `json:"entryFuturesPrice"`
+	PositionStartTime time.Time        `json:"positionStartTime"`
+}
+
+func newState() *State {
+	return &State{
+		PositionState:     PositionClosed,
+		Quantity:          fixedpoint.Zero,
+		EntrySpotPrice:    fixedpoint.Zero,
+		EntryFuturesPrice: fixedpoint.Zero,
+	}
+}
+
+// Strategy is the cash-and-carry basis strategy.
+// It buys spot and shorts the dated futures contract when the annualized basis is higher than the threshold,
+// then holds the position until the delivery, or rolls the short leg to the next contract.
+type Strategy struct {
+	Environment *bbgo.Environment
+
+	// Symbol is the spot symbol, the dated futures contracts are matched by the base and the quote currency
+	Symbol string `json:"symbol"`
+
+	SpotSession    string `json:"spotSession"`
+	FuturesSession string `json:"futuresSession"`
+
+	// Interval is the interval for checking the basis
+	Interval types.Duration `json:"interval"`
+
+	// QuoteInvestment is the quote amount for buying the spot
+	QuoteInvestment fixedpoint.Value `json:"quoteInvestment"`
+
+	// ContractType limits the dated futures contracts, e.g. current_quarter, next_quarter.
+	// when it's empty, all dated futures contracts are considered.
+	ContractType types.ContractType `json:"contractType,omitempty"`
+
+	// MinAnnualizedBasis is the minimal annualized basis for opening (or rolling) the position
+	MinAnnualizedBasis fixedpoint.Value `json:"minAnnualizedBasis"`
+
+	// RollOver rolls the short leg to the next contract before the delivery,
+	// when it's disabled, both legs are closed before the delivery.
+	RollOver bool `json:"rollOver"`
+
+	// RollBefore is the duration before the delivery time for rolling or closing the position
+	RollBefore types.Duration `json:"rollBefore"`
+
+	// Reset your position info
+	Reset bool `json:"reset"`
+
+	ProfitStats *ProfitStats `persistence:"profit_stats"`
+
+	SpotPosition    *types.Position `persistence:"spot_position"`
+	FuturesPosition *types.Position `persistence:"futures_position"`
+
+	State *State `persistence:"state"`
+
+	// mu is used for locking state
+	mu sync.Mutex
+
+	spotSession, futuresSession             *bbgo.ExchangeSession
+	spotOrderExecutor, futuresOrderExecutor *bbgo.GeneralOrderExecutor
+	spotMarket, futuresMarket               types.Market
+
+	// deliveryMarkets is the dated futures markets of the futures session
+	deliveryMarkets types.MarketMap
+
+	// contractStream receives the order and the trade updates of the current contract from the futures user data stream,
+	// the order executor of the previous contract is detached from the user data stream by replacing the contract stream.
+	contractStream   *types.StandardStream
+	contractSymbol   string
+	contractStreamMu sync.Mutex
+}
+
+func (s *Strategy) ID() string {
+	return ID
+}
+
+func (s *Strategy) InstanceID() string {
+	return fmt.Sprintf("%s-%s", ID, s.Symbol)
+}
+
+func (s *Strategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {}
+
+func (s *Strategy) Defaults() error {
+	if s.Interval == 0 {
+		s.Interval = types.Duration(5 * time.Minute)
+	}
+
+	if s.RollBefore == 0 {
+		s.RollBefore = types.Duration(24 * time.Hour)
+	}
+
+	return nil
+}
+
+func (s *Strategy) Validate() error {
+	if len(s.Symbol) == 0 {
+		return errors.New("symbol is required")
+	}
+
+	if len(s.SpotSession) == 0 {
+		return errors.New("spotSession name is required")
+	}
+
+	if len(s.FuturesSession) == 0 {
+		return errors.New("futuresSession name is required")
+	}
+
+	if s.QuoteInvestment.Sign() <= 0 {
+		return errors.New("quoteInvestment must be greater than zero")
+	}
+
+	if s.MinAnnualizedBasis.Sign() <= 0 {
+		return errors.New("minAnnualizedBasis must be greater than zero")
+	}
+
+	return nil
+}
+
+func (s *Strategy) CrossRun(
+	ctx context.Context, _ bbgo.OrderExecutionRouter, sessions map[string]*bbgo.ExchangeSession,
+) error {
+	instanceID := s.InstanceID()
+
+	s.spotSession = sessions[s.SpotSession]
+	s.futuresSession = sessions[s.FuturesSession]
+	if s.spotSession == nil || s.futuresSession == nil {
+		return fmt.Errorf("session %s or %s is not defined", s.SpotSession, s.FuturesSession)
+	}
+
+	if !s.futuresSession.Futures {
+		return fmt.Errorf("session %s is not a futures session", s.FuturesSession)
+	}
+
+	var ok bool
+	s.spotMarket, ok = s.spotSession.Market(s.Symbol)
+	if !ok {
+		return fmt.Errorf("market %s is not found in session %s", s.Symbol, s.SpotSession)
+	}
+
+	s.deliveryMarkets = s.futuresSession.Markets()
+	if service, ok := s.futuresSession.Exchange.(types.DeliveryFuturesMarketService); ok {
+		markets, err := service.QueryDeliveryFuturesMarkets(ctx)
+		if err != nil {
+			return err
+		}
+
+		s.deliveryMarkets = markets
+
+		// register the contracts in the session, so that the order executor can format the contract orders
+		s.futuresSession.AddMarkets(markets)
+	}
+
+	s.futuresSession.UserDataStream.OnOrderUpdate(func(order types.Order) {
+		if stream := s.getContractStream(order.Symbol); stream != nil {
+			stream.EmitOrderUpdate(order)
+		}
+	})
+
+	s.futuresSession.UserDataStream.OnTradeUpdate(func(trade types.Trade) {
+		if stream := s.getContractStream(trade.Symbol); stream != nil {
+			stream.EmitTradeUpdate(trade)
+		}
+	})
+
+	if s.ProfitStats == nil || s.Reset {
+		s.ProfitStats = &ProfitStats{
+			ProfitStats: types.NewProfitStats(s.spotMarket),
+			TotalCarry:  fixedpoint.Zero,
+		}
+	}
+
+	if s.SpotPosition == nil || s.Reset {
+		s.SpotPosition = types.NewPositionFromMarket(s.spotMarket)
+	}
+
+	if s.State == nil || s.Reset {
+		s.State = newState()
+	}
+
+	s.spotOrderExecutor = bbgo.NewGeneralOrderExecutor(s.spotSession, s.Symbol, ID, instanceID, s.SpotPosition)
+	s.setupOrderExecutor(ctx, s.spotOrderExecutor)
+	s.spotOrderExecutor.Bind()
+
+	if s.State.FuturesSymbol != "" {
+		if market, ok := s.deliveryMarkets[s.State.FuturesSymbol]; ok {
+			if s.FuturesPosition == nil || s.Reset {
+				s.FuturesPosition = types.NewPositionFromMarket(market)
+			}
+
+			s.futuresMarket = market
+			s.futuresOrderExecutor = s.allocateFuturesOrderExecutor(ctx, market, s.FuturesPosition)
+		} else {
+			// the contract is delivered and delisted while the strategy was stopped
+			s.expireContract(time.Now())
+		}
+	}
+
+	log.Infof("state: %+v", s.State)
+	log.Infof("loaded spot position: %s", s.SpotPosition.String())
+	if s.FuturesPosition != nil {
+		log.Infof("loaded futures position: %s", s.FuturesPosition.String())
+	}
+
+	bbgo.Notify("%s state: %s", instanceID, s.getPositionState().String())
+
+	go func() {
+		ticker := time.NewTicker(s.Interval.Duration())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				s.tick(ctx, time.Now())
+			}
+		}
+	}()
+
+	bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		for _, orderExecutor := range []*bbgo.GeneralOrderExecutor{s.spotOrderExecutor, s.futuresOrderExecutor} {
+			if orderExecutor == nil {
+				continue
+			}
+
+			_ = orderExecutor.GracefulCancel(ctx)
+			orderExecutor.TradeCollector().Process()
+		}
+
+		bbgo.Sync(ctx, s)
+	})
+
+	return nil
+}
+
+func (s *Strategy) tick(ctx context.Context, now time.Time) {
+	switch s.getPositionState() {
+	case PositionClosed:
+		contract, spotPrice, futuresPrice, ok := s.findBestContract(ctx, now, "")
+		if !ok {
+			return
+		}
+
+		if err := s.openPosition(ctx, contract, spotPrice, futuresPrice, now); err != nil {
+			log.WithError(err).Errorf("unable to open the cash-and-carry position")
+		}
+
+	case PositionReady:
+		if now.Before(s.State.DeliveryTime.Add(-s.RollBefore.Duration())) {
+			return
+		}
+
+		if s.RollOver {
+			if contract, spotPrice, futuresPrice, ok := s.findBestContract(ctx, now, s.State.FuturesSymbol); ok {
+				if err := s.rollPosition(ctx, contract, spotPrice, futuresPrice, now); err != nil {
+					log.WithError(err).Errorf("unable to roll the futures position to %s", contract.Symbol)
+				}
+				return
+			}
+
+			log.Infof("no contract to roll over, closing the position...")
+		}
+
+		if err := s.closePosition(ctx, now); err != nil {
+			log.WithError(err).Errorf("unable to close the cash-and-carry position")
+		}
+
+	case PositionOpening, PositionClosing:
+		s.syncPositionState(ctx)
+	}
+}
+
+// findBestContract finds the dated futures contract with the highest annualized basis that is higher than MinAnnualizedBasis.
+// spotPrice is the ask price of the spot and futuresPrice is the bid price of the contract.
+func (s *Strategy) findBestContract(
+	ctx context.Context, now time.Time, excludeSymbol string,
+) (contract types.Market, spotPrice, futuresPrice fixedpoint.Value, ok bool) {
+	contracts := findDeliveryContracts(s.deliveryMarkets, s.spotMarket.BaseCurrency, s.spotMarket.QuoteCurrency, now.Add(s.RollBefore.Duration()))
+	if len(contracts) == 0 {
+		return contract, spotPrice, futuresPrice, false
+	}
+
+	spotTicker, err := s.spotSession.Exchange.QueryTicker(ctx, s.Symbol)
+	if err != nil {
+		log.WithError(err).Errorf("unable to query %s ticker", s.Symbol)
+		return contract, spotPrice, futuresPrice, false
+	}
+
+	bestBasis := s.MinAnnualizedBasis
+	for _, c := range contracts {
+		if c.Symbol == excludeSymbol {
+			continue
+		}
+
+		if s.ContractType != "" && c.ContractType != s.ContractType {
+			continue
+		}
+
+		futuresTicker, err := s.futuresSession.Exchange.QueryTicker(ctx, c.Symbol)
+		if err != nil {
+			log.WithError(err).Errorf("unable to query %s ticker", c.Symbol)
+			continue
+		}
+
+		basis := calculateBasis(spotTicker.Sell, futuresTicker.Buy)
+		annualizedBasis := annualize(basis, c.TimeToDelivery(now))
+
+		log.Infof("%s basis: %s, annualized: %s, delivery time: %s",
+			c.Symbol, basis.Percentage(), annualizedBasis.Percentage(), c.DeliveryTime)
+
+		if annualizedBasis.Compare(bestBasis) >= 0 {
+			bestBasis = annualizedBasis
+			contract, spotPrice, futuresPrice, ok = c, spotTicker.Sell, futuresTicker.Buy, true
+		}
+	}
+
+	return contract, spotPrice, futuresPrice, ok
+}
+
+func (s *Strategy) openPosition(ctx context.Context, contract types.Market, spotPrice, futuresPrice fixedpoint.Value, now time.Time) error {
+	// the spot quantity is aligned to the contract size, so that both legs hold the same base quantity
+	contracts := contractQuantity(contract, s.spotMarket.TruncateQuantity(s.QuoteInvestment.Div(spotPrice)))
+	quantity := s.spotMarket.TruncateQuantity(contract.ToBaseQuantity(contracts))
+
+	if s.spotMarket.IsDustQuantity(quantity, spotPrice) || isDustContractQuantity(contract, contracts, futuresPrice) {
+		return fmt.Errorf("dust quantity %s, please increase quoteInvestment", quantity.String())
+	}
+
+	if err := s.useContract(ctx, contract); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.State.Quantity = quantity
+	s.State.EntrySpotPrice = spotPrice
+	s.State.EntryFuturesPrice = futuresPrice
+	s.State.PositionStartTime = now
+	s.mu.Unlock()
+
+	s.setPositionState(PositionOpening)
+
+	bbgo.Notify("%s opening cash-and-carry position: buy %s %s, short %s %s, basis %s",
+		s.Symbol, quantity.String(), s.Symbol, contracts.String(), contract.Symbol,
+		calculateBasis(spotPrice, futuresPrice).Percentage())
+
+	if _, err := s.spotOrderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   s.Symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: quantity,
+		Market:   s.spotMarket,
+	}); err != nil {
+		s.setPositionState(PositionClosed)
+		return err
+	}
+
+	if _, err := s.futuresOrderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   contract.Symbol,
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: contracts,
+		Market:   contract,
+	}); err != nil {
+		// the spot leg is filled, unwind it to avoid holding the naked spot position
+		s.setPositionState(PositionClosing)
+		return err
+	}
+
+	bbgo.Sync(ctx, s)
+	return nil
+}
+
+// rollPosition buys back the current contract and shorts the next contract with the same quantity
+func (s *Strategy) rollPosition(ctx context.Context, contract types.Market, spotPrice, futuresPrice fixedpoint.Value, now time.Time) error {
+	s.mu.Lock()
+	quantity := s.State.Quantity
+	s.mu.Unlock()
+
+	contracts := contractQuantity(contract, quantity)
+	if isDustContractQuantity(contract, contracts, futuresPrice) {
+		return fmt.Errorf("dust contract quantity %s of %s", contracts.String(), contract.Symbol)
+	}
+
+	if err := s.closeFuturesLeg(ctx, spotPrice, now); err != nil {
+		return err
+	}
+
+	if err := s.useContract(ctx, contract); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.State.EntrySpotPrice = spotPrice
+	s.State.EntryFuturesPrice = futuresPrice
+	s.State.PositionStartTime = now
+	s.mu.Unlock()
+
+	bbgo.Notify("%s rolling the short leg to %s, basis %s", s.Symbol, contract.Symbol, calculateBasis(spotPrice, futuresPrice).Percentage())
+
+	s.setPositionState(PositionOpening)
+
+	if _, err := s.futuresOrderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   contract.Symbol,
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: contracts,
+		Market:   contract,
+	}); err != nil {
+		s.setPositionState(PositionClosing)
+		return err
+	}
+
+	bbgo.Sync(ctx, s)
+	return nil
+}
+
+func (s *Strategy) closePosition(ctx context.Context, now time.Time) error {
+	spotTicker, err := s.spotSession.Exchange.QueryTicker(ctx, s.Symbol)
+	if err != nil {
+		return err
+	}
+
+	s.setPositionState(PositionClosing)
+
+	if err := s.closeFuturesLeg(ctx, spotTicker.Buy, now); err != nil {
+		return err
+	}
+
+	if err := s.spotOrderExecutor.ClosePosition(ctx, fixedpoint.One); err != nil {
+		return err
+	}
+
+	bbgo.Sync(ctx, s)
+	return nil
+}
+
+// closeFuturesLeg buys back the short futures leg (if it's not delivered yet) and records the carry
+func (s *Strategy) closeFuturesLeg(ctx context.Context, spotPrice fixedpoint.Value, now time.Time) error {
+	exitFuturesPrice := spotPrice
+
+	// the contract converges to the spot price at the delivery, the exchange settles the position for us
+	if now.Before(s.State.DeliveryTime) && !s.FuturesPosition.GetBase().IsZero() {
+		futuresTicker, err := s.futuresSession.Exchange.QueryTicker(ctx, s.futuresMarket.Symbol)
+		if err != nil {
+			return err
+		}
+
+		exitFuturesPrice = futuresTicker.Sell
+
+		if err := s.futuresOrderExecutor.ClosePosition(ctx, fixedpoint.One); err != nil {
+			return err
+		}
+	} else {
+		s.FuturesPosition.Reset()
+	}
+
+	s.mu.Lock()
+	entryBasis := s.State.EntryFuturesPrice.Sub(s.State.EntrySpotPrice)
+	exitBasis := exitFuturesPrice.Sub(spotPrice)
+	record := CarryRecord{
+		FuturesSymbol: s.State.FuturesSymbol,
+		Quantity:      s.State.Quantity,
+		Notional:      s.State.Quantity.Mul(s.State.EntrySpotPrice),
+		Carry:         entryBasis.Sub(exitBasis).Mul(s.State.Quantity),
+		OpenedAt:      s.State.PositionStartTime,
+		ClosedAt:      now,
+	}
+	s.mu.Unlock()
+
+	s.ProfitStats.AddCarry(record)
+	bbgo.Notify(&record)
+	bbgo.Notify(s.ProfitStats)
+	return nil
+}
+
+// useContract switches the futures leg to the given contract,
+// the active orders of the previous contract are canceled and its order executor is detached from the user data stream.
+func (s *Strategy) useContract(ctx context.Context, contract types.Market) error {
+	if s.futuresOrderExecutor != nil && s.futuresMarket.Symbol == contract.Symbol {
+		return nil
+	}
+
+	if s.futuresOrderExecutor != nil {
+		if err := s.futuresOrderExecutor.GracefulCancel(ctx); err != nil {
+			return fmt.Errorf("unable to cancel the orders of %s: %w", s.futuresMarket.Symbol, err)
+		}
+
+		s.futuresOrderExecutor.TradeCollector().Process()
+	}
+
+	s.mu.Lock()
+	s.State.FuturesSymbol = contract.Symbol
+	s.State.DeliveryTime = contract.DeliveryTime
+	s.mu.Unlock()
+
+	s.futuresMarket = contract
+	s.FuturesPosition = types.NewPositionFromMarket(contract)
+	s.futuresOrderExecutor = s.allocateFuturesOrderExecutor(ctx, contract, s.FuturesPosition)
+	return nil
+}
+
+// expireContract settles the futures leg of the contract that is no longer listed.
+// The exchange has settled the short leg at the delivery, so the futures position is reset and the spot leg is kept,
+// then the position is moved to ready, so that the short leg is rolled to the next contract (or closed) on the next tick.
+func (s *Strategy) expireContract(now time.Time) {
+	s.mu.Lock()
+	symbol := s.State.FuturesSymbol
+	if s.State.DeliveryTime.After(now) {
+		s.State.DeliveryTime = now
+	}
+	s.mu.Unlock()
+
+	log.Warnf("futures contract %s is not found in session %s, it's treated as delivered", symbol, s.FuturesSession)
+
+	if s.FuturesPosition == nil {
+		s.FuturesPosition = types.NewPosition(symbol, s.spotMarket.BaseCurrency, s.spotMarket.QuoteCurrency)
+	}
+	s.FuturesPosition.Reset()
+
+	if state := s.getPositionState(); state == PositionOpening || state == PositionReady {
+		s.setPositionState(PositionReady)
+	}
+
+	bbgo.Notify("%s futures contract %s is delivered, the short leg is settled", s.Symbol, symbol)
+}
+
+// syncPositionState moves the opening/closing state forward when both legs are settled
+func (s *Strategy) syncPositionState(ctx context.Context) {
+	switch s.getPositionState() {
+	case PositionOpening:
+		if s.FuturesPosition == nil {
+			return
+		}
+
+		spotBase := s.SpotPosition.GetBase()
+		futuresBase := s.FuturesPosition.GetBase()
+		if spotBase.Sign() > 0 && futuresBase.Sign() < 0 {
+			s.setPositionState(PositionReady)
+			bbgo.Notify("%s cash-and-carry position ready", s.Symbol, s.SpotPosition, s.FuturesPosition)
+		}
+
+	case PositionClosing:
+		if s.FuturesPosition != nil && !s.futuresMarket.IsDustQuantity(s.FuturesPosition.GetBase().Abs(), s.FuturesPosition.AverageCost) {
+			if err := s.futuresOrderExecutor.ClosePosition(ctx, fixedpoint.One); err != nil {
+				log.WithError(err).Errorf("unable to close the futures position")
+			}
+			return
+		}
+
+		if !s.spotMarket.IsDustQuantity(s.SpotPosition.GetBase().Abs(), s.SpotPosition.AverageCost) {
+			if err := s.spotOrderExecutor.ClosePosition(ctx, fixedpoint.One); err != nil {
+				log.WithError(err).Errorf("unable to close the spot position")
+			}
+			return
+		}
+
+		s.setPositionState(PositionClosed)
+		bbgo.Notify("%s cash-and-carry position closed", s.Symbol, s.ProfitStats)
+	}
+
+	bbgo.Sync(ctx, s)
+}
+
+func (s *Strategy) setPositionState(state PositionState) {
+	s.mu.Lock()
+	origState := s.State.PositionState
+	s.State.PositionState = state
+	s.mu.Unlock()
+	log.Infof("position state transition: %s -> %s", origState.String(), state.String())
+}
+
+func (s *Strategy) getPositionState() PositionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.State.PositionState
+}
+
+func (s *Strategy) setupOrderExecutor(ctx context.Context, orderExecutor *bbgo.GeneralOrderExecutor) {
+	orderExecutor.SetMaxRetries(0)
+	orderExecutor.BindEnvironment(s.Environment)
+	orderExecutor.BindProfitStats(s.ProfitStats.ProfitStats)
+	orderExecutor.TradeCollector().OnPositionUpdate(func(position *types.Position) {
+		bbgo.Sync(ctx, s)
+	})
+}
+
+// allocateFuturesOrderExecutor allocates the order executor of the contract,
+// the executor is bound to a new contract stream instead of the futures user data stream.
+func (s *Strategy) allocateFuturesOrderExecutor(
+	ctx context.Context, contract types.Market, position *types.Position,
+) *bbgo.GeneralOrderExecutor {
+	stream := types.NewStandardStream()
+
+	orderExecutor := bbgo.NewGeneralOrderExecutor(s.futuresSession, contract.Symbol, ID, s.InstanceID(), position)
+	s.setupOrderExecutor(ctx, orderExecutor)
+	orderExecutor.ActiveMakerOrders().BindStream(&stream)
+	orderExecutor.OrderStore().BindStream(&stream)
+	orderExecutor.TradeCollector().BindStream(&stream)
+	orderExecutor.TradeCollector().OnTrade(func(trade types.Trade, profit, netProfit fixedpoint.Value) {
+		bbgo.Notify(trade)
+	})
+	orderExecutor.TradeCollector().OnPositionUpdate(func(position *types.Position) {
+		bbgo.Notify(position)
+	})
+
+	s.contractStreamMu.Lock()
+	s.contractStream = &stream
+	s.contractSymbol = contract.Symbol
+	s.contractStreamMu.Unlock()
+
+	return orderExecutor
+}
+
+// getContractStream returns the contract stream if the symbol is the current contract
+func (s *Strategy) getContractStream(symbol string) *types.StandardStream {
+	s.contractStreamMu.Lock()
+	defer s.contractStreamMu.Unlock()
+
+	if s.contractStream == nil || symbol != s.contractSymbol {
+		return nil
+	}
+
+	return s.contractStream
+}
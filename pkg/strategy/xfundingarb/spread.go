@@ -0,0 +1,61 @@
+package xfundingarb
+
+import (
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// FundingRate is the funding rate snapshot of the symbol on one futures session
+type FundingRate struct {
+	Session string `json:"session"`
+
+	// Rate is the funding rate normalized to the default funding interval (8 hours),
+	// so that the rates of the exchanges with different funding intervals are comparable
+	Rate      fixedpoint.Value `json:"rate"`
+	MarkPrice fixedpoint.Value `json:"markPrice"`
+	Time      time.Time        `json:"time"`
+}
+
+// FundingSpread is a pair of futures sessions, the short leg receives the higher funding rate
+// and the long leg pays the lower funding rate.
+type FundingSpread struct {
+	Long  FundingRate `json:"long"`
+	Short FundingRate `json:"short"`
+}
+
+// Spread returns the funding rate difference between the short leg and the long leg
+func (s FundingSpread) Spread() fixedpoint.Value {
+	return s.Short.Rate.Sub(s.Long.Rate)
+}
+
+// findMaxFundingSpread finds the session pair with the largest funding rate spread.
+// ok is false when there are less than two funding rates.
+func findMaxFundingSpread(rates []FundingRate) (spread FundingSpread, ok bool) {
+	if len(rates) < 2 {
+		return spread, false
+	}
+
+	sorted := make([]FundingRate, len(rates))
+	copy(sorted, rates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Rate.Compare(sorted[j].Rate) < 0
+	})
+
+	return FundingSpread{
+		Long:  sorted[0],
+		Short: sorted[len(sorted)-1],
+	}, true
+}
+
+// findFundingRate finds the funding rate of the given session
+func findFundingRate(rates []FundingRate, session string) (FundingRate, bool) {
+	for _, rate := range rates {
+		if rate.Session == session {
+			return rate, true
+		}
+	}
+
+	return FundingRate{}, false
+}
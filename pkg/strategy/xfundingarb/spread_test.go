@@ -0,0 +1,45 @@
+package xfundingarb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func Test_findMaxFundingSpread(t *testing.T) {
+	t.Run("not enough sessions", func(t *testing.T) {
+		_, ok := findMaxFundingSpread([]FundingRate{
+			{Session: "binance_futures", Rate: fixedpoint.NewFromFloat(0.0001)},
+		})
+		assert.False(t, ok)
+	})
+
+	t.Run("three sessions", func(t *testing.T) {
+		spread, ok := findMaxFundingSpread([]FundingRate{
+			{Session: "binance_futures", Rate: fixedpoint.NewFromFloat(0.0001)},
+			{Session: "bybit_futures", Rate: fixedpoint.NewFromFloat(0.0005)},
+			{Session: "okex_futures", Rate: fixedpoint.NewFromFloat(-0.0002)},
+		})
+		if assert.True(t, ok) {
+			assert.Equal(t, "okex_futures", spread.Long.Session)
+			assert.Equal(t, "bybit_futures", spread.Short.Session)
+			assert.Equal(t, "0.0007", spread.Spread().String())
+		}
+	})
+}
+
+func Test_findFundingRate(t *testing.T) {
+	rates := []FundingRate{
+		{Session: "binance_futures", Rate: fixedpoint.NewFromFloat(0.0001)},
+		{Session: "bybit_futures", Rate: fixedpoint.NewFromFloat(0.0005)},
+	}
+
+	rate, ok := findFundingRate(rates, "bybit_futures")
+	assert.True(t, ok)
+	assert.Equal(t, "0.0005", rate.Rate.String())
+
+	_, ok = findFundingRate(rates, "okex_futures")
+	assert.False(t, ok)
+}
@@ -0,0 +1,520 @@
+package xfundingarb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const ID = "xfundingarb"
+
+// Position State Transitions:
+// Closed -> Opening -> Ready -> Closing -> Closed
+//
+//go:generate stringer -type=PositionState
+type PositionState int
+
+const (
+	PositionClosed PositionState = iota
+	PositionOpening
+	PositionReady
+	PositionClosing
+)
+
+var log = logrus.WithField("strategy", ID)
+
+func init() {
+	bbgo.RegisterStrategy(ID, &Strategy{})
+}
+
+type State struct {
+	PositionState PositionState `json:"positionState"`
+
+	// LongSession is the session of the long leg, which pays the lower funding rate
+	LongSession string `json:"longSession"`
+
+	// ShortSession is the session of the short leg, which receives the higher funding rate
+	ShortSession string `json:"shortSession"`
+
+	// EntrySpread is the funding rate spread when the position was opened
+	EntrySpread fixedpoint.Value `json:"entrySpread"`
+
+	PositionStartTime time.Time `json:"positionStartTime"`
+}
+
+func newState() *State {
+	return &State{
+		PositionState: PositionClosed,
+		EntrySpread:   fixedpoint.Zero,
+	}
+}
+
+type MarginRatioConfig struct {
+	// Warning sends a notification when the margin ratio of any leg is higher than this ratio
+	Warning fixedpoint.Value `json:"warning"`
+
+	// Max closes both legs when the margin ratio of any leg is higher than this ratio
+	Max fixedpoint.Value `json:"max"`
+}
+
+// Strategy monitors the funding rates of one symbol across several futures sessions,
+// opens a delta-neutral long/short futures pair between the sessions with the largest funding rate spread,
+// and closes both legs when the spread mean-reverts.
+type Strategy struct {
+	Environment *bbgo.Environment
+
+	Symbol string `json:"symbol"`
+
+	// Sessions is the list of the futures sessions to monitor
+	Sessions []string `json:"sessions"`
+
+	// Interval is the interval for checking the funding rates and the margin ratios
+	Interval types.Duration `json:"interval"`
+
+	// QuoteInvestment is the notional value of each leg
+	QuoteInvestment fixedpoint.Value `json:"quoteInvestment"`
+
+	// OpenSpread is the minimal funding rate spread for opening the position pair,
+	// the funding rates are normalized to the 8-hour funding interval before comparing
+	OpenSpread fixedpoint.Value `json:"openSpread"`
+
+	// CloseSpread closes the position pair when the spread of the opened pair falls below this value
+	CloseSpread fixedpoint.Value `json:"closeSpread"`
+
+	MinHoldingPeriod types.Duration `json:"minHoldingPeriod"`
+
+	MarginRatio *MarginRatioConfig `json:"marginRatio,omitempty"`
+
+	// Reset your position info
+	Reset bool `json:"reset"`
+
+	ProfitStats *types.ProfitStats `persistence:"profit_stats"`
+
+	// Positions stores the futures position of each session
+	Positions map[string]*types.Position `persistence:"positions"`
+
+	State *State `persistence:"state"`
+
+	// mu is used for locking state
+	mu sync.Mutex
+
+	sessions             map[string]*bbgo.ExchangeSession
+	markets              map[string]types.Market
+	orderExecutors       map[string]*bbgo.GeneralOrderExecutor
+	premiumIndexServices map[string]types.PremiumIndexService
+}
+
+func (s *Strategy) ID() string {
+	return ID
+}
+
+func (s *Strategy) InstanceID() string {
+	return fmt.Sprintf("%s-%s", ID, s.Symbol)
+}
+
+func (s *Strategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {}
+
+func (s *Strategy) Defaults() error {
+	if s.Interval == 0 {
+		s.Interval = types.Duration(time.Minute)
+	}
+
+	if s.MinHoldingPeriod == 0 {
+		s.MinHoldingPeriod = types.Duration(24 * time.Hour)
+	}
+
+	return nil
+}
+
+func (s *Strategy) Validate() error {
+	if len(s.Symbol) == 0 {
+		return errors.New("symbol is required")
+	}
+
+	if len(s.Sessions) < 2 {
+		return errors.New("at least 2 futures sessions are required")
+	}
+
+	if s.QuoteInvestment.Sign() <= 0 {
+		return errors.New("quoteInvestment must be greater than zero")
+	}
+
+	if s.OpenSpread.Sign() <= 0 {
+		return errors.New("openSpread must be greater than zero")
+	}
+
+	if s.CloseSpread.Compare(s.OpenSpread) >= 0 {
+		return errors.New("closeSpread must be less than openSpread")
+	}
+
+	return nil
+}
+
+func (s *Strategy) CrossRun(
+	ctx context.Context, _ bbgo.OrderExecutionRouter, sessions map[string]*bbgo.ExchangeSession,
+) error {
+	instanceID := s.InstanceID()
+
+	s.sessions = make(map[string]*bbgo.ExchangeSession)
+	s.markets = make(map[string]types.Market)
+	s.orderExecutors = make(map[string]*bbgo.GeneralOrderExecutor)
+	s.premiumIndexServices = make(map[string]types.PremiumIndexService)
+
+	if s.ProfitStats == nil || s.Reset {
+		s.ProfitStats = types.NewProfitStats(types.Market{Symbol: s.Symbol})
+	}
+
+	if s.Positions == nil || s.Reset {
+		s.Positions = make(map[string]*types.Position)
+	}
+
+	if s.State == nil || s.Reset {
+		s.State = newState()
+	}
+
+	for _, sessionName := range s.Sessions {
+		session, ok := sessions[sessionName]
+		if !ok {
+			return fmt.Errorf("session %s is not defined", sessionName)
+		}
+
+		if !session.Futures {
+			return fmt.Errorf("session %s is not a futures session", sessionName)
+		}
+
+		service, ok := session.Exchange.(types.PremiumIndexService)
+		if !ok {
+			return fmt.Errorf("exchange %s of session %s does not support premium index query", session.ExchangeName, sessionName)
+		}
+
+		market, ok := session.Market(s.Symbol)
+		if !ok {
+			return fmt.Errorf("market %s is not found in session %s", s.Symbol, sessionName)
+		}
+
+		position, ok := s.Positions[sessionName]
+		if !ok {
+			position = types.NewPositionFromMarket(market)
+			s.Positions[sessionName] = position
+		}
+
+		position.Strategy = ID
+		position.StrategyInstanceID = instanceID
+
+		s.sessions[sessionName] = session
+		s.markets[sessionName] = market
+		s.premiumIndexServices[sessionName] = service
+		s.orderExecutors[sessionName] = s.allocateOrderExecutor(ctx, session, instanceID, position)
+	}
+
+	log.Infof("state: %+v", s.State)
+	for sessionName, position := range s.Positions {
+		log.Infof("loaded %s position: %s", sessionName, position.String())
+	}
+
+	bbgo.Notify("%s state: %s", s.InstanceID(), s.getPositionState().String())
+
+	go func() {
+		ticker := time.NewTicker(s.Interval.Duration())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				s.tick(ctx)
+			}
+		}
+	}()
+
+	bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		for _, orderExecutor := range s.orderExecutors {
+			_ = orderExecutor.GracefulCancel(ctx)
+			orderExecutor.TradeCollector().Process()
+		}
+
+		bbgo.Sync(ctx, s)
+	})
+
+	return nil
+}
+
+func (s *Strategy) tick(ctx context.Context) {
+	rates := s.queryFundingRates(ctx)
+
+	switch s.getPositionState() {
+	case PositionClosed:
+		spread, ok := findMaxFundingSpread(rates)
+		if !ok {
+			return
+		}
+
+		log.Infof("max funding spread %s: long %s (%s) <=> short %s (%s)",
+			spread.Spread().Percentage(),
+			spread.Long.Session, spread.Long.Rate.Percentage(),
+			spread.Short.Session, spread.Short.Rate.Percentage())
+
+		if spread.Spread().Compare(s.OpenSpread) < 0 {
+			return
+		}
+
+		if err := s.openPosition(ctx, spread); err != nil {
+			log.WithError(err).Errorf("unable to open position pair")
+		}
+
+	case PositionReady:
+		if s.checkMarginRatio(ctx) {
+			s.startClosingPosition(ctx, "margin ratio exceeded")
+			return
+		}
+
+		longRate, ok1 := findFundingRate(rates, s.State.LongSession)
+		shortRate, ok2 := findFundingRate(rates, s.State.ShortSession)
+		if !ok1 || !ok2 {
+			return
+		}
+
+		spread := FundingSpread{Long: longRate, Short: shortRate}
+		if spread.Spread().Compare(s.CloseSpread) > 0 {
+			return
+		}
+
+		holdingPeriod := time.Since(s.State.PositionStartTime)
+		if holdingPeriod < s.MinHoldingPeriod.Duration() {
+			log.Warnf("position holding period %s is less than %s, skip closing", holdingPeriod, s.MinHoldingPeriod.Duration())
+			return
+		}
+
+		s.startClosingPosition(ctx, fmt.Sprintf("funding spread %s is lower than %s", spread.Spread().Percentage(), s.CloseSpread.Percentage()))
+
+	case PositionOpening, PositionClosing:
+		s.syncPositionState(ctx)
+	}
+}
+
+func (s *Strategy) queryFundingRates(ctx context.Context) (rates []FundingRate) {
+	for _, sessionName := range s.Sessions {
+		premiumIndex, err := s.premiumIndexServices[sessionName].QueryPremiumIndex(ctx, s.Symbol)
+		if err != nil {
+			log.WithError(err).Errorf("unable to query %s premium index from session %s", s.Symbol, sessionName)
+			continue
+		}
+
+		rates = append(rates, FundingRate{
+			Session:   sessionName,
+			Rate:      premiumIndex.NormalizedFundingRate(types.DefaultFundingInterval),
+			MarkPrice: premiumIndex.MarkPrice,
+			Time:      premiumIndex.Time,
+		})
+	}
+
+	return rates
+}
+
+// openPosition submits the long leg and the short leg with the same base quantity at the same time
+func (s *Strategy) openPosition(ctx context.Context, spread FundingSpread) error {
+	longMarket := s.markets[spread.Long.Session]
+	shortMarket := s.markets[spread.Short.Session]
+
+	price := fixedpoint.Max(spread.Long.MarkPrice, spread.Short.MarkPrice)
+	if price.IsZero() {
+		return errors.New("mark price is zero")
+	}
+
+	quantity := s.QuoteInvestment.Div(price)
+	quantity = fixedpoint.Min(longMarket.TruncateQuantity(quantity), shortMarket.TruncateQuantity(quantity))
+
+	if longMarket.IsDustQuantity(quantity, spread.Long.MarkPrice) || shortMarket.IsDustQuantity(quantity, spread.Short.MarkPrice) {
+		return fmt.Errorf("dust quantity %s, please increase quoteInvestment", quantity.String())
+	}
+
+	s.mu.Lock()
+	s.State.LongSession = spread.Long.Session
+	s.State.ShortSession = spread.Short.Session
+	s.State.EntrySpread = spread.Spread()
+	s.State.PositionStartTime = time.Now()
+	s.mu.Unlock()
+	s.setPositionState(PositionOpening)
+
+	bbgo.Notify("%s opening funding spread position %s: long %s %s on %s, short %s %s on %s",
+		s.Symbol, spread.Spread().Percentage(),
+		quantity.String(), s.Symbol, spread.Long.Session,
+		quantity.String(), s.Symbol, spread.Short.Session)
+
+	var g errgroup.Group
+	g.Go(func() error {
+		_, err := s.orderExecutors[spread.Long.Session].SubmitOrders(ctx, types.SubmitOrder{
+			Symbol:   s.Symbol,
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeMarket,
+			Quantity: quantity,
+			Market:   longMarket,
+		})
+		return err
+	})
+	g.Go(func() error {
+		_, err := s.orderExecutors[spread.Short.Session].SubmitOrders(ctx, types.SubmitOrder{
+			Symbol:   s.Symbol,
+			Side:     types.SideTypeSell,
+			Type:     types.OrderTypeMarket,
+			Quantity: quantity,
+			Market:   shortMarket,
+		})
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		// one of the legs might be filled, close the opened leg to stay delta-neutral
+		s.startClosingPosition(ctx, "failed to open the position pair: "+err.Error())
+		return err
+	}
+
+	bbgo.Sync(ctx, s)
+	return nil
+}
+
+func (s *Strategy) startClosingPosition(ctx context.Context, reason string) {
+	log.Infof("start closing position pair: %s", reason)
+	bbgo.Notify("%s start closing funding spread position: %s", s.Symbol, reason)
+
+	s.setPositionState(PositionClosing)
+
+	var g errgroup.Group
+	for sessionName, orderExecutor := range s.orderExecutors {
+		sessionName, orderExecutor := sessionName, orderExecutor
+		position := orderExecutor.Position()
+		if position.GetBase().IsZero() {
+			continue
+		}
+
+		g.Go(func() error {
+			if err := orderExecutor.GracefulCancel(ctx); err != nil {
+				log.WithError(err).Errorf("unable to cancel %s orders", sessionName)
+			}
+
+			return orderExecutor.ClosePosition(ctx, fixedpoint.One)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		log.WithError(err).Errorf("unable to close position pair")
+	}
+
+	bbgo.Sync(ctx, s)
+}
+
+// syncPositionState moves the opening/closing state forward when both legs are settled
+func (s *Strategy) syncPositionState(ctx context.Context) {
+	longPosition := s.Positions[s.State.LongSession]
+	shortPosition := s.Positions[s.State.ShortSession]
+	if longPosition == nil || shortPosition == nil {
+		s.setPositionState(PositionClosed)
+		return
+	}
+
+	switch s.getPositionState() {
+	case PositionOpening:
+		longBase := longPosition.GetBase()
+		shortBase := shortPosition.GetBase()
+		if longBase.Sign() > 0 && shortBase.Sign() < 0 && longBase.Compare(shortBase.Neg()) == 0 {
+			s.setPositionState(PositionReady)
+			bbgo.Notify("%s funding spread position ready", s.Symbol, longPosition, shortPosition)
+		}
+
+	case PositionClosing:
+		if !s.isDustPosition(s.State.LongSession) || !s.isDustPosition(s.State.ShortSession) {
+			s.startClosingPosition(ctx, "retry closing the remaining position")
+			return
+		}
+
+		s.setPositionState(PositionClosed)
+		bbgo.Notify("%s funding spread position closed", s.Symbol, s.ProfitStats)
+	}
+
+	bbgo.Sync(ctx, s)
+}
+
+func (s *Strategy) isDustPosition(sessionName string) bool {
+	position := s.Positions[sessionName]
+	market := s.markets[sessionName]
+	return market.IsDustQuantity(position.GetBase().Abs(), position.AverageCost)
+}
+
+// checkMarginRatio updates the futures accounts of both legs and returns true when any margin ratio exceeds the max margin ratio
+func (s *Strategy) checkMarginRatio(ctx context.Context) bool {
+	if s.MarginRatio == nil {
+		return false
+	}
+
+	for _, sessionName := range []string{s.State.LongSession, s.State.ShortSession} {
+		session, ok := s.sessions[sessionName]
+		if !ok {
+			continue
+		}
+
+		account, err := session.UpdateAccount(ctx)
+		if err != nil {
+			log.WithError(err).Errorf("unable to update %s account", sessionName)
+			continue
+		}
+
+		if account.FuturesInfo == nil || account.FuturesInfo.TotalMarginBalance.IsZero() {
+			continue
+		}
+
+		marginRatio := account.FuturesInfo.TotalMaintMargin.Div(account.FuturesInfo.TotalMarginBalance)
+		log.Infof("%s margin ratio: %s", sessionName, marginRatio.Percentage())
+
+		if s.MarginRatio.Max.Sign() > 0 && marginRatio.Compare(s.MarginRatio.Max) >= 0 {
+			bbgo.Notify("%s margin ratio %s exceeds the max margin ratio %s", sessionName, marginRatio.Percentage(), s.MarginRatio.Max.Percentage())
+			return true
+		}
+
+		if s.MarginRatio.Warning.Sign() > 0 && marginRatio.Compare(s.MarginRatio.Warning) >= 0 {
+			bbgo.Notify("%s margin ratio %s exceeds the warning margin ratio %s", sessionName, marginRatio.Percentage(), s.MarginRatio.Warning.Percentage())
+		}
+	}
+
+	return false
+}
+
+func (s *Strategy) setPositionState(state PositionState) {
+	s.mu.Lock()
+	origState := s.State.PositionState
+	s.State.PositionState = state
+	s.mu.Unlock()
+	log.Infof("position state transition: %s -> %s", origState.String(), state.String())
+}
+
+func (s *Strategy) getPositionState() PositionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.State.PositionState
+}
+
+func (s *Strategy) allocateOrderExecutor(
+	ctx context.Context, session *bbgo.ExchangeSession, instanceID string, position *types.Position,
+) *bbgo.GeneralOrderExecutor {
+	orderExecutor := bbgo.NewGeneralOrderExecutor(session, s.Symbol, ID, instanceID, position)
+	orderExecutor.SetMaxRetries(0)
+	orderExecutor.BindEnvironment(s.Environment)
+	orderExecutor.BindProfitStats(s.ProfitStats)
+	orderExecutor.Bind()
+	orderExecutor.TradeCollector().OnPositionUpdate(func(position *types.Position) {
+		bbgo.Sync(ctx, s)
+	})
+	return orderExecutor
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/pricesolver"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/c9s/bbgo/pkg/util/templateutil"
 	"github.com/c9s/bbgo/pkg/util/timejitter"
@@ -85,6 +86,34 @@ func (s *Strategy) Validate() error {
 
 var Ten = fixedpoint.NewFromInt(10)
 
+var usdCurrencies = []string{"USDT", "USDC", "USD"}
+
+// resolveAssetPrices values the assets without a direct USD market price by the conversion paths of the price solver
+func resolveAssetPrices(assets types.AssetMap, priceSolver *pricesolver.GraphPriceSolver) {
+	btcPrice, hasBtcPrice := priceSolver.ResolvePrice("BTC", usdCurrencies...)
+
+	for currency, asset := range assets {
+		if !asset.PriceInUSD.IsZero() {
+			continue
+		}
+
+		path, ok := priceSolver.ResolvePath(currency, usdCurrencies...)
+		if !ok {
+			continue
+		}
+
+		log.Infof("resolved %s price via %s", currency, path)
+
+		asset.PriceInUSD = path.Price
+		asset.InUSD = asset.NetAsset.Mul(path.Price)
+		if hasBtcPrice && !btcPrice.IsZero() {
+			asset.InBTC = asset.InUSD.Div(btcPrice)
+		}
+
+		assets[currency] = asset
+	}
+}
+
 func (s *Strategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {}
 
 func (s *Strategy) recordNetAssetValue(ctx context.Context, sessions map[string]*bbgo.ExchangeSession) {
@@ -93,7 +122,10 @@ func (s *Strategy) recordNetAssetValue(ctx context.Context, sessions map[string]
 	sessionBalances := map[string]types.BalanceMap{}
 	priceTime := time.Now()
 
-	// iterate the sessions and record them
+	// the price solver values the exotic assets that have no USD market via the markets of all the sessions
+	priceSolver := pricesolver.NewGraphPriceSolver(nil, 0)
+
+	// iterate the sessions and update the balances and the prices
 	quoteCurrency := "USDT"
 	for sessionName, session := range sessions {
 		if session.PublicOnly {
@@ -118,18 +150,38 @@ func (s *Strategy) recordNetAssetValue(ctx context.Context, sessions map[string]
 		totalBalances = totalBalances.Add(balances)
 
 		prices := session.LastPrices()
-		assets := balances.Assets(prices, priceTime)
+		priceSolver.AddMarkets(session.Markets())
 
 		// merge prices
 		for m, p := range prices {
 			allPrices[m] = p
+			priceSolver.UpdateQuote(sessionName, m, p, fixedpoint.Zero, priceTime)
 		}
 
-		s.Environment.RecordAsset(priceTime, session, assets)
+		var symbols []string
+		for currency, asset := range balances.Assets(prices, priceTime) {
+			if !asset.PriceInUSD.IsZero() {
+				continue
+			}
+
+			symbols = append(symbols, pricesolver.ConversionSymbols(session.Markets(), currency, quoteCurrency)...)
+		}
+
+		if err := priceSolver.UpdateFromTickers(ctx, session.Exchange, symbols...); err != nil {
+			log.WithError(err).Warnf("unable to update the conversion prices of session %s", sessionName)
+		}
+	}
+
+	// record the assets of the sessions
+	for sessionName, balances := range sessionBalances {
+		assets := balances.Assets(sessions[sessionName].LastPrices(), priceTime)
+		resolveAssetPrices(assets, priceSolver)
+		s.Environment.RecordAsset(priceTime, sessions[sessionName], assets)
 	}
 
 	displayAssets := types.AssetMap{}
 	totalAssets := totalBalances.Assets(allPrices, priceTime)
+	resolveAssetPrices(totalAssets, priceSolver)
 	s.Environment.RecordAsset(priceTime, &bbgo.ExchangeSession{Name: "ALL"}, totalAssets)
 
 	for currency, asset := range totalAssets {
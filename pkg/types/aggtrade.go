@@ -0,0 +1,41 @@
+package types
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// AggTrade is an aggregated trade, the trades filled by the same taker order at the same price are aggregated.
+// It's used for the tick-level backtest research, the aggregated trades are replayed as the market trades in back-test.
+type AggTrade struct {
+	GID          int64            `json:"gid,omitempty" db:"gid"`
+	Exchange     ExchangeName     `json:"exchange" db:"exchange"`
+	Symbol       string           `json:"symbol" db:"symbol"`
+	ID           uint64           `json:"id" db:"agg_trade_id"`
+	Price        fixedpoint.Value `json:"price" db:"price"`
+	Quantity     fixedpoint.Value `json:"quantity" db:"quantity"`
+	FirstTradeID uint64           `json:"firstTradeID" db:"first_trade_id"`
+	LastTradeID  uint64           `json:"lastTradeID" db:"last_trade_id"`
+	IsBuyerMaker bool             `json:"isBuyerMaker" db:"is_buyer_maker"`
+	Time         Time             `json:"time" db:"traded_at"`
+}
+
+// Trade converts the aggregated trade to the market trade, the side is the taker side
+func (t AggTrade) Trade() Trade {
+	side := SideTypeBuy
+	if t.IsBuyerMaker {
+		side = SideTypeSell
+	}
+
+	return Trade{
+		ID:            t.ID,
+		Exchange:      t.Exchange,
+		Symbol:        t.Symbol,
+		Side:          side,
+		Price:         t.Price,
+		Quantity:      t.Quantity,
+		QuoteQuantity: t.Price.Mul(t.Quantity),
+		IsBuyer:       !t.IsBuyerMaker,
+		IsMaker:       t.IsBuyerMaker,
+		Time:          t.Time,
+	}
+}
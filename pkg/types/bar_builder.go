@@ -0,0 +1,366 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+type BarType string
+
+const (
+	// BarTypeTick closes a bar every N trades
+	BarTypeTick BarType = "tick"
+
+	// BarTypeVolume closes a bar every N base asset volume
+	BarTypeVolume BarType = "volume"
+
+	// BarTypeDollar closes a bar every N quote asset volume (notional)
+	BarTypeDollar BarType = "dollar"
+
+	// BarTypeRange closes a bar when its high-low range reaches N
+	BarTypeRange BarType = "range"
+
+	// BarTypeRenko closes a brick when the price moves N from the previous brick close,
+	// a reversal requires the price to move 2N
+	BarTypeRenko BarType = "renko"
+)
+
+// BarConfig defines an alternative bar type that is built from the trades instead of the time
+type BarConfig struct {
+	Type BarType `json:"type" yaml:"type"`
+
+	// Threshold is the number of trades of the tick bars, the base volume of the volume bars,
+	// the quote volume of the dollar bars or the price range (brick size) of the range and renko bars
+	Threshold fixedpoint.Value `json:"threshold" yaml:"threshold"`
+
+	// Interval is the kline interval to build the bars from when the market trades are not available,
+	// for example, in back-test without the imported aggregated trades.
+	// Each kline is split into the open, high, low and close ticks, so the bars built from klines are approximated.
+	Interval Interval `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+func (c BarConfig) String() string {
+	return fmt.Sprintf("%s(%s)", c.Type, c.Threshold.String())
+}
+
+func (c BarConfig) Validate() error {
+	switch c.Type {
+	case BarTypeTick, BarTypeVolume, BarTypeDollar, BarTypeRange, BarTypeRenko:
+	default:
+		return fmt.Errorf("invalid bar type: %q", c.Type)
+	}
+
+	if c.Threshold.Sign() <= 0 {
+		return fmt.Errorf("%s bar threshold must be positive, got %s", c.Type, c.Threshold.String())
+	}
+
+	if c.Type == BarTypeTick && c.Threshold.Int64() == 0 {
+		return fmt.Errorf("tick bar threshold must be at least 1, got %s", c.Threshold.String())
+	}
+
+	return nil
+}
+
+// barTick is a price print merged into the bars, it is a market trade or a part of a kline
+type barTick struct {
+	id       uint64
+	time     time.Time
+	price    fixedpoint.Value
+	quantity fixedpoint.Value
+	quote    fixedpoint.Value
+	trades   uint64
+
+	takerBuyQuantity fixedpoint.Value
+	takerBuyQuote    fixedpoint.Value
+}
+
+// split splits the tick into the head with the given ratio and the tail with the rest
+func (t barTick) split(ratio fixedpoint.Value) (head, tail barTick) {
+	head, tail = t, t
+
+	head.quantity = t.quantity.Mul(ratio)
+	head.quote = t.quote.Mul(ratio)
+	head.takerBuyQuantity = t.takerBuyQuantity.Mul(ratio)
+	head.takerBuyQuote = t.takerBuyQuote.Mul(ratio)
+	head.trades = uint64(fixedpoint.NewFromInt(int64(t.trades)).Mul(ratio).Round(0, fixedpoint.HalfUp).Int64())
+
+	tail.quantity = t.quantity.Sub(head.quantity)
+	tail.quote = t.quote.Sub(head.quote)
+	tail.takerBuyQuantity = t.takerBuyQuantity.Sub(head.takerBuyQuantity)
+	tail.takerBuyQuote = t.takerBuyQuote.Sub(head.takerBuyQuote)
+	tail.trades = t.trades - head.trades
+	return head, tail
+}
+
+//go:generate callbackgen -type BarBuilder
+
+// BarBuilder builds the alternative bars (tick, volume, dollar, range and renko bars) from the market trades or the klines.
+// The bars are emitted as klines with an empty interval: OnKLine for the updates of the current bar and OnKLineClosed for the closed bars.
+type BarBuilder struct {
+	Symbol string
+	BarConfig
+
+	bar *KLine
+
+	// brick is the last renko brick
+	brick *KLine
+
+	kLineCallbacks       []func(k KLine)
+	kLineClosedCallbacks []func(k KLine)
+}
+
+func NewBarBuilder(symbol string, config BarConfig) *BarBuilder {
+	return &BarBuilder{
+		Symbol:    symbol,
+		BarConfig: config,
+	}
+}
+
+// BindStream binds the builder to the market trades of the stream,
+// or to the closed klines of the configured interval if it's set.
+func (b *BarBuilder) BindStream(stream Stream) {
+	if b.Interval != "" {
+		stream.OnKLineClosed(KLineWith(b.Symbol, b.Interval, b.HandleKLine))
+		return
+	}
+
+	stream.OnMarketTrade(b.HandleTrade)
+}
+
+func (b *BarBuilder) HandleTrade(trade Trade) {
+	if trade.Symbol != b.Symbol {
+		return
+	}
+
+	quote := trade.QuoteQuantity
+	if quote.IsZero() {
+		quote = trade.Price.Mul(trade.Quantity)
+	}
+
+	t := barTick{
+		id:       trade.ID,
+		time:     trade.Time.Time(),
+		price:    trade.Price,
+		quantity: trade.Quantity,
+		quote:    quote,
+		trades:   1,
+	}
+
+	if trade.Side == SideTypeBuy {
+		t.takerBuyQuantity = t.quantity
+		t.takerBuyQuote = t.quote
+	}
+
+	b.add(t)
+}
+
+// HandleKLine splits the kline into the open, high, low and close ticks (open, low, high and close for the bullish kline)
+// and merges them into the bars, the volume and the trades are split equally.
+func (b *BarBuilder) HandleKLine(k KLine) {
+	if k.Symbol != b.Symbol {
+		return
+	}
+
+	prices := []fixedpoint.Value{k.Open, k.High, k.Low, k.Close}
+	if k.Close.Compare(k.Open) > 0 {
+		prices = []fixedpoint.Value{k.Open, k.Low, k.High, k.Close}
+	}
+
+	var (
+		n        = fixedpoint.NewFromInt(int64(len(prices)))
+		start    = k.StartTime.Time()
+		duration = k.EndTime.Time().Sub(start)
+		trades   = k.NumberOfTrades
+	)
+
+	for i, price := range prices {
+		t := barTick{
+			id:               k.LastTradeID,
+			time:             start.Add(duration * time.Duration(i) / time.Duration(len(prices)-1)),
+			price:            price,
+			quantity:         k.Volume.Div(n),
+			quote:            k.QuoteVolume.Div(n),
+			takerBuyQuantity: k.TakerBuyBaseAssetVolume.Div(n),
+			takerBuyQuote:    k.TakerBuyQuoteAssetVolume.Div(n),
+			trades:           1,
+		}
+
+		if trades > 0 {
+			t.trades = trades / uint64(len(prices))
+			if i == len(prices)-1 {
+				t.trades = trades - t.trades*uint64(len(prices)-1)
+			}
+		}
+
+		if t.quote.IsZero() {
+			t.quote = price.Mul(t.quantity)
+		}
+
+		b.add(t)
+	}
+}
+
+func (b *BarBuilder) add(t barTick) {
+	switch b.Type {
+	case BarTypeTick, BarTypeVolume, BarTypeDollar:
+		b.addThreshold(t)
+
+	case BarTypeRange:
+		b.addRange(t)
+
+	case BarTypeRenko:
+		b.addRenko(t)
+	}
+}
+
+// measure returns the amount of the tick or the bar that is compared with the threshold
+func (b *BarBuilder) measure(trades uint64, quantity, quote fixedpoint.Value) fixedpoint.Value {
+	switch b.Type {
+	case BarTypeTick:
+		return fixedpoint.NewFromInt(int64(trades))
+	case BarTypeVolume:
+		return quantity
+	case BarTypeDollar:
+		return quote
+	}
+
+	return fixedpoint.Zero
+}
+
+// addThreshold merges the tick into the current bar, the tick crossing the threshold is split into the next bars
+func (b *BarBuilder) addThreshold(t barTick) {
+	for {
+		amount := b.measure(t.trades, t.quantity, t.quote)
+
+		remaining := b.Threshold
+		if b.bar != nil {
+			remaining = remaining.Sub(b.measure(b.bar.NumberOfTrades, b.bar.Volume, b.bar.QuoteVolume))
+		}
+
+		if amount.Compare(remaining) < 0 {
+			b.merge(t)
+			b.EmitKLine(*b.bar)
+			return
+		}
+
+		head, tail := t.split(remaining.Div(amount))
+
+		// keep the measured amount exact to avoid the rounding error of the ratio
+		switch b.Type {
+		case BarTypeVolume:
+			head.quantity, tail.quantity = remaining, t.quantity.Sub(remaining)
+		case BarTypeDollar:
+			head.quote, tail.quote = remaining, t.quote.Sub(remaining)
+		}
+
+		b.merge(head)
+		b.close()
+
+		if b.measure(tail.trades, tail.quantity, tail.quote).Sign() <= 0 {
+			return
+		}
+
+		t = tail
+	}
+}
+
+// addRange merges the tick into the current bar, the bar is closed at the range boundary when its range reaches the threshold,
+// and the next bar opens at the boundary, so a price gap creates multiple bars.
+func (b *BarBuilder) addRange(t barTick) {
+	for b.bar != nil {
+		var boundary fixedpoint.Value
+		if upper := b.bar.Low.Add(b.Threshold); t.price.Compare(upper) >= 0 {
+			boundary = upper
+		} else if lower := b.bar.High.Sub(b.Threshold); t.price.Compare(lower) <= 0 {
+			boundary = lower
+		} else {
+			break
+		}
+
+		b.merge(barTick{id: t.id, time: t.time, price: boundary})
+		b.close()
+		b.merge(barTick{id: t.id, time: t.time, price: boundary})
+	}
+
+	b.merge(t)
+	b.EmitKLine(*b.bar)
+}
+
+// addRenko merges the tick into the pending brick, and closes the bricks when the price moves
+// the threshold from the previous brick close in the same direction or twice the threshold in the reversed direction.
+func (b *BarBuilder) addRenko(t barTick) {
+	if b.brick == nil {
+		// the first tick sets the reference price of the bricks
+		b.brick = &KLine{Open: t.price, Close: t.price}
+	}
+
+	b.merge(t)
+
+	for {
+		direction := b.brick.Close.Compare(b.brick.Open)
+
+		var open, close fixedpoint.Value
+		if up := b.brick.Close.Add(b.Threshold); direction >= 0 && t.price.Compare(up) >= 0 {
+			open, close = b.brick.Close, up
+		} else if down := b.brick.Close.Sub(b.Threshold); direction <= 0 && t.price.Compare(down) <= 0 {
+			open, close = b.brick.Close, down
+		} else if up := b.brick.Open.Add(b.Threshold); direction < 0 && t.price.Compare(up) >= 0 {
+			open, close = b.brick.Open, up
+		} else if down := b.brick.Open.Sub(b.Threshold); direction > 0 && t.price.Compare(down) <= 0 {
+			open, close = b.brick.Open, down
+		} else {
+			break
+		}
+
+		if b.bar == nil {
+			b.merge(barTick{id: t.id, time: t.time, price: close})
+		}
+
+		b.bar.Open = open
+		b.bar.Close = close
+		b.bar.High = fixedpoint.Max(open, close)
+		b.bar.Low = fixedpoint.Min(open, close)
+
+		brick := *b.bar
+		b.brick = &brick
+		b.close()
+	}
+
+	if b.bar != nil {
+		b.EmitKLine(*b.bar)
+	}
+}
+
+// merge merges the tick into the current bar, a new bar is opened if there is no current bar
+func (b *BarBuilder) merge(t barTick) {
+	if b.bar == nil {
+		b.bar = &KLine{
+			Symbol:    b.Symbol,
+			StartTime: Time(t.time),
+			Open:      t.price,
+			High:      t.price,
+			Low:       t.price,
+		}
+	}
+
+	k := b.bar
+	k.EndTime = Time(t.time)
+	k.Close = t.price
+	k.High = fixedpoint.Max(k.High, t.price)
+	k.Low = fixedpoint.Min(k.Low, t.price)
+	k.Volume = k.Volume.Add(t.quantity)
+	k.QuoteVolume = k.QuoteVolume.Add(t.quote)
+	k.TakerBuyBaseAssetVolume = k.TakerBuyBaseAssetVolume.Add(t.takerBuyQuantity)
+	k.TakerBuyQuoteAssetVolume = k.TakerBuyQuoteAssetVolume.Add(t.takerBuyQuote)
+	k.NumberOfTrades += t.trades
+	k.LastTradeID = t.id
+}
+
+func (b *BarBuilder) close() {
+	k := *b.bar
+	k.Closed = true
+	b.bar = nil
+	b.EmitKLineClosed(k)
+}
@@ -0,0 +1,161 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func buildBars(config BarConfig, prices, quantities []float64) (closed []KLine, pending *KLine) {
+	builder := NewBarBuilder("BTCUSDT", config)
+	builder.OnKLineClosed(func(k KLine) { closed = append(closed, k) })
+	builder.OnKLine(func(k KLine) { pending = &k })
+
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range prices {
+		builder.HandleTrade(Trade{
+			ID:       uint64(i + 1),
+			Symbol:   "BTCUSDT",
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantities[i]),
+			Side:     SideTypeBuy,
+			Time:     Time(start.Add(time.Duration(i) * time.Second)),
+		})
+	}
+
+	return closed, pending
+}
+
+func TestBarConfig_Validate(t *testing.T) {
+	assert.NoError(t, BarConfig{Type: BarTypeVolume, Threshold: fixedpoint.NewFromFloat(10)}.Validate())
+	assert.Error(t, BarConfig{Type: "foo", Threshold: fixedpoint.NewFromFloat(10)}.Validate())
+	assert.Error(t, BarConfig{Type: BarTypeRange}.Validate())
+	assert.Error(t, BarConfig{Type: BarTypeTick, Threshold: fixedpoint.NewFromFloat(0.5)}.Validate())
+}
+
+func TestBarBuilder_TickBars(t *testing.T) {
+	closed, pending := buildBars(BarConfig{Type: BarTypeTick, Threshold: fixedpoint.NewFromInt(3)},
+		[]float64{10, 12, 9, 11, 13, 8, 10},
+		[]float64{1, 1, 1, 1, 1, 1, 1})
+
+	if assert.Len(t, closed, 2) {
+		assert.Equal(t, 10.0, closed[0].Open.Float64())
+		assert.Equal(t, 12.0, closed[0].High.Float64())
+		assert.Equal(t, 9.0, closed[0].Low.Float64())
+		assert.Equal(t, 9.0, closed[0].Close.Float64())
+		assert.Equal(t, uint64(3), closed[0].NumberOfTrades)
+		assert.Equal(t, uint64(3), closed[0].LastTradeID)
+		assert.True(t, closed[0].Closed)
+
+		assert.Equal(t, 11.0, closed[1].Open.Float64())
+		assert.Equal(t, 8.0, closed[1].Close.Float64())
+	}
+
+	if assert.NotNil(t, pending) {
+		assert.Equal(t, 10.0, pending.Close.Float64())
+		assert.False(t, pending.Closed)
+	}
+}
+
+func TestBarBuilder_VolumeBars(t *testing.T) {
+	closed, pending := buildBars(BarConfig{Type: BarTypeVolume, Threshold: fixedpoint.NewFromInt(10)},
+		[]float64{100, 101, 102},
+		[]float64{4, 4, 25})
+
+	// the last trade is split into the first bar, a full bar and the pending bar
+	if assert.Len(t, closed, 3) {
+		for _, k := range closed {
+			assert.Equal(t, 10.0, k.Volume.Float64())
+		}
+
+		assert.Equal(t, 102.0, closed[0].Close.Float64())
+		assert.Equal(t, 4*100+4*101+2*102.0, closed[0].QuoteVolume.Float64())
+		assert.Equal(t, 102.0, closed[1].Open.Float64())
+		assert.InDelta(t, 10.0, closed[1].TakerBuyBaseAssetVolume.Float64(), 1e-6)
+	}
+
+	if assert.NotNil(t, pending) {
+		assert.Equal(t, 3.0, pending.Volume.Float64())
+	}
+}
+
+func TestBarBuilder_DollarBars(t *testing.T) {
+	closed, _ := buildBars(BarConfig{Type: BarTypeDollar, Threshold: fixedpoint.NewFromInt(1000)},
+		[]float64{100, 200, 250},
+		[]float64{5, 2, 8})
+
+	if assert.Len(t, closed, 2) {
+		assert.Equal(t, 1000.0, closed[0].QuoteVolume.Float64())
+		assert.Equal(t, 5.0+2.0+0.4, closed[0].Volume.Float64())
+		assert.Equal(t, 1000.0, closed[1].QuoteVolume.Float64())
+		assert.Equal(t, 250.0, closed[1].Open.Float64())
+	}
+}
+
+func TestBarBuilder_RangeBars(t *testing.T) {
+	closed, pending := buildBars(BarConfig{Type: BarTypeRange, Threshold: fixedpoint.NewFromInt(5)},
+		[]float64{100, 102, 98, 104, 115},
+		[]float64{1, 1, 1, 1, 1})
+
+	// 100..103 closes the first bar at 98+5, the gap to 115 creates the bars of 103..108 and 108..113
+	if assert.Len(t, closed, 3) {
+		assert.Equal(t, 100.0, closed[0].Open.Float64())
+		assert.Equal(t, 98.0, closed[0].Low.Float64())
+		assert.Equal(t, 103.0, closed[0].High.Float64())
+		assert.Equal(t, 103.0, closed[0].Close.Float64())
+
+		assert.Equal(t, 103.0, closed[1].Open.Float64())
+		assert.Equal(t, 108.0, closed[1].Close.Float64())
+		assert.Equal(t, 108.0, closed[2].Open.Float64())
+		assert.Equal(t, 113.0, closed[2].Close.Float64())
+	}
+
+	if assert.NotNil(t, pending) {
+		assert.Equal(t, 113.0, pending.Open.Float64())
+		assert.Equal(t, 115.0, pending.Close.Float64())
+	}
+}
+
+func TestBarBuilder_RenkoBars(t *testing.T) {
+	closed, _ := buildBars(BarConfig{Type: BarTypeRenko, Threshold: fixedpoint.NewFromInt(10)},
+		[]float64{100, 105, 121, 112, 95, 89},
+		[]float64{1, 1, 1, 1, 1, 1})
+
+	// up bricks 100->110->120, 112 is not a reversal, 95 reverses with the brick 110->100 and 89 adds 100->90
+	var bricks [][2]float64
+	for _, k := range closed {
+		bricks = append(bricks, [2]float64{k.Open.Float64(), k.Close.Float64()})
+	}
+
+	assert.Equal(t, [][2]float64{{100, 110}, {110, 120}, {110, 100}, {100, 90}}, bricks)
+	if assert.Len(t, closed, 4) {
+		assert.Equal(t, 3.0, closed[0].Volume.Float64())
+		assert.Equal(t, 0.0, closed[1].Volume.Float64())
+		assert.Equal(t, 2.0, closed[2].Volume.Float64())
+		assert.Equal(t, 110.0, closed[2].High.Float64())
+	}
+}
+
+func TestBarBuilder_KLines(t *testing.T) {
+	builder := NewBarBuilder("BTCUSDT", BarConfig{Type: BarTypeVolume, Threshold: fixedpoint.NewFromInt(8)})
+
+	var closed []KLine
+	builder.OnKLineClosed(func(k KLine) { closed = append(closed, k) })
+
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	builder.HandleKLine(newTestKLine(Interval1m, start, 100, 110, 95, 105, 4))
+	builder.HandleKLine(newTestKLine(Interval1m, start.Add(time.Minute), 105, 106, 90, 92, 4))
+
+	// the bullish kline is split into open, low, high and close, the bearish one into open, high, low and close
+	if assert.Len(t, closed, 1) {
+		assert.Equal(t, 100.0, closed[0].Open.Float64())
+		assert.Equal(t, 110.0, closed[0].High.Float64())
+		assert.Equal(t, 90.0, closed[0].Low.Float64())
+		assert.Equal(t, 92.0, closed[0].Close.Float64())
+		assert.Equal(t, start, closed[0].StartTime.Time())
+		assert.Equal(t, start.Add(2*time.Minute-time.Millisecond), closed[0].EndTime.Time())
+	}
+}
@@ -133,3 +133,17 @@ func (d *Duration) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var o interface{}
+	if err := unmarshal(&o); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	return d.UnmarshalJSON(data)
+}
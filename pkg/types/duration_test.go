@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 func TestParseSimpleDuration(t *testing.T) {
@@ -53,3 +54,18 @@ func TestParseSimpleDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestDuration_UnmarshalYAML(t *testing.T) {
+	var config struct {
+		Interval Duration `yaml:"interval"`
+		Window   Duration `yaml:"window"`
+		Timeout  Duration `yaml:"timeout"`
+	}
+
+	err := yaml.Unmarshal([]byte("interval: 5s\nwindow: 2d\ntimeout: 30\n"), &config)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5*time.Second, config.Interval.Duration())
+		assert.Equal(t, 48*time.Hour, config.Window.Duration())
+		assert.Equal(t, 30*time.Second, config.Timeout.Duration())
+	}
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// EquitySnapshot is the net value of a session account at a point of time,
+// the net value is evaluated in the quote currency and the drawdowns are ratios to the high-water mark
+type EquitySnapshot struct {
+	GID           int64            `json:"gid,omitempty" db:"gid"`
+	Session       string           `json:"session" db:"session"`
+	Exchange      ExchangeName     `json:"exchange" db:"exchange"`
+	Time          Time             `json:"time" db:"time"`
+	QuoteCurrency string           `json:"quoteCurrency" db:"quote_currency"`
+	NetValue      fixedpoint.Value `json:"netValue" db:"net_value"`
+	HighWaterMark fixedpoint.Value `json:"highWaterMark" db:"high_water_mark"`
+	Drawdown      fixedpoint.Value `json:"drawdown" db:"drawdown"`
+	MaxDrawdown   fixedpoint.Value `json:"maxDrawdown" db:"max_drawdown"`
+}
+
+// DrawdownTracker tracks the high-water mark, the current drawdown and the max drawdown of an equity curve
+type DrawdownTracker struct {
+	HighWaterMark fixedpoint.Value `json:"highWaterMark"`
+
+	// Drawdown is the current drawdown ratio from the high-water mark, 0.1 means 10%
+	Drawdown fixedpoint.Value `json:"drawdown"`
+
+	// MaxDrawdown is the largest drawdown ratio since the tracking started
+	MaxDrawdown fixedpoint.Value `json:"maxDrawdown"`
+}
+
+// Update updates the tracker with the latest equity value and returns the current drawdown
+func (t *DrawdownTracker) Update(value fixedpoint.Value) fixedpoint.Value {
+	if value.Compare(t.HighWaterMark) > 0 {
+		t.HighWaterMark = value
+	}
+
+	if t.HighWaterMark.Sign() <= 0 {
+		t.Drawdown = fixedpoint.Zero
+		return t.Drawdown
+	}
+
+	t.Drawdown = t.HighWaterMark.Sub(value).Div(t.HighWaterMark)
+	t.MaxDrawdown = fixedpoint.Max(t.MaxDrawdown, t.Drawdown)
+	return t.Drawdown
+}
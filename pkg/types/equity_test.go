@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestDrawdownTracker(t *testing.T) {
+	tracker := &DrawdownTracker{}
+
+	assert.Equal(t, "0", tracker.Update(fixedpoint.NewFromInt(1000)).String())
+	assert.Equal(t, "1000", tracker.HighWaterMark.String())
+
+	assert.Equal(t, "0.1", tracker.Update(fixedpoint.NewFromInt(900)).String())
+	assert.Equal(t, "0.2", tracker.Update(fixedpoint.NewFromInt(800)).String())
+	assert.Equal(t, "0.05", tracker.Update(fixedpoint.NewFromInt(950)).String())
+	assert.Equal(t, "0.2", tracker.MaxDrawdown.String())
+
+	// new high-water mark
+	assert.Equal(t, "0", tracker.Update(fixedpoint.NewFromInt(1200)).String())
+	assert.Equal(t, "1200", tracker.HighWaterMark.String())
+	assert.Equal(t, "0.25", tracker.Update(fixedpoint.NewFromInt(900)).String())
+	assert.Equal(t, "0.25", tracker.MaxDrawdown.String())
+}
@@ -1,6 +1,32 @@
 package types
 
-import "github.com/c9s/bbgo/pkg/fixedpoint"
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// ContractType is the normalized contract type of the futures markets
+type ContractType string
+
+const (
+	ContractTypePerpetual      ContractType = "perpetual"
+	ContractTypeCurrentWeek    ContractType = "current_week"
+	ContractTypeNextWeek       ContractType = "next_week"
+	ContractTypeCurrentMonth   ContractType = "current_month"
+	ContractTypeNextMonth      ContractType = "next_month"
+	ContractTypeCurrentQuarter ContractType = "current_quarter"
+	ContractTypeNextQuarter    ContractType = "next_quarter"
+
+	// ContractTypeDelivery is used for the dated futures contracts that can not be mapped to the above types
+	ContractTypeDelivery ContractType = "delivery"
+)
+
+// DeliveryFuturesMarketService is implemented by the exchanges that list the dated (delivery) futures contracts,
+// the returned markets carry the ContractType and the DeliveryTime
+type DeliveryFuturesMarketService interface {
+	QueryDeliveryFuturesMarkets(ctx context.Context) (MarketMap, error)
+}
 
 type FuturesExchange interface {
 	UseFutures()
@@ -151,6 +151,102 @@ func (m IntervalMap) Slice() (slice IntervalSlice) {
 	return slice
 }
 
+// BaseInterval returns the largest interval of the map that can be aggregated into the target interval,
+// the target interval itself is never returned.
+// Week and month intervals can only be built from the intervals of the same unit or the intervals that divide one day.
+func (m IntervalMap) BaseInterval(target Interval) (base Interval, ok bool) {
+	targetNum, targetUnit := target.split()
+	for interval := range m {
+		if interval == target {
+			continue
+		}
+
+		num, unit := interval.split()
+		switch unit {
+		case "ms":
+			continue
+
+		case "w", "mo":
+			if unit != targetUnit || targetNum%num != 0 {
+				continue
+			}
+
+		default:
+			switch targetUnit {
+			case "w", "mo":
+				if (24*time.Hour)%interval.Duration() != 0 {
+					continue
+				}
+
+			default:
+				if interval.Duration() >= target.Duration() || target.Duration()%interval.Duration() != 0 {
+					continue
+				}
+			}
+		}
+
+		if !ok || interval.Duration() > base.Duration() {
+			base, ok = interval, true
+		}
+	}
+
+	return base, ok
+}
+
+// weekEpoch is the first Monday after the unix epoch, week intervals are aligned to it.
+var weekEpoch = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+// split returns the number and the lower-cased unit of the interval, for example, 90m returns (90, "m")
+func (i Interval) split() (int, string) {
+	n, index := 0, len(i)
+	for idx, rn := range string(i) {
+		if rn < '0' || rn > '9' {
+			index = idx
+			break
+		}
+
+		n = n*10 + int(rn-'0')
+	}
+
+	if n == 0 {
+		n = 1
+	}
+
+	return n, strings.ToLower(string(i[index:]))
+}
+
+// Truncate returns the start time of the interval period that contains t.
+// Month intervals are aligned to the calendar months, week intervals are aligned to Monday,
+// and the other intervals are aligned to the unix epoch in UTC.
+func (i Interval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+
+	n, unit := i.split()
+	switch unit {
+	case "mo":
+		months := (t.Year()-1970)*12 + int(t.Month()) - 1
+		months -= months % n
+		return time.Date(1970+months/12, time.Month(months%12+1), 1, 0, 0, 0, 0, time.UTC)
+
+	case "w":
+		d := i.Duration()
+		return weekEpoch.Add(t.Sub(weekEpoch) / d * d)
+	}
+
+	ms := int64(i.Milliseconds())
+	return time.UnixMilli(t.UnixMilli() / ms * ms).UTC()
+}
+
+// Next returns the start time of the interval period that follows the period starting at the given start time.
+func (i Interval) Next(start time.Time) time.Time {
+	n, unit := i.split()
+	if unit == "mo" {
+		return start.AddDate(0, n, 0)
+	}
+
+	return start.Add(i.Duration())
+}
+
 var SupportedIntervals = IntervalMap{
 	Interval1s:  1,
 	Interval1m:  1 * 60,
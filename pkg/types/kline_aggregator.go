@@ -0,0 +1,214 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+//go:generate callbackgen -type KLineAggregator
+
+// KLineAggregator builds the klines of an interval that the exchange does not provide (for example, 7m or 90m)
+// from the klines of a lower base interval, or from the market trades when the base interval is empty.
+//
+// The aggregated klines are emitted like the native stream klines: OnKLine for the updates of the current period
+// and OnKLineClosed once the period is closed. A period is closed when the base kline reaching the end of the period is closed,
+// or when the kline (or the trade) of the next period arrives. The trade-built period is also closed by Tick,
+// since the trades of the next period might not come. The handlers are safe to be called from different goroutines.
+type KLineAggregator struct {
+	Symbol       string
+	Interval     Interval
+	BaseInterval Interval
+
+	mu sync.Mutex
+
+	// kLine is the kline of the current period merged from the closed base klines or the trades
+	kLine *KLine
+
+	// closedStartTime is the start time of the last closed period, the late klines and trades of it are ignored
+	closedStartTime time.Time
+
+	kLineCallbacks       []func(k KLine)
+	kLineClosedCallbacks []func(k KLine)
+}
+
+func NewKLineAggregator(symbol string, interval, baseInterval Interval) *KLineAggregator {
+	return &KLineAggregator{
+		Symbol:       symbol,
+		Interval:     interval,
+		BaseInterval: baseInterval,
+	}
+}
+
+// BindStream binds the aggregator to the kline events of the base interval,
+// or to the market trade events if the base interval is empty.
+func (a *KLineAggregator) BindStream(stream Stream) {
+	if a.BaseInterval == "" {
+		stream.OnMarketTrade(a.HandleTrade)
+		return
+	}
+
+	stream.OnKLine(a.HandleKLine)
+	stream.OnKLineClosed(a.HandleKLineClosed)
+}
+
+// HandleKLine handles the updates of the unclosed base kline,
+// closed klines are ignored since they are handled by HandleKLineClosed.
+func (a *KLineAggregator) HandleKLine(k KLine) {
+	if k.Closed || k.Symbol != a.Symbol || k.Interval != a.BaseInterval {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	startTime := a.Interval.Truncate(k.StartTime.Time())
+	if !a.rollover(startTime) {
+		return
+	}
+
+	var kLine KLine
+	if a.kLine == nil {
+		kLine = a.newKLine(k, startTime)
+	} else {
+		kLine = *a.kLine
+		kLine.Merge(&k)
+		kLine.EndTime = a.kLine.EndTime
+	}
+
+	kLine.Closed = false
+	a.EmitKLine(kLine)
+}
+
+// HandleKLineClosed merges the closed base kline into the current period.
+func (a *KLineAggregator) HandleKLineClosed(k KLine) {
+	if k.Symbol != a.Symbol || k.Interval != a.BaseInterval {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	startTime := a.Interval.Truncate(k.StartTime.Time())
+	if !a.rollover(startTime) {
+		return
+	}
+
+	if a.kLine == nil {
+		kLine := a.newKLine(k, startTime)
+		a.kLine = &kLine
+	} else {
+		endTime := a.kLine.EndTime
+		a.kLine.Merge(&k)
+		a.kLine.EndTime = endTime
+	}
+
+	a.kLine.Closed = false
+
+	// the base kline reaches the end of the period
+	if !k.EndTime.Time().Before(a.kLine.EndTime.Time()) {
+		a.close()
+		return
+	}
+
+	a.EmitKLine(*a.kLine)
+}
+
+// HandleTrade merges the market trade into the current period.
+func (a *KLineAggregator) HandleTrade(trade Trade) {
+	if trade.Symbol != a.Symbol {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	startTime := a.Interval.Truncate(trade.Time.Time())
+	if !a.rollover(startTime) {
+		return
+	}
+
+	quoteQuantity := trade.QuoteQuantity
+	if quoteQuantity.IsZero() {
+		quoteQuantity = trade.Price.Mul(trade.Quantity)
+	}
+
+	if a.kLine == nil {
+		a.kLine = &KLine{
+			Exchange:  trade.Exchange,
+			Symbol:    a.Symbol,
+			StartTime: Time(startTime),
+			EndTime:   Time(a.Interval.Next(startTime).Add(-time.Millisecond)),
+			Interval:  a.Interval,
+			Open:      trade.Price,
+			High:      trade.Price,
+			Low:       trade.Price,
+		}
+	}
+
+	k := a.kLine
+	k.Close = trade.Price
+	if trade.Price.Compare(k.High) > 0 {
+		k.High = trade.Price
+	}
+	if trade.Price.Compare(k.Low) < 0 {
+		k.Low = trade.Price
+	}
+
+	k.Volume = k.Volume.Add(trade.Quantity)
+	k.QuoteVolume = k.QuoteVolume.Add(quoteQuantity)
+	if trade.Side == SideTypeBuy {
+		k.TakerBuyBaseAssetVolume = k.TakerBuyBaseAssetVolume.Add(trade.Quantity)
+		k.TakerBuyQuoteAssetVolume = k.TakerBuyQuoteAssetVolume.Add(quoteQuantity)
+	}
+
+	k.LastTradeID = trade.ID
+	k.NumberOfTrades++
+	a.EmitKLine(*k)
+}
+
+// Tick closes the current period if the given time reaches its end,
+// this is used for the trade aggregation since the trades of the next period might not come.
+func (a *KLineAggregator) Tick(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.kLine != nil && !now.Before(a.kLine.EndTime.Time()) {
+		a.close()
+	}
+}
+
+// rollover closes the current period if the given period start time is after it.
+// It returns false if the given period is before the current period.
+func (a *KLineAggregator) rollover(startTime time.Time) bool {
+	if a.kLine == nil {
+		return a.closedStartTime.IsZero() || startTime.After(a.closedStartTime)
+	}
+
+	current := a.kLine.StartTime.Time()
+	if startTime.Before(current) {
+		return false
+	}
+
+	if startTime.After(current) {
+		a.close()
+	}
+
+	return true
+}
+
+func (a *KLineAggregator) newKLine(k KLine, startTime time.Time) KLine {
+	kLine := k
+	kLine.GID = 0
+	kLine.Interval = a.Interval
+	kLine.StartTime = Time(startTime)
+	kLine.EndTime = Time(a.Interval.Next(startTime).Add(-time.Millisecond))
+	return kLine
+}
+
+func (a *KLineAggregator) close() {
+	kLine := *a.kLine
+	kLine.Closed = true
+	a.kLine = nil
+	a.closedStartTime = kLine.StartTime.Time()
+	a.EmitKLineClosed(kLine)
+}
@@ -0,0 +1,188 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func newTestKLine(interval Interval, startTime time.Time, open, high, low, close, volume float64) KLine {
+	return KLine{
+		Symbol:    "BTCUSDT",
+		Interval:  interval,
+		StartTime: Time(startTime),
+		EndTime:   Time(startTime.Add(interval.Duration() - time.Millisecond)),
+		Open:      fixedpoint.NewFromFloat(open),
+		High:      fixedpoint.NewFromFloat(high),
+		Low:       fixedpoint.NewFromFloat(low),
+		Close:     fixedpoint.NewFromFloat(close),
+		Volume:    fixedpoint.NewFromFloat(volume),
+		Closed:    true,
+	}
+}
+
+func TestInterval_Truncate(t *testing.T) {
+	ts := time.Date(2023, time.March, 15, 10, 17, 30, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, time.March, 15, 10, 15, 0, 0, time.UTC), Interval15m.Truncate(ts))
+	assert.Equal(t, time.Date(2023, time.March, 15, 9, 0, 0, 0, time.UTC), Interval("90m").Truncate(ts))
+	assert.Equal(t, time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC), Interval1d.Truncate(ts))
+	// 2023-03-13 is a Monday
+	assert.Equal(t, time.Date(2023, time.March, 13, 0, 0, 0, 0, time.UTC), Interval1w.Truncate(ts))
+	assert.Equal(t, time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), Interval1mo.Truncate(ts))
+	assert.Equal(t, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), Interval("3mo").Truncate(ts))
+
+	// 7m periods are aligned to the unix epoch
+	start := Interval("7m").Truncate(ts)
+	assert.Equal(t, int64(0), start.Unix()%(7*60))
+	assert.False(t, start.After(ts))
+	assert.True(t, start.Add(7*time.Minute).After(ts))
+
+	assert.Equal(t, time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC), Interval1mo.Next(Interval1mo.Truncate(ts)))
+}
+
+func TestIntervalMap_BaseInterval(t *testing.T) {
+	testcases := []struct {
+		target Interval
+		base   Interval
+	}{
+		{"7m", Interval1m},
+		{"10m", Interval5m},
+		{"90m", Interval30m},
+		{"8h", Interval4h},
+		{"2d", Interval1d},
+		{"3w", Interval1w},
+		{"2mo", Interval1mo},
+		{Interval1w, Interval1d},
+		{Interval1mo, Interval1d},
+	}
+
+	for _, tc := range testcases {
+		base, ok := SupportedIntervals.BaseInterval(tc.target)
+		if assert.True(t, ok, tc.target.String()) {
+			assert.Equal(t, tc.base, base, tc.target.String())
+		}
+	}
+
+	_, ok := IntervalMap{Interval1h: 3600}.BaseInterval("7m")
+	assert.False(t, ok)
+}
+
+func TestKLineAggregator_KLines(t *testing.T) {
+	aggregator := NewKLineAggregator("BTCUSDT", "7m", Interval1m)
+
+	var updates, closed []KLine
+	aggregator.OnKLine(func(k KLine) { updates = append(updates, k) })
+	aggregator.OnKLineClosed(func(k KLine) { closed = append(closed, k) })
+
+	start := time.Unix(0, 0).UTC().Add(7 * time.Minute * 1000)
+	for i := 0; i < 15; i++ {
+		v := float64(100 + i)
+		aggregator.HandleKLineClosed(newTestKLine(Interval1m, start.Add(time.Duration(i)*time.Minute), v, v+2, v-1, v+1, 1))
+	}
+
+	if assert.Len(t, closed, 2) {
+		k := closed[0]
+		assert.Equal(t, Interval("7m"), k.Interval)
+		assert.True(t, k.Closed)
+		assert.Equal(t, start, k.StartTime.Time())
+		assert.Equal(t, start.Add(7*time.Minute-time.Millisecond), k.EndTime.Time())
+		assert.Equal(t, 100.0, k.Open.Float64())
+		assert.Equal(t, 108.0, k.High.Float64())
+		assert.Equal(t, 99.0, k.Low.Float64())
+		assert.Equal(t, 107.0, k.Close.Float64())
+		assert.Equal(t, 7.0, k.Volume.Float64())
+
+		assert.Equal(t, start.Add(7*time.Minute), closed[1].StartTime.Time())
+		assert.Equal(t, 107.0, closed[1].Open.Float64())
+		assert.Equal(t, 114.0, closed[1].Close.Float64())
+	}
+
+	// 6 updates in each closed period plus the pending 15th base kline
+	assert.Len(t, updates, 13)
+	last := updates[len(updates)-1]
+	assert.False(t, last.Closed)
+	assert.Equal(t, 114.0, last.Open.Float64())
+	assert.Equal(t, 115.0, last.Close.Float64())
+
+	// the unclosed base kline updates are merged into the preview without changing the period
+	unclosed := newTestKLine(Interval1m, start.Add(15*time.Minute), 115, 120, 110, 118, 2)
+	unclosed.Closed = false
+	aggregator.HandleKLine(unclosed)
+	last = updates[len(updates)-1]
+	assert.Equal(t, 120.0, last.High.Float64())
+	assert.Equal(t, 118.0, last.Close.Float64())
+	assert.Equal(t, 3.0, last.Volume.Float64())
+	assert.Len(t, closed, 2)
+}
+
+func TestKLineAggregator_GapClosesPeriod(t *testing.T) {
+	aggregator := NewKLineAggregator("BTCUSDT", "90m", Interval30m)
+
+	var closed []KLine
+	aggregator.OnKLineClosed(func(k KLine) { closed = append(closed, k) })
+
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	aggregator.HandleKLineClosed(newTestKLine(Interval30m, start, 10, 12, 9, 11, 1))
+	aggregator.HandleKLineClosed(newTestKLine(Interval30m, start.Add(30*time.Minute), 11, 13, 10, 12, 1))
+
+	// the last 30m kline of the first period is missing
+	aggregator.HandleKLineClosed(newTestKLine(Interval30m, start.Add(90*time.Minute), 12, 14, 11, 13, 1))
+	if assert.Len(t, closed, 1) {
+		assert.Equal(t, start, closed[0].StartTime.Time())
+		assert.Equal(t, 12.0, closed[0].Close.Float64())
+		assert.Equal(t, 2.0, closed[0].Volume.Float64())
+	}
+
+	// the klines of the other symbols and intervals are ignored
+	aggregator.HandleKLineClosed(newTestKLine(Interval1h, start.Add(2*time.Hour), 1, 1, 1, 1, 1))
+	assert.Len(t, closed, 1)
+}
+
+func TestKLineAggregator_Trades(t *testing.T) {
+	aggregator := NewKLineAggregator("BTCUSDT", Interval1m, "")
+
+	var closed []KLine
+	aggregator.OnKLineClosed(func(k KLine) { closed = append(closed, k) })
+
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	trade := func(offset time.Duration, price, quantity float64, side SideType) Trade {
+		return Trade{
+			ID:       uint64(offset),
+			Symbol:   "BTCUSDT",
+			Price:    fixedpoint.NewFromFloat(price),
+			Quantity: fixedpoint.NewFromFloat(quantity),
+			Side:     side,
+			Time:     Time(start.Add(offset)),
+		}
+	}
+
+	aggregator.HandleTrade(trade(time.Second, 100, 1, SideTypeBuy))
+	aggregator.HandleTrade(trade(20*time.Second, 105, 2, SideTypeSell))
+	aggregator.HandleTrade(trade(40*time.Second, 95, 1, SideTypeBuy))
+	aggregator.HandleTrade(trade(59*time.Second, 101, 1, SideTypeSell))
+	assert.Len(t, closed, 0)
+
+	aggregator.HandleTrade(trade(61*time.Second, 102, 1, SideTypeBuy))
+	if assert.Len(t, closed, 1) {
+		k := closed[0]
+		assert.Equal(t, 100.0, k.Open.Float64())
+		assert.Equal(t, 105.0, k.High.Float64())
+		assert.Equal(t, 95.0, k.Low.Float64())
+		assert.Equal(t, 101.0, k.Close.Float64())
+		assert.Equal(t, 5.0, k.Volume.Float64())
+		assert.Equal(t, 506.0, k.QuoteVolume.Float64())
+		assert.Equal(t, 2.0, k.TakerBuyBaseAssetVolume.Float64())
+		assert.Equal(t, uint64(4), k.NumberOfTrades)
+	}
+
+	aggregator.Tick(start.Add(119 * time.Second))
+	assert.Len(t, closed, 1)
+
+	aggregator.Tick(start.Add(120 * time.Second))
+	if assert.Len(t, closed, 2) {
+		assert.Equal(t, start.Add(time.Minute), closed[1].StartTime.Time())
+	}
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"math"
 	"strconv"
+	"time"
 
 	"github.com/leekchan/accounting"
 
@@ -54,6 +55,60 @@ type Market struct {
 
 	MinPrice fixedpoint.Value `json:"minPrice,omitempty"`
 	MaxPrice fixedpoint.Value `json:"maxPrice,omitempty"`
+
+	// ContractType is the contract type of the futures market, it's empty for the spot markets
+	ContractType ContractType `json:"contractType,omitempty"`
+
+	// DeliveryTime is the expiry time of the dated (delivery) futures contract,
+	// it's zero for the spot markets and the perpetual contracts
+	DeliveryTime time.Time `json:"deliveryTime,omitempty"`
+
+	// ContractValue is the base quantity of one contract,
+	// it's zero when the order quantity of the market is already in the base currency
+	ContractValue fixedpoint.Value `json:"contractValue,omitempty"`
+
+	// OptionType and StrikePrice are only available in the option markets,
+	// the expiry time of the option is the DeliveryTime
+	OptionType  OptionType       `json:"optionType,omitempty"`
+	StrikePrice fixedpoint.Value `json:"strikePrice,omitempty"`
+}
+
+// IsOption returns true if the market is an option market
+func (m Market) IsOption() bool {
+	return m.OptionType != ""
+}
+
+// IsDeliveryContract returns true if the market is a dated futures contract that has a delivery time
+func (m Market) IsDeliveryContract() bool {
+	return !m.DeliveryTime.IsZero()
+}
+
+// TimeToDelivery returns the duration from the given time to the delivery time of the contract
+func (m Market) TimeToDelivery(now time.Time) time.Duration {
+	if !m.IsDeliveryContract() {
+		return 0
+	}
+
+	return m.DeliveryTime.Sub(now)
+}
+
+// ToContractQuantity converts the base quantity into the order quantity of the market,
+// it's the number of contracts if the market has the contract value.
+func (m Market) ToContractQuantity(base fixedpoint.Value) fixedpoint.Value {
+	if m.ContractValue.Sign() <= 0 {
+		return base
+	}
+
+	return base.Div(m.ContractValue)
+}
+
+// ToBaseQuantity converts the order quantity of the market into the base quantity
+func (m Market) ToBaseQuantity(quantity fixedpoint.Value) fixedpoint.Value {
+	if m.ContractValue.Sign() <= 0 {
+		return quantity
+	}
+
+	return quantity.Mul(m.ContractValue)
 }
 
 func (m Market) IsDustQuantity(quantity, price fixedpoint.Value) bool {
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+type OptionType string
+
+const (
+	OptionTypeCall OptionType = "call"
+	OptionTypePut  OptionType = "put"
+)
+
+// OptionGreeks is the Black-Scholes sensitivity of the option price per unit of the underlying asset
+type OptionGreeks struct {
+	Delta fixedpoint.Value `json:"delta"`
+	Gamma fixedpoint.Value `json:"gamma"`
+	Vega  fixedpoint.Value `json:"vega"`
+	Theta fixedpoint.Value `json:"theta"`
+}
+
+// OptionTicker is the ticker of the option market with the mark price, the implied volatilities and the greeks
+type OptionTicker struct {
+	Ticker
+
+	Symbol string `json:"symbol"`
+
+	MarkPrice       fixedpoint.Value `json:"markPrice"`
+	UnderlyingPrice fixedpoint.Value `json:"underlyingPrice"`
+
+	// MarkIV, BidIV and AskIV are the implied volatilities, 0.5 means 50%
+	MarkIV fixedpoint.Value `json:"markIV"`
+	BidIV  fixedpoint.Value `json:"bidIV"`
+	AskIV  fixedpoint.Value `json:"askIV"`
+
+	Greeks OptionGreeks `json:"greeks"`
+}
+
+// PositionDelta returns the delta of the option position in the unit of the underlying asset,
+// the base (quantity) of the position is converted by the contract value of the market.
+func (t *OptionTicker) PositionDelta(market Market, base fixedpoint.Value) fixedpoint.Value {
+	return t.Greeks.Delta.Mul(market.ToBaseQuantity(base))
+}
+
+// OptionMarketDataService is implemented by the exchanges that list options,
+// the underlying is the base currency of the option markets, e.g. BTC
+type OptionMarketDataService interface {
+	QueryOptionMarkets(ctx context.Context, underlying string) (MarketMap, error)
+	QueryOptionTickers(ctx context.Context, underlying string) (map[string]OptionTicker, error)
+}
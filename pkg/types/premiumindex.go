@@ -1,20 +1,47 @@
 package types
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 )
 
+// DefaultFundingInterval is the funding interval of the most perpetual contracts
+const DefaultFundingInterval = 8 * time.Hour
+
 type PremiumIndex struct {
 	Symbol          string           `json:"symbol"`
 	MarkPrice       fixedpoint.Value `json:"markPrice"`
 	LastFundingRate fixedpoint.Value `json:"lastFundingRate"`
 	NextFundingTime time.Time        `json:"nextFundingTime"`
 	Time            time.Time        `json:"time"`
+
+	// FundingInterval is the interval between the funding settlements, DefaultFundingInterval is used if it's zero
+	FundingInterval time.Duration `json:"fundingInterval,omitempty"`
+}
+
+// NormalizedFundingRate converts the funding rate to the rate of the given interval,
+// so that the funding rates of the contracts with different funding intervals can be compared.
+func (i *PremiumIndex) NormalizedFundingRate(interval time.Duration) fixedpoint.Value {
+	fundingInterval := i.FundingInterval
+	if fundingInterval <= 0 {
+		fundingInterval = DefaultFundingInterval
+	}
+
+	if fundingInterval == interval {
+		return i.LastFundingRate
+	}
+
+	return i.LastFundingRate.Mul(fixedpoint.NewFromFloat(interval.Hours() / fundingInterval.Hours()))
 }
 
 func (i *PremiumIndex) String() string {
 	return fmt.Sprintf("PremiumIndex | %s | %.4f | %s | %s | NEXT FUNDING TIME: %s", i.Symbol, i.MarkPrice.Float64(), i.LastFundingRate.Percentage(), i.Time, i.NextFundingTime)
 }
+
+// PremiumIndexService is implemented by the futures exchanges that provide the mark price and the funding rate of a symbol
+type PremiumIndexService interface {
+	QueryPremiumIndex(ctx context.Context, symbol string) (*PremiumIndex, error)
+}
@@ -0,0 +1,21 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestPremiumIndex_NormalizedFundingRate(t *testing.T) {
+	index := &PremiumIndex{LastFundingRate: fixedpoint.MustNewFromString("0.01%")}
+	assert.Equal(t, "0.0001", index.NormalizedFundingRate(8*time.Hour).String())
+
+	index.FundingInterval = 4 * time.Hour
+	assert.Equal(t, "0.0002", index.NormalizedFundingRate(8*time.Hour).String())
+
+	index.FundingInterval = time.Hour
+	assert.Equal(t, "0.0008", index.NormalizedFundingRate(8*time.Hour).String())
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// AssetBalanceSnapshot is the stored balance of an asset of a session account at a point of time
+type AssetBalanceSnapshot struct {
+	GID       int64            `json:"gid,omitempty" db:"gid"`
+	Session   string           `json:"session" db:"session"`
+	Exchange  ExchangeName     `json:"exchange" db:"exchange"`
+	Time      Time             `json:"time" db:"time"`
+	Asset     string           `json:"asset" db:"asset"`
+	Available fixedpoint.Value `json:"available" db:"available"`
+	Locked    fixedpoint.Value `json:"locked" db:"locked"`
+	Borrowed  fixedpoint.Value `json:"borrowed" db:"borrowed"`
+	Interest  fixedpoint.Value `json:"interest" db:"interest"`
+	NetAsset  fixedpoint.Value `json:"netAsset" db:"net_asset"`
+}
+
+// NewAssetBalanceSnapshots converts the balances into the snapshots, the net asset is calculated from the balance
+func NewAssetBalanceSnapshots(session string, exchange ExchangeName, t Time, balances BalanceMap) []AssetBalanceSnapshot {
+	var snapshots []AssetBalanceSnapshot
+	for currency, b := range balances {
+		snapshots = append(snapshots, AssetBalanceSnapshot{
+			Session:   session,
+			Exchange:  exchange,
+			Time:      t,
+			Asset:     currency,
+			Available: b.Available,
+			Locked:    b.Locked,
+			Borrowed:  b.Borrowed,
+			Interest:  b.Interest,
+			NetAsset:  b.Net(),
+		})
+	}
+
+	return snapshots
+}
+
+// AccountFlow is the balance changes of an asset from the synced records
+type AccountFlow struct {
+	// Trade is the bought quantity minus the sold quantity, the quote quantity is included for the quote currency
+	Trade    fixedpoint.Value `json:"trade" db:"trade"`
+	Fee      fixedpoint.Value `json:"fee" db:"fee"`
+	Deposit  fixedpoint.Value `json:"deposit" db:"deposit"`
+	Withdraw fixedpoint.Value `json:"withdraw" db:"withdraw"`
+	Reward   fixedpoint.Value `json:"reward" db:"reward"`
+	Interest fixedpoint.Value `json:"interest" db:"interest"`
+}
+
+// Net returns the net balance change of the flow
+func (f AccountFlow) Net() fixedpoint.Value {
+	return f.Trade.Sub(f.Fee).
+		Add(f.Deposit).
+		Sub(f.Withdraw).
+		Add(f.Reward).
+		Sub(f.Interest)
+}
+
+// IsZero returns true if there is no balance change in the flow
+func (f AccountFlow) IsZero() bool {
+	return f.Trade.IsZero() && f.Fee.IsZero() &&
+		f.Deposit.IsZero() && f.Withdraw.IsZero() &&
+		f.Reward.IsZero() && f.Interest.IsZero()
+}
+
+// AccountStatement is the daily statement of an asset of a session account,
+// the end balance should be equal to the start balance plus the flows between the balance snapshots
+type AccountStatement struct {
+	GID       int64        `json:"gid,omitempty" db:"gid"`
+	Session   string       `json:"session" db:"session"`
+	Exchange  ExchangeName `json:"exchange" db:"exchange"`
+	Asset     string       `json:"asset" db:"asset"`
+	StartTime Time         `json:"startTime" db:"start_time"`
+	EndTime   Time         `json:"endTime" db:"end_time"`
+
+	StartBalance fixedpoint.Value `json:"startBalance" db:"start_balance"`
+	EndBalance   fixedpoint.Value `json:"endBalance" db:"end_balance"`
+
+	AccountFlow
+
+	// Difference is the end balance minus the expected balance, the balance changes are not explained by the synced records
+	Difference fixedpoint.Value `json:"difference" db:"difference"`
+	Reconciled bool             `json:"reconciled" db:"reconciled"`
+}
+
+// Expected returns the expected end balance from the start balance and the flows
+func (s AccountStatement) Expected() fixedpoint.Value {
+	return s.StartBalance.Add(s.AccountFlow.Net())
+}
+
+// Reconcile calculates the difference, the statement is reconciled if the difference is within the tolerance
+func (s *AccountStatement) Reconcile(tolerance fixedpoint.Value) bool {
+	s.Difference = s.EndBalance.Sub(s.Expected())
+	s.Reconciled = s.Difference.Abs().Compare(tolerance) <= 0
+	return s.Reconciled
+}
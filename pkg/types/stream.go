@@ -155,6 +155,9 @@ type StandardStream struct {
 	heartBeat HeartBeat
 
 	beforeConnect BeforeConnect
+
+	// recorder records the raw messages if it's set
+	recorder *StreamRecorder
 }
 
 type StandardStreamEmitter interface {
@@ -208,6 +211,12 @@ func (s *StandardStream) SetParser(parser Parser) {
 	s.parser = parser
 }
 
+// SetRecorder sets the recorder for recording the raw messages received from the connection,
+// the recorder will be closed when the stream is closed.
+func (s *StandardStream) SetRecorder(recorder *StreamRecorder) {
+	s.recorder = recorder
+}
+
 func (s *StandardStream) SetConn(ctx context.Context, conn *websocket.Conn) (context.Context, context.CancelFunc) {
 	// should only start one connection one time, so we lock the mutex
 	connCtx, connCancel := context.WithCancel(ctx)
@@ -236,9 +245,6 @@ func (s *StandardStream) Read(ctx context.Context, conn *websocket.Conn, cancel
 	// flag format: debug-{component}-{message type}
 	debugRawMessage := viper.GetBool("debug-websocket-raw-message")
 
-	hasParser := s.parser != nil
-	hasDispatcher := s.dispatcher != nil
-
 	for {
 		select {
 
@@ -293,29 +299,39 @@ func (s *StandardStream) Read(ctx context.Context, conn *websocket.Conn, cancel
 				log.Info(string(message))
 			}
 
-			if !hasParser {
-				s.EmitRawMessage(message)
-				continue
+			if s.recorder != nil {
+				if err := s.recorder.RecordMessage(time.Now(), message); err != nil {
+					log.WithError(err).Errorf("unable to record the websocket message")
+				}
 			}
 
-			var e interface{}
-			e, err = s.parser(message)
-			if err != nil {
-				log.WithError(err).Errorf("unable to parse the websocket message. err: %v, message: %s", err, message)
-				// emit raw message even if occurs error, because we want anything can be detected
-				s.EmitRawMessage(message)
-				continue
-			}
+			s.HandleRawMessage(message)
+		}
+	}
+}
 
-			// skip pong event to avoid the message like spam
-			if _, ok := e.(*WebsocketPongEvent); !ok {
-				s.EmitRawMessage(message)
-			}
+// HandleRawMessage parses the raw message with the parser and dispatches the parsed event with the dispatcher
+func (s *StandardStream) HandleRawMessage(message []byte) {
+	if s.parser == nil {
+		s.EmitRawMessage(message)
+		return
+	}
 
-			if hasDispatcher {
-				s.dispatcher(e)
-			}
-		}
+	e, err := s.parser(message)
+	if err != nil {
+		log.WithError(err).Errorf("unable to parse the websocket message. err: %v, message: %s", err, message)
+		// emit raw message even if occurs error, because we want anything can be detected
+		s.EmitRawMessage(message)
+		return
+	}
+
+	// skip pong event to avoid the message like spam
+	if _, ok := e.(*WebsocketPongEvent); !ok {
+		s.EmitRawMessage(message)
+	}
+
+	if s.dispatcher != nil {
+		s.dispatcher(e)
 	}
 }
 
@@ -456,6 +472,13 @@ func (s *StandardStream) DialAndConnect(ctx context.Context) error {
 	}
 
 	connCtx, connCancel := s.SetConn(ctx, conn)
+
+	if s.recorder != nil {
+		if err := s.recorder.RecordConnect(time.Now(), s.GetSubscriptions(), s.PublicOnly); err != nil {
+			log.WithError(err).Errorf("unable to record the websocket subscriptions")
+		}
+	}
+
 	s.EmitConnect()
 
 	s.sg.Add(func() {
@@ -521,6 +544,12 @@ func (s *StandardStream) Close() error {
 		connCancel()
 	}
 
+	if s.recorder != nil {
+		if err := s.recorder.Close(); err != nil {
+			log.WithError(err).Errorf("unable to close the stream recorder")
+		}
+	}
+
 	// gracefully write the close message to the connection
 	err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
 	if err != nil {
@@ -573,12 +602,27 @@ const (
 	SpeedLow    Speed = "LOW"
 )
 
+// KLineSource is the source of the klines of a kline subscription
+type KLineSource string
+
+const (
+	// KLineSourceExchange uses the klines of the exchange,
+	// the intervals that the exchange does not provide are aggregated from a lower interval
+	KLineSourceExchange KLineSource = ""
+
+	// KLineSourceTrade builds the klines from the market trades
+	KLineSourceTrade KLineSource = "trade"
+)
+
 // SubscribeOptions provides the standard stream options
 type SubscribeOptions struct {
 	// TODO: change to Interval type later
 	Interval Interval `json:"interval,omitempty"`
 	Depth    Depth    `json:"depth,omitempty"`
 	Speed    Speed    `json:"speed,omitempty"`
+
+	// Source is the source of the klines of the kline subscription
+	Source KLineSource `json:"source,omitempty"`
 }
 
 func (o SubscribeOptions) String() string {
@@ -0,0 +1,168 @@
+package types
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const streamRecorderFlushInterval = 5 * time.Second
+
+type StreamRecordType string
+
+const (
+	// StreamRecordTypeConnect is recorded when a new connection is established with the subscription set of the connection
+	StreamRecordTypeConnect StreamRecordType = "connect"
+
+	// StreamRecordTypeMessage is a raw message frame received from the connection
+	StreamRecordTypeMessage StreamRecordType = "message"
+)
+
+// StreamRecord is one line of the stream record file
+type StreamRecord struct {
+	Type StreamRecordType `json:"type"`
+
+	// Time is the receive time of the message
+	Time time.Time `json:"time"`
+
+	// Message is the raw message frame, it's not parsed so that it can be replayed with the adapter's parser
+	Message string `json:"message,omitempty"`
+
+	Subscriptions []Subscription `json:"subscriptions,omitempty"`
+	PublicOnly    bool           `json:"publicOnly,omitempty"`
+}
+
+// StreamRecorderSetter is implemented by the streams embedding StandardStream
+type StreamRecorderSetter interface {
+	SetRecorder(recorder *StreamRecorder)
+}
+
+// StreamRecorder writes the raw websocket message frames into a gzip compressed JSON lines file
+type StreamRecorder struct {
+	mu        sync.Mutex
+	file      *os.File
+	writer    *gzip.Writer
+	encoder   *json.Encoder
+	lastFlush time.Time
+	closed    bool
+}
+
+func NewStreamRecorder(filename string) (*StreamRecorder, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := gzip.NewWriter(file)
+	return &StreamRecorder{
+		file:      file,
+		writer:    writer,
+		encoder:   json.NewEncoder(writer),
+		lastFlush: time.Now(),
+	}, nil
+}
+
+func (r *StreamRecorder) RecordConnect(t time.Time, subscriptions []Subscription, publicOnly bool) error {
+	return r.record(StreamRecord{
+		Type:          StreamRecordTypeConnect,
+		Time:          t,
+		Subscriptions: subscriptions,
+		PublicOnly:    publicOnly,
+	}, true)
+}
+
+func (r *StreamRecorder) RecordMessage(t time.Time, message []byte) error {
+	return r.record(StreamRecord{
+		Type:    StreamRecordTypeMessage,
+		Time:    t,
+		Message: string(message),
+	}, false)
+}
+
+func (r *StreamRecorder) record(record StreamRecord, flush bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	if err := r.encoder.Encode(record); err != nil {
+		return err
+	}
+
+	// flush the compressed data periodically so that we don't lose too much data when the process is killed
+	if flush || record.Time.Sub(r.lastFlush) > streamRecorderFlushInterval {
+		r.lastFlush = record.Time
+		return r.writer.Flush()
+	}
+
+	return nil
+}
+
+func (r *StreamRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+	if err := r.writer.Close(); err != nil {
+		_ = r.file.Close()
+		return err
+	}
+
+	return r.file.Close()
+}
+
+// StreamRecordReader reads the stream records written by the StreamRecorder
+type StreamRecordReader struct {
+	file    *os.File
+	reader  *gzip.Reader
+	decoder *json.Decoder
+}
+
+func OpenStreamRecordReader(filename string) (*StreamRecordReader, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &StreamRecordReader{
+		file:    file,
+		reader:  reader,
+		decoder: json.NewDecoder(reader),
+	}, nil
+}
+
+// Next reads the next record, it returns io.EOF when there is no more record.
+// A truncated record (written by a killed process) is also treated as the end of the file.
+func (r *StreamRecordReader) Next() (*StreamRecord, error) {
+	var record StreamRecord
+	if err := r.decoder.Decode(&record); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, io.EOF
+		}
+
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *StreamRecordReader) Close() error {
+	_ = r.reader.Close()
+	return r.file.Close()
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecordEvent struct {
+	Symbol string `json:"s"`
+	Price  string `json:"p"`
+}
+
+func TestStreamRecorder_Replay(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "stream.jsonl.gz")
+
+	recorder, err := NewStreamRecorder(filename)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	now := time.Now()
+	subscriptions := []Subscription{{Symbol: "BTCUSDT", Channel: BookChannel}}
+	assert.NoError(t, recorder.RecordConnect(now, subscriptions, true))
+	assert.NoError(t, recorder.RecordMessage(now.Add(time.Millisecond), []byte(`{"s":"BTCUSDT","p":"100"}`)))
+	assert.NoError(t, recorder.RecordMessage(now.Add(2*time.Millisecond), []byte(`{"s":"BTCUSDT","p":"101"}`)))
+	assert.NoError(t, recorder.Close())
+
+	reader, err := OpenStreamRecordReader(filename)
+	if assert.NoError(t, err) {
+		record, err := reader.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, StreamRecordTypeConnect, record.Type)
+			assert.Equal(t, subscriptions, record.Subscriptions)
+			assert.True(t, record.PublicOnly)
+		}
+		assert.NoError(t, reader.Close())
+	}
+
+	stream := NewStandardStream()
+	stream.SetParser(func(message []byte) (interface{}, error) {
+		var e testRecordEvent
+		err := json.Unmarshal(message, &e)
+		return &e, err
+	})
+
+	var prices []string
+	stream.SetDispatcher(func(e interface{}) {
+		prices = append(prices, e.(*testRecordEvent).Price)
+	})
+
+	replayStream, err := NewReplayStream(&stream, filename, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, replayStream.Connect(context.Background()))
+
+	select {
+	case <-replayStream.Done():
+	case <-time.After(time.Second):
+		t.Fatal("replay timeout")
+	}
+
+	assert.Equal(t, []string{"100", "101"}, prices)
+}
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RawMessageHandler handles the raw message with the stream's own parser and dispatcher,
+// StandardStream implements this interface, so all the exchange streams embedding StandardStream do.
+type RawMessageHandler interface {
+	HandleRawMessage(message []byte)
+}
+
+// ReplayStream feeds the recorded raw messages back to the exchange stream, the messages are parsed and dispatched
+// by the exchange stream's own parser and dispatcher, so the callbacks registered on the stream get the same events
+// as the recorded session.
+//
+// Since there is no real connection, the connect event is not emitted, so the subscribe requests of the
+// exchange stream will not be sent. Replaying the private user data stream that writes to the connection
+// in the auth event handler is not supported.
+type ReplayStream struct {
+	StandardStreamEmitter
+
+	// Speed is the replay speed multiplier, 1.0 replays the messages with the original intervals,
+	// 10.0 replays 10 times faster, and 0 replays the messages without any delay.
+	Speed float64
+
+	filename string
+	handler  RawMessageHandler
+
+	closeOnce sync.Once
+	closeC    chan struct{}
+	doneC     chan struct{}
+}
+
+func NewReplayStream(stream Stream, filename string, speed float64) (*ReplayStream, error) {
+	handler, ok := stream.(RawMessageHandler)
+	if !ok {
+		return nil, fmt.Errorf("stream %T does not support raw message handling", stream)
+	}
+
+	emitter, ok := stream.(StandardStreamEmitter)
+	if !ok {
+		return nil, fmt.Errorf("stream %T is not a standard stream emitter", stream)
+	}
+
+	return &ReplayStream{
+		StandardStreamEmitter: emitter,
+		Speed:                 speed,
+		filename:              filename,
+		handler:               handler,
+		closeC:                make(chan struct{}),
+		doneC:                 make(chan struct{}),
+	}, nil
+}
+
+// Connect opens the record file and starts replaying the messages in the background
+func (s *ReplayStream) Connect(ctx context.Context) error {
+	reader, err := OpenStreamRecordReader(s.filename)
+	if err != nil {
+		return err
+	}
+
+	go s.replay(ctx, reader)
+	s.EmitStart()
+	return nil
+}
+
+// Done returns a channel that is closed when all the recorded messages are replayed
+func (s *ReplayStream) Done() <-chan struct{} {
+	return s.doneC
+}
+
+func (s *ReplayStream) replay(ctx context.Context, reader *StreamRecordReader) {
+	defer func() {
+		_ = reader.Close()
+		close(s.doneC)
+		s.EmitDisconnect()
+	}()
+
+	var lastTime time.Time
+	for {
+		record, err := reader.Next()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.WithError(err).Errorf("unable to read the stream record file %s", s.filename)
+			}
+
+			return
+		}
+
+		if record.Type != StreamRecordTypeMessage {
+			continue
+		}
+
+		if s.Speed > 0 && !lastTime.IsZero() {
+			delay := time.Duration(float64(record.Time.Sub(lastTime)) / s.Speed)
+			if delay > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-s.closeC:
+					return
+				case <-time.After(delay):
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.closeC:
+			return
+		default:
+		}
+
+		lastTime = record.Time
+		s.handler.HandleRawMessage([]byte(record.Message))
+	}
+}
+
+func (s *ReplayStream) Reconnect() {}
+
+func (s *ReplayStream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closeC)
+	})
+	return nil
+}